@@ -0,0 +1,386 @@
+// Package acmclient 管理导入到 AWS ACM 的源站证书：按主机名（SAN）查找已有证书、
+// 原地重新导入到同一 ARN（挂载的 ALB/CloudFront 无需修改）并打上 Cloudflare 证书 ID 标签。
+package acmclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"DomainC/config"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	acmtypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+// 本工具写入 ACM 的标签
+const (
+	TagManagedBy   = "managed-by"
+	TagDomain      = "cf-origin-domain" // 证书覆盖的主机名，见 HostnameTag
+	TagCertID      = "cf-origin-cert-id"
+	ManagedByValue = "DomainC"
+)
+
+// ErrTagging 表示证书已成功重新导入到 ARN，只是更新标签失败。
+// 调用方应把它当作警告：新证书已经在 ARN 上生效，不能再回滚。
+var ErrTagging = errors.New("证书已重新导入，但更新标签失败")
+
+// Cert 是 ACM 中的一张证书
+type Cert struct {
+	Target     string // config.AWSTargets 中的别名
+	Region     string
+	ARN        string
+	DomainName string
+	SANs       []string
+	Serial     string // 小写十六进制，不含冒号与前导 0
+	Type       string // IMPORTED / AMAZON_ISSUED / PRIVATE
+	Status     string
+	Issuer     string
+	NotAfter   time.Time
+	InUseBy    []string
+	Tags       map[string]string
+}
+
+// InUse 表示证书挂载在负载均衡、CloudFront 等资源上
+func (c Cert) InUse() bool {
+	return len(c.InUseBy) > 0
+}
+
+// Imported 表示证书是导入的（只有导入证书可以重新导入）
+func (c Cert) Imported() bool {
+	return c.Type == string(acmtypes.CertificateTypeImported)
+}
+
+// Managed 表示证书由本工具导入
+func (c Cert) Managed() bool {
+	return c.Tags[TagManagedBy] == ManagedByValue
+}
+
+// CloudflareOrigin 表示证书由 Cloudflare Origin CA 签发
+func (c Cert) CloudflareOrigin() bool {
+	return strings.Contains(strings.ToLower(c.Issuer), "cloudflare")
+}
+
+// DaysLeft 返回剩余天数
+func (c Cert) DaysLeft(now time.Time) int {
+	return int(c.NotAfter.Sub(now).Hours() / 24)
+}
+
+// Tags 返回导入证书时写入的标签
+func Tags(hostnames []string, cfCertID string) map[string]string {
+	tags := map[string]string{TagManagedBy: ManagedByValue}
+	if v := HostnameTag(hostnames); v != "" {
+		tags[TagDomain] = v
+	}
+	if cfCertID != "" {
+		tags[TagCertID] = cfCertID
+	}
+	return tags
+}
+
+// tagValueLimit 是 ACM 标签值的最大长度
+const tagValueLimit = 256
+
+// HostnameTag 返回主机名集合的标签值：规范化、去重、排序后以空格连接。
+// ACM 标签值不允许 "*"，通配符写作 "_"；超长时截断（查找证书以 SAN 为准，标签仅供识别）。
+func HostnameTag(hostnames []string) string {
+	v := strings.ReplaceAll(strings.Join(hostnameSet(hostnames), " "), "*", "_")
+	if len(v) > tagValueLimit {
+		v = v[:tagValueLimit]
+	}
+	return v
+}
+
+// hostnameSet 返回小写、去掉末尾点、去重并排序后的主机名
+func hostnameSet(hostnames []string) []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(hostnames))
+	for _, h := range hostnames {
+		h = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(h)), ".")
+		if h != "" && !seen[h] {
+			seen[h] = true
+			out = append(out, h)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Hostnames 返回证书覆盖的主机名集合（SAN 为空时用主域名）
+func (c Cert) Hostnames() []string {
+	if len(c.SANs) > 0 {
+		return hostnameSet(c.SANs)
+	}
+	return hostnameSet([]string{c.DomainName})
+}
+
+// Client 是单个 AWS 目标（账号 + 区域）的 ACM 客户端
+type Client struct {
+	Target string
+	Region string
+	api    *acm.Client
+}
+
+// New 按 AWS 目标配置创建客户端。target.Endpoint 非空时请求发往该地址（本地 ACM 替身）。
+func New(ctx context.Context, alias string, target config.AWSTarget) (*Client, error) {
+	if strings.TrimSpace(target.Region) == "" {
+		return nil, fmt.Errorf("aws target region 为空")
+	}
+	if strings.TrimSpace(target.Creds.AccessKeyID) == "" || strings.TrimSpace(target.Creds.SecretAccessKey) == "" {
+		return nil, fmt.Errorf("aws target creds 不完整")
+	}
+	cfg, err := awscfg.LoadDefaultConfig(
+		ctx,
+		awscfg.WithRegion(target.Region),
+		awscfg.WithCredentialsProvider(
+			aws.NewCredentialsCache(
+				credentials.NewStaticCredentialsProvider(
+					target.Creds.AccessKeyID,
+					target.Creds.SecretAccessKey,
+					target.Creds.SessionToken,
+				),
+			),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	api := acm.NewFromConfig(cfg, func(o *acm.Options) {
+		if ep := strings.TrimSpace(target.Endpoint); ep != "" {
+			o.BaseEndpoint = aws.String(ep)
+		}
+	})
+	return &Client{Target: alias, Region: target.Region, api: api}, nil
+}
+
+// NewForAlias 按别名从 config.Cfg.AWSTargets 创建客户端
+func NewForAlias(ctx context.Context, alias string) (*Client, error) {
+	target, ok := config.Cfg.AWSTargets[alias]
+	if !ok {
+		return nil, fmt.Errorf("未知 AWS 目标别名：%s", alias)
+	}
+	return New(ctx, alias, target)
+}
+
+// Aliases 返回排序后的 AWS 目标别名
+func Aliases(targets map[string]config.AWSTarget) []string {
+	out := make([]string, 0, len(targets))
+	for alias := range targets {
+		out = append(out, alias)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// List 列出全部证书（含详情与标签），按域名排序
+func (c *Client) List(ctx context.Context) ([]Cert, error) {
+	p := acm.NewListCertificatesPaginator(c.api, &acm.ListCertificatesInput{
+		// 默认只返回 RSA_2048，显式列出全部算法
+		Includes: &acmtypes.Filters{KeyTypes: acmtypes.KeyAlgorithm("").Values()},
+	})
+	var out []Cert
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] 列出 ACM 证书失败: %v", c.Target, err)
+		}
+		for _, s := range page.CertificateSummaryList {
+			if s.CertificateArn == nil {
+				continue
+			}
+			cert, err := c.Describe(ctx, *s.CertificateArn)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, cert)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].DomainName != out[j].DomainName {
+			return out[i].DomainName < out[j].DomainName
+		}
+		return out[i].NotAfter.Before(out[j].NotAfter)
+	})
+	return out, nil
+}
+
+// Describe 读取证书详情与标签
+func (c *Client) Describe(ctx context.Context, arn string) (Cert, error) {
+	desc, err := c.api.DescribeCertificate(ctx, &acm.DescribeCertificateInput{CertificateArn: aws.String(arn)})
+	if err != nil {
+		return Cert{}, fmt.Errorf("[%s] 读取 ACM 证书失败 %s: %v", c.Target, arn, err)
+	}
+	cert := Cert{Target: c.Target, Region: c.Region, ARN: arn, Tags: map[string]string{}}
+	if d := desc.Certificate; d != nil {
+		cert.DomainName = aws.ToString(d.DomainName)
+		cert.SANs = d.SubjectAlternativeNames
+		cert.Serial = NormalizeSerial(aws.ToString(d.Serial))
+		cert.Type = string(d.Type)
+		cert.Status = string(d.Status)
+		cert.Issuer = aws.ToString(d.Issuer)
+		cert.NotAfter = aws.ToTime(d.NotAfter)
+		cert.InUseBy = d.InUseBy
+	}
+	tags, err := c.api.ListTagsForCertificate(ctx, &acm.ListTagsForCertificateInput{CertificateArn: aws.String(arn)})
+	if err != nil {
+		return Cert{}, fmt.Errorf("[%s] 读取 ACM 证书标签失败 %s: %v", c.Target, arn, err)
+	}
+	for _, t := range tags.Tags {
+		cert.Tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	return cert, nil
+}
+
+// FindBySerial 返回序列号为 serial 的导入证书
+func (c *Client) FindBySerial(ctx context.Context, serial string) ([]Cert, error) {
+	serial = NormalizeSerial(serial)
+	certs, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []Cert
+	for _, cert := range certs {
+		if cert.Imported() && cert.Serial == serial {
+			out = append(out, cert)
+		}
+	}
+	return out, nil
+}
+
+// FindForHostnames 查找可重新导入的已有证书：本工具导入或由 Cloudflare Origin CA 签发的导入证书，
+// 且其 SAN 与新主机名集合相同或是其子集（重新导入后原有主机名仍被覆盖，不会导致挂载的服务断证）。
+// 有多张时依次优先：SAN 完全相同、本工具导入、正在使用、到期最晚。
+func (c *Client) FindForHostnames(ctx context.Context, hostnames []string) (Cert, bool, error) {
+	certs, err := c.List(ctx)
+	if err != nil {
+		return Cert{}, false, err
+	}
+	return pickForHostnames(certs, hostnames)
+}
+
+func pickForHostnames(certs []Cert, hostnames []string) (Cert, bool, error) {
+	want := map[string]bool{}
+	for _, h := range hostnameSet(hostnames) {
+		want[h] = true
+	}
+	if len(want) == 0 {
+		return Cert{}, false, nil
+	}
+	type candidate struct {
+		cert  Cert
+		exact bool
+	}
+	var candidates []candidate
+	for _, cert := range certs {
+		if !cert.Imported() || !(cert.Managed() || cert.CloudflareOrigin()) {
+			continue
+		}
+		have := cert.Hostnames()
+		covered := len(have) > 0
+		for _, h := range have {
+			if !want[h] {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			candidates = append(candidates, candidate{cert: cert, exact: len(have) == len(want)})
+		}
+	}
+	if len(candidates) == 0 {
+		return Cert{}, false, nil
+	}
+	better := func(a, b candidate) bool {
+		if a.exact != b.exact {
+			return a.exact
+		}
+		if a.cert.Managed() != b.cert.Managed() {
+			return a.cert.Managed()
+		}
+		if a.cert.InUse() != b.cert.InUse() {
+			return a.cert.InUse()
+		}
+		return a.cert.NotAfter.After(b.cert.NotAfter)
+	}
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if better(c, best) {
+			best = c
+		}
+	}
+	return best.cert, true, nil
+}
+
+// Import 导入证书。arn 为空时新建证书并打标签；否则重新导入到该 ARN 后更新标签
+// （ACM 不允许在重新导入时携带标签）。返回证书 ARN；重新导入成功但更新标签失败时
+// 返回 ARN 与包装了 ErrTagging 的错误。
+func (c *Client) Import(ctx context.Context, arn, certPEM, keyPEM string, tags map[string]string) (string, error) {
+	in := &acm.ImportCertificateInput{
+		Certificate: []byte(strings.TrimSpace(certPEM) + "\n"),
+		PrivateKey:  []byte(strings.TrimSpace(keyPEM) + "\n"),
+	}
+	if arn != "" {
+		in.CertificateArn = aws.String(arn)
+	} else {
+		in.Tags = toACMTags(tags)
+	}
+	out, err := c.api.ImportCertificate(ctx, in)
+	if err != nil {
+		if arn != "" {
+			return "", fmt.Errorf("acm reimport certificate: %w", err)
+		}
+		return "", fmt.Errorf("acm import certificate: %w", err)
+	}
+	if arn == "" {
+		return aws.ToString(out.CertificateArn), nil
+	}
+	if len(tags) > 0 {
+		if _, err := c.api.AddTagsToCertificate(ctx, &acm.AddTagsToCertificateInput{
+			CertificateArn: aws.String(arn),
+			Tags:           toACMTags(tags),
+		}); err != nil {
+			return arn, fmt.Errorf("%w: %v", ErrTagging, err)
+		}
+	}
+	return arn, nil
+}
+
+// Upsert 把源站证书导入 ACM：已有 SAN 相同（或为其子集）的证书时重新导入到同一 ARN，否则新建。
+// reused 表示沿用了已有 ARN。
+func (c *Client) Upsert(ctx context.Context, hostnames []string, cfCertID, certPEM, keyPEM string) (arn string, reused bool, err error) {
+	existing, ok, err := c.FindForHostnames(ctx, hostnames)
+	if err != nil {
+		return "", false, err
+	}
+	arn, err = c.Import(ctx, existing.ARN, certPEM, keyPEM, Tags(hostnames, cfCertID))
+	return arn, ok, err
+}
+
+// NormalizeSerial 把 ACM 返回的 "0e:5d:..." 形式序列号转换为小写十六进制（不含冒号与前导 0）
+func NormalizeSerial(s string) string {
+	s = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), ":", ""))
+	s = strings.TrimLeft(s, "0")
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+func toACMTags(tags map[string]string) []acmtypes.Tag {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]acmtypes.Tag, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, acmtypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	return out
+}
@@ -0,0 +1,427 @@
+package acmclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"DomainC/config"
+)
+
+// fakeACM 是本地 ACM 替身，实现 JSON 1.1 协议中用到的接口
+type fakeACM struct {
+	mu    sync.Mutex
+	certs map[string]*fakeCert
+	next  int
+	calls []string
+	// tagFail 为 true 时 AddTagsToCertificate 返回错误
+	tagFail bool
+}
+
+type fakeCert struct {
+	domain  string
+	sans    []string
+	serial  string
+	issuer  string
+	typ     string
+	expires time.Time
+	inUseBy []string
+	tags    map[string]string
+}
+
+type tag struct {
+	Key   string
+	Value string
+}
+
+func newFakeACM() *fakeACM {
+	return &fakeACM{certs: map[string]*fakeCert{}}
+}
+
+func (f *fakeACM) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	op := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "CertificateManager.")
+	f.calls = append(f.calls, op)
+	var in struct {
+		CertificateArn string
+		Certificate    []byte
+		PrivateKey     []byte
+		NextToken      string
+		Tags           []tag
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, "ValidationException", err.Error())
+		return
+	}
+
+	var out any
+	switch op {
+	case "ListCertificates":
+		arns := f.sortedARNs()
+		start := 0
+		if in.NextToken != "" {
+			fmt.Sscanf(in.NextToken, "%d", &start)
+		}
+		end := min(start+2, len(arns)) // 每页 2 张，覆盖分页
+		var list []map[string]string
+		for _, arn := range arns[start:end] {
+			list = append(list, map[string]string{"CertificateArn": arn, "DomainName": f.certs[arn].domain})
+		}
+		resp := map[string]any{"CertificateSummaryList": list}
+		if end < len(arns) {
+			resp["NextToken"] = fmt.Sprint(end)
+		}
+		out = resp
+	case "DescribeCertificate":
+		c, ok := f.certs[in.CertificateArn]
+		if !ok {
+			writeError(w, "ResourceNotFoundException", "not found")
+			return
+		}
+		out = map[string]any{"Certificate": map[string]any{
+			"CertificateArn":          in.CertificateArn,
+			"DomainName":              c.domain,
+			"SubjectAlternativeNames": c.sans,
+			"Serial":                  c.serial,
+			"Issuer":                  c.issuer,
+			"Type":                    c.typ,
+			"Status":                  "ISSUED",
+			"NotAfter":                c.expires.Unix(),
+			"InUseBy":                 c.inUseBy,
+		}}
+	case "ListTagsForCertificate":
+		c, ok := f.certs[in.CertificateArn]
+		if !ok {
+			writeError(w, "ResourceNotFoundException", "not found")
+			return
+		}
+		var tags []tag
+		for k, v := range c.tags {
+			tags = append(tags, tag{Key: k, Value: v})
+		}
+		out = map[string]any{"Tags": tags}
+	case "ImportCertificate":
+		block, _ := pem.Decode(in.Certificate)
+		if block == nil || len(in.PrivateKey) == 0 {
+			writeError(w, "ValidationException", "bad certificate")
+			return
+		}
+		x, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			writeError(w, "ValidationException", err.Error())
+			return
+		}
+		arn := in.CertificateArn
+		c, ok := f.certs[arn]
+		switch {
+		case arn == "":
+			f.next++
+			arn = fmt.Sprintf("arn:aws:acm:us-east-1:123:certificate/%d", f.next)
+			c = &fakeCert{typ: "IMPORTED", tags: map[string]string{}}
+			f.certs[arn] = c
+			for _, t := range in.Tags {
+				c.tags[t.Key] = t.Value
+			}
+		case !ok:
+			writeError(w, "ResourceNotFoundException", "not found")
+			return
+		case len(in.Tags) > 0:
+			writeError(w, "ValidationException", "tags cannot be applied while reimporting")
+			return
+		}
+		c.domain, c.sans, c.expires = x.Subject.CommonName, x.DNSNames, x.NotAfter
+		c.serial = colonHex(x.SerialNumber)
+		c.issuer = strings.Join(x.Issuer.Organization, ",")
+		out = map[string]string{"CertificateArn": arn}
+	case "AddTagsToCertificate":
+		if f.tagFail {
+			writeError(w, "ThrottlingException", "rate exceeded")
+			return
+		}
+		c, ok := f.certs[in.CertificateArn]
+		if !ok {
+			writeError(w, "ResourceNotFoundException", "not found")
+			return
+		}
+		for _, t := range in.Tags {
+			c.tags[t.Key] = t.Value
+		}
+		out = map[string]string{}
+	default:
+		writeError(w, "UnknownOperationException", op)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (f *fakeACM) sortedARNs() []string {
+	arns := make([]string, 0, len(f.certs))
+	for arn := range f.certs {
+		arns = append(arns, arn)
+	}
+	sort.Strings(arns)
+	return arns
+}
+
+func writeError(w http.ResponseWriter, typ, msg string) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"__type": typ, "message": msg})
+}
+
+func colonHex(n *big.Int) string {
+	h := n.Text(16)
+	if len(h)%2 == 1 {
+		h = "0" + h
+	}
+	var parts []string
+	for i := 0; i < len(h); i += 2 {
+		parts = append(parts, h[i:i+2])
+	}
+	return strings.Join(parts, ":")
+}
+
+func testCert(t *testing.T, serial int64, issuer string, hostnames ...string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: hostnames[0], Organization: []string{issuer}},
+		DNSNames:     hostnames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func newTestClient(t *testing.T, fake *fakeACM) *Client {
+	t.Helper()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	c, err := New(context.Background(), "us", config.AWSTarget{
+		Region:   "us-east-1",
+		Creds:    config.AWSCreds{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+		Endpoint: srv.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestUpsertReimportsToSameARN(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeACM()
+	c := newTestClient(t, fake)
+
+	certPEM, keyPEM := testCert(t, 0x1001, "CloudFlare, Inc.", "example.com", "*.example.com")
+	arn, reused, err := c.Upsert(ctx, []string{"example.com", "*.example.com"}, "cf-1", certPEM, keyPEM)
+	if err != nil || reused || arn == "" {
+		t.Fatalf("first upsert: arn=%s reused=%v err=%v", arn, reused, err)
+	}
+
+	certPEM, keyPEM = testCert(t, 0x2002, "CloudFlare, Inc.", "example.com", "*.example.com")
+	arn2, reused, err := c.Upsert(ctx, []string{"example.com", "*.example.com"}, "cf-2", certPEM, keyPEM)
+	if err != nil || !reused || arn2 != arn {
+		t.Fatalf("second upsert: arn=%s reused=%v err=%v", arn2, reused, err)
+	}
+	if len(fake.certs) != 1 {
+		t.Fatalf("expected 1 cert, got %d", len(fake.certs))
+	}
+
+	got, err := c.Describe(ctx, arn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Serial != "2002" || !got.Managed() || got.Tags[TagCertID] != "cf-2" || got.Tags[TagDomain] != "_.example.com example.com" {
+		t.Fatalf("unexpected cert after reimport: %+v", got)
+	}
+}
+
+func TestUpsertAdoptsUntaggedCloudflareCert(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeACM()
+	c := newTestClient(t, fake)
+
+	// 早期 /ssl 导入的证书没有标签，其中一张挂在 ALB 上
+	for i, inUse := range []bool{false, true} {
+		certPEM, keyPEM := testCert(t, int64(0x10+i), "CloudFlare, Inc.", "example.com", "*.example.com")
+		arn, err := c.Import(ctx, "", certPEM, keyPEM, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if inUse {
+			fake.certs[arn].inUseBy = []string{"arn:aws:elasticloadbalancing:us-east-1:123:loadbalancer/app/web/1"}
+		}
+	}
+	// 其他 CA 签发的同名证书不会被覆盖
+	certPEM, keyPEM := testCert(t, 0x99, "Other CA", "example.com")
+	if _, err := c.Import(ctx, "", certPEM, keyPEM, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM, keyPEM = testCert(t, 0x3003, "CloudFlare, Inc.", "example.com", "*.example.com")
+	arn, reused, err := c.Upsert(ctx, []string{"example.com", "*.example.com"}, "cf-3", certPEM, keyPEM)
+	if err != nil || !reused {
+		t.Fatalf("upsert: reused=%v err=%v", reused, err)
+	}
+	if got := fake.certs[arn]; len(got.inUseBy) == 0 || got.serial != "30:03" || got.tags[TagCertID] != "cf-3" {
+		t.Fatalf("expected in-use cert to be reimported, got %+v", got)
+	}
+	if len(fake.certs) != 3 {
+		t.Fatalf("expected no new cert, got %d", len(fake.certs))
+	}
+}
+
+func TestUpsertMatchesBySANs(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeACM()
+	c := newTestClient(t, fake)
+
+	// 已有证书只覆盖 example.com，新证书增加了 api.example.com：旧 SAN 是子集，可以重新导入
+	certPEM, keyPEM := testCert(t, 0x40, "CloudFlare, Inc.", "example.com")
+	arn, _, err := c.Upsert(ctx, []string{"example.com"}, "cf-1", certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, keyPEM = testCert(t, 0x41, "CloudFlare, Inc.", "example.com", "api.example.com")
+	got, reused, err := c.Upsert(ctx, []string{"API.example.com", "example.com"}, "cf-2", certPEM, keyPEM)
+	if err != nil || !reused || got != arn {
+		t.Fatalf("superset upsert: arn=%s reused=%v err=%v", got, reused, err)
+	}
+	if tag := fake.certs[arn].tags[TagDomain]; tag != "api.example.com example.com" {
+		t.Fatalf("unexpected hostname tag %q", tag)
+	}
+
+	// 同一 zone 但 SAN 不同（已有证书覆盖 api.example.com，新证书没有）：必须新建，不能覆盖
+	certPEM, keyPEM = testCert(t, 0x42, "CloudFlare, Inc.", "example.com", "www.example.com")
+	got, reused, err = c.Upsert(ctx, []string{"example.com", "www.example.com"}, "cf-3", certPEM, keyPEM)
+	if err != nil || reused || got == arn {
+		t.Fatalf("mismatched SANs must create a new ARN: arn=%s reused=%v err=%v", got, reused, err)
+	}
+	if fake.certs[arn].serial != "41" || len(fake.certs) != 2 {
+		t.Fatalf("existing cert must be untouched: %+v (%d certs)", fake.certs[arn], len(fake.certs))
+	}
+}
+
+func TestPickForHostnamesPrefersExactMatch(t *testing.T) {
+	now := time.Now()
+	imported := func(arn string, managed bool, sans ...string) Cert {
+		c := Cert{ARN: arn, Type: "IMPORTED", Issuer: "CloudFlare, Inc.", SANs: sans, NotAfter: now, Tags: map[string]string{}}
+		if managed {
+			c.Tags = Tags(sans, "")
+		}
+		return c
+	}
+	certs := []Cert{
+		imported("subset", true, "example.com"),
+		imported("exact", false, "*.example.com", "example.com"),
+		imported("other", true, "other.com"),
+		{ARN: "amazon", Type: "AMAZON_ISSUED", SANs: []string{"example.com", "*.example.com"}},
+	}
+	got, ok, _ := pickForHostnames(certs, []string{"example.com", "*.example.com"})
+	if !ok || got.ARN != "exact" {
+		t.Fatalf("expected exact match, got %s %v", got.ARN, ok)
+	}
+	if _, ok, _ := pickForHostnames(certs, []string{"www.example.com"}); ok {
+		t.Fatal("no cert covers only www.example.com")
+	}
+	if got := HostnameTag([]string{"www.Example.com.", "*.example.com", "www.example.com"}); got != "_.example.com www.example.com" {
+		t.Fatalf("HostnameTag = %q", got)
+	}
+}
+
+func TestListAndFindBySerial(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeACM()
+	c := newTestClient(t, fake)
+
+	for i, domain := range []string{"c.example", "a.example", "b.example"} {
+		certPEM, keyPEM := testCert(t, int64(0x0a00+i), "CloudFlare, Inc.", domain)
+		if _, err := c.Import(ctx, "", certPEM, keyPEM, Tags([]string{domain}, "")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	certs, err := c.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 3 || certs[0].DomainName != "a.example" || certs[2].DomainName != "c.example" {
+		t.Fatalf("unexpected list: %+v", certs)
+	}
+	if certs[0].Target != "us" || certs[0].Region != "us-east-1" || certs[0].NotAfter.IsZero() || certs[0].InUse() {
+		t.Fatalf("unexpected cert: %+v", certs[0])
+	}
+
+	found, err := c.FindBySerial(ctx, "0a:02")
+	if err != nil || len(found) != 1 || found[0].DomainName != "b.example" {
+		t.Fatalf("find by serial: %+v %v", found, err)
+	}
+}
+
+func TestReimportErrors(t *testing.T) {
+	c := newTestClient(t, newFakeACM())
+	certPEM, keyPEM := testCert(t, 1, "CloudFlare, Inc.", "example.com")
+	if _, err := c.Import(context.Background(), "arn:aws:acm:us-east-1:123:certificate/missing", certPEM, keyPEM, nil); err == nil || !strings.Contains(err.Error(), "reimport") {
+		t.Fatalf("expected reimport error, got %v", err)
+	}
+}
+
+func TestReimportTagFailureIsWarning(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeACM()
+	c := newTestClient(t, fake)
+	certPEM, keyPEM := testCert(t, 0x10, "CloudFlare, Inc.", "example.com")
+	arn, err := c.Import(ctx, "", certPEM, keyPEM, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.tagFail = true
+	certPEM, keyPEM = testCert(t, 0x20, "CloudFlare, Inc.", "example.com")
+	got, err := c.Import(ctx, arn, certPEM, keyPEM, Tags([]string{"example.com"}, "cf-2"))
+	if !errors.Is(err, ErrTagging) || got != arn {
+		t.Fatalf("expected ErrTagging with arn, got %s %v", got, err)
+	}
+	if fake.certs[arn].serial != "20" {
+		t.Fatalf("certificate should have been reimported, serial=%s", fake.certs[arn].serial)
+	}
+}
+
+func TestNormalizeSerial(t *testing.T) {
+	for in, want := range map[string]string{"0e:5D:01": "e5d01", "00": "0", "ABC": "abc"} {
+		if got := NormalizeSerial(in); got != want {
+			t.Fatalf("NormalizeSerial(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
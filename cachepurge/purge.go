@@ -0,0 +1,166 @@
+// Package cachepurge 把待清理的 URL、主机名或路径前缀按所属 Zone 分组，
+// 再调用 Cloudflare 按条目清理缓存（cfclient 负责按每批 30 条拆分）。
+package cachepurge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+// KindTitle 是各清理类型的中文名
+var KindTitle = map[string]string{
+	cfclient.PurgeFiles:    "URL",
+	cfclient.PurgeHosts:    "主机名",
+	cfclient.PurgePrefixes: "路径前缀",
+	cfclient.PurgeTags:     "Cache-Tag",
+}
+
+// Zone 是条目所属的 Zone
+type Zone struct {
+	Account config.CF
+	Name    string
+	ID      string
+}
+
+// Resolver 按主机名查找所属 Zone
+type Resolver func(host string) (Zone, error)
+
+// Group 是同一 Zone 下同一类型的待清理条目
+type Group struct {
+	Zone  Zone
+	Kind  string
+	Items []string
+}
+
+// Normalize 规范化条目，并返回用于定位 Zone 的主机名：
+// URL 缺省协议时补 https://；主机名与前缀去掉协议，前缀必须带路径
+func Normalize(kind, item string) (value, host string, err error) {
+	s := strings.Trim(strings.TrimSpace(item), `"'`)
+	if s == "" {
+		return "", "", fmt.Errorf("空条目")
+	}
+	switch kind {
+	case cfclient.PurgeFiles:
+		if !strings.Contains(s, "://") {
+			s = "https://" + s
+		}
+		u, err := url.Parse(s)
+		if err != nil || u.Hostname() == "" {
+			return "", "", fmt.Errorf("无法解析 URL: %s", item)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return "", "", fmt.Errorf("URL 协议必须是 http 或 https: %s", item)
+		}
+		u.Host = strings.ToLower(u.Host)
+		u.Fragment = ""
+		if u.Path == "" {
+			u.Path = "/"
+		}
+		return u.String(), u.Hostname(), nil
+	case cfclient.PurgeHosts, cfclient.PurgePrefixes:
+		if i := strings.Index(s, "://"); i >= 0 {
+			s = s[i+3:]
+		}
+		if i := strings.IndexAny(s, "?#"); i >= 0 {
+			s = s[:i]
+		}
+		host, path, _ := strings.Cut(s, "/")
+		host = strings.TrimSuffix(strings.ToLower(host), ".")
+		if h, _, ok := strings.Cut(host, ":"); ok {
+			host = h
+		}
+		if host == "" || !strings.Contains(host, ".") {
+			return "", "", fmt.Errorf("无效的主机名: %s", item)
+		}
+		if kind == cfclient.PurgeHosts {
+			return host, host, nil
+		}
+		if strings.Trim(path, "/") == "" {
+			return "", "", fmt.Errorf("前缀必须包含路径（如 %s/static），整站请用主机名清理: %s", host, item)
+		}
+		return host + "/" + path, host, nil
+	}
+	return "", "", fmt.Errorf("%s 无法按主机名定位 Zone", kind)
+}
+
+// Plan 规范化并去重条目，按所属 Zone 分组（按 Zone 名排序）；无法解析或定位的条目返回在 errs 中
+func Plan(kind string, items []string, resolve Resolver) ([]Group, []error) {
+	var errs []error
+	zones := map[string]Zone{}    // host -> zone
+	failed := map[string]bool{}   // 已报告过定位失败的 host
+	groups := map[string]*Group{} // zone ID -> group
+	seen := map[string]bool{}
+	for _, item := range items {
+		value, host, err := Normalize(kind, item)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		zone, ok := zones[host]
+		if !ok {
+			if failed[host] {
+				continue
+			}
+			zone, err = resolve(host)
+			if err != nil {
+				failed[host] = true
+				errs = append(errs, fmt.Errorf("%s: %v", host, err))
+				continue
+			}
+			zones[host] = zone
+		}
+		g, ok := groups[zone.ID]
+		if !ok {
+			g = &Group{Zone: zone, Kind: kind}
+			groups[zone.ID] = g
+		}
+		g.Items = append(g.Items, value)
+	}
+
+	out := make([]Group, 0, len(groups))
+	for _, g := range groups {
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Zone.Name < out[j].Zone.Name })
+	return out, errs
+}
+
+// Result 是单个 Zone 的清理结果；失败时 Purged 为失败前已提交的条目数
+type Result struct {
+	Group  Group
+	Purged int
+	Err    error
+}
+
+// Execute 逐个 Zone 提交清理
+func Execute(ctx context.Context, client cfclient.Client, groups []Group) []Result {
+	out := make([]Result, 0, len(groups))
+	for _, g := range groups {
+		var n int
+		var err error
+		switch g.Kind {
+		case cfclient.PurgeFiles:
+			n, err = client.PurgeCacheFiles(ctx, g.Zone.Account, g.Zone.ID, g.Items)
+		case cfclient.PurgeHosts:
+			n, err = client.PurgeCacheHosts(ctx, g.Zone.Account, g.Zone.ID, g.Items)
+		case cfclient.PurgePrefixes:
+			n, err = client.PurgeCachePrefixes(ctx, g.Zone.Account, g.Zone.ID, g.Items)
+		case cfclient.PurgeTags:
+			n, err = client.PurgeCacheTags(ctx, g.Zone.Account, g.Zone.ID, g.Items)
+		default:
+			err = fmt.Errorf("不支持的清理类型: %s", g.Kind)
+		}
+		out = append(out, Result{Group: g, Purged: n, Err: err})
+	}
+	return out
+}
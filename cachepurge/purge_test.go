@@ -0,0 +1,105 @@
+package cachepurge
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		kind, in, value, host string
+	}{
+		{cfclient.PurgeFiles, "https://WWW.Example.com/a.css?v=1#top", "https://www.example.com/a.css?v=1", "www.example.com"},
+		{cfclient.PurgeFiles, "example.com/img/logo.png", "https://example.com/img/logo.png", "example.com"},
+		{cfclient.PurgeFiles, "http://example.com", "http://example.com/", "example.com"},
+		{cfclient.PurgeHosts, "https://Static.Example.com/x", "static.example.com", "static.example.com"},
+		{cfclient.PurgePrefixes, "https://www.example.com/css/?a=1", "www.example.com/css/", "www.example.com"},
+	}
+	for _, c := range cases {
+		value, host, err := Normalize(c.kind, c.in)
+		if err != nil || value != c.value || host != c.host {
+			t.Errorf("Normalize(%s, %q) = %q, %q, %v", c.kind, c.in, value, host, err)
+		}
+	}
+	for _, bad := range []struct{ kind, in string }{
+		{cfclient.PurgeFiles, "ftp://example.com/a"},
+		{cfclient.PurgePrefixes, "www.example.com"},
+		{cfclient.PurgeHosts, "localhost"},
+		{cfclient.PurgeTags, "product-1"},
+	} {
+		if _, _, err := Normalize(bad.kind, bad.in); err == nil {
+			t.Errorf("Normalize(%s, %q): expected error", bad.kind, bad.in)
+		}
+	}
+}
+
+func TestPlanGroupsByZone(t *testing.T) {
+	lookups := 0
+	resolve := func(host string) (Zone, error) {
+		lookups++
+		switch {
+		case strings.HasSuffix(host, "example.com"):
+			return Zone{Account: config.CF{Label: "a"}, Name: "example.com", ID: "z1"}, nil
+		case strings.HasSuffix(host, "example.org"):
+			return Zone{Account: config.CF{Label: "b"}, Name: "example.org", ID: "z2"}, nil
+		}
+		return Zone{}, errors.New("not found")
+	}
+	groups, errs := Plan(cfclient.PurgeFiles, []string{
+		"https://www.example.org/a.js",
+		"https://www.example.com/a.css",
+		"https://www.example.com/a.css",
+		"https://cdn.example.com/b.css",
+		"https://www.example.com/c.css",
+		"https://unknown.net/x",
+		"https://unknown.net/y",
+		"ftp://bad",
+	}, resolve)
+	if len(errs) != 2 {
+		t.Fatalf("errs = %v", errs)
+	}
+	if len(groups) != 2 || groups[0].Zone.ID != "z1" || groups[1].Zone.ID != "z2" {
+		t.Fatalf("groups = %+v", groups)
+	}
+	if len(groups[0].Items) != 3 || groups[0].Items[1] != "https://cdn.example.com/b.css" || groups[0].Kind != cfclient.PurgeFiles {
+		t.Fatalf("example.com items = %v", groups[0].Items)
+	}
+	// 每个主机名只查询一次，失败的主机名也不重复查询
+	if lookups != 4 {
+		t.Fatalf("lookups = %d", lookups)
+	}
+}
+
+type fakeCF struct {
+	cfclient.Client
+	calls []string
+}
+
+func (f *fakeCF) PurgeCacheFiles(ctx context.Context, acc config.CF, zoneID string, urls []string) (int, error) {
+	f.calls = append(f.calls, "files:"+zoneID)
+	return len(urls), nil
+}
+
+func (f *fakeCF) PurgeCacheTags(ctx context.Context, acc config.CF, zoneID string, tags []string) (int, error) {
+	f.calls = append(f.calls, "tags:"+zoneID)
+	return 0, errors.New("not entitled")
+}
+
+func TestExecute(t *testing.T) {
+	cf := &fakeCF{}
+	results := Execute(context.Background(), cf, []Group{
+		{Zone: Zone{ID: "z1"}, Kind: cfclient.PurgeFiles, Items: []string{"https://a/1", "https://a/2"}},
+		{Zone: Zone{ID: "z2"}, Kind: cfclient.PurgeTags, Items: []string{"t"}},
+	})
+	if len(results) != 2 || results[0].Purged != 2 || results[0].Err != nil || results[1].Err == nil {
+		t.Fatalf("results = %+v", results)
+	}
+	if strings.Join(cf.calls, ",") != "files:z1,tags:z2" {
+		t.Fatalf("calls = %v", cf.calls)
+	}
+}
@@ -0,0 +1,32 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"DomainC/cfclient"
+	"DomainC/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func handleCertsCallback(action string, parts []string, user *tgbotapi.User, cb *tgbotapi.CallbackQuery) {
+	if len(parts) < 2 {
+		log.Printf("无效的 certs 回调数据: %v", parts)
+		return
+	}
+	payload, ok := telegram.TakeCertRegenPayload(parts[1])
+	if !ok {
+		telegram.SendTelegramAlert("操作已过期或已处理，请重新执行 /certs。")
+		return
+	}
+
+	switch action {
+	case "certs_regen":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("开始重新签发源站证书: %s（操作人: %s）", payload.Zone, user.UserName))
+			telegram.RegenerateOriginCert(context.Background(), cfclient.NewClient(), telegram.DefaultSender(), payload)
+		}()
+	}
+}
@@ -0,0 +1,42 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"DomainC/cfclient"
+	"DomainC/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func handleDNSImportCallback(action string, parts []string, user *tgbotapi.User, cb *tgbotapi.CallbackQuery) {
+	if len(parts) < 2 {
+		log.Printf("无效的 dnsimport 回调数据: %v", parts)
+		return
+	}
+	payload, ok := telegram.TakeDNSImportPayload(parts[1])
+	if !ok {
+		telegram.SendTelegramAlert("导入计划已过期或已处理，请重新上传文件。")
+		return
+	}
+
+	sender := telegram.DefaultSender()
+	if cb.Message != nil {
+		_ = sender.ClearButtons(context.Background(), cb.Message.Chat.ID, cb.Message.MessageID)
+	}
+
+	switch action {
+	case "dnsimport_apply":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("开始执行导入: %s（%d 个 Zone，确认人: %s）", payload.Filename, len(payload.Plans), user.UserName))
+			telegram.ApplyDNSImport(context.Background(), cfclient.NewClient(), sender, payload)
+		}()
+
+	case "dnsimport_cancel":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("已取消导入: %s（操作人: %s）", payload.Filename, user.UserName))
+		}()
+	}
+}
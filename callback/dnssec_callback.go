@@ -0,0 +1,53 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"DomainC/cfclient"
+	"DomainC/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func handleDNSSECCallback(action string, parts []string, user *tgbotapi.User, cb *tgbotapi.CallbackQuery) {
+	if len(parts) < 2 {
+		log.Printf("无效的 dnssec 回调数据: %v", parts)
+		return
+	}
+	payload, ok := telegram.TakeDNSSECPayload(parts[1])
+	if !ok {
+		telegram.SendTelegramAlert("操作已过期或已处理，请重新执行 /dnssec。")
+		return
+	}
+
+	sender := telegram.DefaultSender()
+	if cb.Message != nil {
+		_ = sender.ClearButtons(context.Background(), cb.Message.Chat.ID, cb.Message.MessageID)
+	}
+
+	switch action {
+	case "dnssec_removeds":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("开始移除注册商 DS: %s（确认人: %s）", payload.Zone, user.UserName))
+			telegram.RemoveRegistrarDS(context.Background(), cfclient.NewClient(), sender, payload)
+		}()
+
+	case "dnssec_forceoff":
+		account := cfclient.GetAccountByLabel(payload.AccountLabel)
+		if account == nil {
+			telegram.SendTelegramAlert(fmt.Sprintf("操作失败：未找到账号 %s", payload.AccountLabel))
+			return
+		}
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("⚠️ 在注册局仍有 DS 的情况下关闭 DNSSEC: %s（确认人: %s）", payload.Zone, user.UserName))
+			telegram.ForceDisableDNSSEC(context.Background(), cfclient.NewClient(), sender, *account, payload)
+		}()
+
+	case "dnssec_cancel":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("已取消关闭 DNSSEC: %s（操作人: %s）", payload.Zone, user.UserName))
+		}()
+	}
+}
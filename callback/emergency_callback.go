@@ -0,0 +1,46 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"DomainC/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func handleEmergencyCallback(action string, parts []string, user *tgbotapi.User, cb *tgbotapi.CallbackQuery) {
+	if len(parts) < 2 {
+		log.Printf("无效的 emergency 回调数据: %v", parts)
+		return
+	}
+	payload, ok := telegram.TakeEmergencyPayload(parts[1])
+	if !ok {
+		telegram.SendTelegramAlert("操作已过期或已处理，请重新执行 /uam 或 /devmode。")
+		return
+	}
+
+	sender := telegram.DefaultSender()
+	if cb.Message != nil {
+		_ = sender.ClearButtons(context.Background(), cb.Message.Chat.ID, cb.Message.MessageID)
+	}
+
+	switch action {
+	case "emergency_confirm":
+		m := telegram.EmergencyManager()
+		if m == nil {
+			telegram.SendTelegramAlert("应急模式切换未启用。")
+			return
+		}
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("开始切换 %s: %d 个 Zone（确认人: %s）", payload.Mode, len(payload.Targets), user.UserName))
+			telegram.RunEmergency(context.Background(), m, sender, payload)
+		}()
+
+	case "emergency_cancel":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("已取消 /%s（操作人: %s）", payload.Mode, user.UserName))
+		}()
+	}
+}
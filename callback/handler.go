@@ -33,6 +33,54 @@ func HandleCallback(cb *tgbotapi.CallbackQuery) {
 		handleIPListCallback(action, parts, user, cb)
 		return
 	}
+	if strings.HasPrefix(action, "setdns_") {
+		handleSetDNSCallback(action, parts, user, cb)
+		return
+	}
+	if strings.HasPrefix(action, "dnsimport_") {
+		handleDNSImportCallback(action, parts, user, cb)
+		return
+	}
+	if strings.HasPrefix(action, "plan_") {
+		handlePlanCallback(action, parts, user, cb)
+		return
+	}
+	if strings.HasPrefix(action, "mailsetup_") {
+		handleMailSetupCallback(action, parts, user, cb)
+		return
+	}
+	if strings.HasPrefix(action, "nscheck_") {
+		handleNSCheckCallback(action, parts, user, cb)
+		return
+	}
+	if strings.HasPrefix(action, "sslrevoke_") {
+		handleSSLRevokeCallback(action, parts, user, cb)
+		return
+	}
+	if strings.HasPrefix(action, "sslrotate_") {
+		handleSSLRotateCallback(action, parts, user, cb)
+		return
+	}
+	if strings.HasPrefix(action, "zoneaudit_") {
+		handleZoneAuditCallback(action, parts, user, cb)
+		return
+	}
+	if strings.HasPrefix(action, "emergency_") {
+		handleEmergencyCallback(action, parts, user, cb)
+		return
+	}
+	if strings.HasPrefix(action, "certs_") {
+		handleCertsCallback(action, parts, user, cb)
+		return
+	}
+	if strings.HasPrefix(action, "dnssec_") {
+		handleDNSSECCallback(action, parts, user, cb)
+		return
+	}
+	if strings.HasPrefix(action, "movezone_") {
+		handleMoveZoneCallback(action, parts, user, cb)
+		return
+	}
 	if len(parts) < 3 {
 		log.Printf("无效的回调数据: %s", callbackData)
 		return
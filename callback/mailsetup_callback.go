@@ -0,0 +1,47 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"DomainC/cfclient"
+	"DomainC/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func handleMailSetupCallback(action string, parts []string, user *tgbotapi.User, cb *tgbotapi.CallbackQuery) {
+	if len(parts) < 2 {
+		log.Printf("无效的 mailsetup 回调数据: %v", parts)
+		return
+	}
+	payload, ok := telegram.TakeMailSetupPayload(parts[1])
+	if !ok {
+		telegram.SendTelegramAlert("操作已过期或已处理，请重新执行 /mailsetup。")
+		return
+	}
+
+	sender := telegram.DefaultSender()
+	if cb.Message != nil {
+		_ = sender.ClearButtons(context.Background(), cb.Message.Chat.ID, cb.Message.MessageID)
+	}
+
+	switch action {
+	case "mailsetup_apply":
+		account := cfclient.GetAccountByLabel(payload.AccountLabel)
+		if account == nil {
+			telegram.SendTelegramAlert(fmt.Sprintf("操作失败：未找到账号 %s", payload.AccountLabel))
+			return
+		}
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("开始套用邮件模板 %s: %s（确认人: %s）", payload.Profile, payload.Zone, user.UserName))
+			telegram.ApplyMailSetup(context.Background(), cfclient.NewClient(), sender, *account, payload)
+		}()
+
+	case "mailsetup_cancel":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("已取消套用邮件模板: %s（操作人: %s）", payload.Zone, user.UserName))
+		}()
+	}
+}
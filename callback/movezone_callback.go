@@ -0,0 +1,48 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/registrarclient"
+	"DomainC/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func handleMoveZoneCallback(action string, parts []string, user *tgbotapi.User, cb *tgbotapi.CallbackQuery) {
+	if len(parts) < 2 {
+		log.Printf("无效的 movezone 回调数据: %v", parts)
+		return
+	}
+	payload, ok := telegram.TakeMoveZonePayload(parts[1])
+	if !ok {
+		telegram.SendTelegramAlert("迁移请求已过期或已处理，请重新执行 /movezone。")
+		return
+	}
+
+	sender := telegram.DefaultSender()
+	if cb.Message != nil {
+		_ = sender.ClearButtons(context.Background(), cb.Message.Chat.ID, cb.Message.MessageID)
+	}
+
+	switch action {
+	case "movezone_confirm":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("开始迁移 %s: %s → %s（确认人: %s）", payload.Domain, payload.SourceAccount, payload.TargetAccount, user.UserName))
+			var registrars *registrarclient.Manager
+			if len(config.Cfg.Registrars) > 0 {
+				registrars = registrarclient.NewManager(nil, config.Cfg.Registrars)
+			}
+			telegram.RunMoveZone(context.Background(), cfclient.NewClient(), registrars, sender, payload)
+		}()
+
+	case "movezone_cancel":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("已取消迁移: %s（操作人: %s）", payload.Domain, user.UserName))
+		}()
+	}
+}
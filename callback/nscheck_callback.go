@@ -0,0 +1,47 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"DomainC/config"
+	"DomainC/registrarclient"
+	"DomainC/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func handleNSCheckCallback(action string, parts []string, user *tgbotapi.User, cb *tgbotapi.CallbackQuery) {
+	if len(parts) < 2 {
+		log.Printf("无效的 nscheck 回调数据: %v", parts)
+		return
+	}
+	payload, ok := telegram.TakeNSCheckPayload(parts[1])
+	if !ok {
+		telegram.SendTelegramAlert("同步请求已过期或已处理，请重新执行 /nscheck。")
+		return
+	}
+
+	sender := telegram.DefaultSender()
+	if cb.Message != nil {
+		_ = sender.ClearButtons(context.Background(), cb.Message.Chat.ID, cb.Message.MessageID)
+	}
+
+	switch action {
+	case "nscheck_sync":
+		go func() {
+			if len(config.Cfg.Registrars) == 0 {
+				telegram.SendTelegramAlert("未配置注册商，无法同步 NS。")
+				return
+			}
+			telegram.SendTelegramAlert(fmt.Sprintf("开始同步 %d 个 Zone 的注册商 NS（确认人: %s）", len(payload.Targets), user.UserName))
+			telegram.SyncRegistrarNS(context.Background(), registrarclient.NewManager(nil, config.Cfg.Registrars), sender, payload)
+		}()
+
+	case "nscheck_cancel":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("已忽略 NS 同步（操作人: %s）", user.UserName))
+		}()
+	}
+}
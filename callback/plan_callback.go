@@ -0,0 +1,42 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"DomainC/cfclient"
+	"DomainC/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func handlePlanCallback(action string, parts []string, user *tgbotapi.User, cb *tgbotapi.CallbackQuery) {
+	if len(parts) < 2 {
+		log.Printf("无效的 plan 回调数据: %v", parts)
+		return
+	}
+	payload, ok := telegram.TakePlanPayload(parts[1])
+	if !ok {
+		telegram.SendTelegramAlert("计划已过期或已处理，请重新执行 /plan。")
+		return
+	}
+
+	sender := telegram.DefaultSender()
+	if cb.Message != nil {
+		_ = sender.ClearButtons(context.Background(), cb.Message.Chat.ID, cb.Message.MessageID)
+	}
+
+	switch action {
+	case "plan_apply":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("开始执行计划: %s（确认人: %s）", payload.Plan.Zone, user.UserName))
+			telegram.ApplyPlan(context.Background(), cfclient.NewClient(), sender, payload)
+		}()
+
+	case "plan_cancel":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("已取消计划: %s（操作人: %s）", payload.Plan.Zone, user.UserName))
+		}()
+	}
+}
@@ -0,0 +1,48 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"DomainC/cfclient"
+	"DomainC/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func handleSetDNSCallback(action string, parts []string, user *tgbotapi.User, cb *tgbotapi.CallbackQuery) {
+	if len(parts) < 2 {
+		log.Printf("无效的 setdns 回调数据: %v", parts)
+		return
+	}
+	payload, ok := telegram.TakeSetDNSPayload(parts[1])
+	if !ok {
+		telegram.SendTelegramAlert("操作已过期或已处理，请重新执行 /setdns。")
+		return
+	}
+
+	sender := telegram.DefaultSender()
+	if cb.Message != nil {
+		_ = sender.ClearButtons(context.Background(), cb.Message.Chat.ID, cb.Message.MessageID)
+	}
+
+	switch action {
+	case "setdns_confirm":
+		account := cfclient.GetAccountByLabel(payload.AccountLabel)
+		if account == nil {
+			telegram.SendTelegramAlert(fmt.Sprintf("操作失败：未找到账号 %s", payload.AccountLabel))
+			return
+		}
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("开始写入解析: %s %s（操作人: %s）",
+				payload.Params.Type, cfclient.RecordFQDN(payload.Params.Name, payload.Domain), user.UserName))
+			telegram.ApplySetDNS(context.Background(), cfclient.NewClient(), sender, *account, payload.Domain, payload.Params)
+		}()
+
+	case "setdns_cancel":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("已取消写入解析: %s（操作人: %s）", payload.Domain, user.UserName))
+		}()
+	}
+}
@@ -0,0 +1,47 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"DomainC/cfclient"
+	"DomainC/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func handleSSLRevokeCallback(action string, parts []string, user *tgbotapi.User, cb *tgbotapi.CallbackQuery) {
+	if len(parts) < 2 {
+		log.Printf("无效的 sslrevoke 回调数据: %v", parts)
+		return
+	}
+	payload, ok := telegram.TakeSSLRevokePayload(parts[1])
+	if !ok {
+		telegram.SendTelegramAlert("操作已过期或已处理，请重新执行 /sslrevoke。")
+		return
+	}
+
+	sender := telegram.DefaultSender()
+	if cb.Message != nil {
+		_ = sender.ClearButtons(context.Background(), cb.Message.Chat.ID, cb.Message.MessageID)
+	}
+
+	switch action {
+	case "sslrevoke_confirm":
+		account := cfclient.GetAccountByLabel(payload.AccountLabel)
+		if account == nil {
+			telegram.SendTelegramAlert(fmt.Sprintf("操作失败：未找到账号 %s", payload.AccountLabel))
+			return
+		}
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("开始吊销源站证书 %s（确认人: %s）", payload.CertID, user.UserName))
+			telegram.RevokeOriginCert(context.Background(), cfclient.NewClient(), sender, *account, payload)
+		}()
+
+	case "sslrevoke_cancel":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("已取消吊销源站证书 %s（操作人: %s）", payload.CertID, user.UserName))
+		}()
+	}
+}
@@ -0,0 +1,42 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"DomainC/cfclient"
+	"DomainC/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func handleZoneAuditCallback(action string, parts []string, user *tgbotapi.User, cb *tgbotapi.CallbackQuery) {
+	if len(parts) < 2 {
+		log.Printf("无效的 zoneaudit 回调数据: %v", parts)
+		return
+	}
+	payload, ok := telegram.TakeZoneAuditPayload(parts[1])
+	if !ok {
+		telegram.SendTelegramAlert("操作已过期或已处理，请重新执行 /zoneaudit。")
+		return
+	}
+
+	sender := telegram.DefaultSender()
+	if cb.Message != nil {
+		_ = sender.ClearButtons(context.Background(), cb.Message.Chat.ID, cb.Message.MessageID)
+	}
+
+	switch action {
+	case "zoneaudit_apply":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("开始应用 Zone 设置基线: %d 个 Zone（确认人: %s）", len(payload.Results), user.UserName))
+			telegram.ApplyZoneBaseline(context.Background(), cfclient.NewClient(), sender, payload)
+		}()
+
+	case "zoneaudit_cancel":
+		go func() {
+			telegram.SendTelegramAlert(fmt.Sprintf("已取消应用 Zone 设置基线（操作人: %s）", user.UserName))
+		}()
+	}
+}
@@ -0,0 +1,228 @@
+// Package certbundle 把 Origin CA 源站证书导出为各部署目标需要的格式：
+// nginx 使用的 fullchain PEM、Java 使用的 PKCS#12、Kubernetes TLS Secret。
+// 证书链附带与私钥类型对应的 Cloudflare Origin CA 根证书。
+package certbundle
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"DomainC/cfclient"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// 导出格式
+const (
+	FormatFullchain = "fullchain" // 证书 + 根证书 PEM（nginx ssl_certificate）
+	FormatPKCS12    = "p12"       // PKCS#12（Java keystore），含私钥
+	FormatK8s       = "k8s"       // Kubernetes TLS Secret 清单，含私钥
+)
+
+// Formats 是全部可选格式
+var Formats = []string{FormatFullchain, FormatPKCS12, FormatK8s}
+
+// ParseFormats 解析逗号分隔的格式列表（去重，保持顺序）
+func ParseFormats(s string) ([]string, error) {
+	var out []string
+	seen := map[string]bool{}
+	for _, f := range strings.Split(s, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		switch f {
+		case "":
+			continue
+		case "pkcs12", "pfx":
+			f = FormatPKCS12
+		case "kubernetes", "secret":
+			f = FormatK8s
+		case "chain":
+			f = FormatFullchain
+		}
+		if !isFormat(f) {
+			return nil, fmt.Errorf("不支持的导出格式: %s（可选 %s）", f, strings.Join(Formats, "、"))
+		}
+		if !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+func isFormat(f string) bool {
+	for _, x := range Formats {
+		if x == f {
+			return true
+		}
+	}
+	return false
+}
+
+// Secret 表示该格式包含私钥
+func Secret(format string) bool {
+	return format == FormatPKCS12 || format == FormatK8s
+}
+
+// Options 是导出参数
+type Options struct {
+	Name      string // 文件名与 Secret 名的基础，通常为 zone
+	Password  string // PKCS#12 密码，为空时随机生成
+	Namespace string // Kubernetes 命名空间，默认 default
+}
+
+// File 是一个导出文件
+type File struct {
+	Format   string
+	Name     string
+	Data     []byte
+	Password string // PKCS#12 密码
+}
+
+// Build 按格式导出证书
+func Build(format string, cert cfclient.OriginCert, roots Roots, opts Options) (File, error) {
+	leaf, err := parseCert([]byte(cert.CertificatePEM))
+	if err != nil {
+		return File{}, fmt.Errorf("解析证书失败: %v", err)
+	}
+	keyType := keyTypeOf(leaf)
+	rootPEM, err := roots.Root(keyType)
+	if err != nil {
+		return File{}, err
+	}
+	root, err := parseCert(rootPEM)
+	if err != nil {
+		return File{}, err
+	}
+	leafPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw})
+	fullchain := append(leafPEM, rootPEM...)
+	name := fileBase(opts.Name)
+
+	switch format {
+	case FormatFullchain:
+		return File{Format: format, Name: name + "-fullchain.pem", Data: fullchain}, nil
+	case FormatPKCS12:
+		key, err := parsePrivateKey(cert.PrivateKeyPEM)
+		if err != nil {
+			return File{}, err
+		}
+		password := opts.Password
+		if password == "" {
+			buf := make([]byte, 12)
+			if _, err := rand.Read(buf); err != nil {
+				return File{}, err
+			}
+			password = hex.EncodeToString(buf)
+		}
+		data, err := pkcs12.Modern2023.Encode(key, leaf, []*x509.Certificate{root}, password)
+		if err != nil {
+			return File{}, fmt.Errorf("生成 PKCS#12 失败: %v", err)
+		}
+		return File{Format: format, Name: name + ".p12", Data: data, Password: password}, nil
+	case FormatK8s:
+		key, err := parsePrivateKey(cert.PrivateKeyPEM)
+		if err != nil {
+			return File{}, err
+		}
+		// PKCS#8 在各类 Ingress 控制器中兼容性最好
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return File{}, fmt.Errorf("编码私钥失败: %v", err)
+		}
+		keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+		ns := strings.TrimSpace(opts.Namespace)
+		if ns == "" {
+			ns = "default"
+		}
+		secret := secretName(opts.Name)
+		var sb strings.Builder
+		sb.WriteString("apiVersion: v1\n")
+		sb.WriteString("kind: Secret\n")
+		sb.WriteString("type: kubernetes.io/tls\n")
+		sb.WriteString("metadata:\n")
+		sb.WriteString(fmt.Sprintf("  name: %s\n", secret))
+		sb.WriteString(fmt.Sprintf("  namespace: %s\n", ns))
+		sb.WriteString("  annotations:\n")
+		sb.WriteString(fmt.Sprintf("    cloudflare.com/origin-cert-id: %q\n", cert.ID))
+		sb.WriteString("data:\n")
+		sb.WriteString(fmt.Sprintf("  tls.crt: %s\n", base64.StdEncoding.EncodeToString(fullchain)))
+		sb.WriteString(fmt.Sprintf("  tls.key: %s\n", base64.StdEncoding.EncodeToString(keyPEM)))
+		return File{Format: format, Name: secret + ".yaml", Data: []byte(sb.String())}, nil
+	}
+	return File{}, fmt.Errorf("不支持的导出格式: %s", format)
+}
+
+// KeyType 返回证书的私钥类型（rsa / ecc）
+func KeyType(certPEM string) (string, error) {
+	cert, err := parseCert([]byte(certPEM))
+	if err != nil {
+		return "", err
+	}
+	return keyTypeOf(cert), nil
+}
+
+func keyTypeOf(cert *x509.Certificate) string {
+	if _, ok := cert.PublicKey.(*ecdsa.PublicKey); ok {
+		return cfclient.OriginKeyECC
+	}
+	return cfclient.OriginKeyRSA
+}
+
+// parsePrivateKey 支持 PKCS#1（RSA PRIVATE KEY）、SEC 1（EC PRIVATE KEY）与 PKCS#8
+func parsePrivateKey(s string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(s)))
+	if block == nil {
+		return nil, fmt.Errorf("私钥 PEM 解析失败")
+	}
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("私钥解析失败: %v", err)
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return k, nil
+	}
+	return nil, fmt.Errorf("不支持的私钥类型 %T", key)
+}
+
+var unsafeName = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// secretName 返回合法的 Kubernetes 资源名：example.com -> example-com-tls
+func secretName(name string) string {
+	n := strings.Trim(unsafeName.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if n == "" {
+		n = "origin"
+	}
+	if len(n) > 240 {
+		n = strings.Trim(n[:240], "-")
+	}
+	return n + "-tls"
+}
+
+func fileBase(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "origin"
+	}
+	name = strings.NewReplacer("/", "_", "\\", "_", " ", "_", ":", "_", "*", "_").Replace(name)
+	return "origin-ca-" + name
+}
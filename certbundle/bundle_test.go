@@ -0,0 +1,291 @@
+package certbundle
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"DomainC/cfclient"
+
+	"gopkg.in/yaml.v3"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+	pem  []byte
+}
+
+func newKey(t *testing.T, keyType string) crypto.Signer {
+	t.Helper()
+	var key crypto.Signer
+	var err error
+	if keyType == cfclient.OriginKeyECC {
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	} else {
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func newCA(t *testing.T, keyType string) testCA {
+	t.Helper()
+	key := newKey(t, keyType)
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"CloudFlare, Inc."}, CommonName: "Test Origin " + keyType + " Root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// originCert 模拟 Cloudflare 返回的源站证书：私钥 PEM 与 cfclient 生成的格式一致
+func originCert(t *testing.T, ca testCA, keyType string) cfclient.OriginCert {
+	t.Helper()
+	key := newKey(t, keyType)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com", "*.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(12 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keyPEM []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)})
+	case *ecdsa.PrivateKey:
+		b, _ := x509.MarshalECPrivateKey(k)
+		keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: b})
+	}
+	return cfclient.OriginCert{
+		ID:             "cf-123",
+		CertificatePEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		PrivateKeyPEM:  string(keyPEM),
+	}
+}
+
+type mapRoots map[string][]byte
+
+func (m mapRoots) Root(keyType string) ([]byte, error) {
+	if r, ok := m[keyType]; ok {
+		return r, nil
+	}
+	return nil, errors.New("no root")
+}
+
+// verifyChain 校验 leaf 能由链中的根证书验证
+func verifyChain(t *testing.T, leaf *x509.Certificate, chain []*x509.Certificate, want testCA) {
+	t.Helper()
+	pool := x509.NewCertPool()
+	for _, c := range chain {
+		pool.AddCert(c)
+	}
+	chains, err := leaf.Verify(x509.VerifyOptions{Roots: pool, DNSName: "www.example.com"})
+	if err != nil {
+		t.Fatalf("verify chain: %v", err)
+	}
+	if got := chains[0][len(chains[0])-1]; !got.Equal(want.cert) {
+		t.Fatalf("chain ends at %s, want %s", got.Subject, want.cert.Subject)
+	}
+}
+
+func parsePEMCerts(t *testing.T, data []byte) []*x509.Certificate {
+	t.Helper()
+	var out []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func TestBuildBundlesUseRootForKeyType(t *testing.T) {
+	cas := map[string]testCA{
+		cfclient.OriginKeyRSA: newCA(t, cfclient.OriginKeyRSA),
+		cfclient.OriginKeyECC: newCA(t, cfclient.OriginKeyECC),
+	}
+	roots := mapRoots{cfclient.OriginKeyRSA: cas[cfclient.OriginKeyRSA].pem, cfclient.OriginKeyECC: cas[cfclient.OriginKeyECC].pem}
+
+	for _, keyType := range []string{cfclient.OriginKeyRSA, cfclient.OriginKeyECC} {
+		ca := cas[keyType]
+		cert := originCert(t, ca, keyType)
+		opts := Options{Name: "example.com", Namespace: "web"}
+
+		if got, _ := KeyType(cert.CertificatePEM); got != keyType {
+			t.Fatalf("KeyType = %s, want %s", got, keyType)
+		}
+
+		// fullchain：leaf + 对应类型的根证书
+		f, err := Build(FormatFullchain, cert, roots, opts)
+		if err != nil {
+			t.Fatalf("%s fullchain: %v", keyType, err)
+		}
+		certs := parsePEMCerts(t, f.Data)
+		if len(certs) != 2 || f.Name != "origin-ca-example.com-fullchain.pem" {
+			t.Fatalf("%s fullchain: %d certs, name %s", keyType, len(certs), f.Name)
+		}
+		verifyChain(t, certs[0], certs[1:], ca)
+
+		// PKCS#12：密码解开后私钥、证书与 CA 链齐全
+		f, err = Build(FormatPKCS12, cert, roots, opts)
+		if err != nil {
+			t.Fatalf("%s p12: %v", keyType, err)
+		}
+		if f.Password == "" {
+			t.Fatalf("%s p12: empty password", keyType)
+		}
+		key, leaf, caCerts, err := pkcs12.DecodeChain(f.Data, f.Password)
+		if err != nil {
+			t.Fatalf("%s p12 decode: %v", keyType, err)
+		}
+		if !key.(crypto.Signer).Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(leaf.PublicKey) {
+			t.Fatalf("%s p12: key does not match certificate", keyType)
+		}
+		verifyChain(t, leaf, caCerts, ca)
+		if _, _, _, err := pkcs12.DecodeChain(f.Data, "wrong"); err == nil {
+			t.Fatalf("%s p12: decoded with wrong password", keyType)
+		}
+
+		// Kubernetes Secret：合法 YAML，tls.crt/tls.key 可组成 TLS 证书对
+		f, err = Build(FormatK8s, cert, roots, opts)
+		if err != nil {
+			t.Fatalf("%s k8s: %v", keyType, err)
+		}
+		var secret struct {
+			Kind     string
+			Type     string
+			Metadata struct {
+				Name      string
+				Namespace string
+			}
+			Data map[string]string
+		}
+		if err := yaml.Unmarshal(f.Data, &secret); err != nil {
+			t.Fatalf("%s k8s yaml: %v", keyType, err)
+		}
+		if secret.Kind != "Secret" || secret.Type != "kubernetes.io/tls" || secret.Metadata.Name != "example-com-tls" || secret.Metadata.Namespace != "web" {
+			t.Fatalf("%s k8s: unexpected secret %+v", keyType, secret)
+		}
+		crt, _ := base64.StdEncoding.DecodeString(secret.Data["tls.crt"])
+		keyPEM, _ := base64.StdEncoding.DecodeString(secret.Data["tls.key"])
+		pair, err := tls.X509KeyPair(crt, keyPEM)
+		if err != nil {
+			t.Fatalf("%s k8s key pair: %v", keyType, err)
+		}
+		if len(pair.Certificate) != 2 {
+			t.Fatalf("%s k8s: tls.crt has %d certs", keyType, len(pair.Certificate))
+		}
+		certs = parsePEMCerts(t, crt)
+		verifyChain(t, certs[0], certs[1:], ca)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	ca := newCA(t, cfclient.OriginKeyRSA)
+	cert := originCert(t, ca, cfclient.OriginKeyRSA)
+
+	if _, err := Build(FormatFullchain, cert, mapRoots{}, Options{}); err == nil {
+		t.Fatal("expected error without root")
+	}
+	if _, err := Build("jks", cert, mapRoots{cfclient.OriginKeyRSA: ca.pem}, Options{}); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+	cert.PrivateKeyPEM = "garbage"
+	if _, err := Build(FormatPKCS12, cert, mapRoots{cfclient.OriginKeyRSA: ca.pem}, Options{}); err == nil {
+		t.Fatal("expected error for bad key")
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	got, err := ParseFormats("pfx, fullchain,k8s,p12")
+	if err != nil || len(got) != 3 || got[0] != FormatPKCS12 || got[1] != FormatFullchain || got[2] != FormatK8s {
+		t.Fatalf("ParseFormats = %v, %v", got, err)
+	}
+	if _, err := ParseFormats("der"); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+	if !Secret(FormatK8s) || Secret(FormatFullchain) {
+		t.Fatal("unexpected Secret()")
+	}
+}
+
+func TestCloudflareRoots(t *testing.T) {
+	rsaCA, eccCA := newCA(t, cfclient.OriginKeyRSA), newCA(t, cfclient.OriginKeyECC)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, RootFileName(cfclient.OriginKeyRSA)), rsaCA.pem, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fetched := 0
+	r := &CloudflareRoots{Dir: dir, fetch: func(alg string) ([]byte, error) {
+		fetched++
+		if alg == cfclient.OriginKeyECC {
+			return eccCA.pem, nil
+		}
+		return nil, errors.New("offline")
+	}}
+
+	// 本地文件优先
+	if got, err := r.Root(cfclient.OriginKeyRSA); err != nil || string(got) != string(rsaCA.pem) {
+		t.Fatalf("rsa root: %v", err)
+	}
+	// 下载结果被缓存
+	for i := 0; i < 2; i++ {
+		if got, err := r.Root(cfclient.OriginKeyECC); err != nil || string(got) != string(eccCA.pem) {
+			t.Fatalf("ecc root: %v", err)
+		}
+	}
+	if fetched != 1 {
+		t.Fatalf("fetched %d times", fetched)
+	}
+
+	// 根证书类型必须与私钥类型一致
+	bad := &CloudflareRoots{fetch: func(string) ([]byte, error) { return rsaCA.pem, nil }}
+	if _, err := bad.Root(cfclient.OriginKeyECC); err == nil {
+		t.Fatal("expected key type mismatch error")
+	}
+	if _, err := bad.Root("dsa"); err == nil {
+		t.Fatal("expected unsupported key type error")
+	}
+}
@@ -0,0 +1,95 @@
+package certbundle
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"DomainC/cfclient"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Roots 返回与私钥类型对应的 Cloudflare Origin CA 根证书（PEM）
+type Roots interface {
+	Root(keyType string) ([]byte, error)
+}
+
+// CloudflareRoots 优先读取 Dir 下的 origin_ca_<rsa|ecc>_root.pem（离线环境可预先放置），
+// 否则从 Cloudflare 文档站下载。结果缓存在内存中。
+type CloudflareRoots struct {
+	Dir string
+
+	mu    sync.Mutex
+	cache map[string][]byte
+	fetch func(algorithm string) ([]byte, error) // 测试替换
+}
+
+// RootFileName 返回本地根证书文件名
+func RootFileName(keyType string) string {
+	return fmt.Sprintf("origin_ca_%s_root.pem", keyType)
+}
+
+func (r *CloudflareRoots) Root(keyType string) ([]byte, error) {
+	if keyType != cfclient.OriginKeyRSA && keyType != cfclient.OriginKeyECC {
+		return nil, fmt.Errorf("不支持的私钥类型: %s", keyType)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if root, ok := r.cache[keyType]; ok {
+		return root, nil
+	}
+
+	var root []byte
+	var err error
+	if r.Dir != "" {
+		root, err = os.ReadFile(filepath.Join(r.Dir, RootFileName(keyType)))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("读取根证书失败: %v", err)
+		}
+	}
+	if len(root) == 0 {
+		fetch := r.fetch
+		if fetch == nil {
+			fetch = cloudflare.GetOriginCARootCertificate
+		}
+		if root, err = fetch(keyType); err != nil {
+			return nil, fmt.Errorf("下载 Cloudflare Origin CA 根证书失败 [%s]: %v", keyType, err)
+		}
+	}
+
+	cert, err := parseCert(root)
+	if err != nil {
+		return nil, fmt.Errorf("根证书无效 [%s]: %v", keyType, err)
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("根证书无效 [%s]: 不是 CA 证书", keyType)
+	}
+	if got := keyTypeOf(cert); got != keyType {
+		return nil, fmt.Errorf("根证书类型不匹配: 需要 %s，实际 %s", keyType, got)
+	}
+	root = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	if r.cache == nil {
+		r.cache = map[string][]byte{}
+	}
+	r.cache[keyType] = root
+	return root, nil
+}
+
+// parseCert 解析第一张 PEM 证书；内容不含 PEM 头时按裸 base64 处理
+func parseCert(data []byte) (*x509.Certificate, error) {
+	s := strings.TrimSpace(string(data))
+	if !strings.Contains(s, "-----BEGIN") {
+		s = "-----BEGIN CERTIFICATE-----\n" + s + "\n-----END CERTIFICATE-----"
+	}
+	block, _ := pem.Decode([]byte(s))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("证书 PEM 解析失败")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
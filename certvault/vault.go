@@ -0,0 +1,222 @@
+// Package certvault 把签发的源站证书与私钥加密保存到本地目录，聊天中只发送证书与引用 ID。
+//
+// 每个条目一个文件 <Dir>/<id>.vault，内容为 12 字节 nonce + AES-256-GCM 密文，
+// 以 ID 作为附加数据，文件被改名或替换时解密失败。
+package certvault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultDir 未配置目录时使用
+	DefaultDir = "cert_vault"
+	// DefaultKeyEnv 未配置时读取密钥的环境变量
+	DefaultKeyEnv = "CERT_VAULT_KEY"
+
+	fileExt = ".vault"
+	idLen   = 12 // 十六进制字符数
+)
+
+// ErrNotFound 表示引用 ID 不存在
+var ErrNotFound = errors.New("vault entry not found")
+
+// Entry 是保存的一张证书
+type Entry struct {
+	ID             string    `json:"id"`
+	Account        string    `json:"account"`
+	Zone           string    `json:"zone"`
+	Hostnames      []string  `json:"hostnames"`
+	CertID         string    `json:"cert_id"` // Cloudflare 证书 ID
+	ExpiresOn      time.Time `json:"expires_on"`
+	CreatedAt      time.Time `json:"created_at"`
+	CreatedBy      string    `json:"created_by"`
+	CertificatePEM string    `json:"certificate_pem"`
+	PrivateKeyPEM  string    `json:"private_key_pem"`
+	CSRPEM         string    `json:"csr_pem"`
+}
+
+// Vault 是基于本地文件的加密存储
+type Vault struct {
+	Dir  string
+	aead cipher.AEAD
+}
+
+// New 使用 32 字节密钥创建 Vault
+func New(dir string, key []byte) (*Vault, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("vault 密钥必须为 32 字节，当前 %d 字节", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(dir) == "" {
+		dir = DefaultDir
+	}
+	return &Vault{Dir: dir, aead: aead}, nil
+}
+
+// LoadKey 依次从环境变量与密钥文件读取密钥。
+// 密钥为 64 位十六进制或 base64 编码的 32 字节，文件内容首尾空白会被忽略。
+func LoadKey(env, file string) ([]byte, error) {
+	if strings.TrimSpace(env) == "" {
+		env = DefaultKeyEnv
+	}
+	if v := strings.TrimSpace(os.Getenv(env)); v != "" {
+		key, err := ParseKey(v)
+		if err != nil {
+			return nil, fmt.Errorf("环境变量 %s: %v", env, err)
+		}
+		return key, nil
+	}
+	if strings.TrimSpace(file) == "" {
+		return nil, fmt.Errorf("未设置环境变量 %s，也未配置密钥文件", env)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥文件失败: %v", err)
+	}
+	key, err := ParseKey(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("密钥文件 %s: %v", file, err)
+	}
+	return key, nil
+}
+
+// ParseKey 解析十六进制或 base64 编码的 32 字节密钥
+func ParseKey(s string) ([]byte, error) {
+	if key, err := hex.DecodeString(s); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if key, err := enc.DecodeString(s); err == nil && len(key) == 32 {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("密钥须为 32 字节（64 位十六进制或 base64），可用 openssl rand -hex 32 生成")
+}
+
+var idPattern = regexp.MustCompile(`^[0-9a-f]{12}$`)
+
+func (v *Vault) path(id string) string {
+	return filepath.Join(v.Dir, id+fileExt)
+}
+
+// Put 加密保存条目，返回引用 ID
+func (v *Vault) Put(e Entry) (string, error) {
+	buf := make([]byte, idLen/2)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	e.ID = hex.EncodeToString(buf)
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	plain, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, v.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	data := v.aead.Seal(nonce, nonce, plain, []byte(e.ID))
+
+	if err := os.MkdirAll(v.Dir, 0o700); err != nil {
+		return "", fmt.Errorf("创建 vault 目录失败: %w", err)
+	}
+	tmp, err := os.CreateTemp(v.Dir, ".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("写入 vault 失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("写入 vault 失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("写入 vault 失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), v.path(e.ID)); err != nil {
+		return "", fmt.Errorf("写入 vault 失败: %w", err)
+	}
+	return e.ID, nil
+}
+
+// Get 读取并解密条目
+func (v *Vault) Get(id string) (Entry, error) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	if !idPattern.MatchString(id) {
+		return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	data, err := os.ReadFile(v.path(id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return Entry{}, fmt.Errorf("读取 vault 失败 [%s]: %v", id, err)
+	}
+	return v.decrypt(id, data)
+}
+
+func (v *Vault) decrypt(id string, data []byte) (Entry, error) {
+	n := v.aead.NonceSize()
+	if len(data) < n {
+		return Entry{}, fmt.Errorf("vault 条目已损坏 [%s]", id)
+	}
+	plain, err := v.aead.Open(nil, data[:n], data[n:], []byte(id))
+	if err != nil {
+		return Entry{}, fmt.Errorf("解密 vault 条目失败 [%s]: 密钥不匹配或文件被篡改", id)
+	}
+	var e Entry
+	if err := json.Unmarshal(plain, &e); err != nil {
+		return Entry{}, fmt.Errorf("解析 vault 条目失败 [%s]: %v", id, err)
+	}
+	return e, nil
+}
+
+// List 返回全部条目（按创建时间倒序），私钥字段被清空。无法解密的文件返回在 errs 中。
+func (v *Vault) List() ([]Entry, []error) {
+	files, err := os.ReadDir(v.Dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, []error{fmt.Errorf("读取 vault 目录失败: %v", err)}
+	}
+	var out []Entry
+	var errs []error
+	for _, f := range files {
+		id, ok := strings.CutSuffix(f.Name(), fileExt)
+		if f.IsDir() || !ok || !idPattern.MatchString(id) {
+			continue
+		}
+		e, err := v.Get(id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		e.PrivateKeyPEM = ""
+		out = append(out, e)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, errs
+}
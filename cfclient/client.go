@@ -2,12 +2,7 @@ package cfclient
 
 import (
 	"context"
-	"crypto/rand"
-	"crypto/rsa"
-	"crypto/x509"
-	"crypto/x509/pkix"
 	"encoding/json"
-	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
@@ -48,11 +43,13 @@ type OriginCert struct {
 	ExpiresOn      time.Time
 }
 type OriginCACertInfo struct {
-	ID          string
-	Hostnames   []string
-	ExpiresOn   time.Time
-	RevokedAt   *time.Time
-	RequestType string
+	ID             string
+	Hostnames      []string
+	ExpiresOn      time.Time
+	RevokedAt      *time.Time
+	RequestType    string
+	RequestedDays  int
+	CertificatePEM string
 }
 
 // Client 定义了 Cloudflare 相关操作的抽象接口
@@ -61,14 +58,22 @@ type Client interface {
 	ListDNSRecords(ctx context.Context, account config.CF, domain string) ([]cloudflare.DNSRecord, error)
 	PauseDomain(ctx context.Context, account config.CF, domain string, pause bool) error
 	DeleteDomain(ctx context.Context, account config.CF, domain string) error
+	DeleteZoneByID(ctx context.Context, account config.CF, zoneID string) error
 	GetZoneDetails(ctx context.Context, account config.CF, domain string) (ZoneDetail, error)
 	CreateZone(ctx context.Context, account config.CF, domain string) (ZoneDetail, error)
 	UpsertDNSRecord(ctx context.Context, account config.CF, domain string, params DNSRecordParams) (cloudflare.DNSRecord, error)
 	DeleteDNSRecord(ctx context.Context, account config.CF, domain string, recordName string) (int, error)
+	DeleteDNSRecordByID(ctx context.Context, account config.CF, domain string, recordID string) error
 	ListZones(ctx context.Context, acc config.CF) ([]ZoneDetail, error)
-	CreateOriginCertificate(ctx context.Context, account config.CF, hostnames []string) (OriginCert, error)
+	CreateOriginCertificate(ctx context.Context, account config.CF, hostnames []string, opts OriginCertOptions) (OriginCert, error)
 	ListOriginCACertificates(ctx context.Context, account config.CF) ([]OriginCACertInfo, error)
+	ListZoneOriginCACertificates(ctx context.Context, account config.CF, zoneID string) ([]OriginCACertInfo, error)
+	RevokeOriginCACertificate(ctx context.Context, account config.CF, certID string) error
 	PurgeZoneCache(ctx context.Context, account config.CF, zoneID string) error
+	PurgeCacheFiles(ctx context.Context, account config.CF, zoneID string, urls []string) (int, error)
+	PurgeCacheHosts(ctx context.Context, account config.CF, zoneID string, hosts []string) (int, error)
+	PurgeCachePrefixes(ctx context.Context, account config.CF, zoneID string, prefixes []string) (int, error)
+	PurgeCacheTags(ctx context.Context, account config.CF, zoneID string, tags []string) (int, error)
 	ListCustomLists(ctx context.Context, account config.CF) ([]cloudflare.List, error)
 	GetCustomList(ctx context.Context, account config.CF, listID string) (cloudflare.List, error)
 	ListCustomListItems(ctx context.Context, account config.CF, listID string) ([]cloudflare.ListItem, error)
@@ -76,6 +81,18 @@ type Client interface {
 	DeleteCustomListItem(ctx context.Context, account config.CF, listID string, itemID string) ([]cloudflare.ListItem, error)
 	SetZoneSSLFullStrict(ctx context.Context, account config.CF, domain string) error
 	GetAbuseReportCount(ctx context.Context, account config.CF) (int, error)
+	GetAccountID(ctx context.Context, account config.CF) (string, error)
+	GetDNSSEC(ctx context.Context, account config.CF, domain string) (DNSSECInfo, error)
+	EnableDNSSEC(ctx context.Context, account config.CF, domain string) (DNSSECInfo, error)
+	DisableDNSSEC(ctx context.Context, account config.CF, domain string) (DNSSECInfo, error)
+	GetZoneSettings(ctx context.Context, account config.CF, zoneID string) (map[string]string, error)
+	UpdateZoneSetting(ctx context.Context, account config.CF, zoneID, id, value string) error
+	GetRedirectRule(ctx context.Context, account config.CF, zoneID string) (*RedirectRule, error)
+	UpsertRedirectRule(ctx context.Context, account config.CF, zoneID string, rule RedirectRule) (bool, error)
+	DeleteRedirectRule(ctx context.Context, account config.CF, zoneID string) (bool, error)
+	ListBulkRedirects(ctx context.Context, account config.CF, listName string) ([]BulkRedirect, error)
+	AddBulkRedirects(ctx context.Context, account config.CF, listName string, redirects []BulkRedirect) error
+	DeleteBulkRedirects(ctx context.Context, account config.CF, listName string, sources []string) (int, error)
 }
 
 type apiClient struct{}
@@ -102,11 +119,13 @@ var ErrZoneNotFound = errors.New("zone not found")
 
 // DNSRecordParams 描述需要创建或更新的解析记录
 type DNSRecordParams struct {
-	Type    string
-	Name    string
-	Content string
-	Proxied bool
-	TTL     int
+	Type     string
+	Name     string
+	Content  string
+	Proxied  bool
+	TTL      int
+	Priority *uint16      // MX/SRV 等需要优先级的记录
+	Mode     DNSWriteMode // 为空时按 update 处理
 }
 
 func truncateForLog(s string, max int) string {
@@ -348,6 +367,34 @@ func (c *apiClient) DeleteDNSRecord(ctx context.Context, account config.CF, doma
 
 	return deleted, nil
 }
+
+// DeleteDNSRecordByID 按记录 ID 精确删除单条解析（不影响同名的其它记录）
+func (c *apiClient) DeleteDNSRecordByID(ctx context.Context, account config.CF, domain string, recordID string) error {
+	ctx, cancel := ensureTimeout(ctx)
+	defer cancel()
+
+	if strings.TrimSpace(recordID) == "" {
+		return errors.New("recordID is empty")
+	}
+
+	api, err := cloudflare.NewWithAPIToken(account.APIToken)
+	if err != nil {
+		return fmt.Errorf("初始化客户端失败 [%s]: %v", account.Label, err)
+	}
+
+	zones, err := api.ListZonesContext(ctx, cloudflare.WithZoneFilters(domain, "", ""))
+	if err != nil {
+		return fmt.Errorf("获取 Zone 失败: %v", err)
+	}
+	if len(zones.Result) == 0 {
+		return fmt.Errorf("%w: %s", ErrZoneNotFound, domain)
+	}
+
+	if err := api.DeleteDNSRecord(ctx, cloudflare.ZoneIdentifier(zones.Result[0].ID), recordID); err != nil {
+		return fmt.Errorf("删除解析记录失败: %v", err)
+	}
+	return nil
+}
 func (c *apiClient) PurgeZoneCache(ctx context.Context, account config.CF, zoneID string) error {
 	ctx, cancel := ensureTimeout(ctx)
 	defer cancel()
@@ -422,6 +469,26 @@ func (c *apiClient) DeleteDomain(ctx context.Context, account config.CF, domain
 	return nil
 }
 
+// DeleteZoneByID 按 Zone ID 删除 zone。同名 Zone 可能同时存在于多个账号（如迁移过程中），
+// 需要精确删除某一个时使用；Zone 不存在时返回 ErrZoneNotFound
+func (c *apiClient) DeleteZoneByID(ctx context.Context, account config.CF, zoneID string) error {
+	ctx, cancel := ensureTimeout(ctx)
+	defer cancel()
+
+	api, err := cloudflare.NewWithAPIToken(account.APIToken)
+	if err != nil {
+		return fmt.Errorf("初始化 Cloudflare 客户端失败 [%s]: %v", account.Label, err)
+	}
+	if _, err := api.DeleteZone(ctx, zoneID); err != nil {
+		var nf *cloudflare.NotFoundError
+		if errors.As(err, &nf) {
+			return fmt.Errorf("%w: %s", ErrZoneNotFound, zoneID)
+		}
+		return fmt.Errorf("删除 Zone 失败 [%s]: %v", zoneID, err)
+	}
+	return nil
+}
+
 // PauseDomain 暂停或恢复域名（兼容旧版 cloudflare-go SDK）
 func (c *apiClient) PauseDomain(ctx context.Context, account config.CF, domain string, pause bool) error {
 	ctx, cancel := ensureTimeout(ctx)
@@ -579,46 +646,51 @@ func (c *apiClient) UpsertDNSRecord(ctx context.Context, account config.CF, doma
 		return cloudflare.DNSRecord{}, fmt.Errorf("查询解析记录失败: %v", err)
 	}
 
+	plan, err := PlanDNSWrite(existing, params)
+	if err != nil {
+		return cloudflare.DNSRecord{}, err
+	}
+
 	ttl := params.TTL
 	if ttl <= 0 {
 		ttl = 1 // auto
 	}
 	proxied := params.Proxied
 
-	// 存在：更新第一条，并删除其它重复的（真正“替换掉”）
-	if len(existing) > 0 {
-		target := existing[0]
-
-		record, err := api.UpdateDNSRecord(ctx, zoneID, cloudflare.UpdateDNSRecordParams{
-			ID:      target.ID,
-			Type:    searchParams.Type,
-			Name:    recordName,
-			Content: params.Content,
-			TTL:     ttl,
-			Proxied: &proxied,
+	var record cloudflare.DNSRecord
+	if plan.Update != nil {
+		record, err = api.UpdateDNSRecord(ctx, zoneID, cloudflare.UpdateDNSRecordParams{
+			ID:       plan.Update.ID,
+			Type:     searchParams.Type,
+			Name:     recordName,
+			Content:  params.Content,
+			TTL:      ttl,
+			Proxied:  &proxied,
+			Priority: params.Priority,
+			Tags:     plan.Update.Tags,
 		})
 		if err != nil {
 			return cloudflare.DNSRecord{}, fmt.Errorf("更新解析记录失败: %v", err)
 		}
-
-		// 可选但强烈建议：清理同名重复记录
-		for i := 1; i < len(existing); i++ {
-			_ = api.DeleteDNSRecord(ctx, zoneID, existing[i].ID)
+	} else {
+		record, err = api.CreateDNSRecord(ctx, zoneID, cloudflare.CreateDNSRecordParams{
+			Type:     searchParams.Type,
+			Name:     recordName,
+			Content:  params.Content,
+			TTL:      ttl,
+			Proxied:  &proxied,
+			Priority: params.Priority,
+		})
+		if err != nil {
+			return cloudflare.DNSRecord{}, fmt.Errorf("创建解析记录失败: %v", err)
 		}
-
-		return record, nil
 	}
 
-	// 不存在：创建
-	record, err := api.CreateDNSRecord(ctx, zoneID, cloudflare.CreateDNSRecordParams{
-		Type:    searchParams.Type,
-		Name:    recordName,
-		Content: params.Content,
-		TTL:     ttl,
-		Proxied: &proxied,
-	})
-	if err != nil {
-		return cloudflare.DNSRecord{}, fmt.Errorf("创建解析记录失败: %v", err)
+	// 仅 replace 模式会产生删除：清理其它同名同类型记录
+	for _, r := range plan.Delete {
+		if err := api.DeleteDNSRecord(ctx, zoneID, r.ID); err != nil {
+			return record, fmt.Errorf("已写入记录，但删除旧记录 %s %s → %s 失败: %v", r.Type, r.Name, r.Content, err)
+		}
 	}
 
 	return record, nil
@@ -712,7 +784,7 @@ func (c *apiClient) ListZones(ctx context.Context, account config.CF) ([]ZoneDet
 	return out, nil
 }
 
-func (c *apiClient) CreateOriginCertificate(ctx context.Context, account config.CF, hostnames []string) (OriginCert, error) {
+func (c *apiClient) CreateOriginCertificate(ctx context.Context, account config.CF, hostnames []string, opts OriginCertOptions) (OriginCert, error) {
 
 	ctx, cancel := ensureTimeout(ctx)
 	defer cancel()
@@ -721,6 +793,15 @@ func (c *apiClient) CreateOriginCertificate(ctx context.Context, account config.
 	if len(hostnames) == 0 {
 		return OriginCert{}, fmt.Errorf("hostnames 不能为空")
 	}
+	for _, h := range hostnames {
+		if err := ValidateOriginHostname(h); err != nil {
+			return OriginCert{}, err
+		}
+	}
+	opts, err := opts.Normalize()
+	if err != nil {
+		return OriginCert{}, err
+	}
 
 	api, err := cloudflare.NewWithAPIToken(account.APIToken)
 	if err != nil {
@@ -730,10 +811,10 @@ func (c *apiClient) CreateOriginCertificate(ctx context.Context, account config.
 		)
 	}
 
-	// 1. 生成 RSA 私钥
-	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	// 1. 生成私钥（RSA 2048 或 ECDSA P-256）
+	priv, keyPEM, err := generateOriginKey(opts.KeyType)
 	if err != nil {
-		return OriginCert{}, fmt.Errorf("生成私钥失败: %v", err)
+		return OriginCert{}, err
 	}
 
 	// 2. 生成 CSR（SAN = hostnames）
@@ -742,12 +823,12 @@ func (c *apiClient) CreateOriginCertificate(ctx context.Context, account config.
 		return OriginCert{}, err
 	}
 
-	// 3. 调用 CreateOriginCACertificate（⚠️ 用你这个 struct）
+	// 3. 调用 CreateOriginCACertificate
 	req := cloudflare.CreateOriginCertificateParams{
 		CSR:             csrPEM,
 		Hostnames:       hostnames,
-		RequestType:     "origin-rsa",
-		RequestValidity: 5475, // 15 年
+		RequestType:     opts.RequestType(),
+		RequestValidity: opts.ValidityDays,
 	}
 
 	cert, err := api.CreateOriginCACertificate(ctx, req)
@@ -755,12 +836,6 @@ func (c *apiClient) CreateOriginCertificate(ctx context.Context, account config.
 		return OriginCert{}, fmt.Errorf("创建 Origin CA 证书失败: %v", err)
 	}
 
-	// 4. 私钥 PEM
-	keyPEM, err := encodeRSAPrivateKeyPEM(priv)
-	if err != nil {
-		return OriginCert{}, err
-	}
-
 	return OriginCert{
 		ID:             cert.ID,
 		CertificatePEM: cert.Certificate,
@@ -789,31 +864,6 @@ func normalizeHostnames(in []string) []string {
 	return out
 }
 
-func buildCSRPEM(priv *rsa.PrivateKey, hostnames []string) (string, error) {
-	// CN 用第一个 hostname（只是展示用途，实际以 SAN 为准）
-	cn := hostnames[0]
-
-	tpl := &x509.CertificateRequest{
-		Subject: pkix.Name{
-			CommonName: cn,
-		},
-		DNSNames: hostnames,
-	}
-
-	der, err := x509.CreateCertificateRequest(rand.Reader, tpl, priv)
-	if err != nil {
-		return "", fmt.Errorf("生成 CSR 失败: %v", err)
-	}
-
-	block := &pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}
-	return string(pem.EncodeToMemory(block)), nil
-}
-
-func encodeRSAPrivateKeyPEM(priv *rsa.PrivateKey) (string, error) {
-	der := x509.MarshalPKCS1PrivateKey(priv)
-	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: der}
-	return string(pem.EncodeToMemory(block)), nil
-}
 func (c *apiClient) SetZoneSSLFullStrict(ctx context.Context, account config.CF, zoneID string) error {
 	ctx, cancel := ensureTimeout(ctx)
 	defer cancel()
@@ -843,6 +893,15 @@ func (c *apiClient) SetZoneSSLFullStrict(ctx context.Context, account config.CF,
 }
 
 func (c *apiClient) ListOriginCACertificates(ctx context.Context, account config.CF) ([]OriginCACertInfo, error) {
+	return c.listOriginCACertificates(ctx, account, "")
+}
+
+// ListZoneOriginCACertificates 列出指定 Zone 下签发的 Origin CA 证书（Cloudflare 接口要求按 zone 查询）
+func (c *apiClient) ListZoneOriginCACertificates(ctx context.Context, account config.CF, zoneID string) ([]OriginCACertInfo, error) {
+	return c.listOriginCACertificates(ctx, account, zoneID)
+}
+
+func (c *apiClient) listOriginCACertificates(ctx context.Context, account config.CF, zoneID string) ([]OriginCACertInfo, error) {
 	ctx, cancel := ensureTimeout(ctx)
 	defer cancel()
 
@@ -853,7 +912,7 @@ func (c *apiClient) ListOriginCACertificates(ctx context.Context, account config
 
 	// params 结构你本地 SDK 里一定有，不同版本字段略不同：
 	// 一般至少可空 struct 或带分页；不行就传 cloudflare.ListOriginCertificatesParams{}
-	certs, err := api.ListOriginCACertificates(ctx, cloudflare.ListOriginCertificatesParams{})
+	certs, err := api.ListOriginCACertificates(ctx, cloudflare.ListOriginCertificatesParams{ZoneID: zoneID})
 	if err != nil {
 		return nil, fmt.Errorf("列出 Origin CA 证书失败 [%s]: %v", account.Label, err)
 	}
@@ -866,15 +925,32 @@ func (c *apiClient) ListOriginCACertificates(ctx context.Context, account config
 			revoked = &t
 		}
 		out = append(out, OriginCACertInfo{
-			ID:          c.ID,
-			Hostnames:   c.Hostnames,
-			ExpiresOn:   c.ExpiresOn,
-			RevokedAt:   revoked,
-			RequestType: c.RequestType,
+			ID:             c.ID,
+			Hostnames:      c.Hostnames,
+			ExpiresOn:      c.ExpiresOn,
+			RevokedAt:      revoked,
+			RequestType:    c.RequestType,
+			RequestedDays:  c.RequestValidity,
+			CertificatePEM: c.Certificate,
 		})
 	}
 	return out, nil
 }
+
+// RevokeOriginCACertificate 吊销 Origin CA 证书，吊销后使用该证书的源站将无法通过 Full (Strict) 校验
+func (c *apiClient) RevokeOriginCACertificate(ctx context.Context, account config.CF, certID string) error {
+	ctx, cancel := ensureTimeout(ctx)
+	defer cancel()
+
+	api, err := cloudflare.NewWithAPIToken(account.APIToken)
+	if err != nil {
+		return fmt.Errorf("初始化 Cloudflare 客户端失败 [%s]: %v", account.Label, err)
+	}
+	if _, err := api.RevokeOriginCACertificate(ctx, certID); err != nil {
+		return fmt.Errorf("吊销 Origin CA 证书失败 [%s/%s]: %v", account.Label, certID, err)
+	}
+	return nil
+}
 func (c *apiClient) ListCustomLists(ctx context.Context, account config.CF) ([]cloudflare.List, error) {
 	ctx, cancel := ensureTimeout(ctx)
 	defer cancel()
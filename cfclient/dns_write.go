@@ -0,0 +1,149 @@
+package cfclient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// DNSWriteMode 决定 UpsertDNSRecord 遇到同名同类型记录时的处理方式
+type DNSWriteMode string
+
+const (
+	// DNSWriteUpdate 更新内容相同的记录；仅有一条同名记录时直接更新它；否则报歧义错误（默认）
+	DNSWriteUpdate DNSWriteMode = "update"
+	// DNSWriteAdd 追加一条新记录（内容相同则只更新 TTL/代理），适用于轮询 A、多条 MX/TXT
+	DNSWriteAdd DNSWriteMode = "add"
+	// DNSWriteReplace 用本条记录替换所有同名同类型记录（其它记录会被删除）
+	DNSWriteReplace DNSWriteMode = "replace"
+)
+
+// ErrAmbiguousDNSRecord 在 update 模式下存在多条同名记录且内容均不匹配时返回
+var ErrAmbiguousDNSRecord = errors.New("存在多条同名同类型记录且内容均不匹配，请使用 add 或 replace 模式")
+
+// ParseDNSWriteMode 解析用户输入的写入模式，空字符串视为默认 update
+func ParseDNSWriteMode(s string) (DNSWriteMode, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "update", "upd":
+		return DNSWriteUpdate, true
+	case "add", "append":
+		return DNSWriteAdd, true
+	case "replace", "replace-all", "replaceall":
+		return DNSWriteReplace, true
+	}
+	return "", false
+}
+
+// DNSWritePlan 描述一次写入需要执行的动作
+type DNSWritePlan struct {
+	Update *cloudflare.DNSRecord // 非空表示更新该记录
+	Create bool                  // 需要新建
+	Delete []cloudflare.DNSRecord
+}
+
+// singleValueTypes 同名只允许存在一条的记录类型
+var singleValueTypes = map[string]bool{
+	"CNAME": true,
+}
+
+// RecordFQDN 把 @ / www 等相对名称转换成 zone 下的完整域名
+func RecordFQDN(name, zone string) string {
+	return fqdn(name, zone)
+}
+
+// FilterSameNameType 从 zone 的全部记录中筛出与 params 同名同类型的记录
+func FilterSameNameType(records []cloudflare.DNSRecord, zone string, params DNSRecordParams) []cloudflare.DNSRecord {
+	name := fqdn(params.Name, zone)
+	typ := strings.ToUpper(strings.TrimSpace(params.Type))
+	out := make([]cloudflare.DNSRecord, 0)
+	for _, r := range records {
+		if !strings.EqualFold(r.Type, typ) {
+			continue
+		}
+		if !strings.EqualFold(strings.TrimSuffix(r.Name, "."), name) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// PlanDNSWrite 根据写入模式计算对已有同名同类型记录的操作，不会调用 API
+func PlanDNSWrite(existing []cloudflare.DNSRecord, params DNSRecordParams) (DNSWritePlan, error) {
+	mode := params.Mode
+	if mode == "" {
+		mode = DNSWriteUpdate
+	}
+	typ := strings.ToUpper(strings.TrimSpace(params.Type))
+
+	matchIdx := -1
+	for i, r := range existing {
+		if DNSContentEqual(typ, r.Content, params.Content) && priorityEqual(r.Priority, params.Priority) {
+			matchIdx = i
+			break
+		}
+	}
+
+	switch mode {
+	case DNSWriteReplace:
+		if len(existing) == 0 {
+			return DNSWritePlan{Create: true}, nil
+		}
+		keep := 0
+		if matchIdx >= 0 {
+			keep = matchIdx
+		}
+		plan := DNSWritePlan{Update: &existing[keep]}
+		for i := range existing {
+			if i != keep {
+				plan.Delete = append(plan.Delete, existing[i])
+			}
+		}
+		return plan, nil
+
+	case DNSWriteAdd:
+		if matchIdx >= 0 {
+			return DNSWritePlan{Update: &existing[matchIdx]}, nil
+		}
+		if singleValueTypes[typ] && len(existing) > 0 {
+			return DNSWritePlan{}, fmt.Errorf("%s 记录同名只能存在一条，请使用 update 或 replace 模式", typ)
+		}
+		return DNSWritePlan{Create: true}, nil
+
+	case DNSWriteUpdate:
+		if matchIdx >= 0 {
+			return DNSWritePlan{Update: &existing[matchIdx]}, nil
+		}
+		switch len(existing) {
+		case 0:
+			return DNSWritePlan{Create: true}, nil
+		case 1:
+			return DNSWritePlan{Update: &existing[0]}, nil
+		}
+		return DNSWritePlan{}, ErrAmbiguousDNSRecord
+	}
+
+	return DNSWritePlan{}, fmt.Errorf("未知写入模式: %s", mode)
+}
+
+// DNSContentEqual 比较记录内容：忽略首尾空白、末尾点；TXT 去引号后区分大小写，其余忽略大小写
+func DNSContentEqual(typ, a, b string) bool {
+	a = strings.TrimSpace(a)
+	b = strings.TrimSpace(b)
+	if strings.EqualFold(typ, "TXT") {
+		return strings.Trim(a, `"`) == strings.Trim(b, `"`)
+	}
+	a = strings.TrimSuffix(a, ".")
+	b = strings.TrimSuffix(b, ".")
+	return strings.EqualFold(a, b)
+}
+
+// priorityEqual 未指定优先级时视为匹配
+func priorityEqual(existing, want *uint16) bool {
+	if want == nil || existing == nil {
+		return true
+	}
+	return *existing == *want
+}
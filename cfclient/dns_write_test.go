@@ -0,0 +1,87 @@
+package cfclient
+
+import (
+	"errors"
+	"testing"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+func roundRobin() []cloudflare.DNSRecord {
+	return []cloudflare.DNSRecord{
+		{ID: "a1", Type: "A", Name: "example.com", Content: "192.0.2.1"},
+		{ID: "a2", Type: "A", Name: "example.com", Content: "192.0.2.2"},
+	}
+}
+
+func TestPlanDNSWriteUpdateKeepsOtherRecords(t *testing.T) {
+	plan, err := PlanDNSWrite(roundRobin(), DNSRecordParams{Type: "A", Name: "@", Content: "192.0.2.2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Update == nil || plan.Update.ID != "a2" {
+		t.Fatalf("expected matching record a2 to be updated, got %+v", plan.Update)
+	}
+	if len(plan.Delete) != 0 || plan.Create {
+		t.Fatalf("expected no delete/create, got %+v", plan)
+	}
+}
+
+func TestPlanDNSWriteUpdateAmbiguous(t *testing.T) {
+	_, err := PlanDNSWrite(roundRobin(), DNSRecordParams{Type: "A", Name: "@", Content: "192.0.2.9"})
+	if !errors.Is(err, ErrAmbiguousDNSRecord) {
+		t.Fatalf("expected ErrAmbiguousDNSRecord, got %v", err)
+	}
+}
+
+func TestPlanDNSWriteAddCreates(t *testing.T) {
+	plan, err := PlanDNSWrite(roundRobin(), DNSRecordParams{Type: "A", Name: "@", Content: "192.0.2.3", Mode: DNSWriteAdd})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !plan.Create || plan.Update != nil || len(plan.Delete) != 0 {
+		t.Fatalf("expected pure create, got %+v", plan)
+	}
+}
+
+func TestPlanDNSWriteAddRejectsSecondCNAME(t *testing.T) {
+	existing := []cloudflare.DNSRecord{{ID: "c1", Type: "CNAME", Name: "www.example.com", Content: "example.com"}}
+	if _, err := PlanDNSWrite(existing, DNSRecordParams{Type: "CNAME", Name: "www", Content: "other.com", Mode: DNSWriteAdd}); err == nil {
+		t.Fatalf("expected error for second CNAME")
+	}
+}
+
+func TestPlanDNSWriteReplaceDeletesOthers(t *testing.T) {
+	plan, err := PlanDNSWrite(roundRobin(), DNSRecordParams{Type: "A", Name: "@", Content: "192.0.2.2", Mode: DNSWriteReplace})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Update == nil || plan.Update.ID != "a2" {
+		t.Fatalf("expected matching record to be kept, got %+v", plan.Update)
+	}
+	if len(plan.Delete) != 1 || plan.Delete[0].ID != "a1" {
+		t.Fatalf("expected a1 to be deleted, got %+v", plan.Delete)
+	}
+}
+
+func TestPlanDNSWriteTXTMatchesQuoted(t *testing.T) {
+	existing := []cloudflare.DNSRecord{
+		{ID: "t1", Type: "TXT", Name: "example.com", Content: `"v=spf1 -all"`},
+		{ID: "t2", Type: "TXT", Name: "example.com", Content: "google-site-verification=abc"},
+	}
+	plan, err := PlanDNSWrite(existing, DNSRecordParams{Type: "TXT", Name: "@", Content: "v=spf1 -all"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Update == nil || plan.Update.ID != "t1" {
+		t.Fatalf("expected quoted TXT to match, got %+v", plan.Update)
+	}
+}
+
+func TestFilterSameNameType(t *testing.T) {
+	records := append(roundRobin(), cloudflare.DNSRecord{ID: "w", Type: "CNAME", Name: "www.example.com", Content: "example.com"})
+	got := FilterSameNameType(records, "example.com", DNSRecordParams{Type: "a", Name: "@"})
+	if len(got) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(got))
+	}
+}
@@ -0,0 +1,142 @@
+package cfclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"DomainC/config"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// DNSSEC 状态（与 Cloudflare API 返回值保持一致）
+const (
+	DNSSECActive          = "active"
+	DNSSECPending         = "pending"
+	DNSSECDisabled        = "disabled"
+	DNSSECPendingDisabled = "pending-disabled"
+)
+
+// DNSSECInfo 描述 zone 的 DNSSEC 状态以及需要提交到注册商的 DS 信息
+type DNSSECInfo struct {
+	Status     string
+	DS         string // 完整 DS 记录文本
+	KeyTag     int
+	Algorithm  int
+	DigestType int
+	Digest     string
+	Flags      int
+	PublicKey  string
+	ModifiedOn time.Time
+}
+
+// Enabled 表示 Cloudflare 侧已经开启（或正在开启）DNSSEC
+func (i DNSSECInfo) Enabled() bool {
+	return i.Status == DNSSECActive || i.Status == DNSSECPending
+}
+
+// HasDS 表示 Cloudflare 已生成可提交的 DS 信息
+func (i DNSSECInfo) HasDS() bool {
+	return i.KeyTag > 0 && i.Algorithm > 0 && i.DigestType > 0 && strings.TrimSpace(i.Digest) != ""
+}
+
+// GetDNSSEC 读取 zone 的 DNSSEC 状态
+func (c *apiClient) GetDNSSEC(ctx context.Context, account config.CF, domain string) (DNSSECInfo, error) {
+	ctx, cancel := ensureTimeout(ctx)
+	defer cancel()
+
+	api, zoneID, err := c.dnssecZone(ctx, account, domain)
+	if err != nil {
+		return DNSSECInfo{}, err
+	}
+	res, err := api.ZoneDNSSECSetting(ctx, zoneID)
+	if err != nil {
+		return DNSSECInfo{}, fmt.Errorf("获取 DNSSEC 状态失败 [%s/%s]: %v", account.Label, domain, err)
+	}
+	return dnssecInfoFromAPI(res), nil
+}
+
+// EnableDNSSEC 开启 zone 的 DNSSEC，返回的 DS 需要提交到注册商后才会生效
+func (c *apiClient) EnableDNSSEC(ctx context.Context, account config.CF, domain string) (DNSSECInfo, error) {
+	return c.updateDNSSEC(ctx, account, domain, DNSSECActive)
+}
+
+// DisableDNSSEC 关闭 zone 的 DNSSEC
+func (c *apiClient) DisableDNSSEC(ctx context.Context, account config.CF, domain string) (DNSSECInfo, error) {
+	return c.updateDNSSEC(ctx, account, domain, DNSSECDisabled)
+}
+
+func (c *apiClient) updateDNSSEC(ctx context.Context, account config.CF, domain, status string) (DNSSECInfo, error) {
+	ctx, cancel := ensureTimeout(ctx)
+	defer cancel()
+
+	api, zoneID, err := c.dnssecZone(ctx, account, domain)
+	if err != nil {
+		return DNSSECInfo{}, err
+	}
+	res, err := api.UpdateZoneDNSSEC(ctx, zoneID, cloudflare.ZoneDNSSECUpdateOptions{Status: status})
+	if err != nil {
+		return DNSSECInfo{}, fmt.Errorf("设置 DNSSEC 为 %s 失败 [%s/%s]: %v", status, account.Label, domain, err)
+	}
+	return dnssecInfoFromAPI(res), nil
+}
+
+func (c *apiClient) dnssecZone(ctx context.Context, account config.CF, domain string) (*cloudflare.API, string, error) {
+	api, err := cloudflare.NewWithAPIToken(account.APIToken)
+	if err != nil {
+		return nil, "", fmt.Errorf("初始化 Cloudflare 客户端失败 [%s]: %v", account.Label, err)
+	}
+	zone, err := c.GetZoneDetails(ctx, account, domain)
+	if err != nil {
+		return nil, "", err
+	}
+	return api, zone.ID, nil
+}
+
+func dnssecInfoFromAPI(res cloudflare.ZoneDNSSEC) DNSSECInfo {
+	info := DNSSECInfo{
+		Status:     res.Status,
+		DS:         strings.TrimSpace(res.DS),
+		KeyTag:     res.KeyTag,
+		Digest:     strings.ToUpper(strings.TrimSpace(res.Digest)),
+		Flags:      res.Flags,
+		PublicKey:  res.PublicKey,
+		ModifiedOn: res.ModifiedOn,
+	}
+	fmt.Sscanf(res.Algorithm, "%d", &info.Algorithm)
+	fmt.Sscanf(res.DigestType, "%d", &info.DigestType)
+
+	// 部分响应只返回完整 DS 文本，从中补齐缺失字段
+	if !info.HasDS() && info.DS != "" {
+		if ds, ok := parseDSText(info.DS); ok {
+			info.KeyTag, info.Algorithm, info.DigestType, info.Digest = ds.keyTag, ds.algorithm, ds.digestType, ds.digest
+		}
+	}
+	return info
+}
+
+type dsFields struct {
+	keyTag     int
+	algorithm  int
+	digestType int
+	digest     string
+}
+
+// parseDSText 解析形如 "example.com. 3600 IN DS 2371 13 2 ABCD..." 的 DS 文本
+func parseDSText(s string) (dsFields, bool) {
+	fields := strings.Fields(s)
+	for i, f := range fields {
+		if !strings.EqualFold(f, "DS") || len(fields) < i+5 {
+			continue
+		}
+		var ds dsFields
+		if _, err := fmt.Sscanf(strings.Join(fields[i+1:i+4], " "), "%d %d %d", &ds.keyTag, &ds.algorithm, &ds.digestType); err != nil {
+			return dsFields{}, false
+		}
+		ds.digest = strings.ToUpper(strings.Join(fields[i+4:], ""))
+		return ds, true
+	}
+	return dsFields{}, false
+}
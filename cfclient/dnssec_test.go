@@ -0,0 +1,29 @@
+package cfclient
+
+import (
+	"testing"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+func TestDNSSECInfoFromAPI(t *testing.T) {
+	info := dnssecInfoFromAPI(cloudflare.ZoneDNSSEC{
+		Status:     "active",
+		Algorithm:  "13",
+		DigestType: "2",
+		Digest:     "abcd",
+		KeyTag:     2371,
+	})
+	if !info.Enabled() || !info.HasDS() || info.Algorithm != 13 || info.DigestType != 2 || info.Digest != "ABCD" {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+
+	// 只有完整 DS 文本时从中补齐字段
+	info = dnssecInfoFromAPI(cloudflare.ZoneDNSSEC{
+		Status: "pending",
+		DS:     "example.com. 3600 IN DS 2371 13 2 ABCD",
+	})
+	if !info.HasDS() || info.KeyTag != 2371 || info.Algorithm != 13 || info.Digest != "ABCD" {
+		t.Fatalf("unexpected info from DS text: %+v", info)
+	}
+}
@@ -0,0 +1,141 @@
+package cfclient
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// Origin CA 私钥类型
+const (
+	OriginKeyRSA = "rsa" // RSA 2048，request_type=origin-rsa
+	OriginKeyECC = "ecc" // ECDSA P-256，request_type=origin-ecc
+)
+
+// DefaultOriginValidityDays 默认有效期（15 年）
+const DefaultOriginValidityDays = 5475
+
+// OriginValidityDays 是 Cloudflare 允许的有效期（天）
+var OriginValidityDays = []int{7, 30, 90, 365, 730, 1095, 5475}
+
+// OriginCertOptions 是签发源站证书的可选参数，零值表示 RSA + 15 年
+type OriginCertOptions struct {
+	KeyType      string
+	ValidityDays int
+}
+
+// Normalize 填充默认值并校验参数
+func (o OriginCertOptions) Normalize() (OriginCertOptions, error) {
+	switch strings.ToLower(strings.TrimSpace(o.KeyType)) {
+	case "", OriginKeyRSA, "origin-rsa":
+		o.KeyType = OriginKeyRSA
+	case OriginKeyECC, "ecdsa", "origin-ecc":
+		o.KeyType = OriginKeyECC
+	default:
+		return o, fmt.Errorf("不支持的私钥类型: %s（可选 rsa、ecc）", o.KeyType)
+	}
+	if o.ValidityDays == 0 {
+		o.ValidityDays = DefaultOriginValidityDays
+	}
+	for _, d := range OriginValidityDays {
+		if d == o.ValidityDays {
+			return o, nil
+		}
+	}
+	return o, fmt.Errorf("不支持的有效期: %d 天（可选 %s）", o.ValidityDays, strings.Trim(fmt.Sprint(OriginValidityDays), "[]"))
+}
+
+// RequestType 返回 Cloudflare 接口使用的 request_type
+func (o OriginCertOptions) RequestType() string {
+	if o.KeyType == OriginKeyECC {
+		return "origin-ecc"
+	}
+	return "origin-rsa"
+}
+
+// OriginCertOptionsFor 根据已有证书的参数构造选项，用于按原参数重新签发
+func OriginCertOptionsFor(requestType string, days int) OriginCertOptions {
+	opts, err := OriginCertOptions{KeyType: requestType, ValidityDays: days}.Normalize()
+	if err != nil {
+		return OriginCertOptions{KeyType: OriginKeyRSA, ValidityDays: DefaultOriginValidityDays}
+	}
+	return opts
+}
+
+// ValidateOriginHostname 校验源站证书主机名：通配符只能作为最左侧的完整标签，
+// 允许 *.a.example.com 这类多级通配。
+func ValidateOriginHostname(h string) error {
+	h = strings.TrimSpace(h)
+	if h == "" {
+		return fmt.Errorf("主机名不能为空")
+	}
+	labels := strings.Split(h, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("主机名不合法: %s", h)
+	}
+	for i, l := range labels {
+		if l == "" {
+			return fmt.Errorf("主机名不合法: %s", h)
+		}
+		if strings.Contains(l, "*") && (i != 0 || l != "*") {
+			return fmt.Errorf("通配符只能作为最左侧的完整标签: %s", h)
+		}
+	}
+	if labels[0] == "*" && len(labels) < 3 {
+		return fmt.Errorf("通配符不能直接用于顶级域: %s", h)
+	}
+	return nil
+}
+
+// generateOriginKey 生成私钥并返回 PEM（RSA 为 PKCS#1，ECDSA 为 SEC 1）
+func generateOriginKey(keyType string) (crypto.Signer, string, error) {
+	switch keyType {
+	case OriginKeyECC:
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, "", fmt.Errorf("生成私钥失败: %v", err)
+		}
+		der, err := x509.MarshalECPrivateKey(priv)
+		if err != nil {
+			return nil, "", fmt.Errorf("编码私钥失败: %v", err)
+		}
+		return priv, string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})), nil
+	default:
+		priv, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, "", fmt.Errorf("生成私钥失败: %v", err)
+		}
+		der := x509.MarshalPKCS1PrivateKey(priv)
+		return priv, string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: der})), nil
+	}
+}
+
+func buildCSRPEM(priv crypto.Signer, hostnames []string) (string, error) {
+	if len(hostnames) == 0 {
+		return "", fmt.Errorf("hostnames 不能为空")
+	}
+	// CN 用第一个 hostname（只是展示用途，实际以 SAN 为准）
+	cn := hostnames[0]
+
+	tpl := &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName: cn,
+		},
+		DNSNames: hostnames,
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, tpl, priv)
+	if err != nil {
+		return "", fmt.Errorf("生成 CSR 失败: %v", err)
+	}
+
+	block := &pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}
+	return string(pem.EncodeToMemory(block)), nil
+}
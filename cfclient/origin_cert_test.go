@@ -0,0 +1,106 @@
+package cfclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildCSRPEM(t *testing.T) {
+	hostnames := []string{"*.api.example.com", "example.net", "*.example.net"}
+	for _, keyType := range []string{OriginKeyRSA, OriginKeyECC} {
+		priv, keyPEM, err := generateOriginKey(keyType)
+		if err != nil {
+			t.Fatalf("%s: generate key: %v", keyType, err)
+		}
+		csrPEM, err := buildCSRPEM(priv, hostnames)
+		if err != nil {
+			t.Fatalf("%s: build csr: %v", keyType, err)
+		}
+		block, _ := pem.Decode([]byte(csrPEM))
+		if block == nil || block.Type != "CERTIFICATE REQUEST" {
+			t.Fatalf("%s: bad csr pem: %q", keyType, csrPEM)
+		}
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil {
+			t.Fatalf("%s: parse csr: %v", keyType, err)
+		}
+		if err := csr.CheckSignature(); err != nil {
+			t.Fatalf("%s: csr signature: %v", keyType, err)
+		}
+		if !reflect.DeepEqual(csr.DNSNames, hostnames) || csr.Subject.CommonName != hostnames[0] {
+			t.Fatalf("%s: unexpected names: %v cn=%s", keyType, csr.DNSNames, csr.Subject.CommonName)
+		}
+
+		keyBlock, _ := pem.Decode([]byte(keyPEM))
+		if keyBlock == nil {
+			t.Fatalf("%s: bad key pem", keyType)
+		}
+		switch keyType {
+		case OriginKeyRSA:
+			if _, ok := csr.PublicKey.(*rsa.PublicKey); !ok || keyBlock.Type != "RSA PRIVATE KEY" {
+				t.Fatalf("rsa: public key %T, key block %s", csr.PublicKey, keyBlock.Type)
+			}
+		case OriginKeyECC:
+			pub, ok := csr.PublicKey.(*ecdsa.PublicKey)
+			if !ok || pub.Curve.Params().Name != "P-256" || keyBlock.Type != "EC PRIVATE KEY" {
+				t.Fatalf("ecc: public key %T, key block %s", csr.PublicKey, keyBlock.Type)
+			}
+			if _, err := x509.ParseECPrivateKey(keyBlock.Bytes); err != nil {
+				t.Fatalf("ecc: parse key: %v", err)
+			}
+		}
+	}
+
+	if _, err := buildCSRPEM(nil, nil); err == nil {
+		t.Fatal("expected error for empty hostnames")
+	}
+}
+
+func TestOriginCertOptionsNormalize(t *testing.T) {
+	opts, err := OriginCertOptions{}.Normalize()
+	if err != nil || opts.KeyType != OriginKeyRSA || opts.ValidityDays != DefaultOriginValidityDays || opts.RequestType() != "origin-rsa" {
+		t.Fatalf("unexpected defaults: %+v %v", opts, err)
+	}
+
+	opts, err = OriginCertOptions{KeyType: "ECC", ValidityDays: 90}.Normalize()
+	if err != nil || opts.KeyType != OriginKeyECC || opts.ValidityDays != 90 || opts.RequestType() != "origin-ecc" {
+		t.Fatalf("unexpected ecc options: %+v %v", opts, err)
+	}
+
+	if _, err := (OriginCertOptions{ValidityDays: 100}).Normalize(); err == nil {
+		t.Fatal("expected error for unsupported validity")
+	}
+	if _, err := (OriginCertOptions{KeyType: "dsa"}).Normalize(); err == nil {
+		t.Fatal("expected error for unsupported key type")
+	}
+
+	// 按已有证书参数重新签发
+	if got := OriginCertOptionsFor("origin-ecc", 365); got.KeyType != OriginKeyECC || got.ValidityDays != 365 {
+		t.Fatalf("unexpected options from ecc cert: %+v", got)
+	}
+	if got := OriginCertOptionsFor("keyless-certificate", 12); got.KeyType != OriginKeyRSA || got.ValidityDays != DefaultOriginValidityDays {
+		t.Fatalf("unexpected fallback options: %+v", got)
+	}
+}
+
+func TestValidateOriginHostname(t *testing.T) {
+	for _, h := range []string{"example.com", "*.example.com", "*.api.example.com", "a.b.example.co.uk"} {
+		if err := ValidateOriginHostname(h); err != nil {
+			t.Fatalf("%s: unexpected error: %v", h, err)
+		}
+	}
+	for _, h := range []string{"", "com", "*.com", "a.*.example.com", "*a.example.com", "example..com"} {
+		err := ValidateOriginHostname(h)
+		if err == nil {
+			t.Fatalf("%s: expected error", h)
+		}
+		if !strings.Contains(err.Error(), "主机名") && !strings.Contains(err.Error(), "通配符") {
+			t.Fatalf("%s: unexpected error text: %v", h, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package cfclient
+
+import (
+	"context"
+	"fmt"
+
+	"DomainC/config"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// PurgeBatchSize 是单次清理缓存请求允许的最大条目数
+const PurgeBatchSize = 30
+
+// 按条目清理缓存的类型
+const (
+	PurgeFiles    = "files"    // 完整 URL
+	PurgeHosts    = "hosts"    // 主机名
+	PurgePrefixes = "prefixes" // 主机名 + 路径前缀，不含协议
+	PurgeTags     = "tags"     // Cache-Tag
+)
+
+// PurgeCacheFiles 按 URL 清理缓存，返回已提交的条目数
+func (c *apiClient) PurgeCacheFiles(ctx context.Context, account config.CF, zoneID string, urls []string) (int, error) {
+	return c.purgeCache(ctx, account, zoneID, PurgeFiles, urls)
+}
+
+// PurgeCacheHosts 按主机名清理缓存
+func (c *apiClient) PurgeCacheHosts(ctx context.Context, account config.CF, zoneID string, hosts []string) (int, error) {
+	return c.purgeCache(ctx, account, zoneID, PurgeHosts, hosts)
+}
+
+// PurgeCachePrefixes 按路径前缀清理缓存
+func (c *apiClient) PurgeCachePrefixes(ctx context.Context, account config.CF, zoneID string, prefixes []string) (int, error) {
+	return c.purgeCache(ctx, account, zoneID, PurgePrefixes, prefixes)
+}
+
+// PurgeCacheTags 按 Cache-Tag 清理缓存
+func (c *apiClient) PurgeCacheTags(ctx context.Context, account config.CF, zoneID string, tags []string) (int, error) {
+	return c.purgeCache(ctx, account, zoneID, PurgeTags, tags)
+}
+
+// purgeCache 按 PurgeBatchSize 分批提交；某批失败时停止，返回此前已提交的条目数
+func (c *apiClient) purgeCache(ctx context.Context, account config.CF, zoneID, kind string, items []string) (int, error) {
+	ctx, cancel := ensureTimeout(ctx)
+	defer cancel()
+
+	api, err := cloudflare.NewWithAPIToken(account.APIToken)
+	if err != nil {
+		return 0, fmt.Errorf("初始化 Cloudflare 客户端失败 [%s]: %v", account.Label, err)
+	}
+	reqs, err := purgeRequests(kind, items)
+	if err != nil {
+		return 0, err
+	}
+	done := 0
+	for i, req := range reqs {
+		if _, err := api.PurgeCache(ctx, zoneID, req); err != nil {
+			return done, fmt.Errorf("清理缓存失败 [%s/%s] 第 %d/%d 批: %v", account.Label, zoneID, i+1, len(reqs), err)
+		}
+		done += len(req.Files) + len(req.Hosts) + len(req.Prefixes) + len(req.Tags)
+	}
+	return done, nil
+}
+
+func purgeRequests(kind string, items []string) ([]cloudflare.PurgeCacheRequest, error) {
+	var reqs []cloudflare.PurgeCacheRequest
+	for start := 0; start < len(items); start += PurgeBatchSize {
+		batch := items[start:min(start+PurgeBatchSize, len(items))]
+		var req cloudflare.PurgeCacheRequest
+		switch kind {
+		case PurgeFiles:
+			req.Files = batch
+		case PurgeHosts:
+			req.Hosts = batch
+		case PurgePrefixes:
+			req.Prefixes = batch
+		case PurgeTags:
+			req.Tags = batch
+		default:
+			return nil, fmt.Errorf("不支持的清理类型: %s", kind)
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
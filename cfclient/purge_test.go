@@ -0,0 +1,34 @@
+package cfclient
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPurgeRequestsBatches(t *testing.T) {
+	var urls []string
+	for i := 0; i < 65; i++ {
+		urls = append(urls, fmt.Sprintf("https://example.com/%d.css", i))
+	}
+	reqs, err := purgeRequests(PurgeFiles, urls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 3 || len(reqs[0].Files) != 30 || len(reqs[1].Files) != 30 || len(reqs[2].Files) != 5 {
+		t.Fatalf("unexpected batches: %d", len(reqs))
+	}
+	if reqs[2].Files[4] != urls[64] || reqs[0].Everything || len(reqs[0].Hosts) != 0 {
+		t.Fatalf("unexpected request: %+v", reqs[2])
+	}
+
+	reqs, err = purgeRequests(PurgeTags, []string{"a", "b"})
+	if err != nil || len(reqs) != 1 || len(reqs[0].Tags) != 2 {
+		t.Fatalf("tags: %+v %v", reqs, err)
+	}
+	if reqs, _ := purgeRequests(PurgeHosts, nil); len(reqs) != 0 {
+		t.Fatalf("empty items should produce no request: %+v", reqs)
+	}
+	if _, err := purgeRequests("everything", []string{"x"}); err == nil {
+		t.Fatal("expected error for unknown kind")
+	}
+}
@@ -0,0 +1,539 @@
+package cfclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"DomainC/config"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+const (
+	// redirectRuleRef 标记本工具管理的 Single Redirect 规则（每个 zone 一条）
+	redirectRuleRef = "domainc_redirect"
+	// bulkRedirectRefPrefix + 列表名 标记引用 Bulk Redirect 列表的账号级规则
+	bulkRedirectRefPrefix = "domainc_bulk_"
+
+	// DefaultBulkRedirectList 未配置时使用的 Bulk Redirect 列表名
+	DefaultBulkRedirectList = "domainc_redirects"
+	// DefaultRedirectPlaceholderIP 占位解析记录的地址（RFC 5737 文档地址，只用于让请求进入 Cloudflare）
+	DefaultRedirectPlaceholderIP = "192.0.2.1"
+)
+
+// RedirectStatusCodes 是允许的重定向状态码
+var RedirectStatusCodes = []int{301, 302, 307, 308}
+
+// RedirectRule 是 zone 上的一条 Single Redirect（Rulesets API，http_request_dynamic_redirect 阶段）
+type RedirectRule struct {
+	Hosts         []string // 匹配的主机名，如 example.com、www.example.com
+	Target        string   // 目标地址，如 https://main.com
+	StatusCode    int      // 默认 301
+	PreservePath  bool     // 把请求路径拼接到目标地址后
+	PreserveQuery bool     // 保留查询参数
+	Enabled       bool
+}
+
+var targetConcat = regexp.MustCompile(`^concat\("([^"]+)",\s*http\.request\.uri\.path\)$`)
+
+// NormalizeRedirectTarget 校验目标地址：必须是 http(s) 绝对地址；保留路径时去掉结尾的 /
+func NormalizeRedirectTarget(target string, preservePath bool) (string, error) {
+	target = strings.TrimSpace(target)
+	if !strings.Contains(target, "://") {
+		target = "https://" + target
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Hostname() == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("目标地址不合法: %s", target)
+	}
+	if strings.ContainsAny(target, `"\`) {
+		return "", fmt.Errorf("目标地址不能包含引号或反斜杠: %s", target)
+	}
+	if preservePath {
+		if u.RawQuery != "" || u.Fragment != "" {
+			return "", fmt.Errorf("保留路径时目标地址不能带查询参数: %s", target)
+		}
+		target = strings.TrimSuffix(target, "/")
+	}
+	return target, nil
+}
+
+// RedirectSourceHosts 从匹配列表中去掉目标地址自身的主机名，避免跳转后再次命中规则形成循环
+func RedirectSourceHosts(hosts []string, target string) []string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return hosts
+	}
+	dest := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	out := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		if strings.TrimSuffix(strings.ToLower(h), ".") != dest {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// ValidRedirectStatus 判断状态码是否可用于重定向
+func ValidRedirectStatus(code int) bool {
+	for _, c := range RedirectStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildRedirectRule 把 RedirectRule 转换为 Rulesets API 规则
+func BuildRedirectRule(r RedirectRule) (cloudflare.RulesetRule, error) {
+	if len(r.Hosts) == 0 {
+		return cloudflare.RulesetRule{}, errors.New("缺少要重定向的主机名")
+	}
+	status := r.StatusCode
+	if status == 0 {
+		status = 301
+	}
+	if !ValidRedirectStatus(status) {
+		return cloudflare.RulesetRule{}, fmt.Errorf("状态码必须是 301/302/307/308: %d", status)
+	}
+	target, err := NormalizeRedirectTarget(r.Target, r.PreservePath)
+	if err != nil {
+		return cloudflare.RulesetRule{}, err
+	}
+	if len(RedirectSourceHosts(r.Hosts, target)) != len(r.Hosts) {
+		return cloudflare.RulesetRule{}, fmt.Errorf("目标地址 %s 的主机名也在匹配列表中，会造成重定向循环", target)
+	}
+
+	quoted := make([]string, 0, len(r.Hosts))
+	for _, h := range r.Hosts {
+		quoted = append(quoted, fmt.Sprintf("%q", strings.ToLower(strings.TrimSuffix(h, "."))))
+	}
+	from := cloudflare.RulesetRuleActionParametersFromValue{
+		StatusCode:          uint16(status),
+		PreserveQueryString: cloudflare.BoolPtr(r.PreserveQuery),
+	}
+	if r.PreservePath {
+		from.TargetURL.Expression = fmt.Sprintf("concat(%q, http.request.uri.path)", target)
+	} else {
+		from.TargetURL.Value = target
+	}
+	return cloudflare.RulesetRule{
+		Ref:              redirectRuleRef,
+		Description:      "DomainC redirect → " + target,
+		Expression:       fmt.Sprintf("(http.host in {%s})", strings.Join(quoted, " ")),
+		Action:           "redirect",
+		ActionParameters: &cloudflare.RulesetRuleActionParameters{FromValue: &from},
+		Enabled:          cloudflare.BoolPtr(true),
+	}, nil
+}
+
+var hostsExpression = regexp.MustCompile(`"([^"]+)"`)
+
+func redirectRuleFromAPI(rule cloudflare.RulesetRule) RedirectRule {
+	r := RedirectRule{Enabled: rule.Enabled == nil || *rule.Enabled}
+	for _, m := range hostsExpression.FindAllStringSubmatch(rule.Expression, -1) {
+		r.Hosts = append(r.Hosts, m[1])
+	}
+	if rule.ActionParameters != nil && rule.ActionParameters.FromValue != nil {
+		from := rule.ActionParameters.FromValue
+		r.StatusCode = int(from.StatusCode)
+		r.PreserveQuery = from.PreserveQueryString != nil && *from.PreserveQueryString
+		r.Target = from.TargetURL.Value
+		if m := targetConcat.FindStringSubmatch(from.TargetURL.Expression); m != nil {
+			r.Target, r.PreservePath = m[1], true
+		} else if r.Target == "" {
+			r.Target = from.TargetURL.Expression
+		}
+	}
+	return r
+}
+
+// replaceRuleByRef 用 rule 替换同 Ref 的规则，不存在时追加；返回是否替换了已有规则。
+// 其它规则原样保留（去掉只读字段以便整体提交）。
+func replaceRuleByRef(rules []cloudflare.RulesetRule, rule cloudflare.RulesetRule) ([]cloudflare.RulesetRule, bool) {
+	out := make([]cloudflare.RulesetRule, 0, len(rules)+1)
+	replaced := false
+	for _, r := range rules {
+		r.Version, r.LastUpdated = nil, nil
+		if r.Ref == rule.Ref {
+			if !replaced {
+				out = append(out, rule)
+				replaced = true
+			}
+			continue
+		}
+		out = append(out, r)
+	}
+	if !replaced {
+		out = append(out, rule)
+	}
+	return out, replaced
+}
+
+// removeRuleByRef 删除同 Ref 的规则，返回是否有规则被删除
+func removeRuleByRef(rules []cloudflare.RulesetRule, ref string) ([]cloudflare.RulesetRule, bool) {
+	out := make([]cloudflare.RulesetRule, 0, len(rules))
+	removed := false
+	for _, r := range rules {
+		if r.Ref == ref {
+			removed = true
+			continue
+		}
+		r.Version, r.LastUpdated = nil, nil
+		out = append(out, r)
+	}
+	return out, removed
+}
+
+// phaseRules 读取入口规则集，阶段尚未创建规则集时返回空
+func phaseRules(ctx context.Context, api *cloudflare.API, rc *cloudflare.ResourceContainer, phase cloudflare.RulesetPhase) ([]cloudflare.RulesetRule, error) {
+	rs, err := api.GetEntrypointRuleset(ctx, rc, string(phase))
+	if err != nil {
+		var nf *cloudflare.NotFoundError
+		if errors.As(err, &nf) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rs.Rules, nil
+}
+
+func updatePhaseRules(ctx context.Context, api *cloudflare.API, rc *cloudflare.ResourceContainer, phase cloudflare.RulesetPhase, rules []cloudflare.RulesetRule) error {
+	_, err := api.UpdateEntrypointRuleset(ctx, rc, cloudflare.UpdateEntrypointRulesetParams{Phase: string(phase), Rules: rules})
+	return err
+}
+
+// GetRedirectRule 返回 zone 上由本工具管理的重定向规则，不存在时返回 nil
+func (c *apiClient) GetRedirectRule(ctx context.Context, account config.CF, zoneID string) (*RedirectRule, error) {
+	ctx, cancel := ensureTimeout(ctx)
+	defer cancel()
+
+	api, err := cloudflare.NewWithAPIToken(account.APIToken)
+	if err != nil {
+		return nil, fmt.Errorf("初始化 Cloudflare 客户端失败 [%s]: %v", account.Label, err)
+	}
+	rules, err := phaseRules(ctx, api, cloudflare.ZoneIdentifier(zoneID), cloudflare.RulesetPhaseHTTPRequestDynamicRedirect)
+	if err != nil {
+		return nil, fmt.Errorf("读取重定向规则失败 [%s/%s]: %v", account.Label, zoneID, err)
+	}
+	for _, rule := range rules {
+		if rule.Ref == redirectRuleRef {
+			r := redirectRuleFromAPI(rule)
+			return &r, nil
+		}
+	}
+	return nil, nil
+}
+
+// UpsertRedirectRule 创建或替换 zone 上由本工具管理的重定向规则，zone 上的其它重定向规则保持不变；
+// 返回是否替换了已有规则
+func (c *apiClient) UpsertRedirectRule(ctx context.Context, account config.CF, zoneID string, r RedirectRule) (bool, error) {
+	ctx, cancel := ensureTimeout(ctx)
+	defer cancel()
+
+	rule, err := BuildRedirectRule(r)
+	if err != nil {
+		return false, err
+	}
+	api, err := cloudflare.NewWithAPIToken(account.APIToken)
+	if err != nil {
+		return false, fmt.Errorf("初始化 Cloudflare 客户端失败 [%s]: %v", account.Label, err)
+	}
+	rc := cloudflare.ZoneIdentifier(zoneID)
+	rules, err := phaseRules(ctx, api, rc, cloudflare.RulesetPhaseHTTPRequestDynamicRedirect)
+	if err != nil {
+		return false, fmt.Errorf("读取重定向规则失败 [%s/%s]: %v", account.Label, zoneID, err)
+	}
+	merged, replaced := replaceRuleByRef(rules, rule)
+	if err := updatePhaseRules(ctx, api, rc, cloudflare.RulesetPhaseHTTPRequestDynamicRedirect, merged); err != nil {
+		return false, fmt.Errorf("写入重定向规则失败 [%s/%s]: %v", account.Label, zoneID, err)
+	}
+	return replaced, nil
+}
+
+// DeleteRedirectRule 删除 zone 上由本工具管理的重定向规则，返回是否存在
+func (c *apiClient) DeleteRedirectRule(ctx context.Context, account config.CF, zoneID string) (bool, error) {
+	ctx, cancel := ensureTimeout(ctx)
+	defer cancel()
+
+	api, err := cloudflare.NewWithAPIToken(account.APIToken)
+	if err != nil {
+		return false, fmt.Errorf("初始化 Cloudflare 客户端失败 [%s]: %v", account.Label, err)
+	}
+	rc := cloudflare.ZoneIdentifier(zoneID)
+	rules, err := phaseRules(ctx, api, rc, cloudflare.RulesetPhaseHTTPRequestDynamicRedirect)
+	if err != nil {
+		return false, fmt.Errorf("读取重定向规则失败 [%s/%s]: %v", account.Label, zoneID, err)
+	}
+	rest, removed := removeRuleByRef(rules, redirectRuleRef)
+	if !removed {
+		return false, nil
+	}
+	if err := updatePhaseRules(ctx, api, rc, cloudflare.RulesetPhaseHTTPRequestDynamicRedirect, rest); err != nil {
+		return false, fmt.Errorf("删除重定向规则失败 [%s/%s]: %v", account.Label, zoneID, err)
+	}
+	return true, nil
+}
+
+// BulkRedirect 是账号级 Bulk Redirect 列表中的一项。整域跳转时匹配子路径与子域名。
+type BulkRedirect struct {
+	ID                string
+	Source            string // 如 typo-example.com/
+	Target            string
+	StatusCode        int
+	PreservePath      bool
+	PreserveQuery     bool
+	IncludeSubdomains bool
+}
+
+// NormalizeRedirectSource 去掉协议并保证以 / 结尾的主机名形式（typo.com → typo.com/）
+func NormalizeRedirectSource(source string) (string, error) {
+	s := strings.ToLower(strings.TrimSpace(source))
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+	}
+	host, path, _ := strings.Cut(s, "/")
+	host = strings.TrimSuffix(host, ".")
+	if host == "" || !strings.Contains(host, ".") {
+		return "", fmt.Errorf("来源地址不合法: %s", source)
+	}
+	return host + "/" + path, nil
+}
+
+func bulkRedirectItem(b BulkRedirect) (cloudflare.ListItemCreateRequest, error) {
+	source, err := NormalizeRedirectSource(b.Source)
+	if err != nil {
+		return cloudflare.ListItemCreateRequest{}, err
+	}
+	target, err := NormalizeRedirectTarget(b.Target, false)
+	if err != nil {
+		return cloudflare.ListItemCreateRequest{}, err
+	}
+	status := b.StatusCode
+	if status == 0 {
+		status = 301
+	}
+	if !ValidRedirectStatus(status) {
+		return cloudflare.ListItemCreateRequest{}, fmt.Errorf("状态码必须是 301/302/307/308: %d", status)
+	}
+	return cloudflare.ListItemCreateRequest{
+		Redirect: &cloudflare.Redirect{
+			SourceUrl:           source,
+			TargetUrl:           target,
+			StatusCode:          &status,
+			IncludeSubdomains:   cloudflare.BoolPtr(b.IncludeSubdomains),
+			SubpathMatching:     cloudflare.BoolPtr(true),
+			PreservePathSuffix:  cloudflare.BoolPtr(b.PreservePath),
+			PreserveQueryString: cloudflare.BoolPtr(b.PreserveQuery),
+		},
+		Comment: "DomainC",
+	}, nil
+}
+
+func bulkRedirectFromItem(item cloudflare.ListItem) BulkRedirect {
+	r := item.Redirect
+	b := BulkRedirect{ID: item.ID, Source: r.SourceUrl, Target: r.TargetUrl, StatusCode: 301}
+	if r.StatusCode != nil {
+		b.StatusCode = *r.StatusCode
+	}
+	b.PreservePath = r.PreservePathSuffix != nil && *r.PreservePathSuffix
+	b.PreserveQuery = r.PreserveQueryString != nil && *r.PreserveQueryString
+	b.IncludeSubdomains = r.IncludeSubdomains != nil && *r.IncludeSubdomains
+	return b
+}
+
+// bulkRedirectRule 是把列表挂到账号 http_request_redirect 阶段的规则
+func bulkRedirectRule(listName string) cloudflare.RulesetRule {
+	return cloudflare.RulesetRule{
+		Ref:         bulkRedirectRefPrefix + listName,
+		Description: "DomainC bulk redirects (" + listName + ")",
+		Expression:  "http.request.full_uri in $" + listName,
+		Action:      "redirect",
+		ActionParameters: &cloudflare.RulesetRuleActionParameters{
+			FromList: &cloudflare.RulesetRuleActionParametersFromList{Name: listName, Key: "http.request.full_uri"},
+		},
+		Enabled: cloudflare.BoolPtr(true),
+	}
+}
+
+func (c *apiClient) findRedirectList(ctx context.Context, api *cloudflare.API, rc *cloudflare.ResourceContainer, name string) (*cloudflare.List, error) {
+	lists, err := api.ListLists(ctx, rc, cloudflare.ListListsParams{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range lists {
+		if lists[i].Kind == cloudflare.ListTypeRedirect && lists[i].Name == name {
+			return &lists[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// ListBulkRedirects 列出 Bulk Redirect 列表中的条目，列表不存在时返回空
+func (c *apiClient) ListBulkRedirects(ctx context.Context, account config.CF, listName string) ([]BulkRedirect, error) {
+	ctx, cancel := ensureTimeout(ctx)
+	defer cancel()
+
+	api, err := cloudflare.NewWithAPIToken(account.APIToken)
+	if err != nil {
+		return nil, fmt.Errorf("初始化 Cloudflare 客户端失败 [%s]: %v", account.Label, err)
+	}
+	accountID, err := c.GetAccountID(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	rc := cloudflare.AccountIdentifier(accountID)
+	list, err := c.findRedirectList(ctx, api, rc, listName)
+	if err != nil {
+		return nil, fmt.Errorf("读取重定向列表失败 [%s]: %v", account.Label, err)
+	}
+	if list == nil {
+		return nil, nil
+	}
+	items, err := api.ListListItems(ctx, rc, cloudflare.ListListItemsParams{ID: list.ID})
+	if err != nil {
+		return nil, fmt.Errorf("读取重定向列表 %s 失败 [%s]: %v", listName, account.Label, err)
+	}
+	out := make([]BulkRedirect, 0, len(items))
+	for _, item := range items {
+		if item.Redirect != nil {
+			out = append(out, bulkRedirectFromItem(item))
+		}
+	}
+	return out, nil
+}
+
+// AddBulkRedirects 向 Bulk Redirect 列表添加条目；列表或账号级重定向规则不存在时自动创建
+func (c *apiClient) AddBulkRedirects(ctx context.Context, account config.CF, listName string, redirects []BulkRedirect) error {
+	ctx, cancel := ensureTimeout(ctx)
+	defer cancel()
+
+	reqs := make([]cloudflare.ListItemCreateRequest, 0, len(redirects))
+	for _, b := range redirects {
+		req, err := bulkRedirectItem(b)
+		if err != nil {
+			return err
+		}
+		reqs = append(reqs, req)
+	}
+	api, err := cloudflare.NewWithAPIToken(account.APIToken)
+	if err != nil {
+		return fmt.Errorf("初始化 Cloudflare 客户端失败 [%s]: %v", account.Label, err)
+	}
+	accountID, err := c.GetAccountID(ctx, account)
+	if err != nil {
+		return err
+	}
+	rc := cloudflare.AccountIdentifier(accountID)
+	list, err := c.findRedirectList(ctx, api, rc, listName)
+	if err != nil {
+		return fmt.Errorf("读取重定向列表失败 [%s]: %v", account.Label, err)
+	}
+	if list == nil {
+		created, err := api.CreateList(ctx, rc, cloudflare.ListCreateParams{Name: listName, Kind: cloudflare.ListTypeRedirect, Description: "Managed by DomainC"})
+		if err != nil {
+			return fmt.Errorf("创建重定向列表 %s 失败 [%s]: %v", listName, account.Label, err)
+		}
+		list = &created
+	}
+	if _, err := api.CreateListItems(ctx, rc, cloudflare.ListCreateItemsParams{ID: list.ID, Items: reqs}); err != nil {
+		return fmt.Errorf("写入重定向列表 %s 失败 [%s]: %v", listName, account.Label, err)
+	}
+
+	rules, err := phaseRules(ctx, api, rc, cloudflare.RulesetPhaseHTTPRequestRedirect)
+	if err != nil {
+		return fmt.Errorf("读取账号重定向规则失败 [%s]: %v", account.Label, err)
+	}
+	for _, r := range rules {
+		if r.Ref == bulkRedirectRefPrefix+listName {
+			return nil
+		}
+	}
+	merged, _ := replaceRuleByRef(rules, bulkRedirectRule(listName))
+	if err := updatePhaseRules(ctx, api, rc, cloudflare.RulesetPhaseHTTPRequestRedirect, merged); err != nil {
+		return fmt.Errorf("启用重定向列表 %s 失败 [%s]: %v", listName, account.Label, err)
+	}
+	return nil
+}
+
+// DeleteBulkRedirects 按来源地址删除 Bulk Redirect 条目，返回删除数量
+func (c *apiClient) DeleteBulkRedirects(ctx context.Context, account config.CF, listName string, sources []string) (int, error) {
+	ctx, cancel := ensureTimeout(ctx)
+	defer cancel()
+
+	want := map[string]bool{}
+	for _, s := range sources {
+		n, err := NormalizeRedirectSource(s)
+		if err != nil {
+			return 0, err
+		}
+		want[n] = true
+	}
+	api, err := cloudflare.NewWithAPIToken(account.APIToken)
+	if err != nil {
+		return 0, fmt.Errorf("初始化 Cloudflare 客户端失败 [%s]: %v", account.Label, err)
+	}
+	accountID, err := c.GetAccountID(ctx, account)
+	if err != nil {
+		return 0, err
+	}
+	rc := cloudflare.AccountIdentifier(accountID)
+	list, err := c.findRedirectList(ctx, api, rc, listName)
+	if err != nil {
+		return 0, fmt.Errorf("读取重定向列表失败 [%s]: %v", account.Label, err)
+	}
+	if list == nil {
+		return 0, nil
+	}
+	items, err := api.ListListItems(ctx, rc, cloudflare.ListListItemsParams{ID: list.ID})
+	if err != nil {
+		return 0, fmt.Errorf("读取重定向列表 %s 失败 [%s]: %v", listName, account.Label, err)
+	}
+	var del []cloudflare.ListItemDeleteItemRequest
+	for _, item := range items {
+		if item.Redirect == nil {
+			continue
+		}
+		if n, err := NormalizeRedirectSource(item.Redirect.SourceUrl); err == nil && want[n] {
+			del = append(del, cloudflare.ListItemDeleteItemRequest{ID: item.ID})
+		}
+	}
+	if len(del) == 0 {
+		return 0, nil
+	}
+	if _, err := api.DeleteListItems(ctx, rc, cloudflare.ListDeleteItemsParams{ID: list.ID, Items: cloudflare.ListItemDeleteRequest{Items: del}}); err != nil {
+		return 0, fmt.Errorf("删除重定向条目失败 [%s]: %v", account.Label, err)
+	}
+	return len(del), nil
+}
+
+// RedirectPlaceholder 描述主机名的解析是否能让请求进入 Cloudflare 以执行重定向
+type RedirectPlaceholder int
+
+const (
+	PlaceholderMissing   RedirectPlaceholder = iota // 没有 A/AAAA/CNAME，需要创建占位记录
+	PlaceholderProxied                              // 已有代理记录
+	PlaceholderUnproxied                            // 有记录但未开启代理，重定向不会生效
+)
+
+// CheckRedirectPlaceholder 检查 name（完整域名）在 records 中的 A/AAAA/CNAME 记录
+func CheckRedirectPlaceholder(records []cloudflare.DNSRecord, name string) RedirectPlaceholder {
+	status := PlaceholderMissing
+	for _, r := range records {
+		if !strings.EqualFold(strings.TrimSuffix(r.Name, "."), name) {
+			continue
+		}
+		switch strings.ToUpper(r.Type) {
+		case "A", "AAAA", "CNAME":
+			if r.Proxied != nil && *r.Proxied {
+				return PlaceholderProxied
+			}
+			status = PlaceholderUnproxied
+		}
+	}
+	return status
+}
@@ -0,0 +1,129 @@
+package cfclient
+
+import (
+	"testing"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+func TestBuildRedirectRule(t *testing.T) {
+	rule, err := BuildRedirectRule(RedirectRule{Hosts: []string{"Typo.com", "www.typo.com."}, Target: "main.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := rule.ActionParameters.FromValue
+	if rule.Expression != `(http.host in {"typo.com" "www.typo.com"})` || rule.Action != "redirect" || rule.Ref != redirectRuleRef {
+		t.Fatalf("unexpected rule: %+v", rule)
+	}
+	if from.StatusCode != 301 || from.TargetURL.Value != "https://main.com" || from.TargetURL.Expression != "" || *from.PreserveQueryString {
+		t.Fatalf("unexpected action: %+v", from)
+	}
+
+	rule, err = BuildRedirectRule(RedirectRule{Hosts: []string{"typo.com"}, Target: "https://main.com/", StatusCode: 302, PreservePath: true, PreserveQuery: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	from = rule.ActionParameters.FromValue
+	if from.StatusCode != 302 || from.TargetURL.Expression != `concat("https://main.com", http.request.uri.path)` || !*from.PreserveQueryString {
+		t.Fatalf("unexpected preserve-path action: %+v", from)
+	}
+
+	// 解析回 RedirectRule
+	back := redirectRuleFromAPI(rule)
+	if back.Target != "https://main.com" || !back.PreservePath || !back.PreserveQuery || back.StatusCode != 302 || len(back.Hosts) != 1 || back.Hosts[0] != "typo.com" || !back.Enabled {
+		t.Fatalf("round trip: %+v", back)
+	}
+
+	for _, bad := range []RedirectRule{
+		{Target: "https://main.com"},
+		{Hosts: []string{"a.com"}, Target: "ftp://main.com"},
+		{Hosts: []string{"a.com"}, Target: "https://main.com", StatusCode: 200},
+		{Hosts: []string{"a.com"}, Target: `https://main.com/"x`},
+		{Hosts: []string{"a.com"}, Target: "https://main.com/?a=1", PreservePath: true},
+		{Hosts: []string{"a.com", "www.a.com"}, Target: "https://WWW.a.com/"},
+	} {
+		if _, err := BuildRedirectRule(bad); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestRedirectSourceHostsAvoidsLoop(t *testing.T) {
+	hosts := []string{"example.com", "www.example.com"}
+	if got := RedirectSourceHosts(hosts, "https://www.example.com"); len(got) != 1 || got[0] != "example.com" {
+		t.Fatalf("apex → www: %v", got)
+	}
+	if got := RedirectSourceHosts(hosts, "https://Example.com/landing"); len(got) != 1 || got[0] != "www.example.com" {
+		t.Fatalf("www → apex: %v", got)
+	}
+	if got := RedirectSourceHosts(hosts, "https://main.com"); len(got) != 2 {
+		t.Fatalf("other target must keep both hosts: %v", got)
+	}
+	rule, err := BuildRedirectRule(RedirectRule{Hosts: RedirectSourceHosts(hosts, "https://www.example.com"), Target: "https://www.example.com"})
+	if err != nil || rule.Expression != `(http.host in {"example.com"})` {
+		t.Fatalf("unexpected rule: %+v %v", rule, err)
+	}
+}
+
+func TestReplaceAndRemoveRuleByRef(t *testing.T) {
+	v := "3"
+	existing := []cloudflare.RulesetRule{
+		{ID: "1", Ref: "other", Version: &v, Expression: "true"},
+		{ID: "2", Ref: redirectRuleRef, Expression: "old"},
+	}
+	rule := cloudflare.RulesetRule{Ref: redirectRuleRef, Expression: "new"}
+	out, replaced := replaceRuleByRef(existing, rule)
+	if !replaced || len(out) != 2 || out[0].Version != nil || out[0].ID != "1" || out[1].Expression != "new" {
+		t.Fatalf("replace: %+v", out)
+	}
+	out, replaced = replaceRuleByRef(existing[:1], rule)
+	if replaced || len(out) != 2 || out[1].Expression != "new" {
+		t.Fatalf("append: %+v", out)
+	}
+	out, removed := removeRuleByRef(existing, redirectRuleRef)
+	if !removed || len(out) != 1 || out[0].Ref != "other" {
+		t.Fatalf("remove: %+v", out)
+	}
+	if _, removed := removeRuleByRef(existing[:1], redirectRuleRef); removed {
+		t.Fatal("nothing should be removed")
+	}
+}
+
+func TestBulkRedirectItem(t *testing.T) {
+	req, err := bulkRedirectItem(BulkRedirect{Source: "https://Typo.com", Target: "main.com/landing", StatusCode: 302, PreservePath: true, IncludeSubdomains: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := req.Redirect
+	if r.SourceUrl != "typo.com/" || r.TargetUrl != "https://main.com/landing" || *r.StatusCode != 302 || !*r.SubpathMatching || !*r.PreservePathSuffix || !*r.IncludeSubdomains || *r.PreserveQueryString {
+		t.Fatalf("unexpected item: %+v", r)
+	}
+	back := bulkRedirectFromItem(cloudflare.ListItem{ID: "i1", Redirect: r})
+	if back.ID != "i1" || back.Source != "typo.com/" || back.StatusCode != 302 || !back.PreservePath || !back.IncludeSubdomains {
+		t.Fatalf("round trip: %+v", back)
+	}
+	if _, err := bulkRedirectItem(BulkRedirect{Source: "localhost", Target: "main.com"}); err == nil {
+		t.Fatal("expected error for bad source")
+	}
+	if rule := bulkRedirectRule("typos"); rule.Expression != "http.request.full_uri in $typos" || rule.ActionParameters.FromList.Name != "typos" {
+		t.Fatalf("unexpected bulk rule: %+v", rule)
+	}
+}
+
+func TestCheckRedirectPlaceholder(t *testing.T) {
+	on, off := true, false
+	records := []cloudflare.DNSRecord{
+		{Name: "typo.com", Type: "A", Proxied: &on},
+		{Name: "www.typo.com", Type: "CNAME", Proxied: &off},
+		{Name: "mail.typo.com", Type: "MX"},
+	}
+	if got := CheckRedirectPlaceholder(records, "typo.com"); got != PlaceholderProxied {
+		t.Fatalf("apex = %v", got)
+	}
+	if got := CheckRedirectPlaceholder(records, "www.typo.com"); got != PlaceholderUnproxied {
+		t.Fatalf("www = %v", got)
+	}
+	if got := CheckRedirectPlaceholder(records, "mail.typo.com"); got != PlaceholderMissing {
+		t.Fatalf("mail = %v", got)
+	}
+}
@@ -0,0 +1,147 @@
+package cfclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"DomainC/config"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// ZoneSettingSpec 描述一个可通过 /zoneset 管理的 zone 设置
+type ZoneSettingSpec struct {
+	ID     string   // Cloudflare 设置 ID
+	Desc   string   // 中文说明
+	Values []string // 合法取值
+}
+
+var onOff = []string{"on", "off"}
+
+// ZoneSettingSpecs 是支持读写的 zone 设置（取值均为字符串）
+var ZoneSettingSpecs = []ZoneSettingSpec{
+	{ID: "ssl", Desc: "SSL/TLS 加密模式", Values: []string{"off", "flexible", "full", "strict"}},
+	{ID: "always_use_https", Desc: "始终使用 HTTPS", Values: onOff},
+	{ID: "min_tls_version", Desc: "最低 TLS 版本", Values: []string{"1.0", "1.1", "1.2", "1.3"}},
+	{ID: "tls_1_3", Desc: "TLS 1.3", Values: []string{"on", "off", "zrt"}},
+	{ID: "automatic_https_rewrites", Desc: "自动 HTTPS 重写", Values: onOff},
+	{ID: "opportunistic_encryption", Desc: "机会性加密", Values: onOff},
+	{ID: "brotli", Desc: "Brotli 压缩", Values: onOff},
+	{ID: "security_level", Desc: "安全级别", Values: []string{"off", "essentially_off", "low", "medium", "high", "under_attack"}},
+	{ID: "browser_check", Desc: "浏览器完整性检查", Values: onOff},
+	{ID: "email_obfuscation", Desc: "电子邮件地址混淆", Values: onOff},
+	{ID: "hotlink_protection", Desc: "防盗链", Values: onOff},
+	{ID: "always_online", Desc: "Always Online", Values: onOff},
+	{ID: "http3", Desc: "HTTP/3 (QUIC)", Values: onOff},
+	{ID: "0rtt", Desc: "0-RTT 连接恢复", Values: onOff},
+	{ID: "ipv6", Desc: "IPv6 兼容性", Values: onOff},
+	{ID: "websockets", Desc: "WebSockets", Values: onOff},
+	{ID: "early_hints", Desc: "Early Hints", Values: onOff},
+	{ID: "cache_level", Desc: "缓存级别", Values: []string{"basic", "simplified", "aggressive"}},
+	{ID: "development_mode", Desc: "开发模式", Values: onOff},
+}
+
+// zoneSettingAliases 常用的别名写法
+var zoneSettingAliases = map[string]string{
+	"https":         "always_use_https",
+	"min_tls":       "min_tls_version",
+	"tls13":         "tls_1_3",
+	"tls1.3":        "tls_1_3",
+	"https_rewrite": "automatic_https_rewrites",
+	"security":      "security_level",
+	"devmode":       "development_mode",
+}
+
+// LookupZoneSetting 按设置 ID（或别名）返回规格
+func LookupZoneSetting(id string) (ZoneSettingSpec, bool) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	if alias, ok := zoneSettingAliases[id]; ok {
+		id = alias
+	}
+	for _, s := range ZoneSettingSpecs {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return ZoneSettingSpec{}, false
+}
+
+// NormalizeZoneSetting 校验设置与取值，返回规范化后的设置 ID 与取值
+func NormalizeZoneSetting(id, value string) (string, string, error) {
+	spec, ok := LookupZoneSetting(id)
+	if !ok {
+		return "", "", fmt.Errorf("不支持的设置: %s", id)
+	}
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case spec.ID == "ssl" && (value == "full_strict" || value == "full-strict"):
+		value = "strict"
+	case value == "true" || value == "enable" || value == "enabled":
+		value = "on"
+	case value == "false" || value == "disable" || value == "disabled":
+		value = "off"
+	}
+	for _, v := range spec.Values {
+		if v == value {
+			return spec.ID, value, nil
+		}
+	}
+	return "", "", fmt.Errorf("%s 的取值必须是 %s", spec.ID, strings.Join(spec.Values, "/"))
+}
+
+// GetZoneSettings 读取 zone 的全部设置，只保留取值为字符串或数字的设置
+func (c *apiClient) GetZoneSettings(ctx context.Context, account config.CF, zoneID string) (map[string]string, error) {
+	ctx, cancel := ensureTimeout(ctx)
+	defer cancel()
+
+	api, err := cloudflare.NewWithAPIToken(account.APIToken)
+	if err != nil {
+		return nil, fmt.Errorf("初始化 Cloudflare 客户端失败 [%s]: %v", account.Label, err)
+	}
+	res, err := api.ZoneSettings(ctx, zoneID)
+	if err != nil {
+		return nil, fmt.Errorf("读取 Zone 设置失败 [%s/%s]: %v", account.Label, zoneID, err)
+	}
+	return zoneSettingValues(res.Result), nil
+}
+
+// UpdateZoneSetting 修改单个 zone 设置
+func (c *apiClient) UpdateZoneSetting(ctx context.Context, account config.CF, zoneID, id, value string) error {
+	ctx, cancel := ensureTimeout(ctx)
+	defer cancel()
+
+	api, err := cloudflare.NewWithAPIToken(account.APIToken)
+	if err != nil {
+		return fmt.Errorf("初始化 Cloudflare 客户端失败 [%s]: %v", account.Label, err)
+	}
+	rc := &cloudflare.ResourceContainer{Level: cloudflare.ZoneRouteLevel, Identifier: zoneID}
+	if _, err := api.UpdateZoneSetting(ctx, rc, cloudflare.UpdateZoneSettingParams{Name: id, Value: value}); err != nil {
+		return fmt.Errorf("修改 Zone 设置 %s=%s 失败 [%s/%s]: %v", id, value, account.Label, zoneID, err)
+	}
+	return nil
+}
+
+func zoneSettingValues(settings []cloudflare.ZoneSetting) map[string]string {
+	out := make(map[string]string, len(settings))
+	for _, s := range settings {
+		switch v := s.Value.(type) {
+		case string:
+			out[s.ID] = v
+		case float64, int, bool:
+			out[s.ID] = fmt.Sprint(v)
+		}
+	}
+	return out
+}
+
+// ZoneSettingIDs 返回排序后的设置 ID
+func ZoneSettingIDs(m map[string]string) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
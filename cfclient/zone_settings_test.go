@@ -0,0 +1,43 @@
+package cfclient
+
+import (
+	"testing"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+func TestNormalizeZoneSetting(t *testing.T) {
+	cases := []struct {
+		id, value       string
+		wantID, wantVal string
+	}{
+		{"ssl", "full_strict", "ssl", "strict"},
+		{"SSL", "Flexible", "ssl", "flexible"},
+		{"https", "true", "always_use_https", "on"},
+		{"min_tls_version", "1.2", "min_tls_version", "1.2"},
+		{"tls13", "zrt", "tls_1_3", "zrt"},
+		{"security_level", "under_attack", "security_level", "under_attack"},
+	}
+	for _, c := range cases {
+		id, val, err := NormalizeZoneSetting(c.id, c.value)
+		if err != nil || id != c.wantID || val != c.wantVal {
+			t.Fatalf("NormalizeZoneSetting(%q, %q) = %q, %q, %v", c.id, c.value, id, val, err)
+		}
+	}
+	for _, c := range [][2]string{{"ssl", "strictest"}, {"min_tls_version", "1.4"}, {"rocket_loader", "on"}} {
+		if _, _, err := NormalizeZoneSetting(c[0], c[1]); err == nil {
+			t.Fatalf("expected error for %v", c)
+		}
+	}
+}
+
+func TestZoneSettingValues(t *testing.T) {
+	got := zoneSettingValues([]cloudflare.ZoneSetting{
+		{ID: "ssl", Value: "strict"},
+		{ID: "browser_cache_ttl", Value: float64(14400)},
+		{ID: "minify", Value: map[string]interface{}{"css": "on"}},
+	})
+	if len(got) != 2 || got["ssl"] != "strict" || got["browser_cache_ttl"] != "14400" {
+		t.Fatalf("unexpected values: %v", got)
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/dnsplan"
+	"DomainC/iacexport"
+)
+
+// cliCommands 是命令行子命令；其它参数不进入命令行模式，照常启动机器人
+var cliCommands = map[string]bool{"plan": true, "apply": true, "tfexport": true}
+
+// isCLI 判断启动参数是否为命令行子命令
+func isCLI(args []string) bool {
+	return len(args) > 0 && cliCommands[args[0]]
+}
+
+// runCLI 处理命令行子命令，返回进程退出码
+//
+//	global-cf-auto plan  [-state dir] [-detailed-exitcode] [zone...]
+//	global-cf-auto apply [-state dir] [-yes] [zone...]
+//	global-cf-auto tfexport [-out dir|file.zip] [-target terraform|dnscontrol] [账号...]
+func runCLI(args []string) int {
+	switch args[0] {
+	case "plan", "apply":
+		return runPlanCLI(args[0], args[1:], os.Stdin, os.Stdout)
+	case "tfexport":
+		return runTFExportCLI(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "未知子命令 %q，可用: plan, apply, tfexport\n", args[0])
+		return 1
+	}
+}
+
+func runPlanCLI(cmd string, args []string, stdin io.Reader, stdout io.Writer) int {
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	stateDir := fs.String("state", config.Cfg.DNSState.Dir, "期望状态 YAML 文件或目录")
+	yes := fs.Bool("yes", false, "apply 时跳过交互确认")
+	detailed := fs.Bool("detailed-exitcode", false, "plan 有变更时返回退出码 2")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if strings.TrimSpace(*stateDir) == "" {
+		fmt.Fprintln(os.Stderr, "未指定期望状态目录（-state 或配置 dnsState.dir）")
+		return 1
+	}
+
+	states, err := dnsplan.LoadStates(*stateDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if zones := fs.Args(); len(zones) > 0 {
+		var selected []dnsplan.ZoneState
+		for _, z := range zones {
+			st, err := dnsplan.FindState(states, z)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			selected = append(selected, st)
+		}
+		states = selected
+	}
+
+	ctx := context.Background()
+	client := cfclient.NewClient()
+	var plans []dnsplan.ZonePlan
+	total, failed := 0, false
+	for _, st := range states {
+		plan, err := dnsplan.PlanState(ctx, client, config.Cfg.CloudflareAccounts, st)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", st.Zone, err)
+			failed = true
+			continue
+		}
+		create, update, del := plan.Counts()
+		total += create + update + del
+		fmt.Fprintf(stdout, "[%s] %s（%s）：新建 %d / 更新 %d / 删除 %d\n", plan.AccountLabel, plan.Zone, st.Source, create, update, del)
+		for _, ch := range plan.Changes {
+			fmt.Fprintf(stdout, "  %s\n", ch.Describe())
+		}
+		plans = append(plans, plan)
+	}
+
+	if failed {
+		return 1
+	}
+	if total == 0 {
+		fmt.Fprintln(stdout, "线上解析已与期望状态一致，无需变更。")
+		return 0
+	}
+	if cmd == "plan" {
+		if *detailed {
+			return 2
+		}
+		return 0
+	}
+
+	if !*yes {
+		fmt.Fprintf(stdout, "\n确认执行以上 %d 项变更？输入 yes 继续: ", total)
+		line, _ := bufio.NewReader(stdin).ReadString('\n')
+		if strings.TrimSpace(line) != "yes" {
+			fmt.Fprintln(stdout, "已取消。")
+			return 1
+		}
+	}
+
+	exit := 0
+	for _, plan := range plans {
+		account := cfclient.GetAccountByLabel(plan.AccountLabel)
+		if account == nil {
+			fmt.Fprintf(os.Stderr, "%s: 未找到账号 %s\n", plan.Zone, plan.AccountLabel)
+			exit = 1
+			continue
+		}
+		for _, r := range dnsplan.Apply(ctx, client, *account, plan) {
+			if r.Err != nil {
+				fmt.Fprintf(stdout, "❌ %s: %v\n", r.Change.Describe(), r.Err)
+				exit = 1
+				continue
+			}
+			fmt.Fprintf(stdout, "✅ %s\n", r.Change.Describe())
+		}
+	}
+	return exit
+}
+
+func runTFExportCLI(args []string) int {
+	fs := flag.NewFlagSet("tfexport", flag.ContinueOnError)
+	out := fs.String("out", "iac-export", "输出目录，以 .zip 结尾时输出压缩包")
+	target := fs.String("target", "", "只导出 terraform 或 dnscontrol，默认全部")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	accounts := config.Cfg.CloudflareAccounts
+	if labels := fs.Args(); len(labels) > 0 {
+		accounts = nil
+		for _, label := range labels {
+			acc := cfclient.GetAccountByLabel(label)
+			if acc == nil {
+				fmt.Fprintf(os.Stderr, "未找到账号 %s\n", label)
+				return 1
+			}
+			accounts = append(accounts, *acc)
+		}
+	}
+
+	inv, err := iacexport.Collect(context.Background(), cfclient.NewClient(), accounts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	var targets []string
+	if *target != "" {
+		targets = []string{*target}
+	}
+	files, err := iacexport.Generate(inv, targets...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if strings.HasSuffix(strings.ToLower(*out), ".zip") {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		if err := files.WriteZip(f); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		for _, name := range files.Names() {
+			p := filepath.Join(*out, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			if err := os.WriteFile(p, files[name], 0o644); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+		}
+	}
+	fmt.Printf("已导出 %d 个账号、%d 个文件到 %s\n", len(inv.Accounts), len(files), *out)
+	return 0
+}
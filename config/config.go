@@ -15,6 +15,22 @@ type Config struct {
 	DomainFiles        []string    `yaml:"domainFiles"`
 
 	AWSTargets map[string]AWSTarget `yaml:"awsTargets"`
+
+	DNSSnapshot DNSSnapshot `yaml:"dnsSnapshot"`
+	DNSState    DNSState    `yaml:"dnsState"`
+	ZoneMove    ZoneMove    `yaml:"zoneMove"`
+	ZoneWatch   ZoneWatch   `yaml:"zoneWatch"`
+	NSCheck     NSCheck     `yaml:"nsCheck"`
+	Dig         Dig         `yaml:"dig"`
+	Takeover    Takeover    `yaml:"takeover"`
+	Lint        Lint        `yaml:"lint"`
+	MailAuth    MailAuth    `yaml:"mailAuth"`
+	DNSSEC      DNSSEC      `yaml:"dnssec"`
+	OriginCerts OriginCerts `yaml:"originCerts"`
+	CertVault   CertVault   `yaml:"certVault"`
+	ZoneAudit   ZoneAudit   `yaml:"zoneAudit"`
+	Emergency   Emergency   `yaml:"emergency"`
+	Redirect    Redirect    `yaml:"redirect"`
 }
 
 type Telegram struct {
@@ -42,8 +58,9 @@ type NamecheapConfig struct {
 }
 
 type GoDaddyConfig struct {
-	APIKey    string `yaml:"apiKey"`
-	APISecret string `yaml:"apiSecret"`
+	APIKey     string `yaml:"apiKey"`
+	APISecret  string `yaml:"apiSecret"`
+	CustomerID string `yaml:"customerId"` // v2 接口（DNSSEC）需要
 }
 type AWSCreds struct {
 	AccessKeyID     string `yaml:"accessKeyId"`
@@ -52,8 +69,145 @@ type AWSCreds struct {
 }
 
 type AWSTarget struct {
-	Region string   `yaml:"region"`
-	Creds  AWSCreds `yaml:"creds"`
+	Region   string   `yaml:"region"`
+	Creds    AWSCreds `yaml:"creds"`
+	Endpoint string   `yaml:"endpoint"` // 可选：自定义 ACM 地址（如本地 ACM 替身）
+}
+
+// DNSSnapshot 控制解析快照与变更检测任务
+type DNSSnapshot struct {
+	Enabled         bool   `yaml:"enabled"`
+	Dir             string `yaml:"dir"`             // 默认 dns_snapshots
+	IntervalMinutes int    `yaml:"intervalMinutes"` // 默认 60
+	Keep            int    `yaml:"keep"`            // 每个 Zone 保留的快照数，默认 168
+}
+
+// DNSState 指向保存期望状态 YAML 的目录（通常是 git 仓库）
+type DNSState struct {
+	Dir     string `yaml:"dir"`
+	GitPull bool   `yaml:"gitPull"` // /plan 前先执行 git pull --ff-only
+}
+
+// ZoneMove 控制 /movezone 的检查点目录与激活等待
+type ZoneMove struct {
+	Dir                      string `yaml:"dir"`                      // 默认 zone_moves
+	ActivationTimeoutMinutes int    `yaml:"activationTimeoutMinutes"` // 默认 1440
+	PollIntervalSeconds      int    `yaml:"pollIntervalSeconds"`      // 默认 300
+}
+
+// ZoneWatch 控制 /getns 新建 Zone 后的激活跟踪，默认开启
+type ZoneWatch struct {
+	Disabled           bool   `yaml:"disabled"`
+	File               string `yaml:"file"`               // 默认 zone_watch.json
+	EscalateDays       int    `yaml:"escalateDays"`       // 超过该天数仍未激活则升级告警，默认 3
+	MaxIntervalMinutes int    `yaml:"maxIntervalMinutes"` // 轮询退避上限，默认 360
+}
+
+// NSCheck 配置 /nscheck 查询 NS 委派时使用的 DNS 服务器
+type NSCheck struct {
+	Resolver string `yaml:"resolver"` // 例如 1.1.1.1:53，为空时使用系统解析器
+}
+
+// DNSSEC 配置 /dnssec 查询注册局 DS 时使用的 DNS 服务器
+type DNSSEC struct {
+	Resolver string `yaml:"resolver"` // 例如 1.1.1.1:53，为空时使用 1.1.1.1
+}
+
+// OriginCerts 控制 Origin CA 源站证书的定时到期提醒
+type OriginCerts struct {
+	Enabled       bool   `yaml:"enabled"`
+	IntervalHours int    `yaml:"intervalHours"` // 默认 24
+	AlertDays     []int  `yaml:"alertDays"`     // 剩余天数阈值，默认 [30, 14, 7, 1]，每个阈值只提醒一次
+	ExpiredDays   int    `yaml:"expiredDays"`   // 过期超过该天数后不再提醒，默认 30
+	StateFile     string `yaml:"stateFile"`     // 已提醒记录，默认 origin_cert_alerts.json
+	// RootCADir 存放 origin_ca_rsa_root.pem / origin_ca_ecc_root.pem 的目录，为空或缺文件时从 Cloudflare 下载
+	RootCADir string `yaml:"rootCADir"`
+}
+
+// CertVault 启用后源站证书私钥加密保存在本地，群里只发送证书与引用 ID，授权用户用 /sslget 私聊获取。
+// 未启用时默认不向群里发送私钥，除非显式设置 AllowKeyInChat
+type CertVault struct {
+	Enabled            bool    `yaml:"enabled"`
+	AllowKeyInChat     bool    `yaml:"allowKeyInChat"`     // 未启用 vault 时仍把私钥、p12、k8s Secret 发到群里（不推荐）
+	Dir                string  `yaml:"dir"`                // 默认 cert_vault
+	KeyEnv             string  `yaml:"keyEnv"`             // 读取密钥的环境变量，默认 CERT_VAULT_KEY
+	KeyFile            string  `yaml:"keyFile"`            // 环境变量为空时读取的密钥文件
+	AllowedUsers       []int64 `yaml:"allowedUsers"`       // 允许 /sslget 的 Telegram 用户 ID
+	DeleteAfterMinutes int     `yaml:"deleteAfterMinutes"` // 私聊中的私钥文件自动删除时间，默认 10
+}
+
+// ZoneAudit 配置 /zoneaudit 使用的 Zone 设置基线
+type ZoneAudit struct {
+	Baseline map[string]string `yaml:"baseline"` // 设置 ID -> 期望值，为空时使用默认基线
+	Exclude  []string          `yaml:"exclude"`  // 不参与审计的 zone
+}
+
+// Emergency 配置 /uam、/devmode 批量切换应急模式
+type Emergency struct {
+	Concurrency   int                 `yaml:"concurrency"`   // 同时处理的 Zone 数，默认 5
+	RatePerSecond float64             `yaml:"ratePerSecond"` // 每秒开始处理的 Zone 数，默认 4
+	StateFile     string              `yaml:"stateFile"`     // 开启记录文件，默认 emergency_state.json
+	Tags          map[string][]string `yaml:"tags"`          // 标签 -> zone 列表，可在命令中按标签选择
+}
+
+// Redirect 配置 /redirect 使用的 Bulk Redirect 列表与占位解析
+type Redirect struct {
+	BulkList      string `yaml:"bulkList"`      // 账号级 Bulk Redirect 列表名，默认 domainc_redirects
+	PlaceholderIP string `yaml:"placeholderIP"` // 占位 A 记录地址，默认 192.0.2.1
+}
+
+// Dig 配置 /dig 默认查询的上游解析器：system、IP[:端口]、DoH 地址，可写成 "别名=地址"
+type Dig struct {
+	Resolvers []string `yaml:"resolvers"`
+}
+
+// Takeover 控制悬空记录 / 子域名接管扫描
+type Takeover struct {
+	Enabled       bool                  `yaml:"enabled"`
+	IntervalHours int                   `yaml:"intervalHours"` // 默认 24
+	MinSeverity   string                `yaml:"minSeverity"`   // 低于该级别的发现不推送，默认 low
+	NoDefaults    bool                  `yaml:"noDefaults"`    // 不使用内置服务商列表
+	Fingerprints  []TakeoverFingerprint `yaml:"fingerprints"`
+}
+
+// Lint 控制解析检查规则。rules 按规则 ID 开关，未列出的规则使用默认值。
+type Lint struct {
+	Enabled       bool            `yaml:"enabled"`       // 开启定时检查
+	IntervalHours int             `yaml:"intervalHours"` // 默认 24
+	MinSeverity   string          `yaml:"minSeverity"`   // 定时推送的最低级别 info/warning/error，默认 warning
+	Rules         map[string]bool `yaml:"rules"`
+}
+
+// MailAuth 配置 /mailcheck 与 /mailsetup
+type MailAuth struct {
+	DKIMSelectors []string      `yaml:"dkimSelectors"` // 需要检查的 DKIM 选择器，例如 google、selector1
+	Resolver      string        `yaml:"resolver"`      // 递归统计 SPF include 时使用的 DNS 服务器，为空时使用系统解析器
+	Profiles      []MailProfile `yaml:"profiles"`      // 自定义模板，与内置模板同名时覆盖
+}
+
+// MailProfile 是 /mailsetup 套用的一组记录，内容中的 {zone} / {zone_dash} 会被替换
+type MailProfile struct {
+	Name        string              `yaml:"name"`
+	Description string              `yaml:"description"`
+	Records     []MailProfileRecord `yaml:"records"`
+}
+
+type MailProfileRecord struct {
+	Type     string  `yaml:"type"`
+	Name     string  `yaml:"name"`
+	Content  string  `yaml:"content"`
+	Priority *uint16 `yaml:"priority"`
+	TTL      int     `yaml:"ttl"`
+}
+
+// TakeoverFingerprint 是一个易被接管的服务商特征
+type TakeoverFingerprint struct {
+	Provider string   `yaml:"provider"`
+	CNAME    []string `yaml:"cname"`    // CNAME 目标后缀，可含 *
+	CIDR     []string `yaml:"cidr"`     // A/AAAA 所在地址段
+	Body     []string `yaml:"body"`     // 资源不存在时的页面特征
+	NXDomain bool     `yaml:"nxdomain"` // 目标不存在即视为悬空
+	Severity string   `yaml:"severity"` // info/low/medium/high/critical，默认 high
 }
 
 var Cfg Config
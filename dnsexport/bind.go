@@ -0,0 +1,172 @@
+package dnsexport
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// BINDExporter 输出 RFC 1035 主文件格式，每个文件只包含一个 Zone。
+// 代理状态写在行尾注释 "cf_tags=cf-proxied:true" 中，与 Cloudflare 官方导出一致。
+type BINDExporter struct{}
+
+func (BINDExporter) Format() string  { return "bind" }
+func (BINDExporter) Ext() string     { return "zone" }
+func (BINDExporter) MultiZone() bool { return false }
+
+// bindDefaultTTL 用于 SOA/NS 以及 $TTL 指令
+const bindDefaultTTL = 3600
+
+func (BINDExporter) Export(w io.Writer, zones []Zone) error {
+	if len(zones) != 1 {
+		return fmt.Errorf("BIND 格式每个文件只能包含一个 Zone，当前 %d 个，请使用 zip 导出", len(zones))
+	}
+	z := zones[0]
+	origin := absName(z.Detail.Name)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(";; Zone: %s\n", z.Detail.Name))
+	if z.AccountLabel != "" {
+		sb.WriteString(fmt.Sprintf(";; Account: %s\n", z.AccountLabel))
+	}
+	sb.WriteString(fmt.Sprintf("$ORIGIN %s\n$TTL %d\n\n", origin, bindDefaultTTL))
+
+	// SOA：Cloudflare 不通过记录接口返回 SOA，这里按托管 NS 合成
+	mname := "ns.cloudflare.com."
+	if len(z.Detail.NameServers) > 0 {
+		mname = absName(z.Detail.NameServers[0])
+	}
+	sb.WriteString(fmt.Sprintf("@\t%d\tIN\tSOA\t%s hostmaster.%s %d 10000 2400 604800 3600\n",
+		bindDefaultTTL, mname, origin, soaSerial(z.Records)))
+
+	hasApexNS := false
+	for _, r := range z.Records {
+		if strings.EqualFold(r.Type, "NS") && strings.EqualFold(trimDot(r.Name), trimDot(z.Detail.Name)) {
+			hasApexNS = true
+			break
+		}
+	}
+	if !hasApexNS {
+		for _, ns := range z.Detail.NameServers {
+			sb.WriteString(fmt.Sprintf("@\t%d\tIN\tNS\t%s\n", bindDefaultTTL, absName(ns)))
+		}
+	}
+	sb.WriteString("\n")
+
+	for _, r := range z.Records {
+		sb.WriteString(bindLine(z.Detail.Name, r))
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func bindLine(zone string, r cloudflare.DNSRecord) string {
+	typ := strings.ToUpper(r.Type)
+	// TTL=1 是 Cloudflare 的 auto，按官方导出原样写 1
+	line := fmt.Sprintf("%s\t%d\tIN\t%s\t%s", ownerName(r.Name, zone), r.TTL, typ, bindRData(r))
+	var tags []string
+	if proxied(r) {
+		tags = append(tags, "cf_tags=cf-proxied:true")
+	}
+	if r.Comment != "" {
+		tags = append(tags, strings.ReplaceAll(r.Comment, "\n", " "))
+	}
+	if len(tags) > 0 {
+		line += " ; " + strings.Join(tags, " ")
+	}
+	return line
+}
+
+// bindRData 把 Cloudflare 的 content/priority 转换为主文件中的 RDATA
+func bindRData(r cloudflare.DNSRecord) string {
+	content := strings.TrimSpace(r.Content)
+	prio := ""
+	if r.Priority != nil {
+		prio = fmt.Sprintf("%d ", *r.Priority)
+	}
+
+	switch strings.ToUpper(r.Type) {
+	case "CNAME", "NS", "PTR", "DNAME":
+		return absName(content)
+	case "MX":
+		return prio + absName(content)
+	case "SRV":
+		// content 为 "weight port target"
+		fields := strings.Fields(content)
+		if len(fields) == 3 {
+			fields[2] = absName(fields[2])
+		}
+		return prio + strings.Join(fields, " ")
+	case "URI":
+		return prio + content
+	case "TXT", "SPF":
+		return quoteTXT(content)
+	case "CAA":
+		// content 为 `flags tag value`，value 需要加引号
+		fields := strings.SplitN(content, " ", 3)
+		if len(fields) == 3 && !strings.HasPrefix(fields[2], `"`) {
+			fields[2] = `"` + fields[2] + `"`
+		}
+		return strings.Join(fields, " ")
+	}
+	return content
+}
+
+// quoteTXT 把 TXT 内容切分为不超过 255 字节的字符串并转义
+func quoteTXT(s string) string {
+	if len(s) >= 2 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
+		// 已是 zone 文件格式（可能是多段）
+		return s
+	}
+	var parts []string
+	for len(s) > 255 {
+		parts = append(parts, s[:255])
+		s = s[255:]
+	}
+	parts = append(parts, s)
+	for i, p := range parts {
+		p = strings.ReplaceAll(p, `\`, `\\`)
+		p = strings.ReplaceAll(p, `"`, `\"`)
+		parts[i] = `"` + p + `"`
+	}
+	return strings.Join(parts, " ")
+}
+
+// ownerName 根域写 @，子域写相对名，其它写绝对名
+func ownerName(name, zone string) string {
+	name, zone = strings.ToLower(trimDot(name)), strings.ToLower(trimDot(zone))
+	switch {
+	case name == zone || name == "@" || name == "":
+		return "@"
+	case strings.HasSuffix(name, "."+zone):
+		return strings.TrimSuffix(name, "."+zone)
+	}
+	return name + "."
+}
+
+// soaSerial 取记录中最近的修改时间，保证同一份数据导出的序列号稳定
+func soaSerial(records []cloudflare.DNSRecord) uint32 {
+	var latest time.Time
+	for _, r := range records {
+		if r.ModifiedOn.After(latest) {
+			latest = r.ModifiedOn
+		}
+	}
+	if latest.IsZero() {
+		return 1
+	}
+	return uint32(latest.Unix())
+}
+
+func absName(s string) string {
+	return trimDot(strings.TrimSpace(s)) + "."
+}
+
+func trimDot(s string) string {
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,85 @@
+package dnsexport
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// CSVExporter 输出与 /csv 相同的中文表头（末列为 MX 等记录的优先级），可直接再次上传导入
+type CSVExporter struct{}
+
+func (CSVExporter) Format() string  { return "csv" }
+func (CSVExporter) Ext() string     { return "csv" }
+func (CSVExporter) MultiZone() bool { return true }
+
+func (CSVExporter) Export(w io.Writer, zones []Zone) error {
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = false
+
+	if err := cw.Write([]string{
+		"所属账户",
+		"主域名",
+		"子域名",
+		"解析类型",
+		"解析地址",
+		"是否代理",
+		"Zone状态",
+		"是否暂停",
+		"优先级",
+	}); err != nil {
+		return err
+	}
+
+	for _, z := range zones {
+		zonePaused := yesNo(z.Detail.Paused)
+
+		// 没有记录也写一行（保留 zone 维度信息）
+		if len(z.Records) == 0 {
+			if err := cw.Write([]string{z.AccountLabel, z.Detail.Name, "", "", "", "", z.Detail.Status, zonePaused, ""}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, r := range z.Records {
+			if err := cw.Write(csvRow(z, r, zonePaused)); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvRow(z Zone, r cloudflare.DNSRecord, zonePaused string) []string {
+	subDomain := r.Name
+	if subDomain == "@" {
+		subDomain = z.Detail.Name
+	}
+	priority := ""
+	if r.Priority != nil {
+		priority = strconv.Itoa(int(*r.Priority))
+	}
+	return []string{
+		z.AccountLabel,    // 所属账户
+		z.Detail.Name,     // 主域名
+		subDomain,         // 子域名（完整 FQDN）
+		r.Type,            // 解析类型
+		r.Content,         // 解析地址
+		yesNo(proxied(r)), // 是否代理
+		z.Detail.Status,   // Zone状态
+		zonePaused,        // 是否暂停
+		priority,          // 优先级
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "是"
+	}
+	return "否"
+}
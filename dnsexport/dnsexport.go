@@ -0,0 +1,106 @@
+// Package dnsexport 把 Cloudflare 解析记录导出为 CSV / BIND / JSON / YAML 等格式。
+package dnsexport
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"DomainC/cfclient"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Zone 是一个待导出的 Zone 及其全部解析记录
+type Zone struct {
+	AccountLabel string
+	Detail       cfclient.ZoneDetail
+	Records      []cloudflare.DNSRecord
+}
+
+// Exporter 是可插拔的导出格式
+type Exporter interface {
+	// Format 返回格式名，如 "csv"、"bind"
+	Format() string
+	// Ext 返回文件后缀（不含点）
+	Ext() string
+	// MultiZone 表示能否把多个 Zone 写进同一个文件；BIND 一个文件只能描述一个 Zone
+	MultiZone() bool
+	Export(w io.Writer, zones []Zone) error
+}
+
+var registry = struct {
+	mu        sync.RWMutex
+	exporters map[string]Exporter
+}{
+	exporters: make(map[string]Exporter),
+}
+
+func init() {
+	Register(CSVExporter{})
+	Register(BINDExporter{})
+	Register(JSONExporter{})
+	Register(YAMLExporter{})
+}
+
+// Register 注册导出格式，同名格式会被覆盖
+func Register(e Exporter) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.exporters[strings.ToLower(e.Format())] = e
+}
+
+// Lookup 按格式名查找导出器（忽略大小写）
+func Lookup(format string) (Exporter, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	e, ok := registry.exporters[strings.ToLower(strings.TrimSpace(format))]
+	return e, ok
+}
+
+// Formats 返回已注册的格式名（排序后）
+func Formats() []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	out := make([]string, 0, len(registry.exporters))
+	for name := range registry.exporters {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// WriteZip 每个 Zone 单独导出一个文件并打包为 zip，文件名为 <账号>/<zone>.<ext>
+func WriteZip(w io.Writer, e Exporter, zones []Zone) error {
+	zw := zip.NewWriter(w)
+	for _, z := range zones {
+		name := ZoneFilename(z, e)
+		f, err := zw.Create(name)
+		if err != nil {
+			return fmt.Errorf("创建压缩文件 %s 失败: %w", name, err)
+		}
+		if err := e.Export(f, []Zone{z}); err != nil {
+			return fmt.Errorf("导出 %s 失败: %w", z.Detail.Name, err)
+		}
+	}
+	return zw.Close()
+}
+
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// ZoneFilename 返回 Zone 在压缩包中的相对路径
+func ZoneFilename(z Zone, e Exporter) string {
+	name := unsafeChars.ReplaceAllString(z.Detail.Name, "_") + "." + e.Ext()
+	if label := unsafeChars.ReplaceAllString(z.AccountLabel, "_"); label != "" {
+		return label + "/" + name
+	}
+	return name
+}
+
+func proxied(r cloudflare.DNSRecord) bool {
+	return r.Proxied != nil && *r.Proxied
+}
@@ -0,0 +1,147 @@
+package dnsexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"DomainC/cfclient"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/miekg/dns"
+	"gopkg.in/yaml.v3"
+)
+
+func testZone() Zone {
+	yes := true
+	prio := uint16(10)
+	srvPrio := uint16(5)
+	return Zone{
+		AccountLabel: "acc",
+		Detail: cfclient.ZoneDetail{
+			ID:          "zone-id",
+			Name:        "example.com",
+			NameServers: []string{"ana.ns.cloudflare.com", "bob.ns.cloudflare.com"},
+			Status:      "active",
+		},
+		Records: []cloudflare.DNSRecord{
+			{ID: "r1", Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 1, Proxied: &yes,
+				ModifiedOn: time.Unix(1700000000, 0)},
+			{ID: "r2", Type: "CNAME", Name: "www.example.com", Content: "example.com", TTL: 300},
+			{ID: "r3", Type: "MX", Name: "example.com", Content: "mail.example.com", TTL: 3600, Priority: &prio},
+			{ID: "r4", Type: "TXT", Name: "example.com", Content: `v=spf1 include:"x" ` + strings.Repeat("a", 300), TTL: 3600},
+			{ID: "r5", Type: "SRV", Name: "_sip._tcp.example.com", Content: "1 5060 sip.example.com", TTL: 3600, Priority: &srvPrio},
+			{ID: "r6", Type: "CAA", Name: "example.com", Content: "0 issue letsencrypt.org", TTL: 3600},
+		},
+	}
+}
+
+func TestCSVExportIncludesPriority(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := (CSVExporter{}).Export(buf, []Zone{testZone()}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if !strings.Contains(buf.String(), ",是否暂停,优先级\n") || !strings.Contains(buf.String(), ",MX,mail.example.com,否,active,否,10\n") {
+		t.Fatalf("expected MX priority column:\n%s", buf.String())
+	}
+}
+
+func TestBINDExportParses(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := (BINDExporter{}).Export(buf, []Zone{testZone()}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	zp := dns.NewZoneParser(strings.NewReader(buf.String()), "", "")
+	counts := map[string]int{}
+	var txt *dns.TXT
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		counts[dns.TypeToString[rr.Header().Rrtype]]++
+		if v, isTXT := rr.(*dns.TXT); isTXT {
+			txt = v
+		}
+		if v, isSOA := rr.(*dns.SOA); isSOA && v.Serial != 1700000000 {
+			t.Fatalf("expected serial from latest modification, got %d", v.Serial)
+		}
+	}
+	if err := zp.Err(); err != nil {
+		t.Fatalf("output is not a valid zone file: %v\n%s", err, buf.String())
+	}
+	want := map[string]int{"SOA": 1, "NS": 2, "A": 1, "CNAME": 1, "MX": 1, "TXT": 1, "SRV": 1, "CAA": 1}
+	for typ, n := range want {
+		if counts[typ] != n {
+			t.Fatalf("expected %d %s records, got %d\n%s", n, typ, counts[typ], buf.String())
+		}
+	}
+	if txt == nil || len(txt.Txt) != 2 || strings.ReplaceAll(strings.Join(txt.Txt, ""), `\"`, `"`) != testZone().Records[3].Content {
+		t.Fatalf("TXT was not split/escaped correctly: %+v", txt)
+	}
+	if !strings.Contains(buf.String(), "cf_tags=cf-proxied:true") {
+		t.Fatalf("expected proxied tag in output")
+	}
+}
+
+func TestBINDExportRejectsMultipleZones(t *testing.T) {
+	if err := (BINDExporter{}).Export(&bytes.Buffer{}, []Zone{testZone(), testZone()}); err == nil {
+		t.Fatalf("expected error for multiple zones")
+	}
+}
+
+func TestJSONExportKeepsAllFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := (JSONExporter{}).Export(buf, []Zone{testZone()}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	var doc jsonDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	r := doc.Zones[0].Records[2]
+	if r.ID != "r3" || r.TTL != 3600 || r.Priority == nil || *r.Priority != 10 {
+		t.Fatalf("record fields lost: %+v", r)
+	}
+}
+
+func TestYAMLExport(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := (YAMLExporter{}).Export(buf, []Zone{testZone()}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	var doc YAMLDocument
+	if err := yaml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid yaml: %v", err)
+	}
+	if doc.Zones[0].Zone != "example.com" || len(doc.Zones[0].Records) != 6 || !doc.Zones[0].Records[0].Proxied {
+		t.Fatalf("unexpected yaml document: %+v", doc)
+	}
+}
+
+func TestWriteZipOneFilePerZone(t *testing.T) {
+	other := testZone()
+	other.Detail.Name = "example.org"
+	buf := &bytes.Buffer{}
+	if err := WriteZip(buf, BINDExporter{}, []Zone{testZone(), other}); err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	if len(zr.File) != 2 || zr.File[0].Name != "acc/example.com.zone" || zr.File[1].Name != "acc/example.org.zone" {
+		t.Fatalf("unexpected zip entries: %v", zr.File)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, f := range []string{"csv", "BIND", "json", "yaml"} {
+		if _, ok := Lookup(f); !ok {
+			t.Fatalf("format %s not registered", f)
+		}
+	}
+	if _, ok := Lookup("xml"); ok {
+		t.Fatalf("unexpected xml exporter")
+	}
+}
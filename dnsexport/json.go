@@ -0,0 +1,110 @@
+package dnsexport
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"gopkg.in/yaml.v3"
+)
+
+// JSONExporter 原样输出 Cloudflare 返回的全部记录字段（含 ID、TTL、priority、meta 等）
+type JSONExporter struct{}
+
+func (JSONExporter) Format() string  { return "json" }
+func (JSONExporter) Ext() string     { return "json" }
+func (JSONExporter) MultiZone() bool { return true }
+
+type jsonDocument struct {
+	Zones []jsonZone `json:"zones"`
+}
+
+type jsonZone struct {
+	Account     string                 `json:"account,omitempty"`
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Status      string                 `json:"status"`
+	Paused      bool                   `json:"paused"`
+	NameServers []string               `json:"name_servers,omitempty"`
+	Records     []cloudflare.DNSRecord `json:"records"`
+}
+
+func (JSONExporter) Export(w io.Writer, zones []Zone) error {
+	doc := jsonDocument{Zones: make([]jsonZone, 0, len(zones))}
+	for _, z := range zones {
+		records := z.Records
+		if records == nil {
+			records = []cloudflare.DNSRecord{}
+		}
+		doc.Zones = append(doc.Zones, jsonZone{
+			Account:     z.AccountLabel,
+			ID:          z.Detail.ID,
+			Name:        z.Detail.Name,
+			Status:      z.Detail.Status,
+			Paused:      z.Detail.Paused,
+			NameServers: z.Detail.NameServers,
+			Records:     records,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// YAMLExporter 输出便于人工编辑的精简记录
+type YAMLExporter struct{}
+
+func (YAMLExporter) Format() string  { return "yaml" }
+func (YAMLExporter) Ext() string     { return "yaml" }
+func (YAMLExporter) MultiZone() bool { return true }
+
+// YAMLDocument 是 YAML 导出的顶层结构
+type YAMLDocument struct {
+	Zones []YAMLZone `yaml:"zones"`
+}
+
+type YAMLZone struct {
+	Account string       `yaml:"account,omitempty"`
+	Zone    string       `yaml:"zone"`
+	Records []YAMLRecord `yaml:"records"`
+}
+
+type YAMLRecord struct {
+	ID       string   `yaml:"id,omitempty"`
+	Type     string   `yaml:"type"`
+	Name     string   `yaml:"name"`
+	Content  string   `yaml:"content"`
+	Proxied  bool     `yaml:"proxied"`
+	TTL      int      `yaml:"ttl,omitempty"`
+	Priority *uint16  `yaml:"priority,omitempty"`
+	Comment  string   `yaml:"comment,omitempty"`
+	Tags     []string `yaml:"tags,omitempty"`
+}
+
+func (YAMLExporter) Export(w io.Writer, zones []Zone) error {
+	doc := YAMLDocument{Zones: make([]YAMLZone, 0, len(zones))}
+	for _, z := range zones {
+		yz := YAMLZone{Account: z.AccountLabel, Zone: z.Detail.Name, Records: []YAMLRecord{}}
+		for _, r := range z.Records {
+			yz.Records = append(yz.Records, YAMLRecord{
+				ID:       r.ID,
+				Type:     strings.ToUpper(r.Type),
+				Name:     r.Name,
+				Content:  r.Content,
+				Proxied:  proxied(r),
+				TTL:      r.TTL,
+				Priority: r.Priority,
+				Comment:  r.Comment,
+				Tags:     r.Tags,
+			})
+		}
+		doc.Zones = append(doc.Zones, yz)
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,327 @@
+// Package dnsfilter 实现 /csv、/dns、/record 共用的解析记录过滤表达式。
+//
+// 表达式由空格分隔的条件组成，条件之间为 AND 关系；同一条件内用逗号分隔多个取值表示 OR。
+// 条件前加 ! 表示取反。支持的条件：
+//
+//	type:A,CNAME            记录类型
+//	proxied:yes|no          是否代理（也接受 true/false、是/否）
+//	content:*.elb.amazonaws.com   内容 glob（* 匹配任意字符，? 匹配单个字符）
+//	content:/^10\./         内容正则（用 / 包裹，忽略大小写）
+//	name:example.com        名称等于该值或以 .<值> 结尾
+//	ttl:300 | ttl:auto | ttl:>=300 | ttl:<3600 | ttl:60-3600
+//	status:active,pending   Zone 状态
+package dnsfilter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"DomainC/cfclient"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Filter 是解析后的过滤表达式，零值匹配全部记录
+type Filter struct {
+	terms []term
+	raw   []string
+}
+
+type term struct {
+	key    string
+	negate bool
+	match  func(r cloudflare.DNSRecord, zone cfclient.ZoneDetail) bool
+}
+
+// Help 是展示给用户的过滤语法说明
+const Help = "type:A,CNAME  记录类型\n" +
+	"proxied:yes|no  是否代理\n" +
+	"content:*.elb.amazonaws.com  内容通配符，或 content:/正则/\n" +
+	"name:example.com  名称后缀\n" +
+	"ttl:300 | ttl:auto | ttl:>=300 | ttl:60-3600  TTL\n" +
+	"status:active,pending  Zone 状态\n" +
+	"条件前加 ! 表示取反，如 !proxied:yes\n"
+
+// Keys 是支持的条件名
+var Keys = []string{"type", "proxied", "content", "name", "ttl", "status"}
+
+// Parse 解析以空格分隔的表达式
+func Parse(expr string) (*Filter, error) {
+	return ParseArgs(strings.Fields(expr))
+}
+
+// ParseArgs 解析已按空格拆分的命令参数
+func ParseArgs(args []string) (*Filter, error) {
+	f := &Filter{}
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			continue
+		}
+		t, err := parseTerm(arg)
+		if err != nil {
+			return nil, err
+		}
+		f.terms = append(f.terms, t)
+		f.raw = append(f.raw, arg)
+	}
+	return f, nil
+}
+
+// IsTerm 判断参数是否形如过滤条件（key:value），用于区分普通参数
+func IsTerm(arg string) bool {
+	key, _, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(arg), "!"), ":")
+	if !ok {
+		return false
+	}
+	key = strings.ToLower(key)
+	for _, k := range Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Empty 表示没有任何条件
+func (f *Filter) Empty() bool {
+	return f == nil || len(f.terms) == 0
+}
+
+// String 返回原始表达式，便于回显
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	return strings.Join(f.raw, " ")
+}
+
+// Match 判断记录是否满足全部条件
+func (f *Filter) Match(r cloudflare.DNSRecord, zone cfclient.ZoneDetail) bool {
+	if f == nil {
+		return true
+	}
+	for _, t := range f.terms {
+		if t.match(r, zone) == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchZone 只检查 Zone 级别的条件（status），用于在拉取记录前跳过不相关的 Zone
+func (f *Filter) MatchZone(zone cfclient.ZoneDetail) bool {
+	if f == nil {
+		return true
+	}
+	for _, t := range f.terms {
+		if t.key == "status" && t.match(cloudflare.DNSRecord{}, zone) == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply 返回满足条件的记录
+func (f *Filter) Apply(records []cloudflare.DNSRecord, zone cfclient.ZoneDetail) []cloudflare.DNSRecord {
+	if f.Empty() {
+		return records
+	}
+	out := make([]cloudflare.DNSRecord, 0, len(records))
+	for _, r := range records {
+		if f.Match(r, zone) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func parseTerm(arg string) (term, error) {
+	t := term{}
+	s := arg
+	if strings.HasPrefix(s, "!") {
+		t.negate = true
+		s = s[1:]
+	}
+	key, value, ok := strings.Cut(s, ":")
+	if !ok {
+		return t, fmt.Errorf("过滤条件 %q 格式错误，应为 key:value", arg)
+	}
+	t.key = strings.ToLower(strings.TrimSpace(key))
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return t, fmt.Errorf("过滤条件 %q 缺少取值", arg)
+	}
+
+	var err error
+	switch t.key {
+	case "type":
+		t.match = matchType(value)
+	case "proxied":
+		t.match, err = matchProxied(value)
+	case "content":
+		t.match, err = matchContent(value)
+	case "name":
+		t.match = matchName(value)
+	case "ttl":
+		t.match, err = matchTTL(value)
+	case "status":
+		t.match = matchStatus(value)
+	default:
+		return t, fmt.Errorf("未知的过滤条件 %q（支持: %s）", key, strings.Join(Keys, ", "))
+	}
+	if err != nil {
+		return t, fmt.Errorf("过滤条件 %q 无效: %v", arg, err)
+	}
+	return t, nil
+}
+
+func splitValues(value string) []string {
+	var out []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func matchType(value string) func(cloudflare.DNSRecord, cfclient.ZoneDetail) bool {
+	types := map[string]bool{}
+	for _, v := range splitValues(value) {
+		types[strings.ToUpper(v)] = true
+	}
+	return func(r cloudflare.DNSRecord, _ cfclient.ZoneDetail) bool {
+		return types[strings.ToUpper(r.Type)]
+	}
+}
+
+func matchProxied(value string) (func(cloudflare.DNSRecord, cfclient.ZoneDetail) bool, error) {
+	var want bool
+	switch strings.ToLower(value) {
+	case "yes", "y", "true", "1", "on", "是":
+		want = true
+	case "no", "n", "false", "0", "off", "否":
+		want = false
+	default:
+		return nil, fmt.Errorf("取值应为 yes 或 no")
+	}
+	return func(r cloudflare.DNSRecord, _ cfclient.ZoneDetail) bool {
+		return (r.Proxied != nil && *r.Proxied) == want
+	}, nil
+}
+
+func matchContent(value string) (func(cloudflare.DNSRecord, cfclient.ZoneDetail) bool, error) {
+	var re *regexp.Regexp
+	var err error
+	if len(value) >= 2 && strings.HasPrefix(value, "/") && strings.HasSuffix(value, "/") {
+		re, err = regexp.Compile("(?i)" + value[1:len(value)-1])
+		if err != nil {
+			return nil, fmt.Errorf("正则表达式错误: %v", err)
+		}
+	} else {
+		var parts []string
+		for _, v := range splitValues(value) {
+			parts = append(parts, globToRegexp(strings.TrimSuffix(v, ".")))
+		}
+		re = regexp.MustCompile("(?i)^(?:" + strings.Join(parts, "|") + ")$")
+	}
+	return func(r cloudflare.DNSRecord, _ cfclient.ZoneDetail) bool {
+		return re.MatchString(strings.TrimSuffix(strings.TrimSpace(r.Content), "."))
+	}, nil
+}
+
+// globToRegexp 把 * / ? 通配符转换为正则，其它字符按字面匹配
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for _, ch := range glob {
+		switch ch {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	return sb.String()
+}
+
+func matchName(value string) func(cloudflare.DNSRecord, cfclient.ZoneDetail) bool {
+	var suffixes []string
+	for _, v := range splitValues(value) {
+		v = strings.ToLower(strings.TrimSuffix(v, "."))
+		v = strings.TrimPrefix(strings.TrimPrefix(v, "*"), ".")
+		suffixes = append(suffixes, v)
+	}
+	return func(r cloudflare.DNSRecord, _ cfclient.ZoneDetail) bool {
+		name := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(r.Name), "."))
+		for _, s := range suffixes {
+			if name == s || strings.HasSuffix(name, "."+s) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func matchTTL(value string) (func(cloudflare.DNSRecord, cfclient.ZoneDetail) bool, error) {
+	lo, hi := 0, int(^uint32(0))
+	parse := func(s string) (int, error) {
+		s = strings.TrimSpace(s)
+		if strings.EqualFold(s, "auto") {
+			return 1, nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("TTL %q 不是有效数字", s)
+		}
+		return n, nil
+	}
+
+	var err error
+	switch {
+	case strings.HasPrefix(value, ">="):
+		lo, err = parse(value[2:])
+	case strings.HasPrefix(value, "<="):
+		hi, err = parse(value[2:])
+	case strings.HasPrefix(value, ">"):
+		lo, err = parse(value[1:])
+		lo++
+	case strings.HasPrefix(value, "<"):
+		hi, err = parse(value[1:])
+		hi--
+	case strings.Contains(value, "-"):
+		from, to, _ := strings.Cut(value, "-")
+		if lo, err = parse(from); err == nil {
+			hi, err = parse(to)
+		}
+		if err == nil && lo > hi {
+			err = fmt.Errorf("区间下限大于上限")
+		}
+	default:
+		lo, err = parse(value)
+		hi = lo
+	}
+	if err != nil {
+		return nil, err
+	}
+	return func(r cloudflare.DNSRecord, _ cfclient.ZoneDetail) bool {
+		return r.TTL >= lo && r.TTL <= hi
+	}, nil
+}
+
+func matchStatus(value string) func(cloudflare.DNSRecord, cfclient.ZoneDetail) bool {
+	statuses := map[string]bool{}
+	for _, v := range splitValues(value) {
+		statuses[strings.ToLower(v)] = true
+	}
+	return func(_ cloudflare.DNSRecord, zone cfclient.ZoneDetail) bool {
+		if statuses["paused"] && zone.Paused {
+			return true
+		}
+		return statuses[strings.ToLower(zone.Status)]
+	}
+}
@@ -0,0 +1,107 @@
+package dnsfilter
+
+import (
+	"strings"
+	"testing"
+
+	"DomainC/cfclient"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+func rec(typ, name, content string, proxied bool, ttl int) cloudflare.DNSRecord {
+	return cloudflare.DNSRecord{Type: typ, Name: name, Content: content, Proxied: &proxied, TTL: ttl}
+}
+
+var active = cfclient.ZoneDetail{Name: "example.com", Status: "active"}
+
+func TestParseErrors(t *testing.T) {
+	cases := []string{
+		"type",          // 缺少冒号
+		"type:",         // 缺少取值
+		"color:red",     // 未知条件
+		"proxied:maybe", // 非布尔
+		"ttl:abc",       // 非数字
+		"ttl:3600-300",  // 区间颠倒
+		"content:/[a-/", // 正则错误
+	}
+	for _, c := range cases {
+		if _, err := Parse(c); err == nil {
+			t.Errorf("expected error for %q", c)
+		}
+	}
+}
+
+func TestEmptyFilterMatchesAll(t *testing.T) {
+	f, err := Parse("   ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !f.Empty() || !f.Match(rec("A", "example.com", "192.0.2.1", false, 1), active) {
+		t.Fatalf("empty filter should match everything")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		expr string
+		r    cloudflare.DNSRecord
+		want bool
+	}{
+		{"type:A proxied:no", rec("A", "a.example.com", "192.0.2.1", false, 1), true},
+		{"type:A proxied:no", rec("A", "a.example.com", "192.0.2.1", true, 1), false},
+		{"type:a,cname", rec("CNAME", "a.example.com", "x", false, 1), true},
+		{"content:*.elb.amazonaws.com", rec("CNAME", "a.example.com", "lb-1.us-east-1.elb.amazonaws.com.", false, 1), true},
+		{"content:*.elb.amazonaws.com", rec("CNAME", "a.example.com", "elb.amazonaws.com.evil.net", false, 1), false},
+		{"content:192.0.2.?", rec("A", "a.example.com", "192.0.2.7", false, 1), true},
+		{`content:/^10\./`, rec("A", "a.example.com", "10.1.2.3", false, 1), true},
+		{`content:/^10\./`, rec("A", "a.example.com", "110.1.2.3", false, 1), false},
+		{"name:example.com", rec("A", "example.com", "x", false, 1), true},
+		{"name:*.dev.example.com", rec("A", "api.dev.example.com", "x", false, 1), true},
+		{"name:dev.example.com", rec("A", "notdev.example.com", "x", false, 1), false},
+		{"ttl:auto", rec("A", "a.example.com", "x", false, 1), true},
+		{"ttl:>=300", rec("A", "a.example.com", "x", false, 300), true},
+		{"ttl:>300", rec("A", "a.example.com", "x", false, 300), false},
+		{"ttl:<300", rec("A", "a.example.com", "x", false, 1), true},
+		{"ttl:60-3600", rec("A", "a.example.com", "x", false, 7200), false},
+		{"status:active", rec("A", "a.example.com", "x", false, 1), true},
+		{"status:pending", rec("A", "a.example.com", "x", false, 1), false},
+		{"!proxied:yes type:A", rec("A", "a.example.com", "x", false, 1), true},
+		{"!type:TXT", rec("TXT", "a.example.com", "x", false, 1), false},
+	}
+	for _, c := range cases {
+		f, err := Parse(c.expr)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", c.expr, err)
+		}
+		if got := f.Match(c.r, active); got != c.want {
+			t.Errorf("%q on %s %s %s: got %v, want %v", c.expr, c.r.Type, c.r.Name, c.r.Content, got, c.want)
+		}
+	}
+}
+
+func TestMatchZoneOnlyChecksStatus(t *testing.T) {
+	f, err := Parse("type:A status:active,paused")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !f.MatchZone(active) {
+		t.Fatalf("active zone should match")
+	}
+	if !f.MatchZone(cfclient.ZoneDetail{Status: "active", Paused: true}) {
+		t.Fatalf("paused zone should match")
+	}
+	if f.MatchZone(cfclient.ZoneDetail{Status: "pending"}) {
+		t.Fatalf("pending zone should not match")
+	}
+}
+
+func TestIsTermAndString(t *testing.T) {
+	if !IsTerm("type:A") || !IsTerm("!proxied:no") || IsTerm("192.0.2.1") || IsTerm("https://example.com") {
+		t.Fatalf("IsTerm misclassified input")
+	}
+	f, _ := ParseArgs([]string{"type:A", "ttl:300"})
+	if !strings.Contains(f.String(), "type:A ttl:300") {
+		t.Fatalf("unexpected String(): %q", f.String())
+	}
+}
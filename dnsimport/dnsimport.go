@@ -0,0 +1,260 @@
+package dnsimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/dnsplan"
+
+	"github.com/miekg/dns"
+)
+
+// Format 表示上传文件的格式
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatBIND Format = "bind"
+)
+
+// ErrUnknownFormat 无法识别文件格式时返回
+var ErrUnknownFormat = errors.New("无法识别的文件格式（支持 CSV 或 BIND zone 文件）")
+
+// ZoneRecords 是从文件解析出的单个 Zone 的期望记录
+type ZoneRecords struct {
+	AccountLabel string // CSV 中的所属账户，可为空
+	Zone         string
+	Records      []dnsplan.Record
+	Skipped      []string // 不支持或无法解析的行
+}
+
+// csvHeader 与 /csv 导出的列保持一致；末列优先级可省略（旧版导出没有这一列）
+var csvHeader = []string{"所属账户", "主域名", "子域名", "解析类型", "解析地址", "是否代理", "Zone状态", "是否暂停", "优先级"}
+
+// csvPriorityCol 是优先级列的下标
+const csvPriorityCol = 8
+
+// supportedTypes 可以直接用 content 字段写入的记录类型
+var supportedTypes = map[string]bool{
+	"A": true, "AAAA": true, "CNAME": true, "MX": true, "TXT": true, "NS": true,
+}
+
+// DetectFormat 根据文件名和内容判断格式
+func DetectFormat(filename string, data []byte) (Format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".zone", ".bind", ".db":
+		return FormatBIND, nil
+	}
+	head := string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	if strings.HasPrefix(head, csvHeader[0]) {
+		return FormatCSV, nil
+	}
+	if strings.Contains(head, "$ORIGIN") || strings.Contains(head, " IN ") || strings.Contains(head, "\tIN\t") {
+		return FormatBIND, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// ParseCSV 解析 /csv 导出格式，按 (账号, 主域名) 分组。
+// MX 记录的优先级取自优先级列，该列为空时也接受 "10 mail.example.com" 形式的解析地址；
+// 两者都没有的 MX 行会被跳过。
+func ParseCSV(r io.Reader) ([]ZoneRecords, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取 CSV 失败: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("解析 CSV 失败: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("CSV 为空")
+	}
+	if strings.TrimSpace(rows[0][0]) == csvHeader[0] {
+		rows = rows[1:]
+	}
+
+	var out []ZoneRecords
+	index := map[string]int{}
+	for i, row := range rows {
+		line := i + 2
+		if len(row) < 6 {
+			return nil, fmt.Errorf("第 %d 行列数不足（需要至少 6 列）", line)
+		}
+		account := strings.TrimSpace(row[0])
+		zone := normalize(row[1])
+		if zone == "" {
+			continue
+		}
+		key := strings.ToLower(account) + "|" + zone
+		idx, ok := index[key]
+		if !ok {
+			out = append(out, ZoneRecords{AccountLabel: account, Zone: zone})
+			idx = len(out) - 1
+			index[key] = idx
+		}
+
+		typ := strings.ToUpper(strings.TrimSpace(row[3]))
+		if typ == "" {
+			// 空 zone 占位行
+			continue
+		}
+		if !supportedTypes[typ] {
+			out[idx].Skipped = append(out[idx].Skipped, fmt.Sprintf("第 %d 行: 不支持的类型 %s", line, typ))
+			continue
+		}
+		name := normalize(row[2])
+		if name == "" {
+			name = zone
+		}
+		rec := dnsplan.Record{
+			Type:    typ,
+			Name:    cfclient.RecordFQDN(name, zone),
+			Content: strings.TrimSpace(row[4]),
+			Proxied: parseBool(row[5]),
+		}
+		if typ == "MX" {
+			if err := csvMXPriority(&rec, row); err != nil {
+				out[idx].Skipped = append(out[idx].Skipped, fmt.Sprintf("第 %d 行: %v", line, err))
+				continue
+			}
+		}
+		out[idx].Records = append(out[idx].Records, rec)
+	}
+	return out, nil
+}
+
+// csvMXPriority 从优先级列或 "优先级 主机" 形式的解析地址中取出 MX 优先级
+func csvMXPriority(rec *dnsplan.Record, row []string) error {
+	raw := ""
+	if len(row) > csvPriorityCol {
+		raw = strings.TrimSpace(row[csvPriorityCol])
+	}
+	if raw == "" {
+		if fields := strings.Fields(rec.Content); len(fields) == 2 {
+			raw, rec.Content = fields[0], fields[1]
+		}
+	}
+	if raw == "" {
+		return fmt.Errorf("MX 记录缺少优先级")
+	}
+	pref, err := strconv.ParseUint(raw, 10, 16)
+	if err != nil {
+		return fmt.Errorf("MX 优先级无效: %s", raw)
+	}
+	p := uint16(pref)
+	rec.Priority = &p
+	rec.Content = normalize(rec.Content)
+	return nil
+}
+
+// ParseBIND 解析标准 BIND zone 文件。origin 为空时使用文件中的 $ORIGIN 或 SOA 所有者。
+// 支持 Cloudflare 导出的 "cf_tags=cf-proxied:true" 注释。
+func ParseBIND(r io.Reader, origin string) (ZoneRecords, error) {
+	origin = normalize(origin)
+	zp := dns.NewZoneParser(r, dns.Fqdn(origin), "")
+	zp.SetIncludeAllowed(false)
+
+	var zr ZoneRecords
+	if origin != "" {
+		zr.Zone = origin
+	}
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		hdr := rr.Header()
+		owner := normalize(hdr.Name)
+
+		if soa, isSOA := rr.(*dns.SOA); isSOA {
+			if zr.Zone == "" {
+				zr.Zone = normalize(soa.Hdr.Name)
+			}
+			continue
+		}
+		if zr.Zone == "" {
+			return zr, fmt.Errorf("zone 文件缺少 $ORIGIN/SOA，请在上传说明中填写域名")
+		}
+
+		typ := dns.TypeToString[hdr.Rrtype]
+		if !supportedTypes[typ] {
+			zr.Skipped = append(zr.Skipped, fmt.Sprintf("%s %s: 不支持的类型", owner, typ))
+			continue
+		}
+		// 根域 NS 由 Cloudflare 托管，不导入
+		if typ == "NS" && owner == zr.Zone {
+			continue
+		}
+
+		rec := dnsplan.Record{
+			Type:    typ,
+			Name:    owner,
+			TTL:     int(hdr.Ttl),
+			Proxied: strings.Contains(zp.Comment(), "cf-proxied:true"),
+		}
+		switch v := rr.(type) {
+		case *dns.A:
+			rec.Content = v.A.String()
+		case *dns.AAAA:
+			rec.Content = v.AAAA.String()
+		case *dns.CNAME:
+			rec.Content = normalize(v.Target)
+		case *dns.NS:
+			rec.Content = normalize(v.Ns)
+		case *dns.MX:
+			pref := v.Preference
+			rec.Content = normalize(v.Mx)
+			rec.Priority = &pref
+		case *dns.TXT:
+			rec.Content = strings.Join(v.Txt, "")
+		}
+		// 代理记录在 Cloudflare 中 TTL 固定为 auto
+		if rec.Proxied {
+			rec.TTL = 1
+		}
+		zr.Records = append(zr.Records, rec)
+	}
+	if err := zp.Err(); err != nil {
+		return zr, fmt.Errorf("解析 zone 文件失败: %w", err)
+	}
+	if zr.Zone == "" {
+		return zr, fmt.Errorf("zone 文件缺少 $ORIGIN/SOA，请在上传说明中填写域名")
+	}
+	return zr, nil
+}
+
+// ZoneFromFilename 尝试从 example.com.zone / example.com.txt 等文件名推断域名
+func ZoneFromFilename(filename string) string {
+	base := filepath.Base(filename)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	if strings.Count(base, ".") < 1 {
+		return ""
+	}
+	return normalize(base)
+}
+
+func parseBool(s string) bool {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "是" || s == "yes" || s == "y" {
+		return true
+	}
+	b, _ := strconv.ParseBool(s)
+	return b
+}
+
+func normalize(s string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), ".")
+}
@@ -0,0 +1,125 @@
+package dnsimport
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCSVGroupsByZone(t *testing.T) {
+	data := "\xef\xbb\xbf所属账户,主域名,子域名,解析类型,解析地址,是否代理,Zone状态,是否暂停\n" +
+		"acc,example.com,example.com,A,192.0.2.1,是,active,否\n" +
+		"acc,example.com,www.example.com,CNAME,example.com,否,active,否\n" +
+		"acc,example.com,srv.example.com,SRV,x,否,active,否\n" +
+		"acc,example.org,,,,,active,否\n"
+
+	zones, err := ParseCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(zones) != 2 {
+		t.Fatalf("expected 2 zones, got %d", len(zones))
+	}
+	z := zones[0]
+	if z.AccountLabel != "acc" || z.Zone != "example.com" {
+		t.Fatalf("unexpected zone header: %+v", z)
+	}
+	if len(z.Records) != 2 {
+		t.Fatalf("expected 2 records, got %+v", z.Records)
+	}
+	if !z.Records[0].Proxied || z.Records[0].Name != "example.com" {
+		t.Fatalf("unexpected first record: %+v", z.Records[0])
+	}
+	if len(z.Skipped) != 1 {
+		t.Fatalf("expected SRV row to be skipped, got %v", z.Skipped)
+	}
+	if len(zones[1].Records) != 0 {
+		t.Fatalf("expected empty zone placeholder, got %+v", zones[1].Records)
+	}
+}
+
+func TestParseCSVMXPriority(t *testing.T) {
+	data := "所属账户,主域名,子域名,解析类型,解析地址,是否代理,Zone状态,是否暂停,优先级\n" +
+		"acc,example.com,example.com,MX,mx1.example.com.,否,active,否,10\n" +
+		"acc,example.com,example.com,MX,20 mx2.example.com,否,active,否,\n" +
+		"acc,example.com,example.com,MX,mx3.example.com,否,active,否\n" +
+		"acc,example.com,example.com,MX,mx4.example.com,否,active,否,high\n"
+
+	zones, err := ParseCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	z := zones[0]
+	if len(z.Records) != 2 {
+		t.Fatalf("expected 2 MX records, got %+v", z.Records)
+	}
+	for i, want := range []struct {
+		content string
+		prio    uint16
+	}{{"mx1.example.com", 10}, {"mx2.example.com", 20}} {
+		r := z.Records[i]
+		if r.Content != want.content || r.Priority == nil || *r.Priority != want.prio {
+			t.Fatalf("record %d: got %+v", i, r)
+		}
+	}
+	if len(z.Skipped) != 2 || !strings.Contains(z.Skipped[0], "缺少优先级") || !strings.Contains(z.Skipped[1], "优先级无效") {
+		t.Fatalf("expected MX rows without valid priority to be skipped, got %v", z.Skipped)
+	}
+}
+
+func TestParseBIND(t *testing.T) {
+	data := `$ORIGIN example.com.
+$TTL 3600
+@	IN	SOA	ns1.example.com. admin.example.com. 1 7200 3600 1209600 3600
+@	IN	NS	ns1.example.com.
+@	300	IN	A	192.0.2.1 ; cf_tags=cf-proxied:true
+www	IN	CNAME	example.com.
+@	IN	MX	10 mail.example.com.
+@	IN	TXT	"v=spf1 " "-all"
+sub	IN	NS	ns.other.net.
+`
+	zr, err := ParseBIND(strings.NewReader(data), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if zr.Zone != "example.com" {
+		t.Fatalf("expected zone from $ORIGIN, got %q", zr.Zone)
+	}
+	if len(zr.Records) != 5 {
+		t.Fatalf("expected 5 records (apex NS skipped), got %+v", zr.Records)
+	}
+	a := zr.Records[0]
+	if a.Type != "A" || !a.Proxied || a.TTL != 1 {
+		t.Fatalf("expected proxied A with auto TTL, got %+v", a)
+	}
+	if cname := zr.Records[1]; cname.Name != "www.example.com" || cname.Content != "example.com" {
+		t.Fatalf("unexpected CNAME: %+v", cname)
+	}
+	if mx := zr.Records[2]; mx.Priority == nil || *mx.Priority != 10 || mx.Content != "mail.example.com" {
+		t.Fatalf("unexpected MX: %+v", mx)
+	}
+	if txt := zr.Records[3]; txt.Content != "v=spf1 -all" {
+		t.Fatalf("unexpected TXT: %+v", txt)
+	}
+}
+
+func TestParseBINDRequiresOrigin(t *testing.T) {
+	if _, err := ParseBIND(strings.NewReader("www 300 IN A 192.0.2.1\n"), ""); err == nil {
+		t.Fatalf("expected error without origin")
+	}
+	zr, err := ParseBIND(strings.NewReader("www 300 IN A 192.0.2.1\n"), "example.com")
+	if err != nil || len(zr.Records) != 1 || zr.Records[0].Name != "www.example.com" {
+		t.Fatalf("unexpected result: %+v, %v", zr, err)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	if f, _ := DetectFormat("export.csv", nil); f != FormatCSV {
+		t.Fatalf("expected csv, got %q", f)
+	}
+	if f, _ := DetectFormat("example.com.txt", []byte("$ORIGIN example.com.\n")); f != FormatBIND {
+		t.Fatalf("expected bind, got %q", f)
+	}
+	if _, err := DetectFormat("notes.txt", []byte("hello")); err != ErrUnknownFormat {
+		t.Fatalf("expected ErrUnknownFormat, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package dnslint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+// Collect 读取待检查的 Zone。zone 为空时读取账号下全部 Zone；
+// 否则在每个账号中查找该 Zone，以便发现跨账号重复。
+func Collect(ctx context.Context, client cfclient.Client, accounts []config.CF, zone string) ([]Zone, []error) {
+	zone = strings.ToLower(strings.TrimSpace(zone))
+	var zones []Zone
+	var errs []error
+	for _, acc := range accounts {
+		names := []string{zone}
+		if zone == "" {
+			list, err := client.ListZones(ctx, acc)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("列出账号 %s 的域名失败: %v", acc.Label, err))
+				continue
+			}
+			names = names[:0]
+			for _, z := range list {
+				names = append(names, z.Name)
+			}
+		}
+		for _, name := range names {
+			records, err := client.ListDNSRecords(ctx, acc, name)
+			if err != nil {
+				if zone != "" && errors.Is(err, cfclient.ErrZoneNotFound) {
+					continue
+				}
+				errs = append(errs, fmt.Errorf("获取 %s(%s) 的解析失败: %v", name, acc.Label, err))
+				continue
+			}
+			zones = append(zones, Zone{Account: acc.Label, Name: name, Records: records})
+		}
+	}
+	return zones, errs
+}
@@ -0,0 +1,145 @@
+// Package dnslint 对 ListDNSRecords 的结果执行一组可开关的检查规则，找出常见的解析配置错误。
+package dnslint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"DomainC/severity"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Severity 是问题的严重程度
+type Severity = severity.Level
+
+const (
+	SeverityInfo    = severity.Info
+	SeverityWarning = severity.Warning
+	SeverityError   = severity.Error
+)
+
+// ParseSeverity 解析 info/warning/error
+func ParseSeverity(s string) (Severity, error) {
+	return severity.Parse(s)
+}
+
+// Zone 是一个待检查的 Zone
+type Zone struct {
+	Account string
+	Name    string
+	Records []cloudflare.DNSRecord
+}
+
+// Issue 是一条检查结果，Rule 与 Severity 由 Linter 根据规则填写
+type Issue struct {
+	Rule     string
+	Severity Severity
+	Account  string
+	Zone     string
+	Name     string
+	Type     string
+	Content  string
+	Message  string
+}
+
+func (i Issue) String() string {
+	target := i.Name
+	if i.Type != "" {
+		target = fmt.Sprintf("%s %s → %s", i.Type, i.Name, i.Content)
+	}
+	return fmt.Sprintf("%s [%s] %s: %s", i.Severity.Icon(), i.Rule, target, i.Message)
+}
+
+// Rule 是一条检查规则。Check 接收全部待检查的 Zone，以便实现跨 Zone / 跨账号的规则。
+type Rule struct {
+	ID          string
+	Description string
+	Severity    Severity
+	// DefaultOff 为 true 时需在配置中显式开启
+	DefaultOff bool
+	Check      func(zones []Zone) []Issue
+}
+
+// Linter 执行启用的规则
+type Linter struct {
+	Rules []Rule
+}
+
+// New 返回启用了指定规则的 Linter。toggles 的 key 为规则 ID，未出现的规则按默认开关处理。
+func New(toggles map[string]bool) (*Linter, error) {
+	known := make(map[string]bool)
+	for _, r := range BuiltinRules() {
+		known[r.ID] = true
+	}
+	for id := range toggles {
+		if !known[id] {
+			return nil, fmt.Errorf("未知的检查规则: %s", id)
+		}
+	}
+	l := &Linter{}
+	for _, r := range BuiltinRules() {
+		enabled, ok := toggles[r.ID]
+		if !ok {
+			enabled = !r.DefaultOff
+		}
+		if enabled {
+			l.Rules = append(l.Rules, r)
+		}
+	}
+	return l, nil
+}
+
+// Lint 对给定 Zone 执行全部规则，结果按严重程度降序排列
+func (l *Linter) Lint(zones []Zone) []Issue {
+	var issues []Issue
+	for _, r := range l.Rules {
+		for _, is := range r.Check(zones) {
+			is.Rule = r.ID
+			is.Severity = r.Severity
+			issues = append(issues, is)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Severity != issues[j].Severity {
+			return issues[i].Severity > issues[j].Severity
+		}
+		if issues[i].Zone != issues[j].Zone {
+			return issues[i].Zone < issues[j].Zone
+		}
+		return issues[i].Name < issues[j].Name
+	})
+	return issues
+}
+
+// Filter 返回涉及指定名称与类型的问题，类型为空时只按名称过滤
+func Filter(issues []Issue, name, typ string) []Issue {
+	name = canonical(name)
+	var out []Issue
+	for _, is := range issues {
+		if canonical(is.Name) != name {
+			continue
+		}
+		if typ != "" && is.Type != "" && !strings.EqualFold(is.Type, typ) {
+			continue
+		}
+		out = append(out, is)
+	}
+	return out
+}
+
+// AtLeast 返回严重程度不低于 min 的问题
+func AtLeast(issues []Issue, min Severity) []Issue {
+	var out []Issue
+	for _, is := range issues {
+		if is.Severity >= min {
+			out = append(out, is)
+		}
+	}
+	return out
+}
+
+func canonical(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
@@ -0,0 +1,84 @@
+package dnslint
+
+import (
+	"testing"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func rec(typ, name, content string, proxied bool, ttl int) cloudflare.DNSRecord {
+	return cloudflare.DNSRecord{Type: typ, Name: name, Content: content, Proxied: boolPtr(proxied), TTL: ttl}
+}
+
+func TestBuiltinRules(t *testing.T) {
+	zones := []Zone{
+		{Account: "a", Name: "example.com", Records: []cloudflare.DNSRecord{
+			rec("CNAME", "example.com", "lb.example.net", false, 300),
+			rec("A", "intranet.example.com", "10.0.0.5", true, 1),
+			rec("A", "public.example.com", "192.0.2.1", true, 300),
+			rec("A", "dns-only.example.com", "10.0.0.6", false, 300),
+			rec("MX", "example.com", "mail.example.com", false, 3600),
+			rec("CNAME", "mail.example.com", "ghs.googlehosted.com", false, 300),
+		}},
+		{Account: "a", Name: "good.com", Records: []cloudflare.DNSRecord{
+			rec("A", "good.com", "192.0.2.2", true, 1),
+			rec("CNAME", "www.good.com", "good.com", true, 1),
+		}},
+		{Account: "b", Name: "good.com", Records: []cloudflare.DNSRecord{
+			rec("A", "good.com", "192.0.2.2", true, 1),
+			rec("A", "www.good.com", "192.0.2.3", true, 1),
+		}},
+	}
+
+	linter, err := New(nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	issues := linter.Lint(zones)
+
+	got := map[string][]string{}
+	for _, is := range issues {
+		got[is.Rule] = append(got[is.Rule], is.Name)
+	}
+	want := map[string][]string{
+		"proxied-private-ip":      {"intranet.example.com"},
+		"apex-cname":              {"example.com"},
+		"mx-cname":                {"example.com"},
+		"missing-www":             {"www.example.com"},
+		"cross-account-duplicate": {"good.com"},
+	}
+	for rule, names := range want {
+		if len(got[rule]) != len(names) || got[rule][0] != names[0] {
+			t.Fatalf("rule %s: got %v, want %v (all: %v)", rule, got[rule], names, issues)
+		}
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("unexpected extra issues: %v", issues)
+	}
+	if issues[0].Severity != SeverityError {
+		t.Fatalf("issues should be sorted by severity, got %v first", issues[0])
+	}
+}
+
+func TestTogglesAndFilter(t *testing.T) {
+	if _, err := New(map[string]bool{"no-such-rule": true}); err == nil {
+		t.Fatalf("expected error for unknown rule")
+	}
+	linter, _ := New(map[string]bool{"missing-www": false})
+	zones := []Zone{{Account: "a", Name: "example.com", Records: []cloudflare.DNSRecord{
+		rec("A", "example.com", "192.0.2.1", true, 300),
+		rec("A", "api.example.com", "127.0.0.1", true, 1),
+	}}}
+	issues := linter.Lint(zones)
+	if len(issues) != 1 || issues[0].Rule != "proxied-private-ip" {
+		t.Fatalf("disabled rules should not run: %v", issues)
+	}
+	if len(Filter(issues, "API.example.com.", "A")) != 1 || len(Filter(issues, "example.com", "")) != 0 {
+		t.Fatalf("unexpected filter result")
+	}
+	if len(AtLeast(issues, SeverityError)) != 1 || len(AtLeast(issues, SeverityError+1)) != 0 {
+		t.Fatalf("unexpected AtLeast result")
+	}
+}
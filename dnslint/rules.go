@@ -0,0 +1,151 @@
+package dnslint
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// BuiltinRules 返回内置规则
+func BuiltinRules() []Rule {
+	return []Rule{
+		{ID: "proxied-private-ip", Severity: SeverityError, Description: "开启代理的 A/AAAA 指向内网、回环或保留地址，Cloudflare 无法回源", Check: checkProxiedPrivateIP},
+		{ID: "apex-cname", Severity: SeverityWarning, Description: "根域使用未代理的 CNAME，依赖 CNAME flattening，导出到其它 DNS 服务商时无效", Check: checkApexCNAME},
+		{ID: "cross-account-duplicate", Severity: SeverityWarning, Description: "同一记录出现在多个账号中，只有 NS 指向的账号生效", Check: checkCrossAccountDuplicate},
+		{ID: "mx-cname", Severity: SeverityError, Description: "MX 指向 CNAME，违反 RFC 2181，部分邮件服务器会拒收", Check: checkMXCNAME},
+		{ID: "missing-www", Severity: SeverityInfo, Description: "根域有解析但缺少 www 记录", Check: checkMissingWWW},
+	}
+}
+
+func proxied(r cloudflare.DNSRecord) bool {
+	return r.Proxied != nil && *r.Proxied
+}
+
+func issueFor(z Zone, r cloudflare.DNSRecord, msg string) Issue {
+	return Issue{Account: z.Account, Zone: z.Name, Name: r.Name, Type: r.Type, Content: r.Content, Message: msg}
+}
+
+func isApex(r cloudflare.DNSRecord, zone string) bool {
+	return canonical(r.Name) == canonical(zone)
+}
+
+func checkProxiedPrivateIP(zones []Zone) []Issue {
+	var out []Issue
+	for _, z := range zones {
+		for _, r := range z.Records {
+			if !proxied(r) || (r.Type != "A" && r.Type != "AAAA") {
+				continue
+			}
+			ip := net.ParseIP(strings.TrimSpace(r.Content))
+			if ip == nil {
+				continue
+			}
+			if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() || isSharedAddress(ip) {
+				out = append(out, issueFor(z, r, "代理记录指向非公网地址，请关闭代理或改为公网源站"))
+			}
+		}
+	}
+	return out
+}
+
+// isSharedAddress 判断 100.64.0.0/10（运营商级 NAT）
+func isSharedAddress(ip net.IP) bool {
+	ip4 := ip.To4()
+	return ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64
+}
+
+func checkApexCNAME(zones []Zone) []Issue {
+	var out []Issue
+	for _, z := range zones {
+		for _, r := range z.Records {
+			if r.Type == "CNAME" && isApex(r, z.Name) && !proxied(r) {
+				out = append(out, issueFor(z, r, "根域 CNAME 依赖 Cloudflare 的 CNAME flattening，请确认已开启，或改为 A/AAAA、开启代理"))
+			}
+		}
+	}
+	return out
+}
+
+func checkCrossAccountDuplicate(zones []Zone) []Issue {
+	type seen struct {
+		accounts map[string]bool
+		zone     Zone
+		record   cloudflare.DNSRecord
+	}
+	index := make(map[string]*seen)
+	var keys []string
+	for _, z := range zones {
+		for _, r := range z.Records {
+			key := canonical(r.Name) + "|" + r.Type + "|" + strings.ToLower(strings.TrimSpace(r.Content))
+			s, ok := index[key]
+			if !ok {
+				s = &seen{accounts: map[string]bool{}, zone: z, record: r}
+				index[key] = s
+				keys = append(keys, key)
+			}
+			s.accounts[z.Account] = true
+		}
+	}
+	var out []Issue
+	for _, key := range keys {
+		s := index[key]
+		if len(s.accounts) < 2 {
+			continue
+		}
+		accounts := make([]string, 0, len(s.accounts))
+		for a := range s.accounts {
+			accounts = append(accounts, a)
+		}
+		sort.Strings(accounts)
+		is := issueFor(s.zone, s.record, "同一记录存在于账号 "+strings.Join(accounts, ", "))
+		is.Account = strings.Join(accounts, ",")
+		out = append(out, is)
+	}
+	return out
+}
+
+func checkMXCNAME(zones []Zone) []Issue {
+	cnames := make(map[string]string)
+	for _, z := range zones {
+		for _, r := range z.Records {
+			if r.Type == "CNAME" {
+				cnames[canonical(r.Name)] = r.Content
+			}
+		}
+	}
+	var out []Issue
+	for _, z := range zones {
+		for _, r := range z.Records {
+			if r.Type != "MX" {
+				continue
+			}
+			if target, ok := cnames[canonical(r.Content)]; ok {
+				out = append(out, issueFor(z, r, fmt.Sprintf("MX 目标 %s 是 CNAME（→ %s），请改为指向 A/AAAA 主机", r.Content, target)))
+			}
+		}
+	}
+	return out
+}
+
+func checkMissingWWW(zones []Zone) []Issue {
+	var out []Issue
+	for _, z := range zones {
+		hasApex, hasWWW := false, false
+		www := "www." + canonical(z.Name)
+		for _, r := range z.Records {
+			switch {
+			case isApex(r, z.Name) && (r.Type == "A" || r.Type == "AAAA" || r.Type == "CNAME"):
+				hasApex = true
+			case canonical(r.Name) == www:
+				hasWWW = true
+			}
+		}
+		if hasApex && !hasWWW {
+			out = append(out, Issue{Account: z.Account, Zone: z.Name, Name: www, Message: "根域已解析但缺少 www，访问 www 会失败"})
+		}
+	}
+	return out
+}
@@ -0,0 +1,258 @@
+package dnsplan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Record 描述期望存在的一条解析记录（Name 为完整域名）
+type Record struct {
+	Type     string
+	Name     string
+	Content  string
+	Proxied  bool
+	TTL      int // 0 表示未指定：新建时用 auto，更新时保留现值
+	Priority *uint16
+}
+
+type ChangeKind string
+
+const (
+	ChangeCreate ChangeKind = "create"
+	ChangeUpdate ChangeKind = "update"
+	ChangeDelete ChangeKind = "delete"
+)
+
+// Change 是计划中的一项变更；Current 为线上现有记录（create 时为空）
+type Change struct {
+	Kind    ChangeKind
+	Desired Record
+	Current *cloudflare.DNSRecord
+}
+
+// ZonePlan 是单个 Zone 的变更计划
+type ZonePlan struct {
+	AccountLabel string
+	Zone         string
+	Changes      []Change
+	Warnings     []string
+}
+
+// Result 记录单项变更的执行结果
+type Result struct {
+	Change Change
+	Err    error
+}
+
+// Compute 对比期望记录与线上记录，返回变更列表。
+// 只处理期望记录中出现过的 (name,type) 组合，其它线上记录保持不动。
+func Compute(zone string, desired []Record, current []cloudflare.DNSRecord) []Change {
+	zone = normalizeName(zone)
+
+	type groupKey struct{ name, typ string }
+	desiredGroups := map[groupKey][]Record{}
+	var order []groupKey
+	for _, d := range desired {
+		d.Type = strings.ToUpper(strings.TrimSpace(d.Type))
+		d.Name = cfclient.RecordFQDN(d.Name, zone)
+		k := groupKey{d.Name, d.Type}
+		if _, ok := desiredGroups[k]; !ok {
+			order = append(order, k)
+		}
+		desiredGroups[k] = append(desiredGroups[k], d)
+	}
+
+	currentGroups := map[groupKey][]cloudflare.DNSRecord{}
+	for _, r := range current {
+		k := groupKey{normalizeName(r.Name), strings.ToUpper(r.Type)}
+		currentGroups[k] = append(currentGroups[k], r)
+	}
+
+	var changes []Change
+	for _, k := range order {
+		want := desiredGroups[k]
+		have := currentGroups[k]
+		used := make([]bool, len(have))
+
+		var unmatched []Record
+		for _, d := range want {
+			idx := -1
+			for i, r := range have {
+				if used[i] || !cfclient.DNSContentEqual(k.typ, r.Content, d.Content) {
+					continue
+				}
+				if d.Priority != nil && r.Priority != nil && *d.Priority != *r.Priority {
+					continue
+				}
+				idx = i
+				break
+			}
+			if idx < 0 {
+				unmatched = append(unmatched, d)
+				continue
+			}
+			used[idx] = true
+			if needsUpdate(d, have[idx]) {
+				cur := have[idx]
+				changes = append(changes, Change{Kind: ChangeUpdate, Desired: d, Current: &cur})
+			}
+		}
+
+		var leftovers []cloudflare.DNSRecord
+		for i, r := range have {
+			if !used[i] {
+				leftovers = append(leftovers, r)
+			}
+		}
+
+		// CNAME 同名只能有一条：内容变化走更新而不是删除再新建
+		if k.typ == "CNAME" && len(unmatched) > 0 && len(leftovers) > 0 {
+			cur := leftovers[0]
+			changes = append(changes, Change{Kind: ChangeUpdate, Desired: unmatched[0], Current: &cur})
+			unmatched = unmatched[1:]
+			leftovers = leftovers[1:]
+		}
+
+		for _, d := range unmatched {
+			changes = append(changes, Change{Kind: ChangeCreate, Desired: d})
+		}
+		for _, r := range leftovers {
+			cur := r
+			changes = append(changes, Change{Kind: ChangeDelete, Current: &cur, Desired: recordFromCF(r)})
+		}
+	}
+
+	sortChanges(changes)
+	return changes
+}
+
+// Apply 依次执行计划：先新建/更新，再删除，避免解析出现空窗
+func Apply(ctx context.Context, client cfclient.Client, account config.CF, plan ZonePlan) []Result {
+	results := make([]Result, 0, len(plan.Changes))
+	for _, ch := range plan.Changes {
+		if ch.Kind == ChangeDelete {
+			continue
+		}
+		params := cfclient.DNSRecordParams{
+			Type:     ch.Desired.Type,
+			Name:     ch.Desired.Name,
+			Content:  ch.Desired.Content,
+			Proxied:  ch.Desired.Proxied,
+			TTL:      ch.Desired.TTL,
+			Priority: ch.Desired.Priority,
+			Mode:     cfclient.DNSWriteAdd,
+		}
+		if ch.Kind == ChangeUpdate {
+			params.Mode = cfclient.DNSWriteUpdate
+			if params.TTL == 0 && ch.Current != nil {
+				params.TTL = ch.Current.TTL
+			}
+		}
+		_, err := client.UpsertDNSRecord(ctx, account, plan.Zone, params)
+		results = append(results, Result{Change: ch, Err: err})
+	}
+	for _, ch := range plan.Changes {
+		if ch.Kind != ChangeDelete || ch.Current == nil {
+			continue
+		}
+		err := client.DeleteDNSRecordByID(ctx, account, plan.Zone, ch.Current.ID)
+		results = append(results, Result{Change: ch, Err: err})
+	}
+	return results
+}
+
+// Counts 返回计划中新建/更新/删除的数量
+func (p ZonePlan) Counts() (create, update, del int) {
+	for _, ch := range p.Changes {
+		switch ch.Kind {
+		case ChangeCreate:
+			create++
+		case ChangeUpdate:
+			update++
+		case ChangeDelete:
+			del++
+		}
+	}
+	return create, update, del
+}
+
+// Describe 以 diff 形式描述一项变更：+ 新建，~ 更新，- 删除
+func (c Change) Describe() string {
+	switch c.Kind {
+	case ChangeCreate:
+		return fmt.Sprintf("+ %s %s → %s (%s)", c.Desired.Type, c.Desired.Name, c.Desired.Content, describeAttrs(c.Desired.Proxied, c.Desired.TTL))
+	case ChangeUpdate:
+		cur := recordFromCF(*c.Current)
+		if !cfclient.DNSContentEqual(c.Desired.Type, cur.Content, c.Desired.Content) {
+			return fmt.Sprintf("~ %s %s: %s → %s (%s)", c.Desired.Type, c.Desired.Name, cur.Content, c.Desired.Content, describeAttrs(c.Desired.Proxied, c.Desired.TTL))
+		}
+		return fmt.Sprintf("~ %s %s → %s (%s ⇒ %s)", c.Desired.Type, c.Desired.Name, c.Desired.Content, describeAttrs(cur.Proxied, cur.TTL), describeAttrs(c.Desired.Proxied, c.Desired.TTL))
+	case ChangeDelete:
+		return fmt.Sprintf("- %s %s → %s", c.Desired.Type, c.Desired.Name, c.Desired.Content)
+	}
+	return ""
+}
+
+func describeAttrs(proxied bool, ttl int) string {
+	p := "否"
+	if proxied {
+		p = "是"
+	}
+	t := "不变"
+	switch {
+	case ttl == 1:
+		t = "auto"
+	case ttl > 1:
+		t = fmt.Sprintf("%d", ttl)
+	}
+	return fmt.Sprintf("代理:%s, TTL:%s", p, t)
+}
+
+func needsUpdate(d Record, r cloudflare.DNSRecord) bool {
+	proxied := r.Proxied != nil && *r.Proxied
+	if d.Proxied != proxied {
+		return true
+	}
+	if d.TTL != 0 && d.TTL != r.TTL {
+		return true
+	}
+	if d.Priority != nil && (r.Priority == nil || *r.Priority != *d.Priority) {
+		return true
+	}
+	return false
+}
+
+func recordFromCF(r cloudflare.DNSRecord) Record {
+	return Record{
+		Type:     strings.ToUpper(r.Type),
+		Name:     normalizeName(r.Name),
+		Content:  r.Content,
+		Proxied:  r.Proxied != nil && *r.Proxied,
+		TTL:      r.TTL,
+		Priority: r.Priority,
+	}
+}
+
+func normalizeName(s string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), ".")
+}
+
+func sortChanges(changes []Change) {
+	rank := map[ChangeKind]int{ChangeCreate: 0, ChangeUpdate: 1, ChangeDelete: 2}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if rank[changes[i].Kind] != rank[changes[j].Kind] {
+			return rank[changes[i].Kind] < rank[changes[j].Kind]
+		}
+		if changes[i].Desired.Name != changes[j].Desired.Name {
+			return changes[i].Desired.Name < changes[j].Desired.Name
+		}
+		return changes[i].Desired.Type < changes[j].Desired.Type
+	})
+}
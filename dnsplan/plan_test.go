@@ -0,0 +1,62 @@
+package dnsplan
+
+import (
+	"testing"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestComputeCreatesUpdatesDeletes(t *testing.T) {
+	current := []cloudflare.DNSRecord{
+		{ID: "1", Type: "A", Name: "example.com", Content: "192.0.2.1", Proxied: boolPtr(false), TTL: 1},
+		{ID: "2", Type: "A", Name: "example.com", Content: "192.0.2.2", Proxied: boolPtr(false), TTL: 1},
+		{ID: "3", Type: "CNAME", Name: "www.example.com", Content: "old.example.net", TTL: 1},
+		{ID: "4", Type: "TXT", Name: "example.com", Content: "untouched"},
+	}
+	desired := []Record{
+		{Type: "A", Name: "@", Content: "192.0.2.1", Proxied: true},
+		{Type: "A", Name: "@", Content: "192.0.2.3"},
+		{Type: "CNAME", Name: "www", Content: "example.com"},
+	}
+
+	changes := Compute("example.com", desired, current)
+	if len(changes) != 4 {
+		t.Fatalf("expected 4 changes, got %+v", changes)
+	}
+
+	var create, update, del int
+	for _, ch := range changes {
+		switch ch.Kind {
+		case ChangeCreate:
+			create++
+			if ch.Desired.Content != "192.0.2.3" {
+				t.Fatalf("unexpected create: %+v", ch.Desired)
+			}
+		case ChangeUpdate:
+			update++
+		case ChangeDelete:
+			del++
+			if ch.Current.ID != "2" {
+				t.Fatalf("expected record 2 to be deleted, got %s", ch.Current.ID)
+			}
+		}
+	}
+	if create != 1 || update != 2 || del != 1 {
+		t.Fatalf("unexpected counts: create=%d update=%d delete=%d", create, update, del)
+	}
+	if changes[0].Kind != ChangeCreate || changes[len(changes)-1].Kind != ChangeDelete {
+		t.Fatalf("expected creates first and deletes last, got %+v", changes)
+	}
+}
+
+func TestComputeNoChanges(t *testing.T) {
+	current := []cloudflare.DNSRecord{
+		{ID: "1", Type: "CNAME", Name: "www.example.com", Content: "example.com", Proxied: boolPtr(true), TTL: 1},
+	}
+	desired := []Record{{Type: "cname", Name: "www.example.com.", Content: "example.com.", Proxied: true}}
+	if changes := Compute("example.com", desired, current); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %+v", changes)
+	}
+}
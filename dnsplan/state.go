@@ -0,0 +1,290 @@
+package dnsplan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"gopkg.in/yaml.v3"
+)
+
+// ZoneState 是单个 Zone 的期望状态（通常保存在 git 仓库中的 YAML 文件里）
+//
+//	zone: example.com
+//	account: acc1          # 可选，不填时自动查找所在账号
+//	prune: true            # 删除文件中没有、且未被 ignore 的线上记录
+//	ignore:
+//	  - name: "_acme-challenge.*"
+//	    type: TXT
+//	records:
+//	  - {name: "@", type: A, content: 192.0.2.1, proxied: true}
+//	  - {name: "@", type: MX, content: mail.example.com, priority: 10, ttl: 3600}
+type ZoneState struct {
+	Zone    string        `yaml:"zone"`
+	Account string        `yaml:"account,omitempty"`
+	Prune   bool          `yaml:"prune,omitempty"`
+	Ignore  []IgnoreRule  `yaml:"ignore,omitempty"`
+	Records []StateRecord `yaml:"records"`
+
+	// Source 为加载该状态的文件路径
+	Source string `yaml:"-"`
+}
+
+// StateRecord 是 YAML 中的一条记录；name 可写 @、相对名或完整域名
+type StateRecord struct {
+	Type     string  `yaml:"type"`
+	Name     string  `yaml:"name"`
+	Content  string  `yaml:"content"`
+	Proxied  bool    `yaml:"proxied,omitempty"`
+	TTL      int     `yaml:"ttl,omitempty"`
+	Priority *uint16 `yaml:"priority,omitempty"`
+}
+
+// IgnoreRule 匹配不受管理的线上记录，所有字段为空时不生效。
+// name/content 支持 * ? 通配符，name 可写相对名。
+type IgnoreRule struct {
+	Name    string `yaml:"name,omitempty"`
+	Type    string `yaml:"type,omitempty"`
+	Content string `yaml:"content,omitempty"`
+}
+
+// stateFile 兼容单 Zone 文件与 /export yaml 导出的多 Zone 文件
+type stateFile struct {
+	ZoneState `yaml:",inline"`
+	Zones     []ZoneState `yaml:"zones,omitempty"`
+}
+
+// ErrStateNotFound 没有该 Zone 的期望状态文件
+var ErrStateNotFound = errors.New("未找到该 Zone 的期望状态文件")
+
+// LoadStateFile 读取一个 YAML 文件中的全部 Zone 状态
+func LoadStateFile(file string) ([]ZoneState, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("读取期望状态失败: %w", err)
+	}
+	var sf stateFile
+	if err := yaml.Unmarshal(data, &sf); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", file, err)
+	}
+
+	states := sf.Zones
+	if sf.Zone != "" {
+		states = append([]ZoneState{sf.ZoneState}, states...)
+	}
+	for i := range states {
+		states[i].Source = file
+		if err := states[i].validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return states, nil
+}
+
+// LoadStates 读取文件或目录（递归查找 .yaml/.yml）中的全部 Zone 状态
+func LoadStates(root string) ([]ZoneState, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("读取期望状态失败: %w", err)
+	}
+	if !info.IsDir() {
+		return LoadStateFile(root)
+	}
+
+	var files []string
+	err = filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && strings.HasPrefix(d.Name(), ".") && p != root {
+			return filepath.SkipDir
+		}
+		if ext := strings.ToLower(filepath.Ext(p)); !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("遍历期望状态目录失败: %w", err)
+	}
+	sort.Strings(files)
+
+	var out []ZoneState
+	seen := map[string]string{}
+	for _, f := range files {
+		states, err := LoadStateFile(f)
+		if err != nil {
+			return nil, err
+		}
+		for _, st := range states {
+			if prev, ok := seen[st.Zone]; ok {
+				return nil, fmt.Errorf("Zone %s 同时定义在 %s 和 %s", st.Zone, prev, f)
+			}
+			seen[st.Zone] = f
+			out = append(out, st)
+		}
+	}
+	return out, nil
+}
+
+// FindState 从状态列表中按 Zone 名查找
+func FindState(states []ZoneState, zone string) (ZoneState, error) {
+	zone = normalizeName(zone)
+	for _, st := range states {
+		if st.Zone == zone {
+			return st, nil
+		}
+	}
+	return ZoneState{}, fmt.Errorf("%w: %s", ErrStateNotFound, zone)
+}
+
+func (s *ZoneState) validate() error {
+	s.Zone = normalizeName(s.Zone)
+	if s.Zone == "" {
+		return fmt.Errorf("缺少 zone 字段")
+	}
+	for i, r := range s.Records {
+		if strings.TrimSpace(r.Type) == "" || strings.TrimSpace(r.Content) == "" {
+			return fmt.Errorf("%s 第 %d 条记录缺少 type 或 content", s.Zone, i+1)
+		}
+	}
+	for i, rule := range s.Ignore {
+		if rule.Name == "" && rule.Type == "" && rule.Content == "" {
+			return fmt.Errorf("%s 第 %d 条 ignore 规则为空", s.Zone, i+1)
+		}
+		for _, pattern := range []string{rule.Name, rule.Content} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%s 第 %d 条 ignore 规则通配符无效: %v", s.Zone, i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// DesiredRecords 把 YAML 记录转换为 Compute 使用的期望记录
+func (s ZoneState) DesiredRecords() []Record {
+	out := make([]Record, 0, len(s.Records))
+	for _, r := range s.Records {
+		out = append(out, Record{
+			Type:     strings.ToUpper(strings.TrimSpace(r.Type)),
+			Name:     cfclient.RecordFQDN(r.Name, s.Zone),
+			Content:  strings.TrimSpace(r.Content),
+			Proxied:  r.Proxied,
+			TTL:      r.TTL,
+			Priority: r.Priority,
+		})
+	}
+	return out
+}
+
+// Ignored 判断线上记录是否命中 ignore 规则
+func (s ZoneState) Ignored(r cloudflare.DNSRecord) bool {
+	for _, rule := range s.Ignore {
+		if rule.matches(s.Zone, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule IgnoreRule) matches(zone string, r cloudflare.DNSRecord) bool {
+	if rule.Type != "" && !strings.EqualFold(rule.Type, r.Type) {
+		return false
+	}
+	if rule.Name != "" {
+		pattern := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(rule.Name), "."))
+		if pattern == "@" {
+			pattern = zone
+		} else if pattern != zone && !strings.HasSuffix(pattern, "."+zone) {
+			pattern += "." + zone
+		}
+		if ok, _ := path.Match(pattern, normalizeName(r.Name)); !ok {
+			return false
+		}
+	}
+	if rule.Content != "" {
+		if ok, _ := path.Match(rule.Content, strings.TrimSuffix(r.Content, ".")); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ComputeState 计算让线上记录收敛到期望状态所需的变更。
+// 命中 ignore 的线上记录不参与对比；prune 时删除文件中没有的其它记录。
+func ComputeState(state ZoneState, current []cloudflare.DNSRecord) []Change {
+	managed := make([]cloudflare.DNSRecord, 0, len(current))
+	for _, r := range current {
+		if !state.Ignored(r) {
+			managed = append(managed, r)
+		}
+	}
+
+	desired := state.DesiredRecords()
+	changes := Compute(state.Zone, desired, managed)
+	if !state.Prune {
+		return changes
+	}
+
+	declared := map[string]bool{}
+	for _, d := range desired {
+		declared[d.Name+"|"+d.Type] = true
+	}
+	for _, r := range managed {
+		if declared[normalizeName(r.Name)+"|"+strings.ToUpper(r.Type)] {
+			continue
+		}
+		cur := r
+		changes = append(changes, Change{Kind: ChangeDelete, Current: &cur, Desired: recordFromCF(r)})
+	}
+	sortChanges(changes)
+	return changes
+}
+
+// PlanState 定位 Zone 所在账号，读取线上记录并计算计划
+func PlanState(ctx context.Context, client cfclient.Client, accounts []config.CF, state ZoneState) (ZonePlan, error) {
+	plan := ZonePlan{Zone: state.Zone}
+
+	account, err := resolveAccount(ctx, client, accounts, state)
+	if err != nil {
+		return plan, err
+	}
+	plan.AccountLabel = account.Label
+
+	current, err := client.ListDNSRecords(ctx, account, state.Zone)
+	if err != nil {
+		return plan, fmt.Errorf("获取 %s(%s) 线上解析失败: %w", state.Zone, account.Label, err)
+	}
+	plan.Changes = ComputeState(state, current)
+	return plan, nil
+}
+
+func resolveAccount(ctx context.Context, client cfclient.Client, accounts []config.CF, state ZoneState) (config.CF, error) {
+	if state.Account != "" {
+		for _, acc := range accounts {
+			if strings.EqualFold(acc.Label, state.Account) {
+				return acc, nil
+			}
+		}
+		return config.CF{}, fmt.Errorf("未找到账号 %s", state.Account)
+	}
+	for _, acc := range accounts {
+		_, err := client.GetZoneDetails(ctx, acc, state.Zone)
+		if err == nil {
+			return acc, nil
+		}
+		if !errors.Is(err, cfclient.ErrZoneNotFound) {
+			return config.CF{}, err
+		}
+	}
+	return config.CF{}, fmt.Errorf("%w: %s", cfclient.ErrZoneNotFound, state.Zone)
+}
@@ -0,0 +1,103 @@
+package dnsplan
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+const exampleState = `zone: Example.com.
+account: acc
+prune: true
+ignore:
+  - name: "_acme-challenge*"
+    type: TXT
+  - content: "*.herokudns.com"
+records:
+  - {name: "@", type: A, content: 192.0.2.1, proxied: true}
+  - {name: www, type: CNAME, content: example.com}
+  - {name: "@", type: MX, content: mail.example.com, priority: 10, ttl: 3600}
+`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestLoadStates(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "example.com.yaml", exampleState)
+	writeFile(t, dir, "export/all.yml", "zones:\n  - zone: example.org\n    records:\n      - {name: example.org, type: A, content: 192.0.2.5}\n")
+	writeFile(t, dir, ".git/ignored.yaml", "not: [valid")
+
+	states, err := LoadStates(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(states) != 2 {
+		t.Fatalf("expected 2 zones, got %d", len(states))
+	}
+	st, err := FindState(states, "example.com")
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if st.Account != "acc" || !st.Prune || len(st.Records) != 3 || len(st.Ignore) != 2 {
+		t.Fatalf("unexpected state: %+v", st)
+	}
+	if _, err := FindState(states, "missing.com"); !errors.Is(err, ErrStateNotFound) {
+		t.Fatalf("expected ErrStateNotFound, got %v", err)
+	}
+}
+
+func TestLoadStatesRejectsDuplicatesAndInvalid(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.yaml", exampleState)
+	writeFile(t, dir, "b.yaml", exampleState)
+	if _, err := LoadStates(dir); err == nil {
+		t.Fatalf("expected duplicate zone error")
+	}
+
+	bad := writeFile(t, t.TempDir(), "bad.yaml", "zone: example.com\nrecords:\n  - {name: www, type: A}\n")
+	if _, err := LoadStates(bad); err == nil {
+		t.Fatalf("expected validation error for record without content")
+	}
+}
+
+func TestComputeStateIgnoreAndPrune(t *testing.T) {
+	dir := t.TempDir()
+	states, err := LoadStates(writeFile(t, dir, "example.com.yaml", exampleState))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	st := states[0]
+
+	prio := uint16(10)
+	current := []cloudflare.DNSRecord{
+		{ID: "1", Type: "A", Name: "example.com", Content: "192.0.2.1", Proxied: boolPtr(true), TTL: 1},
+		{ID: "2", Type: "CNAME", Name: "www.example.com", Content: "example.com", Proxied: boolPtr(false), TTL: 1},
+		{ID: "3", Type: "MX", Name: "example.com", Content: "mail.example.com", Priority: &prio, TTL: 3600},
+		{ID: "4", Type: "TXT", Name: "_acme-challenge.example.com", Content: "token"},
+		{ID: "5", Type: "CNAME", Name: "app.example.com", Content: "x.herokudns.com"},
+		{ID: "6", Type: "A", Name: "stale.example.com", Content: "192.0.2.9"},
+	}
+
+	changes := ComputeState(st, current)
+	if len(changes) != 1 || changes[0].Kind != ChangeDelete || changes[0].Current.ID != "6" {
+		t.Fatalf("expected only stale record to be pruned, got %+v", changes)
+	}
+
+	st.Prune = false
+	if changes := ComputeState(st, current); len(changes) != 0 {
+		t.Fatalf("expected no changes without prune, got %+v", changes)
+	}
+}
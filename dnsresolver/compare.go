@@ -0,0 +1,160 @@
+package dnsresolver
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/miekg/dns"
+)
+
+// Comparison 是某个解析器的实际应答与 Cloudflare 保存记录的对比结果
+type Comparison struct {
+	Type    string   // 实际对比的类型（名称上是 CNAME 时为 CNAME）
+	Stored  []string // Cloudflare 中的记录值（已规范化）
+	Live    []string // 实际解析到的值（已规范化）
+	Missing []string // 已保存但未解析到
+	Extra   []string // 解析到但未保存
+	// Proxied 表示记录开启了代理，公网只会看到 Cloudflare 的 IP，因此不做逐值对比
+	Proxied bool
+}
+
+// Match 判断实际解析与保存的记录是否一致
+func (c Comparison) Match() bool {
+	return c.Proxied || (len(c.Missing) == 0 && len(c.Extra) == 0)
+}
+
+// Summary 返回一行对比结论
+func (c Comparison) Summary() string {
+	switch {
+	case c.Proxied:
+		return "☁️ 已开启代理，公网返回 Cloudflare 边缘地址，跳过逐值对比"
+	case len(c.Stored) == 0 && len(c.Live) == 0:
+		return "✅ Cloudflare 与公网均无此记录"
+	case c.Match():
+		return "✅ 与 Cloudflare 记录一致"
+	}
+	var parts []string
+	if len(c.Missing) > 0 {
+		parts = append(parts, "缺少: "+strings.Join(c.Missing, ", "))
+	}
+	if len(c.Extra) > 0 {
+		parts = append(parts, "多出: "+strings.Join(c.Extra, ", "))
+	}
+	return "⚠️ 不一致 — " + strings.Join(parts, "；")
+}
+
+// Compare 把应答与 Cloudflare 中同名记录比较。查询名称上存在 CNAME 时改为比较 CNAME 目标。
+func Compare(ans Answer, stored []cloudflare.DNSRecord) Comparison {
+	name := canonicalName(ans.Name)
+	var sameName []cloudflare.DNSRecord
+	for _, r := range stored {
+		if canonicalName(r.Name) == name {
+			sameName = append(sameName, r)
+		}
+	}
+
+	typ := strings.ToUpper(ans.Type)
+	if !hasType(sameName, typ) && hasType(sameName, "CNAME") {
+		typ = "CNAME"
+	}
+	cmp := Comparison{Type: typ}
+
+	for _, r := range sameName {
+		if !strings.EqualFold(r.Type, typ) {
+			continue
+		}
+		if r.Proxied != nil && *r.Proxied {
+			cmp.Proxied = true
+		}
+		cmp.Stored = append(cmp.Stored, normalizeValue(typ, storedValue(r)))
+	}
+	for _, rr := range ans.Records {
+		if strings.EqualFold(rr.Type, typ) && canonicalName(rr.Name) == name {
+			cmp.Live = append(cmp.Live, normalizeValue(typ, rr.Value))
+		}
+	}
+	cmp.Stored = uniqueSorted(cmp.Stored)
+	cmp.Live = uniqueSorted(cmp.Live)
+	if cmp.Proxied {
+		return cmp
+	}
+	cmp.Missing = difference(cmp.Stored, cmp.Live)
+	cmp.Extra = difference(cmp.Live, cmp.Stored)
+	return cmp
+}
+
+func storedValue(r cloudflare.DNSRecord) string {
+	if strings.EqualFold(r.Type, "MX") {
+		prio := uint16(0)
+		if r.Priority != nil {
+			prio = *r.Priority
+		}
+		return fmt.Sprintf("%d %s", prio, r.Content)
+	}
+	return r.Content
+}
+
+func normalizeValue(typ, v string) string {
+	v = strings.TrimSpace(v)
+	switch typ {
+	case "A", "AAAA":
+		if ip := net.ParseIP(v); ip != nil {
+			return ip.String()
+		}
+	case "CNAME", "NS", "PTR":
+		return canonicalName(v)
+	case "MX":
+		fields := strings.Fields(v)
+		if len(fields) == 2 {
+			return fields[0] + " " + canonicalName(fields[1])
+		}
+	case "TXT":
+		// Cloudflare 可能返回带引号、分段的 TXT
+		if strings.HasPrefix(v, `"`) && strings.HasSuffix(v, `"`) && len(v) >= 2 {
+			parts := strings.Split(v[1:len(v)-1], `" "`)
+			return strings.Join(parts, "")
+		}
+	}
+	return v
+}
+
+func canonicalName(s string) string {
+	return strings.ToLower(dns.Fqdn(strings.TrimSpace(s)))
+}
+
+func hasType(records []cloudflare.DNSRecord, typ string) bool {
+	for _, r := range records {
+		if strings.EqualFold(r.Type, typ) {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueSorted(in []string) []string {
+	seen := make(map[string]bool, len(in))
+	var out []string
+	for _, v := range in {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return sortedCopy(out)
+}
+
+func difference(a, b []string) []string {
+	set := make(map[string]bool, len(b))
+	for _, v := range b {
+		set[v] = true
+	}
+	var out []string
+	for _, v := range a {
+		if !set[v] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
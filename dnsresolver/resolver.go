@@ -0,0 +1,321 @@
+// Package dnsresolver 提供可替换的 DNS 查询实现（系统解析器、指定 DNS 服务器、DoH），
+// 用于查看公网实际解析结果，并与 Cloudflare 中保存的记录对比。
+package dnsresolver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Resolver 是一个上游解析器
+type Resolver interface {
+	Name() string
+	Query(ctx context.Context, name string, qtype uint16) (Answer, error)
+}
+
+// RR 是一条应答记录，Value 为记录数据（MX 为 "优先级 主机"）
+type RR struct {
+	Name  string
+	Type  string
+	TTL   uint32
+	Value string
+}
+
+// Answer 是一次查询的结果
+type Answer struct {
+	Resolver string
+	Name     string
+	Type     string
+	Rcode    string
+	Records  []RR
+	RTT      time.Duration
+}
+
+// Values 返回与查询类型一致的记录值（忽略 CNAME 链中间记录）
+func (a Answer) Values() []string {
+	var out []string
+	for _, rr := range a.Records {
+		if strings.EqualFold(rr.Type, a.Type) {
+			out = append(out, rr.Value)
+		}
+	}
+	return out
+}
+
+// DefaultTimeout 单次查询的超时
+const DefaultTimeout = 5 * time.Second
+
+// Parse 根据配置字符串构造解析器：
+//
+//	system                      系统解析器
+//	1.1.1.1 / 1.1.1.1:53 / [::1]:53   指定 DNS 服务器（UDP，截断时改用 TCP）
+//	https://dns.google/dns-query      DoH（RFC 8484）
+//
+// 可用 "名称=地址" 的形式为解析器起别名，例如 cf=1.1.1.1。
+func Parse(spec string) (Resolver, error) {
+	spec = strings.TrimSpace(spec)
+	alias := ""
+	if i := strings.Index(spec, "="); i > 0 && !strings.Contains(spec[:i], "/") {
+		alias, spec = strings.TrimSpace(spec[:i]), strings.TrimSpace(spec[i+1:])
+	}
+	var r Resolver
+	switch {
+	case spec == "" || strings.EqualFold(spec, "system"):
+		r = &System{Alias: alias}
+	case strings.HasPrefix(spec, "https://") || strings.HasPrefix(spec, "http://"):
+		r = &DoH{URL: spec, Alias: alias}
+	default:
+		s, err := ParseServer(spec)
+		if err != nil {
+			return nil, err
+		}
+		s.Alias = alias
+		r = s
+	}
+	return r, nil
+}
+
+// ParseServer 只接受 IP[:端口] 形式的 DNS 服务器地址。
+// 来自聊天等不可信输入的地址应使用它，DoH 地址只能写在配置中，避免机器人向任意 URL 发起请求。
+func ParseServer(spec string) (*Server, error) {
+	addr := strings.TrimSpace(spec)
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(strings.Trim(addr, "[]"), "53")
+	}
+	host, _, _ := net.SplitHostPort(addr)
+	if net.ParseIP(host) == nil {
+		return nil, fmt.Errorf("无效的 DNS 服务器地址: %s", spec)
+	}
+	return &Server{Addr: addr}, nil
+}
+
+// ParseType 把 "A"、"mx" 等转换为查询类型
+func ParseType(s string) (uint16, error) {
+	t, ok := dns.StringToType[strings.ToUpper(strings.TrimSpace(s))]
+	if !ok {
+		return 0, fmt.Errorf("不支持的记录类型: %s", s)
+	}
+	return t, nil
+}
+
+// Server 直接向指定 DNS 服务器发起查询
+type Server struct {
+	Addr    string
+	Alias   string
+	Timeout time.Duration
+}
+
+func (s *Server) Name() string {
+	if s.Alias != "" {
+		return s.Alias
+	}
+	return s.Addr
+}
+
+func (s *Server) Query(ctx context.Context, name string, qtype uint16) (Answer, error) {
+	msg := newQuery(name, qtype)
+	client := &dns.Client{Timeout: timeoutOr(s.Timeout)}
+	resp, rtt, err := client.ExchangeContext(ctx, msg, s.Addr)
+	if err == nil && resp.Truncated {
+		client.Net = "tcp"
+		resp, rtt, err = client.ExchangeContext(ctx, msg, s.Addr)
+	}
+	if err != nil {
+		return Answer{}, fmt.Errorf("查询 %s 失败 [%s]: %v", name, s.Name(), err)
+	}
+	ans := fromMsg(s.Name(), name, qtype, resp)
+	ans.RTT = rtt
+	return ans, nil
+}
+
+// DoH 通过 DNS over HTTPS（RFC 8484 POST）查询
+type DoH struct {
+	URL        string
+	Alias      string
+	HTTPClient *http.Client
+}
+
+func (d *DoH) Name() string {
+	if d.Alias != "" {
+		return d.Alias
+	}
+	return d.URL
+}
+
+func (d *DoH) Query(ctx context.Context, name string, qtype uint16) (Answer, error) {
+	msg := newQuery(name, qtype)
+	msg.Id = 0 // RFC 8484 建议使用 0 以便缓存
+	packed, err := msg.Pack()
+	if err != nil {
+		return Answer{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(packed))
+	if err != nil {
+		return Answer{}, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	client := d.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return Answer{}, fmt.Errorf("查询 %s 失败 [%s]: %v", name, d.Name(), err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return Answer{}, fmt.Errorf("查询 %s 失败 [%s]: %v", name, d.Name(), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Answer{}, fmt.Errorf("查询 %s 失败 [%s]: HTTP %d", name, d.Name(), resp.StatusCode)
+	}
+	out := new(dns.Msg)
+	if err := out.Unpack(body); err != nil {
+		return Answer{}, fmt.Errorf("解析 DoH 响应失败 [%s]: %v", d.Name(), err)
+	}
+	ans := fromMsg(d.Name(), name, qtype, out)
+	ans.RTT = time.Since(start)
+	return ans, nil
+}
+
+// System 使用操作系统的解析器（/etc/resolv.conf），只支持常见类型，TTL 不可用
+type System struct {
+	Alias    string
+	Resolver *net.Resolver
+}
+
+func (s *System) Name() string {
+	if s.Alias != "" {
+		return s.Alias
+	}
+	return "system"
+}
+
+func (s *System) Query(ctx context.Context, name string, qtype uint16) (Answer, error) {
+	r := s.Resolver
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	typ := dns.TypeToString[qtype]
+	ans := Answer{Resolver: s.Name(), Name: dns.Fqdn(name), Type: typ, Rcode: "NOERROR"}
+	add := func(v string) { ans.Records = append(ans.Records, RR{Name: ans.Name, Type: typ, Value: v}) }
+	start := time.Now()
+
+	var err error
+	switch qtype {
+	case dns.TypeA, dns.TypeAAAA:
+		network := "ip4"
+		if qtype == dns.TypeAAAA {
+			network = "ip6"
+		}
+		var ips []net.IP
+		ips, err = r.LookupIP(ctx, network, name)
+		for _, ip := range ips {
+			add(ip.String())
+		}
+	case dns.TypeCNAME:
+		var cname string
+		cname, err = r.LookupCNAME(ctx, name)
+		if err == nil && !strings.EqualFold(dns.Fqdn(cname), dns.Fqdn(name)) {
+			add(dns.Fqdn(cname))
+		}
+	case dns.TypeMX:
+		var mxs []*net.MX
+		mxs, err = r.LookupMX(ctx, name)
+		for _, mx := range mxs {
+			add(fmt.Sprintf("%d %s", mx.Pref, dns.Fqdn(mx.Host)))
+		}
+	case dns.TypeTXT:
+		var txts []string
+		txts, err = r.LookupTXT(ctx, name)
+		for _, t := range txts {
+			add(t)
+		}
+	case dns.TypeNS:
+		var nss []*net.NS
+		nss, err = r.LookupNS(ctx, name)
+		for _, ns := range nss {
+			add(dns.Fqdn(ns.Host))
+		}
+	default:
+		return Answer{}, fmt.Errorf("系统解析器不支持 %s 查询，请指定 DNS 服务器", typ)
+	}
+	ans.RTT = time.Since(start)
+	if err != nil {
+		if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+			ans.Rcode = "NXDOMAIN"
+			return ans, nil
+		}
+		return Answer{}, fmt.Errorf("查询 %s 失败 [%s]: %v", name, s.Name(), err)
+	}
+	return ans, nil
+}
+
+func newQuery(name string, qtype uint16) *dns.Msg {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.RecursionDesired = true
+	return msg
+}
+
+func fromMsg(resolver, name string, qtype uint16, msg *dns.Msg) Answer {
+	ans := Answer{
+		Resolver: resolver,
+		Name:     dns.Fqdn(name),
+		Type:     dns.TypeToString[qtype],
+		Rcode:    dns.RcodeToString[msg.Rcode],
+	}
+	for _, rr := range msg.Answer {
+		h := rr.Header()
+		ans.Records = append(ans.Records, RR{
+			Name:  h.Name,
+			Type:  dns.TypeToString[h.Rrtype],
+			TTL:   h.Ttl,
+			Value: rdata(rr),
+		})
+	}
+	return ans
+}
+
+// rdata 返回记录数据部分，TXT 会把多段字符串拼接为一个值
+func rdata(rr dns.RR) string {
+	switch v := rr.(type) {
+	case *dns.TXT:
+		return strings.Join(v.Txt, "")
+	case *dns.MX:
+		return fmt.Sprintf("%d %s", v.Preference, v.Mx)
+	}
+	full := rr.String()
+	hdr := rr.Header().String()
+	return strings.TrimSpace(strings.TrimPrefix(full, hdr))
+}
+
+func timeoutOr(d time.Duration) time.Duration {
+	if d <= 0 {
+		return DefaultTimeout
+	}
+	return d
+}
+
+// sortedCopy 返回排序后的副本
+func sortedCopy(in []string) []string {
+	out := append([]string(nil), in...)
+	sort.Strings(out)
+	return out
+}
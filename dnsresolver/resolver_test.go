@@ -0,0 +1,198 @@
+package dnsresolver
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/miekg/dns"
+)
+
+// zoneHandler 是进程内的权威 DNS，应答固定的记录
+func zoneHandler(t *testing.T) dns.HandlerFunc {
+	records := map[string][]string{
+		"www.example.com.|A":   {"www.example.com. 300 IN A 192.0.2.10", "www.example.com. 300 IN A 192.0.2.11"},
+		"example.com.|MX":      {"example.com. 3600 IN MX 10 mx.example.com."},
+		"example.com.|TXT":     {`example.com. 300 IN TXT "v=spf1 " "-all"`},
+		"cdn.example.com.|A":   {"cdn.example.com. 300 IN CNAME target.example.net.", "target.example.net. 60 IN A 198.51.100.1"},
+		"proxy.example.com.|A": {"proxy.example.com. 300 IN A 104.16.0.1"},
+	}
+	return func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		q := req.Question[0]
+		lines, ok := records[strings.ToLower(q.Name)+"|"+dns.TypeToString[q.Qtype]]
+		if !ok {
+			resp.Rcode = dns.RcodeNameError
+		}
+		for _, l := range lines {
+			rr, err := dns.NewRR(l)
+			if err != nil {
+				t.Errorf("bad RR %q: %v", l, err)
+				continue
+			}
+			resp.Answer = append(resp.Answer, rr)
+		}
+		_ = w.WriteMsg(resp)
+	}
+}
+
+func startServer(t *testing.T) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	started := make(chan struct{})
+	srv := &dns.Server{PacketConn: pc, Handler: zoneHandler(t), NotifyStartedFunc: func() { close(started) }}
+	go func() { _ = srv.ActivateAndServe() }()
+	<-started
+	t.Cleanup(func() { _ = srv.Shutdown() })
+	return pc.LocalAddr().String()
+}
+
+func TestParse(t *testing.T) {
+	cases := map[string]string{
+		"system":                         "system",
+		"1.1.1.1":                        "1.1.1.1:53",
+		"[2606:4700:4700::1111]:53":      "[2606:4700:4700::1111]:53",
+		"cf=1.1.1.1":                     "cf",
+		"https://dns.google/dns-query":   "https://dns.google/dns-query",
+		"g=https://dns.google/dns-query": "g",
+	}
+	for spec, want := range cases {
+		r, err := Parse(spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", spec, err)
+		}
+		if r.Name() != want {
+			t.Fatalf("Parse(%q).Name() = %q, want %q", spec, r.Name(), want)
+		}
+	}
+	if _, err := Parse("not-an-ip"); err == nil {
+		t.Fatalf("expected error for hostname server")
+	}
+}
+
+func TestParseServerRejectsURLs(t *testing.T) {
+	for _, spec := range []string{"https://dns.google/dns-query", "http://169.254.169.254/latest", "x=1.1.1.1", "system", "dns.google"} {
+		if _, err := ParseServer(spec); err == nil {
+			t.Errorf("ParseServer(%q) should fail", spec)
+		}
+	}
+	s, err := ParseServer("[2606:4700:4700::1111]:5353")
+	if err != nil || s.Addr != "[2606:4700:4700::1111]:5353" {
+		t.Fatalf("ParseServer: %+v %v", s, err)
+	}
+	if s, _ := ParseServer("1.1.1.1"); s == nil || s.Addr != "1.1.1.1:53" {
+		t.Fatalf("default port: %+v", s)
+	}
+}
+
+func TestServerQueryAndCompare(t *testing.T) {
+	addr := startServer(t)
+	r, err := Parse(addr)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	ctx := context.Background()
+
+	ans, err := r.Query(ctx, "www.example.com", dns.TypeA)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if ans.Rcode != "NOERROR" || len(ans.Values()) != 2 {
+		t.Fatalf("unexpected answer: %+v", ans)
+	}
+
+	stored := []cloudflare.DNSRecord{
+		{Type: "A", Name: "www.example.com", Content: "192.0.2.10"},
+		{Type: "A", Name: "www.example.com", Content: "192.0.2.12"},
+	}
+	cmp := Compare(ans, stored)
+	if cmp.Match() || strings.Join(cmp.Missing, ",") != "192.0.2.12" || strings.Join(cmp.Extra, ",") != "192.0.2.11" {
+		t.Fatalf("unexpected comparison: %+v", cmp)
+	}
+
+	ans, _ = r.Query(ctx, "example.com", dns.TypeMX)
+	cmp = Compare(ans, []cloudflare.DNSRecord{{Type: "MX", Name: "example.com", Content: "MX.example.com", Priority: uint16Ptr(10)}})
+	if !cmp.Match() {
+		t.Fatalf("MX should match: %+v", cmp)
+	}
+
+	ans, _ = r.Query(ctx, "example.com", dns.TypeTXT)
+	cmp = Compare(ans, []cloudflare.DNSRecord{{Type: "TXT", Name: "example.com", Content: `"v=spf1 -all"`}})
+	if !cmp.Match() {
+		t.Fatalf("TXT should match after joining strings: %+v", cmp)
+	}
+
+	ans, _ = r.Query(ctx, "cdn.example.com", dns.TypeA)
+	cmp = Compare(ans, []cloudflare.DNSRecord{{Type: "CNAME", Name: "cdn.example.com", Content: "target.example.net"}})
+	if cmp.Type != "CNAME" || !cmp.Match() {
+		t.Fatalf("A query on CNAME should compare the CNAME target: %+v", cmp)
+	}
+
+	ans, _ = r.Query(ctx, "proxy.example.com", dns.TypeA)
+	cmp = Compare(ans, []cloudflare.DNSRecord{{Type: "A", Name: "proxy.example.com", Content: "192.0.2.50", Proxied: boolPtr(true)}})
+	if !cmp.Proxied || !cmp.Match() {
+		t.Fatalf("proxied record should skip comparison: %+v", cmp)
+	}
+
+	ans, _ = r.Query(ctx, "missing.example.com", dns.TypeA)
+	if ans.Rcode != "NXDOMAIN" {
+		t.Fatalf("expected NXDOMAIN, got %s", ans.Rcode)
+	}
+}
+
+func TestDoHQuery(t *testing.T) {
+	handler := zoneHandler(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Content-Type") != "application/dns-message" {
+			http.Error(w, "bad content type", http.StatusUnsupportedMediaType)
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		msg := new(dns.Msg)
+		if err := msg.Unpack(body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rw := &captureWriter{}
+		handler(rw, msg)
+		packed, _ := rw.msg.Pack()
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write(packed)
+	}))
+	defer srv.Close()
+
+	r, err := Parse("test=" + srv.URL)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	ans, err := r.Query(context.Background(), "www.example.com", dns.TypeA)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if ans.Resolver != "test" || len(ans.Values()) != 2 {
+		t.Fatalf("unexpected DoH answer: %+v", ans)
+	}
+}
+
+// captureWriter 把 dns.Handler 的应答保存下来，供 DoH 测试复用同一个 handler
+type captureWriter struct {
+	dns.ResponseWriter
+	msg *dns.Msg
+}
+
+func (c *captureWriter) WriteMsg(m *dns.Msg) error {
+	c.msg = m
+	return nil
+}
+
+func boolPtr(b bool) *bool       { return &b }
+func uint16Ptr(v uint16) *uint16 { return &v }
@@ -0,0 +1,232 @@
+// Package dnssec 协调 Cloudflare 侧的 DNSSEC 开关与注册商侧的 DS 提交，
+// 并通过公网解析核对注册局实际发布的 DS。
+package dnssec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/dnsresolver"
+	"DomainC/registrarclient"
+
+	"github.com/miekg/dns"
+)
+
+// DefaultResolver 查询注册局 DS 时默认使用的 DNS 服务器（系统解析器无法查询 DS）
+const DefaultResolver = "1.1.1.1"
+
+// Registrar 是注册商侧的 DS 读写能力，由 registrarclient.Manager 实现
+type Registrar interface {
+	GetDSRecordsForDomain(ctx context.Context, domain string) (config.Registrar, []registrarclient.DSRecord, error)
+	SetDSRecordsForDomain(ctx context.Context, domain string, records []registrarclient.DSRecord) (config.Registrar, error)
+}
+
+// DSLookup 查询注册局（父域）当前发布的 DS
+type DSLookup interface {
+	LookupDS(ctx context.Context, zone string) ([]registrarclient.DSRecord, error)
+}
+
+// ResolverLookup 使用 dnsresolver 查询 DS
+type ResolverLookup struct {
+	Resolver dnsresolver.Resolver
+}
+
+// NewLookup 根据配置构造 DS 查询器，spec 为空时使用 DefaultResolver
+func NewLookup(spec string) (*ResolverLookup, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = DefaultResolver
+	}
+	r, err := dnsresolver.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &ResolverLookup{Resolver: r}, nil
+}
+
+func (l *ResolverLookup) LookupDS(ctx context.Context, zone string) ([]registrarclient.DSRecord, error) {
+	ans, err := l.Resolver.Query(ctx, zone, dns.TypeDS)
+	if err != nil {
+		return nil, err
+	}
+	if ans.Rcode != "NOERROR" && ans.Rcode != "NXDOMAIN" {
+		return nil, fmt.Errorf("查询 %s DS 失败: %s", zone, ans.Rcode)
+	}
+	var out []registrarclient.DSRecord
+	for _, v := range ans.Values() {
+		ds, err := registrarclient.ParseDSRecord(v)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ds)
+	}
+	return out, nil
+}
+
+// Status 是一个 Zone 的 DNSSEC 全链路状态
+type Status struct {
+	Zone    string
+	Account string
+
+	Cloudflare cfclient.DNSSECInfo
+
+	Registry    []registrarclient.DSRecord // 注册局实际发布的 DS
+	RegistryErr error
+
+	Registrar    config.Registrar
+	RegistrarDS  []registrarclient.DSRecord // 注册商后台登记的 DS
+	RegistrarErr error
+}
+
+// Expected 返回 Cloudflare 要求发布的 DS
+func (s Status) Expected() (registrarclient.DSRecord, bool) {
+	info := s.Cloudflare
+	if !info.HasDS() {
+		return registrarclient.DSRecord{}, false
+	}
+	return registrarclient.DSRecord{
+		KeyTag:     info.KeyTag,
+		Algorithm:  info.Algorithm,
+		DigestType: info.DigestType,
+		Digest:     info.Digest,
+	}, true
+}
+
+// Published 表示注册局仍发布着 DS 记录
+func (s Status) Published() bool {
+	return len(s.Registry) > 0
+}
+
+// RegistryMatches 表示注册局发布的 DS 恰好就是 Cloudflare 要求的 DS
+func (s Status) RegistryMatches() bool {
+	want, ok := s.Expected()
+	if !ok {
+		return false
+	}
+	return registrarclient.SameDSRecords(s.Registry, []registrarclient.DSRecord{want})
+}
+
+// OK 表示 Cloudflare 签名与注册局 DS 处于一致状态：都开启且匹配，或都未开启
+func (s Status) OK() bool {
+	if s.RegistryErr != nil {
+		return false
+	}
+	if s.Cloudflare.Enabled() {
+		return s.RegistryMatches()
+	}
+	return !s.Published()
+}
+
+// Problems 返回可读的问题列表
+func (s Status) Problems() []string {
+	var out []string
+	if s.RegistryErr != nil {
+		out = append(out, fmt.Sprintf("查询注册局 DS 失败: %v", s.RegistryErr))
+		return out
+	}
+	want, hasWant := s.Expected()
+	switch {
+	case s.Cloudflare.Enabled() && !s.Published():
+		out = append(out, "Cloudflare 已签名，但注册局尚未发布 DS（DNSSEC 未生效）")
+	case s.Cloudflare.Enabled() && hasWant && !registrarclient.ContainsDS(s.Registry, want):
+		out = append(out, "⚠️ 注册局发布的 DS 与 Cloudflare 不一致，开启验证的解析器会返回 SERVFAIL")
+	case s.Cloudflare.Enabled() && !s.RegistryMatches():
+		out = append(out, "注册局除 Cloudflare 的 DS 外还发布了其他 DS")
+	case !s.Cloudflare.Enabled() && s.Published():
+		out = append(out, "⚠️ Cloudflare 未签名，但注册局仍发布 DS，开启验证的解析器会返回 SERVFAIL")
+	}
+	return out
+}
+
+// Manager 编排 Cloudflare 与注册商两侧的 DNSSEC 操作
+type Manager struct {
+	CF        cfclient.Client
+	Registrar Registrar // 为空时只操作 Cloudflare
+	Lookup    DSLookup
+}
+
+// Status 汇总 Cloudflare、注册商与注册局三方的 DNSSEC 状态
+func (m *Manager) Status(ctx context.Context, account config.CF, zone string) (Status, error) {
+	info, err := m.CF.GetDNSSEC(ctx, account, zone)
+	if err != nil {
+		return Status{}, err
+	}
+	st := Status{Zone: zone, Account: account.Label, Cloudflare: info}
+	if m.Lookup != nil {
+		st.Registry, st.RegistryErr = m.Lookup.LookupDS(ctx, zone)
+	} else {
+		st.RegistryErr = fmt.Errorf("未配置 DS 查询")
+	}
+	if m.Registrar != nil {
+		st.Registrar, st.RegistrarDS, st.RegistrarErr = m.Registrar.GetDSRecordsForDomain(ctx, zone)
+	}
+	return st, nil
+}
+
+// EnableResult 是开启 DNSSEC 的结果
+type EnableResult struct {
+	Cloudflare   cfclient.DNSSECInfo
+	Registrar    config.Registrar
+	Submitted    bool  // DS 已通过 API 提交到注册商
+	RegistrarErr error // 为 ErrDNSSECUnsupported 时需手动提交
+}
+
+// Enable 开启 Cloudflare 签名并把 DS 提交到注册商
+func (m *Manager) Enable(ctx context.Context, account config.CF, zone string) (EnableResult, error) {
+	info, err := m.CF.EnableDNSSEC(ctx, account, zone)
+	if err != nil {
+		return EnableResult{}, err
+	}
+	res := EnableResult{Cloudflare: info}
+	if !info.HasDS() {
+		// 部分情况下开启接口不直接返回 DS，重新读取一次
+		if again, err := m.CF.GetDNSSEC(ctx, account, zone); err == nil {
+			info = again
+			res.Cloudflare = again
+		}
+	}
+	if m.Registrar == nil {
+		res.RegistrarErr = fmt.Errorf("未配置注册商")
+		return res, nil
+	}
+	if !info.HasDS() {
+		res.RegistrarErr = fmt.Errorf("Cloudflare 未返回 DS 信息，请稍后执行 status 重试")
+		return res, nil
+	}
+	st := Status{Cloudflare: info}
+	want, _ := st.Expected()
+	res.Registrar, res.RegistrarErr = m.Registrar.SetDSRecordsForDomain(ctx, zone, []registrarclient.DSRecord{want})
+	res.Submitted = res.RegistrarErr == nil
+	return res, nil
+}
+
+// RemoveRegistrarDS 移除注册商上登记的全部 DS
+func (m *Manager) RemoveRegistrarDS(ctx context.Context, zone string) (config.Registrar, error) {
+	if m.Registrar == nil {
+		return config.Registrar{}, fmt.Errorf("未配置注册商")
+	}
+	return m.Registrar.SetDSRecordsForDomain(ctx, zone, nil)
+}
+
+// ErrDSPublished 表示注册局仍发布 DS，直接关闭签名会导致解析失败
+var ErrDSPublished = errors.New("ds records still published at registry")
+
+// Disable 关闭 Cloudflare 签名。force 为 false 时若注册局仍发布 DS 则拒绝执行并返回 ErrDSPublished。
+func (m *Manager) Disable(ctx context.Context, account config.CF, zone string, force bool) (cfclient.DNSSECInfo, error) {
+	if !force {
+		if m.Lookup == nil {
+			return cfclient.DNSSECInfo{}, fmt.Errorf("未配置 DS 查询，无法确认注册局 DS 状态")
+		}
+		ds, err := m.Lookup.LookupDS(ctx, zone)
+		if err != nil {
+			return cfclient.DNSSECInfo{}, fmt.Errorf("查询注册局 DS 失败 [%s]: %v", zone, err)
+		}
+		if len(ds) > 0 {
+			return cfclient.DNSSECInfo{}, fmt.Errorf("%w: %s", ErrDSPublished, zone)
+		}
+	}
+	return m.CF.DisableDNSSEC(ctx, account, zone)
+}
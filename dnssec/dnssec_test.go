@@ -0,0 +1,154 @@
+package dnssec
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/dnsresolver"
+	"DomainC/registrarclient"
+)
+
+type fakeCF struct {
+	cfclient.Client
+	info     cfclient.DNSSECInfo
+	disabled bool
+}
+
+func (f *fakeCF) GetDNSSEC(ctx context.Context, account config.CF, domain string) (cfclient.DNSSECInfo, error) {
+	return f.info, nil
+}
+
+func (f *fakeCF) EnableDNSSEC(ctx context.Context, account config.CF, domain string) (cfclient.DNSSECInfo, error) {
+	f.info = cfclient.DNSSECInfo{Status: cfclient.DNSSECPending, KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: "ABCD"}
+	return f.info, nil
+}
+
+func (f *fakeCF) DisableDNSSEC(ctx context.Context, account config.CF, domain string) (cfclient.DNSSECInfo, error) {
+	f.disabled = true
+	f.info = cfclient.DNSSECInfo{Status: cfclient.DNSSECDisabled}
+	return f.info, nil
+}
+
+type fakeRegistrar struct {
+	records []registrarclient.DSRecord
+	err     error
+}
+
+func (f *fakeRegistrar) GetDSRecordsForDomain(ctx context.Context, domain string) (config.Registrar, []registrarclient.DSRecord, error) {
+	return config.Registrar{Label: "gd"}, f.records, f.err
+}
+
+func (f *fakeRegistrar) SetDSRecordsForDomain(ctx context.Context, domain string, records []registrarclient.DSRecord) (config.Registrar, error) {
+	if f.err != nil {
+		return config.Registrar{Label: "nc"}, f.err
+	}
+	f.records = records
+	return config.Registrar{Label: "gd"}, nil
+}
+
+type fakeLookup struct {
+	records []registrarclient.DSRecord
+}
+
+func (f fakeLookup) LookupDS(ctx context.Context, zone string) ([]registrarclient.DSRecord, error) {
+	return f.records, nil
+}
+
+var cfDS = registrarclient.DSRecord{KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: "ABCD"}
+
+func TestEnableSubmitsDS(t *testing.T) {
+	reg := &fakeRegistrar{}
+	m := &Manager{CF: &fakeCF{}, Registrar: reg}
+	res, err := m.Enable(context.Background(), config.CF{Label: "a"}, "example.com")
+	if err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	if !res.Submitted || len(reg.records) != 1 || reg.records[0] != cfDS {
+		t.Fatalf("expected DS submitted, got %+v %+v", res, reg.records)
+	}
+}
+
+func TestEnableUnsupportedRegistrar(t *testing.T) {
+	reg := &fakeRegistrar{err: registrarclient.ErrDNSSECUnsupported}
+	m := &Manager{CF: &fakeCF{}, Registrar: reg}
+	res, err := m.Enable(context.Background(), config.CF{}, "example.com")
+	if err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	if res.Submitted || !errors.Is(res.RegistrarErr, registrarclient.ErrDNSSECUnsupported) || res.Registrar.Label != "nc" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestDisableRefusesWhilePublished(t *testing.T) {
+	cf := &fakeCF{info: cfclient.DNSSECInfo{Status: cfclient.DNSSECActive}}
+	m := &Manager{CF: cf, Lookup: fakeLookup{records: []registrarclient.DSRecord{cfDS}}}
+	if _, err := m.Disable(context.Background(), config.CF{}, "example.com", false); !errors.Is(err, ErrDSPublished) {
+		t.Fatalf("expected ErrDSPublished, got %v", err)
+	}
+	if cf.disabled {
+		t.Fatal("should not disable while DS is published")
+	}
+	if _, err := m.Disable(context.Background(), config.CF{}, "example.com", true); err != nil || !cf.disabled {
+		t.Fatalf("force disable: %v", err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	active := cfclient.DNSSECInfo{Status: cfclient.DNSSECActive, KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: "ABCD"}
+	other := registrarclient.DSRecord{KeyTag: 1, Algorithm: 8, DigestType: 2, Digest: "FFFF"}
+	cases := []struct {
+		name     string
+		info     cfclient.DNSSECInfo
+		registry []registrarclient.DSRecord
+		ok       bool
+		problems int
+	}{
+		{"matched", active, []registrarclient.DSRecord{cfDS}, true, 0},
+		{"not-published", active, nil, false, 1},
+		{"wrong-ds", active, []registrarclient.DSRecord{other}, false, 1},
+		{"extra-ds", active, []registrarclient.DSRecord{cfDS, other}, false, 1},
+		{"off-clean", cfclient.DNSSECInfo{Status: cfclient.DNSSECDisabled}, nil, true, 0},
+		{"off-dangling", cfclient.DNSSECInfo{Status: cfclient.DNSSECDisabled}, []registrarclient.DSRecord{other}, false, 1},
+	}
+	for _, c := range cases {
+		m := &Manager{CF: &fakeCF{info: c.info}, Lookup: fakeLookup{records: c.registry}}
+		st, err := m.Status(context.Background(), config.CF{Label: "a"}, "example.com")
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if st.OK() != c.ok || len(st.Problems()) != c.problems {
+			t.Fatalf("%s: ok=%v problems=%v", c.name, st.OK(), st.Problems())
+		}
+	}
+}
+
+type fakeResolver struct {
+	ans dnsresolver.Answer
+}
+
+func (f fakeResolver) Name() string { return "fake" }
+func (f fakeResolver) Query(ctx context.Context, name string, qtype uint16) (dnsresolver.Answer, error) {
+	return f.ans, nil
+}
+
+func TestResolverLookupParsesDS(t *testing.T) {
+	l := &ResolverLookup{Resolver: fakeResolver{ans: dnsresolver.Answer{
+		Type:  "DS",
+		Rcode: "NOERROR",
+		Records: []dnsresolver.RR{
+			{Type: "RRSIG", Value: "DS 13 2 86400 ..."},
+			{Type: "DS", Value: "2371 13 2 abcd"},
+		},
+	}}}
+	got, err := l.LookupDS(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if len(got) != 1 || got[0] != cfDS {
+		t.Fatalf("unexpected DS: %+v", got)
+	}
+}
@@ -0,0 +1,178 @@
+package dnssnapshot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Changed ChangeKind = "changed"
+	Removed ChangeKind = "removed"
+)
+
+// Change 是单条记录的变化；Added 时 Before 为空，Removed 时 After 为空
+type Change struct {
+	Kind   ChangeKind
+	Before *cloudflare.DNSRecord
+	After  *cloudflare.DNSRecord
+}
+
+// Diff 按记录 ID 对比两次快照；ID 缺失时退化为 类型+名称+内容 匹配
+func Diff(prev, cur []cloudflare.DNSRecord) []Change {
+	before := make(map[string]cloudflare.DNSRecord, len(prev))
+	for _, r := range prev {
+		before[recordKey(r)] = r
+	}
+
+	var changes []Change
+	seen := make(map[string]bool, len(cur))
+	for _, r := range cur {
+		r := r
+		key := recordKey(r)
+		seen[key] = true
+		old, ok := before[key]
+		if !ok {
+			changes = append(changes, Change{Kind: Added, After: &r})
+			continue
+		}
+		if recordChanged(old, r) {
+			changes = append(changes, Change{Kind: Changed, Before: &old, After: &r})
+		}
+	}
+	for _, r := range prev {
+		r := r
+		if !seen[recordKey(r)] {
+			changes = append(changes, Change{Kind: Removed, Before: &r})
+		}
+	}
+
+	rank := map[ChangeKind]int{Added: 0, Changed: 1, Removed: 2}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if rank[changes[i].Kind] != rank[changes[j].Kind] {
+			return rank[changes[i].Kind] < rank[changes[j].Kind]
+		}
+		return changes[i].record().Name < changes[j].record().Name
+	})
+	return changes
+}
+
+// Describe 以 diff 形式描述：+ 新增，~ 修改，- 删除
+func (c Change) Describe() string {
+	switch c.Kind {
+	case Added:
+		return "+ " + describeRecord(*c.After)
+	case Removed:
+		return "- " + describeRecord(*c.Before)
+	case Changed:
+		return fmt.Sprintf("~ %s ⇒ %s", describeRecord(*c.Before), describeAttrs(*c.After, c.Before))
+	}
+	return ""
+}
+
+func (c Change) record() cloudflare.DNSRecord {
+	if c.After != nil {
+		return *c.After
+	}
+	return *c.Before
+}
+
+func recordKey(r cloudflare.DNSRecord) string {
+	if r.ID != "" {
+		return r.ID
+	}
+	return strings.ToUpper(r.Type) + "|" + strings.ToLower(r.Name) + "|" + r.Content
+}
+
+func recordChanged(a, b cloudflare.DNSRecord) bool {
+	return !strings.EqualFold(a.Type, b.Type) ||
+		!strings.EqualFold(a.Name, b.Name) ||
+		a.Content != b.Content ||
+		isProxied(a) != isProxied(b) ||
+		a.TTL != b.TTL ||
+		priority(a) != priority(b) ||
+		a.Comment != b.Comment
+}
+
+func describeRecord(r cloudflare.DNSRecord) string {
+	s := fmt.Sprintf("%s %s → %s (代理:%s, TTL:%s", r.Type, r.Name, r.Content, yesNo(isProxied(r)), ttlText(r.TTL))
+	if r.Priority != nil {
+		s += fmt.Sprintf(", 优先级:%d", *r.Priority)
+	}
+	return s + ")"
+}
+
+// describeAttrs 只列出修改后发生变化的字段
+func describeAttrs(after cloudflare.DNSRecord, before *cloudflare.DNSRecord) string {
+	var parts []string
+	if !strings.EqualFold(after.Name, before.Name) || !strings.EqualFold(after.Type, before.Type) {
+		parts = append(parts, fmt.Sprintf("%s %s", after.Type, after.Name))
+	}
+	if after.Content != before.Content {
+		parts = append(parts, "内容:"+after.Content)
+	}
+	if isProxied(after) != isProxied(*before) {
+		parts = append(parts, "代理:"+yesNo(isProxied(after)))
+	}
+	if after.TTL != before.TTL {
+		parts = append(parts, "TTL:"+ttlText(after.TTL))
+	}
+	if priority(after) != priority(*before) {
+		parts = append(parts, fmt.Sprintf("优先级:%d", priority(after)))
+	}
+	if after.Comment != before.Comment {
+		parts = append(parts, "备注:"+after.Comment)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func isProxied(r cloudflare.DNSRecord) bool {
+	return r.Proxied != nil && *r.Proxied
+}
+
+func priority(r cloudflare.DNSRecord) int {
+	if r.Priority == nil {
+		return -1
+	}
+	return int(*r.Priority)
+}
+
+func ttlText(ttl int) string {
+	if ttl == 1 {
+		return "auto"
+	}
+	return fmt.Sprintf("%d", ttl)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "是"
+	}
+	return "否"
+}
+
+// Format 格式化一次快照差异，limit 为最多列出的变更数（<=0 不限制）
+func (cs ChangeSet) Format(limit int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("账号: %s\nZone: %s\n时间: %s → %s\n",
+		cs.Account, cs.Zone, cs.From.Local().Format("2006-01-02 15:04"), cs.To.Local().Format("2006-01-02 15:04")))
+
+	counts := map[ChangeKind]int{}
+	for _, ch := range cs.Changes {
+		counts[ch.Kind]++
+	}
+	sb.WriteString(fmt.Sprintf("新增 %d / 修改 %d / 删除 %d\n", counts[Added], counts[Changed], counts[Removed]))
+	for i, ch := range cs.Changes {
+		if limit > 0 && i >= limit {
+			sb.WriteString(fmt.Sprintf("… 另有 %d 项未列出\n", len(cs.Changes)-limit))
+			break
+		}
+		sb.WriteString(ch.Describe() + "\n")
+	}
+	return sb.String()
+}
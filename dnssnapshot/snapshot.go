@@ -0,0 +1,206 @@
+// Package dnssnapshot 把各 Zone 的解析记录按次保存到本地目录，并计算相邻快照之间的差异。
+//
+// 目录结构：<Dir>/<账号>/<zone>/<UTC时间>.json，每次运行每个 Zone 一个文件。
+package dnssnapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+const (
+	// DefaultDir 未配置目录时使用
+	DefaultDir = "dns_snapshots"
+	// DefaultKeep 每个 Zone 默认保留的快照数量
+	DefaultKeep = 168
+
+	fileTimeLayout = "20060102T150405Z"
+)
+
+// Snapshot 是某一时刻单个 Zone 的全部解析记录
+type Snapshot struct {
+	Account string                 `json:"account"`
+	Zone    string                 `json:"zone"`
+	ZoneID  string                 `json:"zone_id"`
+	TakenAt time.Time              `json:"taken_at"`
+	Records []cloudflare.DNSRecord `json:"records"`
+}
+
+// ChangeSet 是两次快照之间的差异
+type ChangeSet struct {
+	Account string
+	Zone    string
+	From    time.Time
+	To      time.Time
+	Changes []Change
+}
+
+// Store 是基于本地文件的快照存储
+type Store struct {
+	Dir string
+}
+
+func NewStore(dir string) *Store {
+	if strings.TrimSpace(dir) == "" {
+		dir = DefaultDir
+	}
+	return &Store{Dir: dir}
+}
+
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func pathPart(s string) string {
+	s = unsafeChars.ReplaceAllString(strings.ToLower(strings.TrimSpace(s)), "_")
+	if s == "" || s == "." || s == ".." {
+		return "_"
+	}
+	return s
+}
+
+func (s *Store) zoneDir(account, zone string) string {
+	return filepath.Join(s.Dir, pathPart(account), pathPart(zone))
+}
+
+// Save 写入一份快照，返回文件路径
+func (s *Store) Save(snap Snapshot) (string, error) {
+	if snap.TakenAt.IsZero() {
+		snap.TakenAt = time.Now()
+	}
+	dir := s.zoneDir(snap.Account, snap.Zone)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("创建快照目录失败: %w", err)
+	}
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("序列化快照失败: %w", err)
+	}
+	path := filepath.Join(dir, snap.TakenAt.UTC().Format(fileTimeLayout)+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", fmt.Errorf("写入快照失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", fmt.Errorf("写入快照失败: %w", err)
+	}
+	return path, nil
+}
+
+// Load 读取单个快照文件
+func (s *Store) Load(path string) (Snapshot, error) {
+	var snap Snapshot
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snap, fmt.Errorf("读取快照失败: %w", err)
+	}
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return snap, fmt.Errorf("解析快照 %s 失败: %w", filepath.Base(path), err)
+	}
+	return snap, nil
+}
+
+// Latest 返回账号下某 Zone 最近一次快照；没有快照时返回 nil, nil
+func (s *Store) Latest(account, zone string) (*Snapshot, error) {
+	files, err := s.files(s.zoneDir(account, zone))
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+	snap, err := s.Load(files[len(files)-1])
+	if err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// Prune 只保留最近 keep 份快照
+func (s *Store) Prune(account, zone string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	files, err := s.files(s.zoneDir(account, zone))
+	if err != nil {
+		return err
+	}
+	for len(files) > keep {
+		if err := os.Remove(files[0]); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("清理旧快照失败: %w", err)
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// History 返回某 Zone 最近 n 次有变化的差异（新的在前），会跨账号查找
+func (s *Store) History(zone string, n int) ([]ChangeSet, error) {
+	accounts, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取快照目录失败: %w", err)
+	}
+
+	var out []ChangeSet
+	for _, acc := range accounts {
+		if !acc.IsDir() {
+			continue
+		}
+		files, err := s.files(filepath.Join(s.Dir, acc.Name(), pathPart(zone)))
+		if err != nil {
+			return nil, err
+		}
+		var prev *Snapshot
+		for _, f := range files {
+			snap, err := s.Load(f)
+			if err != nil {
+				return nil, err
+			}
+			if prev != nil {
+				if changes := Diff(prev.Records, snap.Records); len(changes) > 0 {
+					out = append(out, ChangeSet{
+						Account: snap.Account,
+						Zone:    snap.Zone,
+						From:    prev.TakenAt,
+						To:      snap.TakenAt,
+						Changes: changes,
+					})
+				}
+			}
+			prev = &snap
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].To.After(out[j].To) })
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out, nil
+}
+
+// files 返回目录下按时间排序的快照文件
+func (s *Store) files(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取快照目录失败: %w", err)
+	}
+	var out []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		out = append(out, filepath.Join(dir, e.Name()))
+	}
+	sort.Strings(out)
+	return out, nil
+}
@@ -0,0 +1,83 @@
+package dnssnapshot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestDiff(t *testing.T) {
+	prev := []cloudflare.DNSRecord{
+		{ID: "1", Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 1, Proxied: boolPtr(true)},
+		{ID: "2", Type: "A", Name: "old.example.com", Content: "192.0.2.2", TTL: 1},
+		{ID: "3", Type: "TXT", Name: "example.com", Content: "same", TTL: 1},
+	}
+	cur := []cloudflare.DNSRecord{
+		{ID: "1", Type: "A", Name: "example.com", Content: "192.0.2.9", TTL: 1, Proxied: boolPtr(false)},
+		{ID: "3", Type: "TXT", Name: "example.com", Content: "same", TTL: 1},
+		{ID: "4", Type: "CNAME", Name: "new.example.com", Content: "example.com", TTL: 300},
+	}
+
+	changes := Diff(prev, cur)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", changes)
+	}
+	if changes[0].Kind != Added || changes[0].After.ID != "4" {
+		t.Fatalf("expected record 4 added first, got %+v", changes[0])
+	}
+	if changes[1].Kind != Changed || !strings.Contains(changes[1].Describe(), "内容:192.0.2.9") || !strings.Contains(changes[1].Describe(), "代理:否") {
+		t.Fatalf("unexpected change description: %s", changes[1].Describe())
+	}
+	if changes[2].Kind != Removed || changes[2].Before.ID != "2" {
+		t.Fatalf("expected record 2 removed, got %+v", changes[2])
+	}
+}
+
+func TestStoreSaveLatestPruneHistory(t *testing.T) {
+	store := NewStore(t.TempDir())
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := [][]cloudflare.DNSRecord{
+		{{ID: "1", Type: "A", Name: "example.com", Content: "192.0.2.1"}},
+		{{ID: "1", Type: "A", Name: "example.com", Content: "192.0.2.1"}},
+		{{ID: "1", Type: "A", Name: "example.com", Content: "192.0.2.2"}},
+		{{ID: "1", Type: "A", Name: "example.com", Content: "192.0.2.2"}, {ID: "2", Type: "A", Name: "www.example.com", Content: "192.0.2.3"}},
+	}
+	for i, r := range records {
+		snap := Snapshot{Account: "acc", Zone: "example.com", TakenAt: base.Add(time.Duration(i) * time.Hour), Records: r}
+		if _, err := store.Save(snap); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	latest, err := store.Latest("acc", "example.com")
+	if err != nil || latest == nil || len(latest.Records) != 2 {
+		t.Fatalf("unexpected latest: %+v, %v", latest, err)
+	}
+
+	history, err := store.History("example.com", 10)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 non-empty change sets, got %d", len(history))
+	}
+	if !history[0].To.Equal(base.Add(3*time.Hour)) || history[0].Changes[0].Kind != Added {
+		t.Fatalf("expected newest change set first, got %+v", history[0])
+	}
+
+	if err := store.Prune("acc", "example.com", 2); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	history, _ = store.History("example.com", 10)
+	if len(history) != 1 {
+		t.Fatalf("expected 1 change set after prune, got %d", len(history))
+	}
+
+	if snap, err := store.Latest("acc", "missing.com"); err != nil || snap != nil {
+		t.Fatalf("expected no snapshot for unknown zone, got %+v, %v", snap, err)
+	}
+}
@@ -0,0 +1,304 @@
+// Package emergency 在事故期间批量切换 Zone 的应急模式（Under Attack、开发模式）：
+// 并发且限速地修改设置，记录开启前的取值以便关闭时恢复，并支持定时自动回滚。
+package emergency
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+const (
+	// DefaultConcurrency 未配置时同时处理的 Zone 数
+	DefaultConcurrency = 5
+	// DefaultRatePerSecond 未配置时每秒开始处理的 Zone 数（每个 Zone 最多 2 次 API 调用）
+	DefaultRatePerSecond = 4
+)
+
+// Mode 是一种应急模式：开启时把 Setting 改为 OnValue，关闭时恢复原值；
+// 没有开启记录时关闭恢复为 DefaultOff
+type Mode struct {
+	Name       string
+	Title      string
+	Setting    string
+	OnValue    string
+	DefaultOff string
+}
+
+var (
+	// UnderAttack 对应 security_level=under_attack（"I'm Under Attack" 模式）
+	UnderAttack = Mode{Name: "uam", Title: "Under Attack 模式", Setting: "security_level", OnValue: "under_attack", DefaultOff: "medium"}
+	// DevMode 对应 development_mode=on（绕过缓存，Cloudflare 会在 3 小时后自动关闭）
+	DevMode = Mode{Name: "devmode", Title: "开发模式", Setting: "development_mode", OnValue: "on", DefaultOff: "off"}
+)
+
+// Modes 是全部应急模式
+var Modes = []Mode{UnderAttack, DevMode}
+
+// LookupMode 按名称返回应急模式
+func LookupMode(name string) (Mode, bool) {
+	for _, m := range Modes {
+		if strings.EqualFold(m.Name, name) {
+			return m, true
+		}
+	}
+	return Mode{}, false
+}
+
+// Target 是一个要切换的 Zone
+type Target struct {
+	Account config.CF
+	Zone    string
+	ZoneID  string
+}
+
+// Outcome 是单个 Zone 的切换结果；Unchanged 表示取值已符合要求，未调用修改接口
+type Outcome struct {
+	Target    Target
+	From      string
+	To        string
+	Unchanged bool
+	Err       error
+}
+
+// Manager 执行应急模式切换。Accounts 用于定时回滚时按标签找回账号凭据（记录中不保存 token）。
+type Manager struct {
+	CF            cfclient.Client
+	Store         *Store
+	Accounts      []config.CF
+	Concurrency   int
+	RatePerSecond float64
+	// Notify 接收定时回滚的结果，为空时不通知
+	Notify func(msg string)
+}
+
+// On 开启应急模式。已有开启记录的 Zone 保留最初记录的原值；
+// revertAfter > 0 时到期自动关闭，为 0 时取消之前设置的定时回滚。
+func (m *Manager) On(ctx context.Context, mode Mode, targets []Target, revertAfter time.Duration, operator string) []Outcome {
+	var revertAt *time.Time
+	if revertAfter > 0 {
+		at := time.Now().Add(revertAfter)
+		revertAt = &at
+	}
+	return m.run(ctx, targets, func(t Target) Outcome {
+		return m.on(ctx, mode, t, revertAt, operator)
+	})
+}
+
+// Off 关闭应急模式，恢复开启前记录的取值；没有记录但处于开启状态时恢复为 DefaultOff
+func (m *Manager) Off(ctx context.Context, mode Mode, targets []Target) []Outcome {
+	return m.run(ctx, targets, func(t Target) Outcome {
+		return m.off(ctx, mode, t)
+	})
+}
+
+// Active 返回某模式当前的开启记录
+func (m *Manager) Active(mode Mode) ([]Record, error) {
+	return m.Store.List(mode.Name)
+}
+
+// Resume 在启动时重新安排已保存的定时回滚，已过期的立即执行
+func (m *Manager) Resume() (int, error) {
+	records, err := m.Store.List("")
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, r := range records {
+		if r.RevertAt != nil {
+			m.schedule(r)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *Manager) on(ctx context.Context, mode Mode, t Target, revertAt *time.Time, operator string) Outcome {
+	o := Outcome{Target: t, To: mode.OnValue}
+	settings, err := m.CF.GetZoneSettings(ctx, t.Account, t.ZoneID)
+	if err != nil {
+		o.Err = err
+		return o
+	}
+	cur, ok := settings[mode.Setting]
+	if !ok {
+		o.Err = fmt.Errorf("Cloudflare 未返回 %s 设置", mode.Setting)
+		return o
+	}
+	o.From = cur
+
+	rec, exists, err := m.Store.Get(mode.Name, t.ZoneID)
+	if err != nil {
+		o.Err = err
+		return o
+	}
+	if !exists {
+		previous := cur
+		if cur == mode.OnValue {
+			previous = mode.DefaultOff
+		}
+		rec = Record{
+			Mode:     mode.Name,
+			Account:  t.Account.Label,
+			Zone:     t.Zone,
+			ZoneID:   t.ZoneID,
+			Setting:  mode.Setting,
+			Previous: previous,
+			Operator: operator,
+			At:       time.Now(),
+		}
+	}
+	rec.RevertAt = revertAt
+	// 先写记录再修改，保证修改成功后一定能恢复原值
+	if err := m.Store.Put(rec); err != nil {
+		o.Err = err
+		return o
+	}
+	if cur == mode.OnValue {
+		o.Unchanged = true
+	} else if err := m.CF.UpdateZoneSetting(ctx, t.Account, t.ZoneID, mode.Setting, mode.OnValue); err != nil {
+		if !exists {
+			_ = m.Store.Delete(mode.Name, t.ZoneID)
+		}
+		o.Err = err
+		return o
+	}
+	if revertAt != nil {
+		m.schedule(rec)
+	}
+	return o
+}
+
+func (m *Manager) off(ctx context.Context, mode Mode, t Target) Outcome {
+	o := Outcome{Target: t, To: mode.DefaultOff}
+	rec, exists, err := m.Store.Get(mode.Name, t.ZoneID)
+	if err != nil {
+		o.Err = err
+		return o
+	}
+	if exists {
+		o.To = rec.Previous
+	}
+	settings, err := m.CF.GetZoneSettings(ctx, t.Account, t.ZoneID)
+	if err != nil {
+		o.Err = err
+		return o
+	}
+	cur, ok := settings[mode.Setting]
+	if !ok {
+		o.Err = fmt.Errorf("Cloudflare 未返回 %s 设置", mode.Setting)
+		return o
+	}
+	o.From = cur
+	if !exists && cur != mode.OnValue {
+		o.To = cur
+		o.Unchanged = true
+		return o
+	}
+	if cur == o.To {
+		o.Unchanged = true
+	} else if err := m.CF.UpdateZoneSetting(ctx, t.Account, t.ZoneID, mode.Setting, o.To); err != nil {
+		o.Err = err
+		return o
+	}
+	if exists {
+		if err := m.Store.Delete(mode.Name, t.ZoneID); err != nil {
+			o.Err = err
+		}
+	}
+	return o
+}
+
+// run 并发处理 targets：最多 Concurrency 个同时进行，且每秒最多开始 RatePerSecond 个
+func (m *Manager) run(ctx context.Context, targets []Target, fn func(Target) Outcome) []Outcome {
+	out := make([]Outcome, len(targets))
+	workers := m.Concurrency
+	if workers <= 0 {
+		workers = DefaultConcurrency
+	}
+	rate := m.RatePerSecond
+	if rate <= 0 {
+		rate = DefaultRatePerSecond
+	}
+	tick := time.NewTicker(time.Duration(float64(time.Second) / rate))
+	defer tick.Stop()
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		if i > 0 {
+			select {
+			case <-tick.C:
+			case <-ctx.Done():
+				for j := i; j < len(targets); j++ {
+					out[j] = Outcome{Target: targets[j], Err: ctx.Err()}
+				}
+				wg.Wait()
+				return out
+			}
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, t Target) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			out[i] = fn(t)
+		}(i, t)
+	}
+	wg.Wait()
+	return out
+}
+
+// schedule 到期后关闭应急模式；记录已被关闭或回滚时间被修改时不执行
+func (m *Manager) schedule(rec Record) {
+	time.AfterFunc(time.Until(*rec.RevertAt), func() {
+		m.revert(rec)
+	})
+}
+
+func (m *Manager) revert(rec Record) {
+	cur, ok, err := m.Store.Get(rec.Mode, rec.ZoneID)
+	if err != nil {
+		m.notify(fmt.Sprintf("❌ %s 定时回滚失败: %v", rec.Zone, err))
+		return
+	}
+	if !ok || cur.RevertAt == nil || !cur.RevertAt.Equal(*rec.RevertAt) {
+		return
+	}
+	mode, ok := LookupMode(rec.Mode)
+	if !ok {
+		m.notify(fmt.Sprintf("❌ %s 定时回滚失败: 未知模式 %s", rec.Zone, rec.Mode))
+		return
+	}
+	account := m.account(rec.Account)
+	if account == nil {
+		m.notify(fmt.Sprintf("❌ %s 定时关闭%s失败: 未找到账号 %s", rec.Zone, mode.Title, rec.Account))
+		return
+	}
+	o := m.off(context.Background(), mode, Target{Account: *account, Zone: rec.Zone, ZoneID: rec.ZoneID})
+	if o.Err != nil {
+		m.notify(fmt.Sprintf("❌ %s (%s) 定时关闭%s失败: %v", rec.Zone, rec.Account, mode.Title, o.Err))
+		return
+	}
+	m.notify(fmt.Sprintf("⏱ 已按计划关闭 %s (%s) 的%s：%s %s → %s", rec.Zone, rec.Account, mode.Title, mode.Setting, o.From, o.To))
+}
+
+func (m *Manager) account(label string) *config.CF {
+	for i := range m.Accounts {
+		if strings.EqualFold(m.Accounts[i].Label, label) {
+			return &m.Accounts[i]
+		}
+	}
+	return nil
+}
+
+func (m *Manager) notify(msg string) {
+	if m.Notify != nil {
+		m.Notify(msg)
+	}
+}
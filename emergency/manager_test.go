@@ -0,0 +1,199 @@
+package emergency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+type fakeCF struct {
+	cfclient.Client
+	mu       sync.Mutex
+	settings map[string]map[string]string
+	failID   string
+	delay    time.Duration
+	running  int
+	peak     int
+}
+
+func (f *fakeCF) GetZoneSettings(ctx context.Context, acc config.CF, zoneID string) (map[string]string, error) {
+	f.mu.Lock()
+	f.running++
+	if f.running > f.peak {
+		f.peak = f.running
+	}
+	out := map[string]string{}
+	for k, v := range f.settings[zoneID] {
+		out[k] = v
+	}
+	f.mu.Unlock()
+
+	time.Sleep(f.delay)
+	f.mu.Lock()
+	f.running--
+	f.mu.Unlock()
+	return out, nil
+}
+
+func (f *fakeCF) UpdateZoneSetting(ctx context.Context, acc config.CF, zoneID, id, value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if zoneID == f.failID {
+		return errors.New("forbidden")
+	}
+	f.settings[zoneID][id] = value
+	return nil
+}
+
+func (f *fakeCF) get(zoneID, id string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.settings[zoneID][id]
+}
+
+var acc = config.CF{Label: "main", APIToken: "t"}
+
+func newManager(t *testing.T, cf *fakeCF) *Manager {
+	t.Helper()
+	return &Manager{
+		CF:            cf,
+		Store:         &Store{File: filepath.Join(t.TempDir(), "state", DefaultFile)},
+		Accounts:      []config.CF{acc},
+		RatePerSecond: 1000,
+	}
+}
+
+func target(zone string) Target {
+	return Target{Account: acc, Zone: zone + ".com", ZoneID: zone}
+}
+
+func TestOnOffRestoresPrevious(t *testing.T) {
+	cf := &fakeCF{settings: map[string]map[string]string{
+		"a": {"security_level": "high"},
+		"b": {"security_level": "under_attack"},
+		"c": {"security_level": "low"},
+	}, failID: "c"}
+	m := newManager(t, cf)
+	ctx := context.Background()
+
+	out := m.On(ctx, UnderAttack, []Target{target("a"), target("b"), target("c")}, 0, "@ops")
+	if out[0].Err != nil || out[0].From != "high" || out[0].Unchanged {
+		t.Fatalf("a: %+v", out[0])
+	}
+	if out[1].Err != nil || !out[1].Unchanged {
+		t.Fatalf("b: %+v", out[1])
+	}
+	if out[2].Err == nil {
+		t.Fatalf("c: expected error")
+	}
+	if cf.get("a", "security_level") != "under_attack" {
+		t.Fatal("a not switched")
+	}
+
+	// 再次开启不覆盖最初记录的原值
+	m.On(ctx, UnderAttack, []Target{target("a")}, 0, "@ops")
+	records, _ := m.Active(UnderAttack)
+	if len(records) != 2 || records[0].ZoneID != "a" || records[0].Previous != "high" || records[1].Previous != "medium" {
+		t.Fatalf("records = %+v", records)
+	}
+
+	out = m.Off(ctx, UnderAttack, []Target{target("a"), target("b"), target("c")})
+	if out[0].Err != nil || out[0].To != "high" || cf.get("a", "security_level") != "high" {
+		t.Fatalf("a off: %+v", out[0])
+	}
+	// 开启前已是 under_attack：恢复为默认值
+	if out[1].Err != nil || out[1].To != "medium" || cf.get("b", "security_level") != "medium" {
+		t.Fatalf("b off: %+v", out[1])
+	}
+	// 从未开启：不修改
+	if out[2].Err != nil || !out[2].Unchanged || cf.get("c", "security_level") != "low" {
+		t.Fatalf("c off: %+v", out[2])
+	}
+	if records, _ := m.Active(UnderAttack); len(records) != 0 {
+		t.Fatalf("records left: %+v", records)
+	}
+}
+
+func TestAutoRevertAndResume(t *testing.T) {
+	cf := &fakeCF{settings: map[string]map[string]string{"a": {"development_mode": "off"}}}
+	m := newManager(t, cf)
+	done := make(chan string, 1)
+	m.Notify = func(msg string) { done <- msg }
+
+	if out := m.On(context.Background(), DevMode, []Target{target("a")}, 30*time.Millisecond, "@ops"); out[0].Err != nil {
+		t.Fatal(out[0].Err)
+	}
+	if cf.get("a", "development_mode") != "on" {
+		t.Fatal("not switched on")
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("auto revert did not run")
+	}
+	if cf.get("a", "development_mode") != "off" {
+		t.Fatal("not reverted")
+	}
+
+	// 重启后恢复已过期的定时回滚
+	past := time.Now().Add(-time.Minute)
+	cf.settings["a"]["development_mode"] = "on"
+	if err := m.Store.Put(Record{Mode: DevMode.Name, Account: "main", Zone: "a.com", ZoneID: "a", Setting: DevMode.Setting, Previous: "off", RevertAt: &past}); err != nil {
+		t.Fatal(err)
+	}
+	restarted := &Manager{CF: cf, Store: &Store{File: m.Store.File}, Accounts: m.Accounts, Notify: m.Notify}
+	if n, err := restarted.Resume(); err != nil || n != 1 {
+		t.Fatalf("Resume = %d, %v", n, err)
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("resumed revert did not run")
+	}
+	if cf.get("a", "development_mode") != "off" {
+		t.Fatal("not reverted after resume")
+	}
+}
+
+func TestReenableCancelsRevert(t *testing.T) {
+	cf := &fakeCF{settings: map[string]map[string]string{"a": {"development_mode": "off"}}}
+	m := newManager(t, cf)
+	m.Notify = func(msg string) { t.Errorf("unexpected notify: %s", msg) }
+	ctx := context.Background()
+
+	m.On(ctx, DevMode, []Target{target("a")}, 20*time.Millisecond, "@ops")
+	m.On(ctx, DevMode, []Target{target("a")}, 0, "@ops")
+	time.Sleep(80 * time.Millisecond)
+	if cf.get("a", "development_mode") != "on" {
+		t.Fatal("revert should have been cancelled")
+	}
+}
+
+func TestRunLimitsConcurrency(t *testing.T) {
+	cf := &fakeCF{settings: map[string]map[string]string{}, delay: 20 * time.Millisecond}
+	var targets []Target
+	for i := 0; i < 12; i++ {
+		id := fmt.Sprintf("z%d", i)
+		cf.settings[id] = map[string]string{"security_level": "medium"}
+		targets = append(targets, target(id))
+	}
+	m := newManager(t, cf)
+	m.Concurrency = 3
+
+	out := m.On(context.Background(), UnderAttack, targets, 0, "@ops")
+	for _, o := range out {
+		if o.Err != nil {
+			t.Fatal(o.Err)
+		}
+	}
+	if cf.peak > 3 || cf.peak < 2 {
+		t.Fatalf("peak concurrency = %d", cf.peak)
+	}
+}
@@ -0,0 +1,160 @@
+package emergency
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFile 未配置时保存开启记录的文件
+const DefaultFile = "emergency_state.json"
+
+// Record 是一个已开启应急模式的 Zone，Previous 为开启前的取值，关闭时恢复
+type Record struct {
+	Mode     string     `json:"mode"`
+	Account  string     `json:"account"`
+	Zone     string     `json:"zone"`
+	ZoneID   string     `json:"zoneId"`
+	Setting  string     `json:"setting"`
+	Previous string     `json:"previous"`
+	Operator string     `json:"operator,omitempty"`
+	At       time.Time  `json:"at"`
+	RevertAt *time.Time `json:"revertAt,omitempty"`
+}
+
+func (r Record) key() string {
+	return recordKey(r.Mode, r.ZoneID)
+}
+
+func recordKey(mode, zoneID string) string {
+	return mode + "|" + zoneID
+}
+
+// Store 把开启记录保存在 JSON 文件中，重启后仍能恢复原设置与定时回滚
+type Store struct {
+	File string
+
+	mu sync.Mutex
+}
+
+// Get 返回 Zone 在某模式下的开启记录
+func (s *Store) Get(mode, zoneID string) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.load()
+	if err != nil {
+		return Record{}, false, err
+	}
+	r, ok := records[recordKey(mode, zoneID)]
+	return r, ok, nil
+}
+
+// Put 新增或覆盖开启记录
+func (s *Store) Put(r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+	records[r.key()] = r
+	return s.save(records)
+}
+
+// Delete 删除开启记录，记录不存在时不报错
+func (s *Store) Delete(mode, zoneID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := records[recordKey(mode, zoneID)]; !ok {
+		return nil
+	}
+	delete(records, recordKey(mode, zoneID))
+	return s.save(records)
+}
+
+// List 返回某模式的全部开启记录，mode 为空时返回全部（按账号、Zone 排序）
+func (s *Store) List(mode string) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	var out []Record
+	for _, r := range records {
+		if mode == "" || r.Mode == mode {
+			out = append(out, r)
+		}
+	}
+	sortRecords(out)
+	return out, nil
+}
+
+func sortRecords(list []Record) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Mode != list[j].Mode {
+			return list[i].Mode < list[j].Mode
+		}
+		if list[i].Account != list[j].Account {
+			return list[i].Account < list[j].Account
+		}
+		return list[i].Zone < list[j].Zone
+	})
+}
+
+func (s *Store) path() string {
+	if strings.TrimSpace(s.File) == "" {
+		return DefaultFile
+	}
+	return s.File
+}
+
+func (s *Store) load() (map[string]Record, error) {
+	records := make(map[string]Record)
+	data, err := os.ReadFile(s.path())
+	if os.IsNotExist(err) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取应急模式记录失败 [%s]: %v", s.path(), err)
+	}
+	var list []Record
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("解析应急模式记录失败 [%s]: %v", s.path(), err)
+	}
+	for _, r := range list {
+		records[r.key()] = r
+	}
+	return records, nil
+}
+
+func (s *Store) save(records map[string]Record) error {
+	list := make([]Record, 0, len(records))
+	for _, r := range records {
+		list = append(list, r)
+	}
+	sortRecords(list)
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(s.path()); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("保存应急模式记录失败 [%s]: %v", s.path(), err)
+		}
+	}
+	tmp := s.path() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("保存应急模式记录失败 [%s]: %v", s.path(), err)
+	}
+	return os.Rename(tmp, s.path())
+}
@@ -0,0 +1,116 @@
+package iacexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// DNSControl 生成 dnsconfig.js 与 creds.json 模板，每个账号对应一个 DNS provider
+func DNSControl(inv Inventory) Files {
+	var js strings.Builder
+	js.WriteString("// 由 global-cf-auto 导出，凭据见 creds.json（每个账号一个 CLOUDFLAREAPI provider）\n")
+	js.WriteString("var REG_NONE = NewRegistrar(\"none\");\n")
+
+	creds := map[string]map[string]string{}
+	for _, acc := range inv.Accounts {
+		name := "cloudflare_" + identifier(acc.Label)
+		creds[name] = map[string]string{
+			"TYPE":      "CLOUDFLAREAPI",
+			"accountid": acc.AccountID,
+			"apitoken":  "$CF_API_TOKEN_" + strings.ToUpper(identifier(acc.Label)),
+		}
+		js.WriteString(fmt.Sprintf("var %s = NewDnsProvider(%s);\n", dspVar(acc.Label), jsString(name)))
+	}
+
+	for _, acc := range inv.Accounts {
+		for _, z := range acc.Zones {
+			var lines, skipped []string
+			for _, r := range z.Records {
+				line, ok := dnscontrolRecord(z.Detail.Name, r)
+				if !ok {
+					skipped = append(skipped, fmt.Sprintf("// 不支持自动转换: %s %s %s", r.Type, r.Name, strings.ReplaceAll(r.Content, "\n", " ")))
+					continue
+				}
+				lines = append(lines, line)
+			}
+			js.WriteString(fmt.Sprintf("\n// 账号: %s\n", acc.Label))
+			for _, s := range skipped {
+				js.WriteString(s + "\n")
+			}
+			js.WriteString(fmt.Sprintf("D(%s, REG_NONE, DnsProvider(%s)", jsString(z.Detail.Name), dspVar(acc.Label)))
+			for _, line := range lines {
+				js.WriteString(",\n\t" + line)
+			}
+			js.WriteString("\n);\n")
+		}
+	}
+
+	credsJSON, _ := json.MarshalIndent(creds, "", "  ")
+	return Files{
+		"dnsconfig.js": []byte(js.String()),
+		"creds.json":   append(credsJSON, '\n'),
+	}
+}
+
+func dspVar(label string) string {
+	return "DSP_" + strings.ToUpper(identifier(label))
+}
+
+// dnscontrolRecord 把一条记录转换为 DNSControl 的记录函数调用，不支持的类型返回 false
+func dnscontrolRecord(zone string, r cloudflare.DNSRecord) (string, bool) {
+	name := jsString(relativeName(r.Name, zone))
+	content := strings.TrimSpace(r.Content)
+	target := jsString(strings.TrimSuffix(content, ".") + ".")
+
+	var mods []string
+	if r.TTL > 0 {
+		// TTL(1) 在 Cloudflare provider 中表示 auto
+		mods = append(mods, fmt.Sprintf("TTL(%d)", r.TTL))
+	}
+	if isProxied(r) {
+		mods = append(mods, "CF_PROXY_ON")
+	}
+	suffix := ""
+	if len(mods) > 0 {
+		suffix = ", " + strings.Join(mods, ", ")
+	}
+	prio := 0
+	if r.Priority != nil {
+		prio = int(*r.Priority)
+	}
+
+	switch typ := strings.ToUpper(r.Type); typ {
+	case "A", "AAAA":
+		return fmt.Sprintf("%s(%s, %s%s)", typ, name, jsString(content), suffix), true
+	case "CNAME", "NS", "PTR", "ALIAS":
+		return fmt.Sprintf("%s(%s, %s%s)", typ, name, target, suffix), true
+	case "MX":
+		return fmt.Sprintf("MX(%s, %d, %s%s)", name, prio, target, suffix), true
+	case "TXT":
+		return fmt.Sprintf("TXT(%s, %s%s)", name, jsString(strings.Trim(content, `"`)), suffix), true
+	case "SRV":
+		// content 为 "weight port target"
+		if f := strings.Fields(content); len(f) == 3 {
+			return fmt.Sprintf("SRV(%s, %d, %s, %s, %s%s)", name, prio, f[0], f[1], jsString(strings.TrimSuffix(f[2], ".")+"."), suffix), true
+		}
+	case "CAA":
+		// content 为 `flags tag "value"`
+		if p := strings.SplitN(content, " ", 3); len(p) == 3 {
+			caaMod := ""
+			if p[0] == "128" {
+				caaMod = ", CAA_CRITICAL"
+			}
+			return fmt.Sprintf("CAA(%s, %s, %s%s%s)", name, jsString(p[1]), jsString(strings.Trim(p[2], `"`)), caaMod, suffix), true
+		}
+	}
+	return "", false
+}
+
+// jsString 生成 JS 字符串字面量（JSON 字符串是合法的 JS 字符串）
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
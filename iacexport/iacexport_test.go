@@ -0,0 +1,113 @@
+package iacexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"DomainC/cfclient"
+	"DomainC/dnsexport"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+func testInventory() Inventory {
+	yes := true
+	prio := uint16(10)
+	ip := "192.0.2.10"
+	return Inventory{Accounts: []AccountInventory{{
+		Label:     "Main Acc",
+		AccountID: "acc-id",
+		Zones: []dnsexport.Zone{{
+			AccountLabel: "Main Acc",
+			Detail:       cfclient.ZoneDetail{ID: "zone-id", Name: "example.com"},
+			Records: []cloudflare.DNSRecord{
+				{ID: "r1", Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 1, Proxied: &yes, Proxiable: true},
+				{ID: "r2", Type: "MX", Name: "example.com", Content: "mail.example.com", TTL: 3600, Priority: &prio},
+				{ID: "r3", Type: "TXT", Name: "example.com", Content: `v=spf1 ${x} "q"`, TTL: 1},
+				{ID: "r4", Type: "SRV", Name: "_sip._tcp.example.com", Content: "5 5060 sip.example.com", TTL: 1, Priority: &prio},
+				{ID: "r5", Type: "HTTPS", Name: "example.com", Content: "1 . alpn=h2", TTL: 1},
+			},
+		}},
+		Lists: []ListInventory{{
+			List:  cloudflare.List{ID: "list-id", Name: "blocked", Kind: "ip"},
+			Items: []cloudflare.ListItem{{IP: &ip, Comment: "bad bot"}},
+		}},
+	}}}
+}
+
+func TestTerraform(t *testing.T) {
+	files := Terraform(testInventory())
+	tf := string(files["main_acc.tf"])
+	for _, want := range []string{
+		`resource "cloudflare_zone" "example_com"`,
+		`resource "cloudflare_record" "example_com_a"`,
+		`zone_id  = cloudflare_zone.example_com.id`,
+		`content  = "v=spf1 $${x} \"q\""`,
+		"data {\n    priority = 10\n    weight   = 5\n    port     = 5060\n    target   = \"sip.example.com\"",
+		`resource "cloudflare_list" "list_main_acc_blocked"`,
+		`ip = "192.0.2.10"`,
+	} {
+		if !strings.Contains(tf, want) {
+			t.Errorf("terraform output missing %q\n%s", want, tf)
+		}
+	}
+	imports := string(files["import.sh"])
+	for _, want := range []string{
+		"terraform import cloudflare_zone.example_com zone-id",
+		"terraform import cloudflare_record.example_com_a zone-id/r1",
+		"terraform import cloudflare_list.list_main_acc_blocked acc-id/list-id",
+	} {
+		if !strings.Contains(imports, want) {
+			t.Errorf("import.sh missing %q\n%s", want, imports)
+		}
+	}
+	if !strings.Contains(string(files["providers.tf"]), `alias     = "main_acc"`) {
+		t.Errorf("providers.tf missing alias:\n%s", files["providers.tf"])
+	}
+}
+
+func TestDNSControl(t *testing.T) {
+	js := string(DNSControl(testInventory())["dnsconfig.js"])
+	for _, want := range []string{
+		`var DSP_MAIN_ACC = NewDnsProvider("cloudflare_main_acc");`,
+		`A("@", "192.0.2.1", TTL(1), CF_PROXY_ON)`,
+		`MX("@", 10, "mail.example.com.", TTL(3600))`,
+		`SRV("_sip._tcp", 10, 5, 5060, "sip.example.com.", TTL(1))`,
+		"// 不支持自动转换: HTTPS",
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("dnsconfig.js missing %q\n%s", want, js)
+		}
+	}
+	if strings.Contains(js, ",\n);") {
+		t.Errorf("unexpected trailing comma in D():\n%s", js)
+	}
+}
+
+func TestGenerateZip(t *testing.T) {
+	files, err := Generate(testInventory())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := files.WriteZip(buf); err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	want := "dnscontrol/creds.json dnscontrol/dnsconfig.js terraform/import.sh terraform/main_acc.tf terraform/providers.tf"
+	if strings.Join(names, " ") != want {
+		t.Fatalf("unexpected zip entries: %v", names)
+	}
+	if _, err := Generate(testInventory(), "pulumi"); err == nil {
+		t.Fatalf("expected error for unknown target")
+	}
+}
@@ -0,0 +1,176 @@
+// Package iacexport 把 Cloudflare 现有资源导出为 Terraform HCL（含 terraform import 命令）与 DNSControl dnsconfig.js，
+// 用于把存量 Zone 迁移到基础设施即代码管理。
+package iacexport
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/dnsexport"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Inventory 是一次导出的全部资源
+type Inventory struct {
+	Accounts []AccountInventory
+}
+
+// AccountInventory 是单个账号下的 Zone、解析记录与自定义列表
+type AccountInventory struct {
+	Label     string
+	AccountID string
+	Zones     []dnsexport.Zone
+	Lists     []ListInventory
+}
+
+type ListInventory struct {
+	List  cloudflare.List
+	Items []cloudflare.ListItem
+}
+
+// Collect 遍历账号收集 Zone、解析记录与自定义列表
+func Collect(ctx context.Context, client cfclient.Client, accounts []config.CF) (Inventory, error) {
+	var inv Inventory
+	for _, acc := range accounts {
+		ai := AccountInventory{Label: acc.Label, AccountID: strings.TrimSpace(acc.AccountID)}
+		if ai.AccountID == "" {
+			id, err := client.GetAccountID(ctx, acc)
+			if err != nil {
+				return inv, err
+			}
+			ai.AccountID = id
+		}
+
+		zones, err := client.ListZones(ctx, acc)
+		if err != nil {
+			return inv, fmt.Errorf("列出账号 %s 的域名失败: %w", acc.Label, err)
+		}
+		sort.Slice(zones, func(i, j int) bool { return zones[i].Name < zones[j].Name })
+		for _, z := range zones {
+			records, err := client.ListDNSRecords(ctx, acc, z.Name)
+			if err != nil {
+				return inv, fmt.Errorf("获取 %s(%s) DNS 失败: %w", z.Name, acc.Label, err)
+			}
+			ai.Zones = append(ai.Zones, dnsexport.Zone{AccountLabel: acc.Label, Detail: z, Records: records})
+		}
+
+		lists, err := client.ListCustomLists(ctx, acc)
+		if err != nil {
+			return inv, fmt.Errorf("获取账号 %s 的自定义列表失败: %w", acc.Label, err)
+		}
+		for _, l := range lists {
+			items, err := client.ListCustomListItems(ctx, acc, l.ID)
+			if err != nil {
+				return inv, fmt.Errorf("获取列表 %s(%s) 条目失败: %w", l.Name, acc.Label, err)
+			}
+			ai.Lists = append(ai.Lists, ListInventory{List: l, Items: items})
+		}
+		inv.Accounts = append(inv.Accounts, ai)
+	}
+	return inv, nil
+}
+
+// Files 是导出结果：相对路径 → 文件内容
+type Files map[string][]byte
+
+// Generate 按目标生成文件，targets 可为 terraform、dnscontrol；为空时全部生成
+func Generate(inv Inventory, targets ...string) (Files, error) {
+	if len(targets) == 0 {
+		targets = []string{TargetTerraform, TargetDNSControl}
+	}
+	files := Files{}
+	for _, t := range targets {
+		switch strings.ToLower(t) {
+		case TargetTerraform:
+			for name, data := range Terraform(inv) {
+				files["terraform/"+name] = data
+			}
+		case TargetDNSControl:
+			for name, data := range DNSControl(inv) {
+				files["dnscontrol/"+name] = data
+			}
+		default:
+			return nil, fmt.Errorf("不支持的导出目标 %q（可选: %s, %s）", t, TargetTerraform, TargetDNSControl)
+		}
+	}
+	return files, nil
+}
+
+const (
+	TargetTerraform  = "terraform"
+	TargetDNSControl = "dnscontrol"
+)
+
+// WriteZip 把文件按路径排序写入 zip
+func (f Files) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	for _, name := range f.Names() {
+		fw, err := zw.Create(name)
+		if err != nil {
+			return fmt.Errorf("创建压缩文件 %s 失败: %w", name, err)
+		}
+		if _, err := fw.Write(f[name]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// Names 返回排序后的文件路径
+func (f Files) Names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var identUnsafe = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// identifier 生成 Terraform / JS 可用的标识符
+func identifier(parts ...string) string {
+	s := strings.ToLower(strings.Join(parts, "_"))
+	s = identUnsafe.ReplaceAllString(s, "_")
+	s = strings.Trim(s, "_")
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		s = "r_" + s
+	}
+	return s
+}
+
+// uniqueNames 为重复的标识符追加序号
+type uniqueNames map[string]int
+
+func (u uniqueNames) next(name string) string {
+	u[name]++
+	if n := u[name]; n > 1 {
+		return fmt.Sprintf("%s_%d", name, n)
+	}
+	return name
+}
+
+// relativeName 返回相对 Zone 的记录名，根域为 @
+func relativeName(name, zone string) string {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+	switch {
+	case name == zone:
+		return "@"
+	case strings.HasSuffix(name, "."+zone):
+		return strings.TrimSuffix(name, "."+zone)
+	}
+	return name
+}
+
+func isProxied(r cloudflare.DNSRecord) bool {
+	return r.Proxied != nil && *r.Proxied
+}
@@ -0,0 +1,221 @@
+package iacexport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Terraform 生成 Cloudflare provider v4 语法的 HCL：
+// providers.tf（每个账号一个 provider 别名）、<账号>.tf 与 import.sh
+func Terraform(inv Inventory) Files {
+	files := Files{}
+	names := uniqueNames{}
+
+	var providers, imports strings.Builder
+	providers.WriteString("terraform {\n  required_providers {\n    cloudflare = {\n      source  = \"cloudflare/cloudflare\"\n      version = \"~> 4.0\"\n    }\n  }\n}\n")
+	imports.WriteString("#!/bin/sh\n# 把现有 Cloudflare 资源导入 Terraform state\nset -e\n\n")
+
+	for _, acc := range inv.Accounts {
+		alias := identifier(acc.Label)
+		providers.WriteString(fmt.Sprintf("\nvariable %s {\n  type      = string\n  sensitive = true\n}\n", hclString(alias+"_api_token")))
+		providers.WriteString(fmt.Sprintf("\nprovider \"cloudflare\" {\n  alias     = %s\n  api_token = var.%s_api_token\n}\n", hclString(alias), alias))
+
+		var tf strings.Builder
+		tf.WriteString(fmt.Sprintf("# 账号: %s\n", acc.Label))
+		for _, z := range acc.Zones {
+			zoneRes := names.next(identifier(z.Detail.Name))
+			tf.WriteString(fmt.Sprintf("\nresource \"cloudflare_zone\" %s {\n", hclString(zoneRes)))
+			writeAttrs(&tf, 2,
+				attr{"provider", "cloudflare." + alias},
+				attr{"account_id", hclString(acc.AccountID)},
+				attr{"zone", hclString(z.Detail.Name)},
+				attr{"paused", strconv.FormatBool(z.Detail.Paused)},
+			)
+			tf.WriteString("}\n")
+			imports.WriteString(fmt.Sprintf("terraform import cloudflare_zone.%s %s\n", zoneRes, z.Detail.ID))
+
+			for _, r := range z.Records {
+				recRes := names.next(identifier(z.Detail.Name, r.Type, relativeName(r.Name, z.Detail.Name)))
+				tf.WriteString(fmt.Sprintf("\nresource \"cloudflare_record\" %s {\n", hclString(recRes)))
+				writeRecord(&tf, alias, zoneRes, z.Detail.Name, r)
+				tf.WriteString("}\n")
+				imports.WriteString(fmt.Sprintf("terraform import cloudflare_record.%s %s/%s\n", recRes, z.Detail.ID, r.ID))
+			}
+		}
+
+		for _, l := range acc.Lists {
+			listRes := names.next(identifier("list", acc.Label, l.List.Name))
+			tf.WriteString(fmt.Sprintf("\nresource \"cloudflare_list\" %s {\n", hclString(listRes)))
+			writeAttrs(&tf, 2,
+				attr{"provider", "cloudflare." + alias},
+				attr{"account_id", hclString(acc.AccountID)},
+				attr{"name", hclString(l.List.Name)},
+				attr{"kind", hclString(l.List.Kind)},
+				attr{"description", hclString(l.List.Description)},
+			)
+			for _, item := range l.Items {
+				writeListItem(&tf, item)
+			}
+			tf.WriteString("}\n")
+			imports.WriteString(fmt.Sprintf("terraform import cloudflare_list.%s %s/%s\n", listRes, acc.AccountID, l.List.ID))
+		}
+
+		files[identifier(acc.Label)+".tf"] = []byte(tf.String())
+	}
+
+	files["providers.tf"] = []byte(providers.String())
+	files["import.sh"] = []byte(imports.String())
+	return files
+}
+
+func writeRecord(sb *strings.Builder, alias, zoneRes, zone string, r cloudflare.DNSRecord) {
+	attrs := []attr{
+		{"provider", "cloudflare." + alias},
+		{"zone_id", "cloudflare_zone." + zoneRes + ".id"},
+		{"name", hclString(relativeName(r.Name, zone))},
+		{"type", hclString(strings.ToUpper(r.Type))},
+	}
+	data := recordData(r)
+	if data == nil {
+		attrs = append(attrs, attr{"content", hclString(r.Content)})
+	}
+	if r.Proxiable || isProxied(r) {
+		attrs = append(attrs, attr{"proxied", strconv.FormatBool(isProxied(r))})
+	}
+	attrs = append(attrs, attr{"ttl", strconv.Itoa(r.TTL)})
+	if r.Priority != nil && data == nil {
+		attrs = append(attrs, attr{"priority", strconv.Itoa(int(*r.Priority))})
+	}
+	if r.Comment != "" {
+		attrs = append(attrs, attr{"comment", hclString(r.Comment)})
+	}
+	if len(r.Tags) > 0 {
+		quoted := make([]string, 0, len(r.Tags))
+		for _, t := range r.Tags {
+			quoted = append(quoted, hclString(t))
+		}
+		attrs = append(attrs, attr{"tags", "[" + strings.Join(quoted, ", ") + "]"})
+	}
+	writeAttrs(sb, 2, attrs...)
+	if data != nil {
+		sb.WriteString("\n  data {\n")
+		writeAttrs(sb, 4, data...)
+		sb.WriteString("  }\n")
+	}
+}
+
+// recordData 返回需要写成 data 块的记录（SRV、CAA），其它类型返回 nil
+func recordData(r cloudflare.DNSRecord) []attr {
+	fields := strings.Fields(r.Content)
+	switch strings.ToUpper(r.Type) {
+	case "SRV":
+		// content 为 "weight port target"
+		if len(fields) != 3 {
+			return nil
+		}
+		prio := 0
+		if r.Priority != nil {
+			prio = int(*r.Priority)
+		}
+		return []attr{
+			{"priority", strconv.Itoa(prio)},
+			{"weight", fields[0]},
+			{"port", fields[1]},
+			{"target", hclString(strings.TrimSuffix(fields[2], "."))},
+		}
+	case "CAA":
+		// content 为 `flags tag "value"`
+		parts := strings.SplitN(r.Content, " ", 3)
+		if len(parts) != 3 {
+			return nil
+		}
+		return []attr{
+			{"flags", parts[0]},
+			{"tag", hclString(parts[1])},
+			{"value", hclString(strings.Trim(parts[2], `"`))},
+		}
+	}
+	return nil
+}
+
+func writeListItem(sb *strings.Builder, item cloudflare.ListItem) {
+	sb.WriteString("\n  item {\n    value {\n")
+	switch {
+	case item.IP != nil:
+		writeAttrs(sb, 6, attr{"ip", hclString(*item.IP)})
+	case item.ASN != nil:
+		writeAttrs(sb, 6, attr{"asn", strconv.FormatUint(uint64(*item.ASN), 10)})
+	case item.Hostname != nil:
+		sb.WriteString("      hostname {\n")
+		writeAttrs(sb, 8, attr{"url_hostname", hclString(item.Hostname.UrlHostname)})
+		sb.WriteString("      }\n")
+	case item.Redirect != nil:
+		rd := item.Redirect
+		attrs := []attr{{"source_url", hclString(rd.SourceUrl)}, {"target_url", hclString(rd.TargetUrl)}}
+		if rd.StatusCode != nil {
+			attrs = append(attrs, attr{"status_code", strconv.Itoa(*rd.StatusCode)})
+		}
+		for _, opt := range []struct {
+			name string
+			v    *bool
+		}{
+			{"include_subdomains", rd.IncludeSubdomains},
+			{"subpath_matching", rd.SubpathMatching},
+			{"preserve_query_string", rd.PreserveQueryString},
+			{"preserve_path_suffix", rd.PreservePathSuffix},
+		} {
+			if opt.v != nil {
+				attrs = append(attrs, attr{opt.name, hclString(enabled(*opt.v))})
+			}
+		}
+		sb.WriteString("      redirect {\n")
+		writeAttrs(sb, 8, attrs...)
+		sb.WriteString("      }\n")
+	}
+	sb.WriteString("    }\n")
+	if item.Comment != "" {
+		writeAttrs(sb, 4, attr{"comment", hclString(item.Comment)})
+	}
+	sb.WriteString("  }\n")
+}
+
+type attr struct {
+	name  string
+	value string
+}
+
+// writeAttrs 按 terraform fmt 的习惯对齐等号
+func writeAttrs(sb *strings.Builder, indent int, attrs ...attr) {
+	width := 0
+	for _, a := range attrs {
+		width = max(width, len(a.name))
+	}
+	pad := strings.Repeat(" ", indent)
+	for _, a := range attrs {
+		sb.WriteString(fmt.Sprintf("%s%-*s = %s\n", pad, width, a.name, a.value))
+	}
+}
+
+// hclString 按 HCL 规则转义字符串，包括 ${ 与 %{ 模板序列
+func hclString(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+		"${", "$${",
+		"%{", "%%{",
+	)
+	return `"` + r.Replace(s) + `"`
+}
+
+func enabled(b bool) string {
+	if b {
+		return "enabled"
+	}
+	return "disabled"
+}
@@ -24,6 +24,14 @@ type Notifier interface {
 
 type Scheduler interface {
 	ScheduleDaily(ctx context.Context, hour, min int, job func())
+	ScheduleEvery(ctx context.Context, interval time.Duration, job func())
+}
+
+// Job 是按固定间隔执行的后台任务
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context)
 }
 
 type App struct {
@@ -33,6 +41,7 @@ type App struct {
 	Scheduler Scheduler
 	AlertHour int
 	AlertMin  int
+	Jobs      []Job
 }
 
 func (a *App) Run(ctx context.Context) error {
@@ -72,6 +81,14 @@ func (a *App) Run(ctx context.Context) error {
 		run()
 	})
 
+	for _, job := range a.Jobs {
+		job := job
+		log.Printf("注册计划任务: %s（每 %v）", job.Name, job.Interval)
+		a.Scheduler.ScheduleEvery(ctx, job.Interval, func() {
+			job.Run(ctx)
+		})
+	}
+
 	<-ctx.Done()
 	return ctx.Err()
 }
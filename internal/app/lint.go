@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/dnslint"
+	"DomainC/telegram"
+)
+
+// DNSLintService 定时对全部账号执行解析检查并推送问题
+type DNSLintService struct {
+	CFClient    cfclient.Client
+	Linter      *dnslint.Linter
+	Sender      telegram.Sender
+	Accounts    []config.CF
+	MinSeverity dnslint.Severity
+}
+
+// Run 执行一次检查
+func (s *DNSLintService) Run(ctx context.Context) {
+	if s.CFClient == nil || s.Linter == nil || s.Sender == nil {
+		log.Printf("解析检查任务缺少依赖，跳过")
+		return
+	}
+	zones, errs := dnslint.Collect(ctx, s.CFClient, s.Accounts, "")
+	for _, err := range errs {
+		log.Printf("解析检查: %v", err)
+	}
+	issues := dnslint.AtLeast(s.Linter.Lint(zones), s.MinSeverity)
+	if len(issues) == 0 {
+		return
+	}
+	lines := make([]string, 0, len(issues))
+	for _, is := range issues {
+		lines = append(lines, fmt.Sprintf("%s\n   账号 %s / Zone %s", is.String(), is.Account, is.Zone))
+	}
+	header := fmt.Sprintf("🧹【解析检查】%d 个 Zone 发现 %d 个问题", len(zones), len(issues))
+	if err := s.Sender.Send(ctx, header+"\n"+strings.Join(lines, "\n")); err != nil {
+		log.Printf("发送解析检查结果失败: %v", err)
+	}
+}
@@ -55,6 +55,15 @@ func (f *fakeSender) ClearButtons(ctx context.Context, chatID int64, messageID i
 func (f *fakeSender) AnswerCallback(ctx context.Context, callbackID, text string) error {
 	return nil
 }
+func (f *fakeSender) DownloadFile(ctx context.Context, fileID string) ([]byte, error) {
+	return nil, nil
+}
+func (f *fakeSender) SendDocumentTo(ctx context.Context, chatID int64, filepath string, caption string) (int, error) {
+	return 0, nil
+}
+func (f *fakeSender) DeleteMessage(ctx context.Context, chatID int64, messageID int) error {
+	return nil
+}
 func (f *fakeSender) StartListener(ctx context.Context, handleCallback func(cb *tgbotapi.CallbackQuery), handleMessage func(msg *tgbotapi.Message)) error {
 	<-ctx.Done()
 	return nil
@@ -75,6 +84,9 @@ func (f *fakeCF) DeleteDomain(ctx context.Context, account config.CF, domain str
 	f.deleted = append(f.deleted, domain)
 	return nil
 }
+func (f *fakeCF) DeleteZoneByID(ctx context.Context, account config.CF, zoneID string) error {
+	return nil
+}
 func (f *fakeCF) GetZoneDetails(ctx context.Context, account config.CF, domain string) (cfclient.ZoneDetail, error) {
 	return cfclient.ZoneDetail{}, nil
 }
@@ -87,6 +99,9 @@ func (f *fakeCF) UpsertDNSRecord(ctx context.Context, account config.CF, domain
 func (f *fakeCF) DeleteDNSRecord(ctx context.Context, account config.CF, domain string, recordName string) (int, error) {
 	return 0, nil
 }
+func (f *fakeCF) DeleteDNSRecordByID(ctx context.Context, account config.CF, domain string, recordID string) error {
+	return nil
+}
 func (f *fakeCF) ListZones(ctx context.Context, account config.CF) ([]cfclient.ZoneDetail, error) {
 	return nil, nil
 }
@@ -94,7 +109,47 @@ func (f *fakeCF) PurgeZoneCache(ctx context.Context, account config.CF, zoneID s
 	return nil
 }
 
-func (f *fakeCF) CreateOriginCertificate(ctx context.Context, account config.CF, hostnames []string) (cfclient.OriginCert, error) {
+func (f *fakeCF) PurgeCacheFiles(ctx context.Context, account config.CF, zoneID string, urls []string) (int, error) {
+	return len(urls), nil
+}
+
+func (f *fakeCF) PurgeCacheHosts(ctx context.Context, account config.CF, zoneID string, hosts []string) (int, error) {
+	return len(hosts), nil
+}
+
+func (f *fakeCF) PurgeCachePrefixes(ctx context.Context, account config.CF, zoneID string, prefixes []string) (int, error) {
+	return len(prefixes), nil
+}
+
+func (f *fakeCF) PurgeCacheTags(ctx context.Context, account config.CF, zoneID string, tags []string) (int, error) {
+	return len(tags), nil
+}
+
+func (f *fakeCF) GetRedirectRule(ctx context.Context, account config.CF, zoneID string) (*cfclient.RedirectRule, error) {
+	return nil, nil
+}
+
+func (f *fakeCF) UpsertRedirectRule(ctx context.Context, account config.CF, zoneID string, rule cfclient.RedirectRule) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeCF) DeleteRedirectRule(ctx context.Context, account config.CF, zoneID string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeCF) ListBulkRedirects(ctx context.Context, account config.CF, listName string) ([]cfclient.BulkRedirect, error) {
+	return nil, nil
+}
+
+func (f *fakeCF) AddBulkRedirects(ctx context.Context, account config.CF, listName string, redirects []cfclient.BulkRedirect) error {
+	return nil
+}
+
+func (f *fakeCF) DeleteBulkRedirects(ctx context.Context, account config.CF, listName string, sources []string) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeCF) CreateOriginCertificate(ctx context.Context, account config.CF, hostnames []string, opts cfclient.OriginCertOptions) (cfclient.OriginCert, error) {
 	return cfclient.OriginCert{}, nil
 }
 func (f *fakeCF) ListOriginCACertificates(ctx context.Context, account config.CF) ([]cfclient.OriginCACertInfo, error) {
@@ -121,6 +176,30 @@ func (f *fakeCF) SetZoneSSLFullStrict(ctx context.Context, account config.CF, zo
 func (f *fakeCF) GetAbuseReportCount(ctx context.Context, account config.CF) (int, error) {
 	return 0, nil
 }
+func (f *fakeCF) GetAccountID(ctx context.Context, account config.CF) (string, error) {
+	return "", nil
+}
+func (f *fakeCF) ListZoneOriginCACertificates(ctx context.Context, account config.CF, zoneID string) ([]cfclient.OriginCACertInfo, error) {
+	return nil, nil
+}
+func (f *fakeCF) RevokeOriginCACertificate(ctx context.Context, account config.CF, certID string) error {
+	return nil
+}
+func (f *fakeCF) GetDNSSEC(ctx context.Context, account config.CF, domain string) (cfclient.DNSSECInfo, error) {
+	return cfclient.DNSSECInfo{}, nil
+}
+func (f *fakeCF) EnableDNSSEC(ctx context.Context, account config.CF, domain string) (cfclient.DNSSECInfo, error) {
+	return cfclient.DNSSECInfo{}, nil
+}
+func (f *fakeCF) DisableDNSSEC(ctx context.Context, account config.CF, domain string) (cfclient.DNSSECInfo, error) {
+	return cfclient.DNSSECInfo{}, nil
+}
+func (f *fakeCF) GetZoneSettings(ctx context.Context, account config.CF, zoneID string) (map[string]string, error) {
+	return nil, nil
+}
+func (f *fakeCF) UpdateZoneSetting(ctx context.Context, account config.CF, zoneID, id, value string) error {
+	return nil
+}
 func TestNotifierSendsAlertsAndDeletes(t *testing.T) {
 	sender := &fakeSender{}
 	cf := &fakeCF{}
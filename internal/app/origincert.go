@@ -0,0 +1,172 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/origincert"
+	"DomainC/telegram"
+)
+
+// DefaultOriginCertStateFile 未配置时保存已提醒记录的文件
+const DefaultOriginCertStateFile = "origin_cert_alerts.json"
+
+// OriginCertService 定时检查源站证书到期情况，每张证书在每个阈值只提醒一次，
+// 已提醒记录保存在 File 中，重启后不会重复提醒
+type OriginCertService struct {
+	CFClient    cfclient.Client
+	Sender      telegram.Sender
+	Accounts    []config.CF
+	AlertDays   []int
+	ExpiredDays int // 过期超过该天数后不再提醒，<= 0 时使用 origincert.DefaultExpiredDays
+	File        string
+	Now         func() time.Time
+
+	mu       sync.Mutex
+	reported map[string]bool
+}
+
+// Run 执行一次检查
+func (s *OriginCertService) Run(ctx context.Context) {
+	if s.CFClient == nil || s.Sender == nil {
+		log.Printf("源站证书检查缺少依赖，跳过")
+		return
+	}
+	now := time.Now()
+	if s.Now != nil {
+		now = s.Now()
+	}
+	certs, errs := origincert.Collect(ctx, s.CFClient, s.Accounts, "")
+	for _, err := range errs {
+		log.Printf("源站证书检查: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reported == nil {
+		reported, err := s.load()
+		if err != nil {
+			log.Printf("源站证书检查: %v", err)
+			reported = map[string]bool{}
+		}
+		s.reported = reported
+	}
+	expiring := origincert.Expiring(certs, now, s.AlertDays, s.ExpiredDays)
+	changed := false
+	if len(errs) == 0 {
+		// 只在完整拉取时清理：不再命中提醒的证书（已续签、吊销或过期太久）无需保留记录
+		current := map[string]bool{}
+		for _, e := range expiring {
+			current[e.ID] = true
+		}
+		for key := range s.reported {
+			if id, _, _ := strings.Cut(key, "|"); !current[id] {
+				delete(s.reported, key)
+				changed = true
+			}
+		}
+	}
+
+	var keys, lines, zones []string
+	for _, e := range expiring {
+		key := fmt.Sprintf("%s|%d", e.ID, e.Threshold)
+		if s.reported[key] {
+			continue
+		}
+		keys = append(keys, key)
+		state := fmt.Sprintf("剩 %d 天", e.DaysLeft)
+		if e.DaysLeft < 0 {
+			state = "已过期"
+		}
+		lines = append(lines, fmt.Sprintf("⚠️ %s (%s) [%s] %s\n   %s，到期 %s",
+			e.Zone, e.Account, origincert.ShortID(e.ID), strings.Join(e.Hostnames, ", "), state, e.ExpiresOn.Format("2006-01-02")))
+		zones = append(zones, e.Zone)
+	}
+	if len(lines) > 0 && s.notify(ctx, lines, zones) {
+		for _, key := range keys {
+			s.reported[key] = true
+		}
+		changed = true
+	}
+	if changed {
+		if err := s.save(); err != nil {
+			log.Printf("源站证书检查: %v", err)
+		}
+	}
+}
+
+// notify 发送提醒与重新签发按钮，返回提醒是否发送成功
+func (s *OriginCertService) notify(ctx context.Context, lines, zones []string) bool {
+	header := fmt.Sprintf("📜【源站证书到期提醒】%d 张证书即将到期", len(lines))
+	if err := s.Sender.Send(ctx, header+"\n"+strings.Join(lines, "\n")); err != nil {
+		log.Printf("发送源站证书提醒失败: %v", err)
+		return false
+	}
+	if buttons := telegram.CertRegenButtons(zones, "定时任务"); len(buttons) > 0 {
+		if err := s.Sender.SendWithButtons(ctx, "可按 /ssl 流程重新签发（裸域 + 通配符）：", buttons); err != nil {
+			log.Printf("发送重新签发按钮失败: %v", err)
+		}
+	}
+	return true
+}
+
+func (s *OriginCertService) path() string {
+	if strings.TrimSpace(s.File) == "" {
+		return DefaultOriginCertStateFile
+	}
+	return s.File
+}
+
+func (s *OriginCertService) load() (map[string]bool, error) {
+	reported := map[string]bool{}
+	data, err := os.ReadFile(s.path())
+	if os.IsNotExist(err) {
+		return reported, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取源站证书提醒记录失败 [%s]: %v", s.path(), err)
+	}
+	var keys []string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("解析源站证书提醒记录失败 [%s]: %v", s.path(), err)
+	}
+	for _, key := range keys {
+		reported[key] = true
+	}
+	return reported, nil
+}
+
+func (s *OriginCertService) save() error {
+	keys := make([]string, 0, len(s.reported))
+	for key := range s.reported {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(s.path()); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("保存源站证书提醒记录失败 [%s]: %v", s.path(), err)
+		}
+	}
+	tmp := s.path() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("保存源站证书提醒记录失败 [%s]: %v", s.path(), err)
+	}
+	if err := os.Rename(tmp, s.path()); err != nil {
+		return fmt.Errorf("保存源站证书提醒记录失败 [%s]: %v", s.path(), err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+type originCertCF struct {
+	*fakeCF
+	certs []cfclient.OriginCACertInfo
+}
+
+func (f *originCertCF) ListZones(ctx context.Context, account config.CF) ([]cfclient.ZoneDetail, error) {
+	return []cfclient.ZoneDetail{{ID: "z1", Name: "example.com"}}, nil
+}
+
+func (f *originCertCF) ListZoneOriginCACertificates(ctx context.Context, account config.CF, zoneID string) ([]cfclient.OriginCACertInfo, error) {
+	return f.certs, nil
+}
+
+func TestOriginCertServicePersistsReported(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	cf := &originCertCF{fakeCF: &fakeCF{}, certs: []cfclient.OriginCACertInfo{
+		{ID: "soon", Hostnames: []string{"a.example.com"}, ExpiresOn: now.Add(5*day + time.Hour)},
+		{ID: "expired", Hostnames: []string{"b.example.com"}, ExpiresOn: now.Add(-3 * day)},
+		{ID: "stale", Hostnames: []string{"c.example.com"}, ExpiresOn: now.Add(-90 * day)},
+	}}
+	file := filepath.Join(t.TempDir(), "alerts.json")
+	newService := func(sender *fakeSender) *OriginCertService {
+		return &OriginCertService{
+			CFClient:    cf,
+			Sender:      sender,
+			Accounts:    []config.CF{{Label: "acc"}},
+			AlertDays:   []int{30, 7},
+			ExpiredDays: 30,
+			File:        file,
+			Now:         func() time.Time { return now },
+		}
+	}
+
+	sender := &fakeSender{}
+	newService(sender).Run(context.Background())
+	if len(sender.messages) == 0 {
+		t.Fatalf("expected an alert")
+	}
+	alert := sender.messages[0]
+	if !strings.Contains(alert, "a.example.com") || !strings.Contains(alert, "b.example.com") {
+		t.Fatalf("alert missing expiring certs: %s", alert)
+	}
+	if strings.Contains(alert, "c.example.com") {
+		t.Fatalf("cert expired long ago should not be alerted: %s", alert)
+	}
+
+	// 模拟重启：新的实例从文件读取已提醒记录，不再重复提醒
+	restarted := &fakeSender{}
+	newService(restarted).Run(context.Background())
+	if len(restarted.messages) != 0 {
+		t.Fatalf("expected no repeated alert after restart, got %v", restarted.messages)
+	}
+}
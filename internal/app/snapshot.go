@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/dnssnapshot"
+	"DomainC/telegram"
+)
+
+// snapshotAlertLimit 单条告警最多列出的变更数
+const snapshotAlertLimit = 40
+
+// DNSSnapshotService 定时保存所有 Zone 的解析快照，并把与上次快照的差异发到 Telegram
+type DNSSnapshotService struct {
+	CFClient cfclient.Client
+	Store    *dnssnapshot.Store
+	Sender   telegram.Sender
+	Accounts []config.CF
+	Keep     int
+}
+
+// Run 对所有账号执行一次快照
+func (s *DNSSnapshotService) Run(ctx context.Context) {
+	if s.CFClient == nil || s.Store == nil || s.Sender == nil {
+		log.Printf("DNS 快照任务缺少依赖，跳过")
+		return
+	}
+	for _, acc := range s.Accounts {
+		zones, err := s.CFClient.ListZones(ctx, acc)
+		if err != nil {
+			log.Printf("DNS 快照: 列出账号 %s 的域名失败: %v", acc.Label, err)
+			continue
+		}
+		for _, z := range zones {
+			if err := s.snapshotZone(ctx, acc, z); err != nil {
+				log.Printf("DNS 快照: %s(%s) 失败: %v", z.Name, acc.Label, err)
+			}
+		}
+	}
+}
+
+func (s *DNSSnapshotService) snapshotZone(ctx context.Context, acc config.CF, zone cfclient.ZoneDetail) error {
+	records, err := s.CFClient.ListDNSRecords(ctx, acc, zone.Name)
+	if err != nil {
+		return fmt.Errorf("获取解析失败: %w", err)
+	}
+	prev, err := s.Store.Latest(acc.Label, zone.Name)
+	if err != nil {
+		return err
+	}
+
+	snap := dnssnapshot.Snapshot{
+		Account: acc.Label,
+		Zone:    zone.Name,
+		ZoneID:  zone.ID,
+		TakenAt: time.Now(),
+		Records: records,
+	}
+	if _, err := s.Store.Save(snap); err != nil {
+		return err
+	}
+	keep := s.Keep
+	if keep <= 0 {
+		keep = dnssnapshot.DefaultKeep
+	}
+	if err := s.Store.Prune(acc.Label, zone.Name, keep); err != nil {
+		log.Printf("DNS 快照: 清理 %s(%s) 旧快照失败: %v", zone.Name, acc.Label, err)
+	}
+
+	// 第一次快照只作为基线，不告警
+	if prev == nil {
+		return nil
+	}
+	changes := dnssnapshot.Diff(prev.Records, records)
+	if len(changes) == 0 {
+		return nil
+	}
+	cs := dnssnapshot.ChangeSet{
+		Account: acc.Label,
+		Zone:    zone.Name,
+		From:    prev.TakenAt,
+		To:      snap.TakenAt,
+		Changes: changes,
+	}
+	return s.Sender.Send(ctx, "【DNS 变更检测】\n"+cs.Format(snapshotAlertLimit))
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"DomainC/config"
+	"DomainC/takeover"
+	"DomainC/telegram"
+)
+
+// TakeoverScanService 定时扫描悬空记录，只推送上次扫描之后新出现的发现
+type TakeoverScanService struct {
+	Scanner     *takeover.Scanner
+	Sender      telegram.Sender
+	Accounts    []config.CF
+	MinSeverity takeover.Severity
+
+	mu       sync.Mutex
+	reported map[string]bool
+}
+
+// Run 执行一次扫描
+func (s *TakeoverScanService) Run(ctx context.Context) {
+	if s.Scanner == nil || s.Sender == nil {
+		log.Printf("子域名接管扫描缺少依赖，跳过")
+		return
+	}
+	findings, errs := s.Scanner.Scan(ctx, s.Accounts)
+	for _, err := range errs {
+		log.Printf("子域名接管扫描: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := make(map[string]bool, len(findings))
+	var fresh []string
+	known := 0
+	for _, f := range findings {
+		if f.Severity < s.MinSeverity {
+			continue
+		}
+		key := f.Account + "|" + f.Name + "|" + f.Type + "|" + f.Target
+		current[key] = true
+		if s.reported[key] {
+			known++
+			continue
+		}
+		fresh = append(fresh, fmt.Sprintf("%s\n   账号 %s / Zone %s", f.String(), f.Account, f.Zone))
+	}
+	s.reported = current
+
+	if len(fresh) == 0 {
+		return
+	}
+	header := fmt.Sprintf("🚨【子域名接管风险】新发现 %d 条疑似悬空记录", len(fresh))
+	if known > 0 {
+		header += fmt.Sprintf("（另有 %d 条此前已报告）", known)
+	}
+	if err := s.Sender.Send(ctx, header+"\n"+strings.Join(fresh, "\n")); err != nil {
+		log.Printf("发送子域名接管告警失败: %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package mailauth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"DomainC/severity"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Level 是检查结果级别
+type Level = severity.Level
+
+const (
+	LevelOK      = severity.OK
+	LevelInfo    = severity.Info
+	LevelWarning = severity.Warning
+	LevelError   = severity.Error
+)
+
+// Finding 是一项检查结果
+type Finding struct {
+	Check   string // SPF / DMARC / DKIM / MTA-STS / TLS-RPT
+	Level   Level
+	Message string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s %s: %s", f.Level.Icon(), f.Check, f.Message)
+}
+
+// Report 是单个 Zone 的检查报告
+type Report struct {
+	Account   string
+	Zone      string
+	SendsMail bool // 根域存在非空 MX
+	Findings  []Finding
+}
+
+// Worst 返回报告中最严重的级别
+func (r Report) Worst() Level {
+	worst := LevelOK
+	for _, f := range r.Findings {
+		if f.Level > worst {
+			worst = f.Level
+		}
+	}
+	return worst
+}
+
+// Auditor 根据 Cloudflare 中保存的记录检查邮件认证
+type Auditor struct {
+	// DKIMSelectors 为需要检查的 DKIM 选择器，例如 google、selector1、k1
+	DKIMSelectors []string
+	// Lookup 用于递归统计 SPF include 的查询次数，为空时只统计本条记录
+	Lookup TXTLookup
+}
+
+// Audit 检查一个 Zone
+func (a *Auditor) Audit(ctx context.Context, account, zone string, records []cloudflare.DNSRecord) Report {
+	zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+	rep := Report{Account: account, Zone: zone}
+	byName := indexRecords(records)
+
+	for _, r := range byName[zone] {
+		if r.Type == "MX" && strings.TrimSuffix(strings.TrimSpace(r.Content), ".") != "" {
+			rep.SendsMail = true
+		}
+	}
+
+	rep.Findings = append(rep.Findings, a.checkSPF(ctx, rep.SendsMail, txtValues(byName[zone]))...)
+	rep.Findings = append(rep.Findings, checkDMARC(txtValues(byName["_dmarc."+zone]))...)
+	rep.Findings = append(rep.Findings, a.checkDKIM(zone, rep.SendsMail, byName)...)
+	if rep.SendsMail {
+		rep.Findings = append(rep.Findings, checkMTASTS(zone, byName)...)
+	}
+	return rep
+}
+
+func (a *Auditor) checkSPF(ctx context.Context, sendsMail bool, txts []string) []Finding {
+	var spfs []string
+	for _, t := range txts {
+		if IsSPF(t) {
+			spfs = append(spfs, t)
+		}
+	}
+	switch len(spfs) {
+	case 0:
+		msg := "缺少 SPF 记录，任何人都可以冒充该域名发信"
+		if !sendsMail {
+			msg += "；不收发邮件的域名建议设置 v=spf1 -all"
+		}
+		return []Finding{{Check: "SPF", Level: LevelError, Message: msg}}
+	case 1:
+	default:
+		return []Finding{{Check: "SPF", Level: LevelError, Message: fmt.Sprintf("存在 %d 条 SPF 记录，接收方会判定为 permerror，请合并为一条", len(spfs))}}
+	}
+
+	spf, err := ParseSPF(spfs[0])
+	if err != nil {
+		return []Finding{{Check: "SPF", Level: LevelError, Message: fmt.Sprintf("语法错误: %v（%s）", err, unquote(spfs[0]))}}
+	}
+	var out []Finding
+	lookups, lookupErr := CountLookups(ctx, spf, a.Lookup)
+	switch {
+	case lookups > SPFLookupLimit:
+		out = append(out, Finding{Check: "SPF", Level: LevelError, Message: fmt.Sprintf("DNS 查询次数 %d 超过上限 %d，接收方会判定为 permerror", lookups, SPFLookupLimit)})
+	case lookupErr != nil:
+		out = append(out, Finding{Check: "SPF", Level: LevelWarning, Message: fmt.Sprintf("无法完整统计查询次数（已计 %d 次）: %v", lookups, lookupErr)})
+	}
+
+	switch all := spf.All(); {
+	case all == 0 && spf.Redirect() == "":
+		out = append(out, Finding{Check: "SPF", Level: LevelWarning, Message: "缺少 all 机制，未授权的发信不会被拒绝"})
+	case all == '+':
+		out = append(out, Finding{Check: "SPF", Level: LevelError, Message: "+all 允许任何服务器代发，等同于没有 SPF"})
+	case all == '?':
+		out = append(out, Finding{Check: "SPF", Level: LevelWarning, Message: "?all 为中立，建议改为 ~all 或 -all"})
+	}
+	if len(out) == 0 {
+		out = append(out, Finding{Check: "SPF", Level: LevelOK, Message: fmt.Sprintf("%s（%d 次查询）", spf.Raw, lookups)})
+	}
+	return out
+}
+
+func checkDMARC(txts []string) []Finding {
+	var records []string
+	for _, t := range txts {
+		if IsDMARC(t) {
+			records = append(records, t)
+		}
+	}
+	switch len(records) {
+	case 0:
+		return []Finding{{Check: "DMARC", Level: LevelError, Message: "缺少 _dmarc 记录，接收方不会拒收冒充邮件"}}
+	case 1:
+	default:
+		return []Finding{{Check: "DMARC", Level: LevelError, Message: fmt.Sprintf("存在 %d 条 DMARC 记录，接收方会忽略 DMARC", len(records))}}
+	}
+	d, err := ParseDMARC(records[0])
+	if err != nil {
+		return []Finding{{Check: "DMARC", Level: LevelError, Message: fmt.Sprintf("语法错误: %v（%s）", err, unquote(records[0]))}}
+	}
+
+	var out []Finding
+	if d.Policy == "none" {
+		out = append(out, Finding{Check: "DMARC", Level: LevelWarning, Message: "p=none 只监控不拦截，确认报告无误后建议改为 quarantine 或 reject"})
+	}
+	if d.Percent < 100 {
+		out = append(out, Finding{Check: "DMARC", Level: LevelWarning, Message: fmt.Sprintf("pct=%d，只有部分邮件执行策略", d.Percent)})
+	}
+	if len(d.RUA) == 0 {
+		out = append(out, Finding{Check: "DMARC", Level: LevelInfo, Message: "未设置 rua，收不到聚合报告"})
+	}
+	if len(out) == 0 {
+		out = append(out, Finding{Check: "DMARC", Level: LevelOK, Message: "p=" + d.Policy})
+	}
+	return out
+}
+
+func (a *Auditor) checkDKIM(zone string, sendsMail bool, byName map[string][]cloudflare.DNSRecord) []Finding {
+	var found, broken []string
+	for _, sel := range a.DKIMSelectors {
+		name := strings.ToLower(sel) + "._domainkey." + zone
+		for _, r := range byName[name] {
+			switch r.Type {
+			case "CNAME":
+				// Microsoft 365 等通过 CNAME 托管密钥
+				found = append(found, sel+"(CNAME)")
+			case "TXT":
+				v := strings.ReplaceAll(unquote(r.Content), " ", "")
+				if strings.Contains(v, "p=") && !strings.Contains(v, "p=;") && !strings.HasSuffix(v, "p=") {
+					found = append(found, sel)
+				} else {
+					broken = append(broken, sel)
+				}
+			}
+		}
+	}
+
+	var out []Finding
+	if len(broken) > 0 {
+		out = append(out, Finding{Check: "DKIM", Level: LevelWarning, Message: "选择器公钥为空或无效: " + strings.Join(broken, ", ")})
+	}
+	switch {
+	case len(found) > 0:
+		out = append(out, Finding{Check: "DKIM", Level: LevelOK, Message: "已配置选择器: " + strings.Join(found, ", ")})
+	case !sendsMail:
+	case len(a.DKIMSelectors) == 0:
+		out = append(out, Finding{Check: "DKIM", Level: LevelInfo, Message: "未配置 mailAuth.dkimSelectors，跳过 DKIM 检查"})
+	default:
+		out = append(out, Finding{Check: "DKIM", Level: LevelWarning, Message: "未找到已知选择器的 DKIM 记录: " + strings.Join(a.DKIMSelectors, ", ")})
+	}
+	return out
+}
+
+func checkMTASTS(zone string, byName map[string][]cloudflare.DNSRecord) []Finding {
+	var out []Finding
+	sts := false
+	for _, t := range txtValues(byName["_mta-sts."+zone]) {
+		if strings.HasPrefix(strings.ToLower(unquote(t)), "v=stsv1") {
+			sts = true
+		}
+	}
+	if sts {
+		out = append(out, Finding{Check: "MTA-STS", Level: LevelOK, Message: "已发布 _mta-sts 记录"})
+	} else {
+		out = append(out, Finding{Check: "MTA-STS", Level: LevelInfo, Message: "未发布 MTA-STS，入站邮件可被降级为明文传输"})
+	}
+	rpt := false
+	for _, t := range txtValues(byName["_smtp._tls."+zone]) {
+		if strings.HasPrefix(strings.ToLower(unquote(t)), "v=tlsrptv1") {
+			rpt = true
+		}
+	}
+	if !rpt {
+		out = append(out, Finding{Check: "TLS-RPT", Level: LevelInfo, Message: "未发布 _smtp._tls 报告地址"})
+	}
+	return out
+}
+
+func indexRecords(records []cloudflare.DNSRecord) map[string][]cloudflare.DNSRecord {
+	out := make(map[string][]cloudflare.DNSRecord)
+	for _, r := range records {
+		name := strings.ToLower(strings.TrimSuffix(r.Name, "."))
+		out[name] = append(out[name], r)
+	}
+	return out
+}
+
+func txtValues(records []cloudflare.DNSRecord) []string {
+	var out []string
+	for _, r := range records {
+		if r.Type == "TXT" {
+			out = append(out, r.Content)
+		}
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package mailauth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DMARC 是解析后的 DMARC 记录
+type DMARC struct {
+	Policy          string // none/quarantine/reject
+	SubdomainPolicy string
+	Percent         int
+	RUA             []string
+	Tags            map[string]string
+}
+
+// IsDMARC 判断 TXT 内容是否为 DMARC 记录
+func IsDMARC(txt string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.ReplaceAll(unquote(txt), " ", "")), "v=dmarc1")
+}
+
+// ParseDMARC 解析并校验 DMARC 记录（RFC 7489）
+func ParseDMARC(txt string) (DMARC, error) {
+	d := DMARC{Percent: 100, Tags: map[string]string{}}
+	parts := strings.Split(unquote(txt), ";")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return DMARC{}, fmt.Errorf("无效的 DMARC 标签: %s", part)
+		}
+		key := strings.ToLower(strings.TrimSpace(kv[0]))
+		value := strings.TrimSpace(kv[1])
+		if i == 0 && (key != "v" || !strings.EqualFold(value, "DMARC1")) {
+			return DMARC{}, fmt.Errorf("DMARC 必须以 v=DMARC1 开头")
+		}
+		d.Tags[key] = value
+		switch key {
+		case "p", "sp":
+			value = strings.ToLower(value)
+			if value != "none" && value != "quarantine" && value != "reject" {
+				return DMARC{}, fmt.Errorf("%s 只能是 none/quarantine/reject: %s", key, value)
+			}
+			if key == "p" {
+				d.Policy = value
+			} else {
+				d.SubdomainPolicy = value
+			}
+		case "pct":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 || n > 100 {
+				return DMARC{}, fmt.Errorf("pct 必须是 0-100: %s", value)
+			}
+			d.Percent = n
+		case "rua":
+			for _, uri := range strings.Split(value, ",") {
+				if uri = strings.TrimSpace(uri); uri != "" {
+					d.RUA = append(d.RUA, uri)
+				}
+			}
+		}
+	}
+	if d.Policy == "" {
+		return DMARC{}, fmt.Errorf("DMARC 缺少 p 标签")
+	}
+	return d, nil
+}
@@ -0,0 +1,201 @@
+package mailauth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+type fakeTXT map[string][]string
+
+func (f fakeTXT) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	txts, ok := f[name]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return txts, nil
+}
+
+func TestParseSPF(t *testing.T) {
+	valid := []string{
+		`"v=spf1 include:_spf.google.com ~all"`,
+		"v=spf1 ip4:192.0.2.0/24 ip6:2001:db8::/32 a mx -all",
+		"v=spf1 redirect=_spf.example.com",
+		"v=spf1 a:mail.example.com/24 exists:%{i}.bl.example.com ?all",
+	}
+	for _, s := range valid {
+		if _, err := ParseSPF(s); err != nil {
+			t.Fatalf("ParseSPF(%q): %v", s, err)
+		}
+	}
+	invalid := []string{
+		"v=spf2 -all",
+		"v=spf1 ip4:300.1.1.1 -all",
+		"v=spf1 ip6:192.0.2.1 -all",
+		"v=spf1 include: -all",
+		"v=spf1 foo:bar -all",
+		"v=spf1 redirect=a redirect=b",
+	}
+	for _, s := range invalid {
+		if _, err := ParseSPF(s); err == nil {
+			t.Fatalf("ParseSPF(%q) should fail", s)
+		}
+	}
+}
+
+func TestCountLookups(t *testing.T) {
+	lookup := fakeTXT{
+		"_spf.a.com": {"v=spf1 include:_spf.b.com include:_spf.c.com ~all"},
+		"_spf.b.com": {"v=spf1 a mx ip4:192.0.2.1 ~all"},
+		"_spf.c.com": {"some-verification", "v=spf1 exists:x.c.com -all"},
+		"loop.com":   {"v=spf1 include:loop.com -all"},
+	}
+	spf, _ := ParseSPF("v=spf1 include:_spf.a.com mx -all")
+	n, err := CountLookups(context.Background(), spf, lookup)
+	// include(a) + mx + include(b) + include(c) + a + mx + exists = 7
+	if err != nil || n != 7 {
+		t.Fatalf("CountLookups = %d, %v; want 7", n, err)
+	}
+
+	spf, _ = ParseSPF("v=spf1 include:loop.com -all")
+	if _, err := CountLookups(context.Background(), spf, lookup); err == nil || !strings.Contains(err.Error(), "循环") {
+		t.Fatalf("expected loop error, got %v", err)
+	}
+
+	// 菱形引用：b 与 c 都 include 同一个 shared，不是循环，但 shared 的查询计两次
+	lookup["_spf.b.com"] = []string{"v=spf1 include:shared.com ~all"}
+	lookup["_spf.c.com"] = []string{"v=spf1 include:shared.com ~all"}
+	lookup["shared.com"] = []string{"v=spf1 a mx -all"}
+	spf, _ = ParseSPF("v=spf1 include:_spf.a.com -all")
+	n, err = CountLookups(context.Background(), spf, lookup)
+	// include(a) + include(b) + include(c) + 2×(include(shared) + a + mx) = 9
+	if err != nil || n != 9 {
+		t.Fatalf("diamond CountLookups = %d, %v; want 9", n, err)
+	}
+}
+
+func TestParseDMARC(t *testing.T) {
+	d, err := ParseDMARC(`"v=DMARC1; p=reject; pct=50; rua=mailto:a@example.com,mailto:b@example.com"`)
+	if err != nil || d.Policy != "reject" || d.Percent != 50 || len(d.RUA) != 2 {
+		t.Fatalf("unexpected DMARC: %+v, %v", d, err)
+	}
+	for _, bad := range []string{"v=DMARC1; pct=100", "p=reject; v=DMARC1", "v=DMARC1; p=block", "v=DMARC1; p=none; pct=200"} {
+		if _, err := ParseDMARC(bad); err == nil {
+			t.Fatalf("ParseDMARC(%q) should fail", bad)
+		}
+	}
+}
+
+func prioPtr(v uint16) *uint16 { return &v }
+
+func TestAudit(t *testing.T) {
+	a := &Auditor{DKIMSelectors: []string{"google", "selector1"}}
+
+	records := []cloudflare.DNSRecord{
+		{Type: "MX", Name: "example.com", Content: "smtp.google.com", Priority: prioPtr(1)},
+		{Type: "TXT", Name: "example.com", Content: `"v=spf1 include:_spf.google.com ~all"`},
+		{Type: "TXT", Name: "example.com", Content: "google-site-verification=abc"},
+		{Type: "TXT", Name: "_dmarc.example.com", Content: "v=DMARC1; p=none"},
+		{Type: "TXT", Name: "google._domainkey.example.com", Content: "v=DKIM1; k=rsa; p=MIIB"},
+	}
+	rep := a.Audit(context.Background(), "acc", "example.com", records)
+	if !rep.SendsMail || rep.Worst() != LevelWarning {
+		t.Fatalf("unexpected report: %+v", rep)
+	}
+	text := findingsText(rep)
+	for _, want := range []string{"✅ SPF", "p=none", "rua", "✅ DKIM: 已配置选择器: google", "MTA-STS"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("report missing %q:\n%s", want, text)
+		}
+	}
+
+	rep = a.Audit(context.Background(), "acc", "parked.com", []cloudflare.DNSRecord{
+		{Type: "TXT", Name: "parked.com", Content: "v=spf1 +all"},
+		{Type: "TXT", Name: "parked.com", Content: "v=spf1 -all"},
+	})
+	text = findingsText(rep)
+	if rep.SendsMail || rep.Worst() != LevelError || !strings.Contains(text, "2 条 SPF") || !strings.Contains(text, "缺少 _dmarc") {
+		t.Fatalf("unexpected report for parked.com:\n%s", text)
+	}
+	if strings.Contains(text, "DKIM") || strings.Contains(text, "MTA-STS") {
+		t.Fatalf("non-mail domain should skip DKIM/MTA-STS checks:\n%s", text)
+	}
+}
+
+func findingsText(r Report) string {
+	var lines []string
+	for _, f := range r.Findings {
+		lines = append(lines, f.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+type fakeCF struct {
+	cfclient.Client
+	deleted []string
+	written []cfclient.DNSRecordParams
+}
+
+func (f *fakeCF) DeleteDNSRecordByID(ctx context.Context, account config.CF, domain, id string) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func (f *fakeCF) UpsertDNSRecord(ctx context.Context, account config.CF, domain string, params cfclient.DNSRecordParams) (cloudflare.DNSRecord, error) {
+	f.written = append(f.written, params)
+	return cloudflare.DNSRecord{}, nil
+}
+
+func TestPlanAndApplyProfile(t *testing.T) {
+	profiles, err := LoadProfiles([]config.MailProfile{{
+		Name: "custom",
+		Records: []config.MailProfileRecord{
+			{Type: "MX", Name: "@", Content: "mx1.{zone}", Priority: prioPtr(10)},
+			{Type: "MX", Name: "@", Content: "mx2.{zone}", Priority: prioPtr(20)},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("LoadProfiles: %v", err)
+	}
+	if _, ok := profiles["no-mail"]; !ok {
+		t.Fatalf("builtin profiles should be kept")
+	}
+
+	steps := Plan("example.com", profiles["custom"], nil)
+	if steps[0].Params.Mode != cfclient.DNSWriteReplace || steps[1].Params.Mode != cfclient.DNSWriteAdd || steps[1].Params.Content != "mx2.example.com" {
+		t.Fatalf("unexpected MX steps: %+v", steps)
+	}
+
+	existing := []cloudflare.DNSRecord{
+		{ID: "spf", Type: "TXT", Name: "example.com", Content: `"v=spf1 include:old.example.net ~all"`},
+		{ID: "verify", Type: "TXT", Name: "example.com", Content: "google-site-verification=abc"},
+		{ID: "dmarc", Type: "TXT", Name: "_dmarc.example.com", Content: "v=DMARC1; p=none"},
+	}
+	steps = Plan("example.com", profiles["microsoft-365"], existing)
+	if steps[0].Params.Content != "example-com.mail.protection.outlook.com" {
+		t.Fatalf("zone_dash placeholder not rendered: %+v", steps[0])
+	}
+	cf := &fakeCF{}
+	lines, failed := Apply(context.Background(), cf, config.CF{}, "example.com", steps)
+	if failed != 0 || len(lines) != 4 {
+		t.Fatalf("unexpected apply result: %v", lines)
+	}
+	if strings.Join(cf.deleted, ",") != "spf,dmarc" {
+		t.Fatalf("only conflicting SPF/DMARC should be deleted, got %v", cf.deleted)
+	}
+}
+
+func TestLoadProfilesRejectsInvalid(t *testing.T) {
+	if _, err := LoadProfiles([]config.MailProfile{{Name: "x"}}); err == nil {
+		t.Fatalf("expected error for empty profile")
+	}
+	if _, err := LoadProfiles([]config.MailProfile{{Name: "x", Records: []config.MailProfileRecord{{Type: "TXT"}}}}); err == nil {
+		t.Fatalf("expected error for incomplete record")
+	}
+}
@@ -0,0 +1,176 @@
+package mailauth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Profile 是一组邮件相关记录模板
+type Profile struct {
+	Name        string
+	Description string
+	Records     []config.MailProfileRecord
+}
+
+func prio(v uint16) *uint16 { return &v }
+
+// BuiltinProfiles 返回内置模板
+func BuiltinProfiles() map[string]Profile {
+	return map[string]Profile{
+		"no-mail": {
+			Name:        "no-mail",
+			Description: "不收发邮件的域名：空 MX、拒绝一切 SPF、DMARC reject、吊销 DKIM",
+			Records: []config.MailProfileRecord{
+				{Type: "MX", Name: "@", Content: ".", Priority: prio(0)},
+				{Type: "TXT", Name: "@", Content: "v=spf1 -all"},
+				{Type: "TXT", Name: "_dmarc", Content: "v=DMARC1; p=reject; sp=reject; adkim=s; aspf=s"},
+				{Type: "TXT", Name: "*._domainkey", Content: "v=DKIM1; p="},
+			},
+		},
+		"google-workspace": {
+			Name:        "google-workspace",
+			Description: "Google Workspace：MX、SPF、DMARC（quarantine）",
+			Records: []config.MailProfileRecord{
+				{Type: "MX", Name: "@", Content: "smtp.google.com", Priority: prio(1)},
+				{Type: "TXT", Name: "@", Content: "v=spf1 include:_spf.google.com ~all"},
+				{Type: "TXT", Name: "_dmarc", Content: "v=DMARC1; p=quarantine; rua=mailto:dmarc@{zone}"},
+			},
+		},
+		"microsoft-365": {
+			Name:        "microsoft-365",
+			Description: "Microsoft 365：MX、SPF、DKIM CNAME、DMARC（quarantine）",
+			Records: []config.MailProfileRecord{
+				{Type: "MX", Name: "@", Content: "{zone_dash}.mail.protection.outlook.com", Priority: prio(0)},
+				{Type: "TXT", Name: "@", Content: "v=spf1 include:spf.protection.outlook.com -all"},
+				{Type: "CNAME", Name: "autodiscover", Content: "autodiscover.outlook.com"},
+				{Type: "TXT", Name: "_dmarc", Content: "v=DMARC1; p=quarantine; rua=mailto:dmarc@{zone}"},
+			},
+		},
+	}
+}
+
+// LoadProfiles 合并内置模板与配置中的模板，同名时配置优先
+func LoadProfiles(custom []config.MailProfile) (map[string]Profile, error) {
+	out := BuiltinProfiles()
+	for _, p := range custom {
+		name := strings.TrimSpace(p.Name)
+		if name == "" {
+			return nil, fmt.Errorf("mailAuth.profiles 中存在未命名的模板")
+		}
+		if len(p.Records) == 0 {
+			return nil, fmt.Errorf("模板 %s 没有记录", name)
+		}
+		for _, r := range p.Records {
+			if r.Type == "" || r.Name == "" || r.Content == "" {
+				return nil, fmt.Errorf("模板 %s 中的记录缺少 type/name/content", name)
+			}
+		}
+		out[name] = Profile{Name: name, Description: p.Description, Records: p.Records}
+	}
+	return out, nil
+}
+
+// ProfileNames 返回排序后的模板名称
+func ProfileNames(profiles map[string]Profile) []string {
+	names := make([]string, 0, len(profiles))
+	for n := range profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Step 是套用模板时对一条记录的写入；Delete 为需要先删除的冲突记录
+type Step struct {
+	Params cfclient.DNSRecordParams
+	Delete []cloudflare.DNSRecord
+}
+
+// Plan 把模板展开为对指定 Zone 的写入步骤：
+//   - MX 替换同名全部 MX（同名多条时第一条 replace、其余 add）；
+//   - SPF / DMARC / DKIM / MTA-STS / TLS-RPT 类 TXT 只替换同名同类记录，不影响站点验证等其它 TXT；
+//   - 其它记录按 update 写入。
+func Plan(zone string, profile Profile, existing []cloudflare.DNSRecord) []Step {
+	var steps []Step
+	replacedMX := map[string]bool{}
+	for _, r := range profile.Records {
+		params := cfclient.DNSRecordParams{
+			Type:     strings.ToUpper(r.Type),
+			Name:     r.Name,
+			Content:  render(r.Content, zone),
+			Priority: r.Priority,
+			TTL:      r.TTL,
+			Mode:     cfclient.DNSWriteUpdate,
+		}
+		step := Step{Params: params}
+		fqdn := cfclient.RecordFQDN(r.Name, zone)
+
+		switch {
+		case params.Type == "MX":
+			if replacedMX[fqdn] {
+				step.Params.Mode = cfclient.DNSWriteAdd
+			} else {
+				step.Params.Mode = cfclient.DNSWriteReplace
+				replacedMX[fqdn] = true
+			}
+		case params.Type == "TXT" && txtKind(params.Content) != "":
+			kind := txtKind(params.Content)
+			step.Params.Mode = cfclient.DNSWriteAdd
+			for _, e := range cfclient.FilterSameNameType(existing, zone, params) {
+				if txtKind(e.Content) == kind && !cfclient.DNSContentEqual("TXT", e.Content, params.Content) {
+					step.Delete = append(step.Delete, e)
+				}
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// Apply 依次执行写入步骤，返回每一步的结果描述；任一步失败不影响后续步骤
+func Apply(ctx context.Context, client cfclient.Client, account config.CF, zone string, steps []Step) (lines []string, failed int) {
+	for _, s := range steps {
+		label := fmt.Sprintf("%s %s → %s", s.Params.Type, cfclient.RecordFQDN(s.Params.Name, zone), s.Params.Content)
+		var err error
+		for _, d := range s.Delete {
+			if err = client.DeleteDNSRecordByID(ctx, account, zone, d.ID); err != nil {
+				err = fmt.Errorf("删除旧记录 %s 失败: %v", d.Content, err)
+				break
+			}
+		}
+		if err == nil {
+			_, err = client.UpsertDNSRecord(ctx, account, zone, s.Params)
+		}
+		if err != nil {
+			failed++
+			lines = append(lines, fmt.Sprintf("❌ %s: %v", label, err))
+			continue
+		}
+		lines = append(lines, "✅ "+label)
+	}
+	return lines, failed
+}
+
+// txtKind 识别需要"同类替换"的 TXT 记录
+func txtKind(content string) string {
+	v := strings.ToLower(strings.ReplaceAll(unquote(content), " ", ""))
+	for _, prefix := range []string{"v=spf1", "v=dmarc1", "v=dkim1", "v=stsv1", "v=tlsrptv1"} {
+		if strings.HasPrefix(v, prefix) {
+			return prefix
+		}
+	}
+	return ""
+}
+
+func render(content, zone string) string {
+	zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+	r := strings.NewReplacer("{zone}", zone, "{zone_dash}", strings.ReplaceAll(zone, ".", "-"))
+	return r.Replace(content)
+}
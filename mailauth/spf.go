@@ -0,0 +1,210 @@
+// Package mailauth 检查 Zone 的邮件认证配置（SPF、DKIM、DMARC、MTA-STS），
+// 并提供可套用的邮件记录模板。
+package mailauth
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// SPFLookupLimit 是 RFC 7208 规定的 DNS 查询次数上限
+const SPFLookupLimit = 10
+
+// TXTLookup 查询 TXT 记录，*net.Resolver 即满足该接口
+type TXTLookup interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// SPFTerm 是 SPF 中的一个机制或修饰符
+type SPFTerm struct {
+	Qualifier byte   // + - ~ ?，修饰符为 0
+	Name      string // all/include/a/mx/ptr/ip4/ip6/exists/redirect/exp
+	Value     string
+}
+
+// SPF 是解析后的 SPF 记录
+type SPF struct {
+	Raw   string
+	Terms []SPFTerm
+}
+
+// IsSPF 判断 TXT 内容是否为 SPF 记录
+func IsSPF(txt string) bool {
+	txt = strings.ToLower(unquote(txt))
+	return txt == "v=spf1" || strings.HasPrefix(txt, "v=spf1 ")
+}
+
+// ParseSPF 解析并校验 SPF 语法
+func ParseSPF(txt string) (SPF, error) {
+	raw := unquote(txt)
+	fields := strings.Fields(raw)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], "v=spf1") {
+		return SPF{}, fmt.Errorf("SPF 必须以 v=spf1 开头")
+	}
+	spf := SPF{Raw: raw}
+	seenMod := map[string]bool{}
+	for _, f := range fields[1:] {
+		lower := strings.ToLower(f)
+		if i := strings.IndexByte(lower, '='); i > 0 && !strings.ContainsAny(lower[:i], ":/") {
+			name := lower[:i]
+			if name != "redirect" && name != "exp" {
+				// 未知修饰符按 RFC 7208 忽略
+				continue
+			}
+			if seenMod[name] {
+				return SPF{}, fmt.Errorf("修饰符 %s 重复", name)
+			}
+			seenMod[name] = true
+			if f[i+1:] == "" {
+				return SPF{}, fmt.Errorf("修饰符 %s 缺少值", name)
+			}
+			spf.Terms = append(spf.Terms, SPFTerm{Name: name, Value: f[i+1:]})
+			continue
+		}
+
+		term := SPFTerm{Qualifier: '+'}
+		if strings.ContainsRune("+-~?", rune(f[0])) {
+			term.Qualifier = f[0]
+			f, lower = f[1:], lower[1:]
+		}
+		name, value := lower, ""
+		if i := strings.IndexAny(lower, ":/"); i >= 0 {
+			name, value = lower[:i], f[i:]
+			value = strings.TrimPrefix(value, ":")
+		}
+		term.Name, term.Value = name, value
+
+		switch name {
+		case "all":
+			if value != "" {
+				return SPF{}, fmt.Errorf("all 不能带参数: %s", f)
+			}
+		case "include", "exists":
+			if value == "" {
+				return SPF{}, fmt.Errorf("%s 缺少域名", name)
+			}
+		case "a", "mx", "ptr":
+		case "ip4":
+			if !validCIDR(value, false) {
+				return SPF{}, fmt.Errorf("无效的 ip4: %s", value)
+			}
+		case "ip6":
+			if !validCIDR(value, true) {
+				return SPF{}, fmt.Errorf("无效的 ip6: %s", value)
+			}
+		default:
+			return SPF{}, fmt.Errorf("未知的 SPF 机制: %s", f)
+		}
+		spf.Terms = append(spf.Terms, term)
+	}
+	return spf, nil
+}
+
+// All 返回 all 机制的限定符，没有 all 时返回 0
+func (s SPF) All() byte {
+	for _, t := range s.Terms {
+		if t.Name == "all" {
+			return t.Qualifier
+		}
+	}
+	return 0
+}
+
+// Redirect 返回 redirect 目标
+func (s SPF) Redirect() string {
+	for _, t := range s.Terms {
+		if t.Name == "redirect" {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+// CountLookups 递归统计 SPF 求值需要的 DNS 查询次数（include/a/mx/ptr/exists/redirect 各计 1 次）。
+// lookup 为空时只统计本条记录。超过上限后停止递归。
+// 同一域名被多个分支 include（菱形引用）不算循环，但每次引用都计入查询次数；
+// 只有出现在当前递归路径上的域名才是循环引用。
+func CountLookups(ctx context.Context, spf SPF, lookup TXTLookup) (int, error) {
+	return countLookups(ctx, spf, lookup, map[string]bool{})
+}
+
+func countLookups(ctx context.Context, spf SPF, lookup TXTLookup, stack map[string]bool) (int, error) {
+	count := 0
+	var nested []string
+	for _, t := range spf.Terms {
+		switch t.Name {
+		case "a", "mx", "ptr", "exists":
+			count++
+		case "include", "redirect":
+			count++
+			nested = append(nested, t.Value)
+		}
+	}
+	if lookup == nil {
+		return count, nil
+	}
+	for _, domain := range nested {
+		if count > SPFLookupLimit {
+			break
+		}
+		domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+		if strings.Contains(domain, "%{") {
+			continue // 宏在求值时才能展开
+		}
+		if stack[domain] {
+			return count, fmt.Errorf("SPF 循环引用: %s", domain)
+		}
+		txts, err := lookup.LookupTXT(ctx, domain)
+		if err != nil {
+			return count, fmt.Errorf("查询 %s 的 SPF 失败: %v", domain, err)
+		}
+		var child *SPF
+		for _, txt := range txts {
+			if IsSPF(txt) {
+				parsed, err := ParseSPF(txt)
+				if err != nil {
+					return count, fmt.Errorf("%s 的 SPF 无效: %v", domain, err)
+				}
+				child = &parsed
+				break
+			}
+		}
+		if child == nil {
+			return count, fmt.Errorf("%s 没有 SPF 记录", domain)
+		}
+		stack[domain] = true
+		n, err := countLookups(ctx, *child, lookup, stack)
+		delete(stack, domain)
+		count += n
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+func validCIDR(v string, v6 bool) bool {
+	ip := v
+	if i := strings.IndexByte(v, '/'); i >= 0 {
+		ip = v[:i]
+		if _, _, err := net.ParseCIDR(v); err != nil {
+			return false
+		}
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	return (parsed.To4() == nil) == v6
+}
+
+// unquote 去掉 Cloudflare 返回的 TXT 引号并拼接多段字符串
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
+		return strings.Join(strings.Split(s[1:len(s)-1], `" "`), "")
+	}
+	return s
+}
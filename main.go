@@ -3,16 +3,23 @@ package main
 import (
 	"context"
 	"log"
+	"os"
 	"time"
 
 	"DomainC/callback"
+	"DomainC/certvault"
 	"DomainC/cfclient"
 	"DomainC/config"
+	"DomainC/dnslint"
+	"DomainC/dnssnapshot"
 	"DomainC/domain"
+	"DomainC/emergency"
 	"DomainC/internal/app"
 	"DomainC/registrarclient"
 	"DomainC/scheduler"
+	"DomainC/takeover"
 	"DomainC/telegram"
+	"DomainC/zonewatch"
 )
 
 const (
@@ -25,6 +32,9 @@ func main() {
 	if err := config.Load("config.yaml"); err != nil {
 		log.Fatalf("加载配置失败: %v", err)
 	}
+	if isCLI(os.Args[1:]) {
+		os.Exit(runCLI(os.Args[1:]))
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -47,7 +57,59 @@ func main() {
 		sender = botSender
 	}
 
+	if cfg := config.Cfg.CertVault; cfg.Enabled {
+		key, err := certvault.LoadKey(cfg.KeyEnv, cfg.KeyFile)
+		if err != nil {
+			log.Fatalf("certVault 密钥配置错误: %v", err)
+		}
+		vault, err := certvault.New(cfg.Dir, key)
+		if err != nil {
+			log.Fatalf("certVault 初始化失败: %v", err)
+		}
+		telegram.SetCertVault(vault)
+	}
+
 	commandHandler := telegram.NewCommandHandler(cfClient, registrarManager, sender, config.Cfg.CloudflareAccounts, int64(config.Cfg.Telegram.ChatID))
+	snapshotStore := dnssnapshot.NewStore(config.Cfg.DNSSnapshot.Dir)
+	commandHandler.Snapshots = snapshotStore
+
+	var zoneWatcher *zonewatch.Watcher
+	if cfg := config.Cfg.ZoneWatch; !cfg.Disabled {
+		zoneWatcher = &zonewatch.Watcher{
+			CF:            cfClient,
+			Registrar:     registrarManager,
+			Accounts:      config.Cfg.CloudflareAccounts,
+			File:          cfg.File,
+			EscalateAfter: time.Duration(cfg.EscalateDays) * 24 * time.Hour,
+			MaxInterval:   time.Duration(cfg.MaxIntervalMinutes) * time.Minute,
+			Notify: func(msg string) {
+				if err := sender.Send(context.Background(), msg); err != nil {
+					log.Printf("发送 Zone 激活通知失败: %v", err)
+				}
+			},
+		}
+		commandHandler.ZoneWatcher = zoneWatcher
+	}
+
+	emergencyCfg := config.Cfg.Emergency
+	emergencyManager := &emergency.Manager{
+		CF:            cfClient,
+		Store:         &emergency.Store{File: emergencyCfg.StateFile},
+		Accounts:      config.Cfg.CloudflareAccounts,
+		Concurrency:   emergencyCfg.Concurrency,
+		RatePerSecond: emergencyCfg.RatePerSecond,
+		Notify: func(msg string) {
+			if err := sender.Send(context.Background(), msg); err != nil {
+				log.Printf("发送应急模式回滚通知失败: %v", err)
+			}
+		},
+	}
+	if n, err := emergencyManager.Resume(); err != nil {
+		log.Printf("恢复应急模式定时回滚失败: %v", err)
+	} else if n > 0 {
+		log.Printf("已恢复 %d 个应急模式定时回滚", n)
+	}
+	telegram.SetEmergencyManager(emergencyManager)
 
 	go func() {
 		if err := sender.StartListener(ctx, callback.HandleCallback, commandHandler.HandleMessage); err != nil {
@@ -79,6 +141,91 @@ func main() {
 		AlertMin:  0,
 	}
 
+	if cfg := config.Cfg.DNSSnapshot; cfg.Enabled {
+		snapshots := &app.DNSSnapshotService{
+			CFClient: cfClient,
+			Store:    snapshotStore,
+			Sender:   sender,
+			Accounts: config.Cfg.CloudflareAccounts,
+			Keep:     cfg.Keep,
+		}
+		interval := time.Duration(cfg.IntervalMinutes) * time.Minute
+		if interval <= 0 {
+			interval = time.Hour
+		}
+		application.Jobs = append(application.Jobs, app.Job{Name: "DNS 快照", Interval: interval, Run: snapshots.Run})
+	}
+
+	if cfg := config.Cfg.Takeover; cfg.Enabled {
+		fps, err := takeover.FromConfig(cfg.Fingerprints, !cfg.NoDefaults)
+		if err != nil {
+			log.Fatalf("takeover.fingerprints 配置错误: %v", err)
+		}
+		minSeverity := takeover.SeverityLow
+		if cfg.MinSeverity != "" {
+			if minSeverity, err = takeover.ParseSeverity(cfg.MinSeverity); err != nil {
+				log.Fatalf("takeover.minSeverity 配置错误: %v", err)
+			}
+		}
+		scan := &app.TakeoverScanService{
+			Scanner:     &takeover.Scanner{CF: cfClient, Fingerprints: fps},
+			Sender:      sender,
+			Accounts:    config.Cfg.CloudflareAccounts,
+			MinSeverity: minSeverity,
+		}
+		interval := time.Duration(cfg.IntervalHours) * time.Hour
+		if interval <= 0 {
+			interval = 24 * time.Hour
+		}
+		application.Jobs = append(application.Jobs, app.Job{Name: "子域名接管扫描", Interval: interval, Run: scan.Run})
+	}
+
+	if cfg := config.Cfg.Lint; cfg.Enabled {
+		linter, err := dnslint.New(cfg.Rules)
+		if err != nil {
+			log.Fatalf("lint.rules 配置错误: %v", err)
+		}
+		minSeverity := dnslint.SeverityWarning
+		if cfg.MinSeverity != "" {
+			if minSeverity, err = dnslint.ParseSeverity(cfg.MinSeverity); err != nil {
+				log.Fatalf("lint.minSeverity 配置错误: %v", err)
+			}
+		}
+		lint := &app.DNSLintService{
+			CFClient:    cfClient,
+			Linter:      linter,
+			Sender:      sender,
+			Accounts:    config.Cfg.CloudflareAccounts,
+			MinSeverity: minSeverity,
+		}
+		interval := time.Duration(cfg.IntervalHours) * time.Hour
+		if interval <= 0 {
+			interval = 24 * time.Hour
+		}
+		application.Jobs = append(application.Jobs, app.Job{Name: "解析检查", Interval: interval, Run: lint.Run})
+	}
+
+	if cfg := config.Cfg.OriginCerts; cfg.Enabled {
+		certs := &app.OriginCertService{
+			CFClient:    cfClient,
+			Sender:      sender,
+			Accounts:    config.Cfg.CloudflareAccounts,
+			AlertDays:   cfg.AlertDays,
+			ExpiredDays: cfg.ExpiredDays,
+			File:        cfg.StateFile,
+		}
+		interval := time.Duration(cfg.IntervalHours) * time.Hour
+		if interval <= 0 {
+			interval = 24 * time.Hour
+		}
+		application.Jobs = append(application.Jobs, app.Job{Name: "源站证书到期检查", Interval: interval, Run: certs.Run})
+	}
+
+	if zoneWatcher != nil {
+		// 每分钟只检查到期的条目，实际轮询间隔由退避决定
+		application.Jobs = append(application.Jobs, app.Job{Name: "Zone 激活跟踪", Interval: time.Minute, Run: zoneWatcher.Run})
+	}
+
 	if err := application.Run(ctx); err != nil {
 		log.Fatalf("程序退出: %v", err)
 	}
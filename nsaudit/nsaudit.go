@@ -0,0 +1,183 @@
+// Package nsaudit 对比各 Zone 的 Cloudflare 分配 NS、注册商登记的 NS 与公网解析到的 NS，
+// 找出迁移未完成或可能被劫持的域名。
+package nsaudit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+// Registrar 读取注册商登记的 NS，由 registrarclient.Manager 实现
+type Registrar interface {
+	GetNameServersForDomain(ctx context.Context, domain string) (config.Registrar, []string, error)
+}
+
+// NSLookup 查询域名的 NS 委派，*net.Resolver 即满足该接口
+type NSLookup interface {
+	LookupNS(ctx context.Context, name string) ([]*net.NS, error)
+}
+
+// Result 是单个 Zone 的检查结果
+type Result struct {
+	Account      string
+	Zone         string
+	Status       string
+	CloudflareNS []string
+	Registrar    string // 注册商标签，为空表示未能读取
+	RegistrarNS  []string
+	RegistrarErr error
+	DelegatedNS  []string
+	LookupErr    error
+}
+
+// RegistrarMismatch 表示注册商 NS 已读取且与 Cloudflare 分配的不一致，可以一键同步
+func (r Result) RegistrarMismatch() bool {
+	return r.RegistrarErr == nil && r.Registrar != "" && len(r.CloudflareNS) > 0 && !SameNS(r.RegistrarNS, r.CloudflareNS)
+}
+
+// DelegationMismatch 表示公网解析到的 NS 与 Cloudflare 分配的不一致
+func (r Result) DelegationMismatch() bool {
+	return r.LookupErr == nil && len(r.CloudflareNS) > 0 && !SameNS(r.DelegatedNS, r.CloudflareNS)
+}
+
+// OK 表示没有发现不一致（查询失败不算不一致，但会在报告中注明）
+func (r Result) OK() bool {
+	return !r.RegistrarMismatch() && !r.DelegationMismatch()
+}
+
+// Problems 返回需要在报告中展示的问题描述
+func (r Result) Problems() []string {
+	var out []string
+	if r.RegistrarMismatch() {
+		out = append(out, fmt.Sprintf("注册商 %s NS: %s", r.Registrar, joinNS(r.RegistrarNS)))
+	}
+	if r.DelegationMismatch() {
+		out = append(out, "解析到的 NS: "+joinNS(r.DelegatedNS))
+	}
+	return out
+}
+
+// Auditor 执行检查
+type Auditor struct {
+	CF        cfclient.Client
+	Registrar Registrar // 为空时跳过注册商对比
+	Lookup    NSLookup  // 为空时跳过委派查询
+	Timeout   time.Duration
+}
+
+// Audit 检查给定账号下的全部 Zone
+func (a *Auditor) Audit(ctx context.Context, accounts []config.CF) ([]Result, []error) {
+	var results []Result
+	var errs []error
+	for _, acc := range accounts {
+		zones, err := a.CF.ListZones(ctx, acc)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("列出账号 %s 的域名失败: %v", acc.Label, err))
+			continue
+		}
+		for _, z := range zones {
+			results = append(results, a.Check(ctx, acc.Label, z))
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Account != results[j].Account {
+			return results[i].Account < results[j].Account
+		}
+		return results[i].Zone < results[j].Zone
+	})
+	return results, errs
+}
+
+// Check 检查单个 Zone
+func (a *Auditor) Check(ctx context.Context, account string, zone cfclient.ZoneDetail) Result {
+	res := Result{Account: account, Zone: zone.Name, Status: zone.Status, CloudflareNS: zone.NameServers}
+
+	if a.Registrar != nil {
+		cctx, cancel := a.withTimeout(ctx)
+		reg, ns, err := a.Registrar.GetNameServersForDomain(cctx, zone.Name)
+		cancel()
+		res.Registrar, res.RegistrarNS, res.RegistrarErr = reg.Label, ns, err
+	}
+	if a.Lookup != nil {
+		cctx, cancel := a.withTimeout(ctx)
+		records, err := a.Lookup.LookupNS(cctx, zone.Name)
+		cancel()
+		if err != nil {
+			res.LookupErr = err
+		}
+		for _, r := range records {
+			res.DelegatedNS = append(res.DelegatedNS, r.Host)
+		}
+	}
+	return res
+}
+
+func (a *Auditor) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := a.Timeout
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// NewResolver 返回查询 NS 用的解析器；addr 为空时使用系统解析器，否则固定走该 DNS 服务器（host 或 host:port）
+func NewResolver(addr string) *net.Resolver {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return net.DefaultResolver
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+}
+
+// SameNS 忽略大小写、末尾点与顺序比较两组 NS
+func SameNS(a, b []string) bool {
+	na, nb := normalize(a), normalize(b)
+	if len(na) != len(nb) {
+		return false
+	}
+	for i := range na {
+		if na[i] != nb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func normalize(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	out := make([]string, 0, len(list))
+	for _, ns := range list {
+		ns = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(ns)), ".")
+		if ns == "" || seen[ns] {
+			continue
+		}
+		seen[ns] = true
+		out = append(out, ns)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func joinNS(list []string) string {
+	n := normalize(list)
+	if len(n) == 0 {
+		return "(空)"
+	}
+	return strings.Join(n, ", ")
+}
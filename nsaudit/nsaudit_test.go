@@ -0,0 +1,113 @@
+package nsaudit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+type fakeCF struct {
+	cfclient.Client
+	zones []cfclient.ZoneDetail
+}
+
+func (f fakeCF) ListZones(ctx context.Context, account config.CF) ([]cfclient.ZoneDetail, error) {
+	return f.zones, nil
+}
+
+type fakeRegistrar map[string][]string
+
+func (f fakeRegistrar) GetNameServersForDomain(ctx context.Context, domain string) (config.Registrar, []string, error) {
+	ns, ok := f[domain]
+	if !ok {
+		return config.Registrar{}, nil, errors.New("not found")
+	}
+	return config.Registrar{Label: "gd"}, ns, nil
+}
+
+type fakeLookup map[string][]string
+
+func (f fakeLookup) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	hosts, ok := f[name]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	var out []*net.NS
+	for _, h := range hosts {
+		out = append(out, &net.NS{Host: h})
+	}
+	return out, nil
+}
+
+func TestAudit(t *testing.T) {
+	cfNS := []string{"ada.ns.cloudflare.com", "bob.ns.cloudflare.com"}
+	auditor := &Auditor{
+		CF: fakeCF{zones: []cfclient.ZoneDetail{
+			{Name: "good.com", NameServers: cfNS},
+			{Name: "half.com", NameServers: cfNS},
+			{Name: "hijack.com", NameServers: cfNS},
+			{Name: "other.com", NameServers: cfNS},
+		}},
+		Registrar: fakeRegistrar{
+			"good.com":   {"BOB.ns.cloudflare.com.", "ada.ns.cloudflare.com"},
+			"half.com":   {"ns1.oldhost.net", "ns2.oldhost.net"},
+			"hijack.com": cfNS,
+		},
+		Lookup: fakeLookup{
+			"good.com.":   {"ada.ns.cloudflare.com.", "bob.ns.cloudflare.com."},
+			"half.com.":   {"ns1.oldhost.net."},
+			"hijack.com.": {"ns1.evil.example."},
+			"other.com.":  {"ada.ns.cloudflare.com.", "bob.ns.cloudflare.com."},
+		},
+	}
+	// LookupNS 传入的是不带点的域名，这里按 net.Resolver 的习惯补点
+	auditor.Lookup = dotLookup{auditor.Lookup}
+
+	results, errs := auditor.Audit(context.Background(), []config.CF{{Label: "acc"}})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	byZone := map[string]Result{}
+	for _, r := range results {
+		byZone[r.Zone] = r
+	}
+
+	if !byZone["good.com"].OK() {
+		t.Fatalf("good.com should be consistent: %+v", byZone["good.com"])
+	}
+	half := byZone["half.com"]
+	if !half.RegistrarMismatch() || !half.DelegationMismatch() {
+		t.Fatalf("half.com should mismatch both: %+v", half)
+	}
+	hijack := byZone["hijack.com"]
+	if hijack.RegistrarMismatch() || !hijack.DelegationMismatch() {
+		t.Fatalf("hijack.com should only mismatch delegation: %+v", hijack)
+	}
+	if p := strings.Join(hijack.Problems(), "\n"); !strings.Contains(p, "ns1.evil.example") {
+		t.Fatalf("problems should list delegated NS, got %q", p)
+	}
+	other := byZone["other.com"]
+	if other.RegistrarErr == nil || other.RegistrarMismatch() || !other.OK() {
+		t.Fatalf("registrar lookup failure must not count as mismatch: %+v", other)
+	}
+}
+
+type dotLookup struct{ inner NSLookup }
+
+func (d dotLookup) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	return d.inner.LookupNS(ctx, name+".")
+}
+
+func TestSameNS(t *testing.T) {
+	if !SameNS([]string{"A.example.", "b.example", "b.example"}, []string{"b.example", "a.example"}) {
+		t.Fatalf("expected equal")
+	}
+	if SameNS(nil, []string{"a.example"}) {
+		t.Fatalf("expected different")
+	}
+}
@@ -0,0 +1,185 @@
+// Package origincert 汇总各账号签发的 Cloudflare Origin CA 证书，
+// 找出重复签发与即将到期的证书。
+package origincert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+// DefaultAlertDays 未配置时的到期提醒阈值（天）
+var DefaultAlertDays = []int{30, 14, 7, 1}
+
+// DefaultExpiredDays 未配置时，证书过期超过该天数后不再提醒
+const DefaultExpiredDays = 30
+
+// Cert 是一张 Origin CA 证书及其所属 Zone
+type Cert struct {
+	Account string
+	Zone    string
+	cfclient.OriginCACertInfo
+}
+
+// Revoked 表示证书已被吊销
+func (c Cert) Revoked() bool {
+	return c.RevokedAt != nil && !c.RevokedAt.IsZero()
+}
+
+// DaysLeft 返回距离到期的天数（向下取整，已过期为负数）
+func (c Cert) DaysLeft(now time.Time) int {
+	d := c.ExpiresOn.Sub(now)
+	days := int(d / (24 * time.Hour))
+	if d < 0 && d%(24*time.Hour) != 0 {
+		days--
+	}
+	return days
+}
+
+// Active 表示证书未吊销且未过期
+func (c Cert) Active(now time.Time) bool {
+	return !c.Revoked() && c.ExpiresOn.After(now)
+}
+
+// HostKey 返回归一化后的 hostname 集合，用于判断是否重复签发
+func (c Cert) HostKey() string {
+	hosts := make([]string, 0, len(c.Hostnames))
+	for _, h := range c.Hostnames {
+		hosts = append(hosts, strings.ToLower(strings.TrimSpace(h)))
+	}
+	sort.Strings(hosts)
+	return strings.Join(hosts, ",")
+}
+
+// Collect 读取证书。zone 为空时遍历账号下全部 Zone，否则只读取该 Zone。
+func Collect(ctx context.Context, client cfclient.Client, accounts []config.CF, zone string) ([]Cert, []error) {
+	zone = strings.ToLower(strings.TrimSpace(zone))
+	var certs []Cert
+	var errs []error
+	seen := map[string]bool{}
+	for _, acc := range accounts {
+		var zones []cfclient.ZoneDetail
+		if zone == "" {
+			list, err := client.ListZones(ctx, acc)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("列出账号 %s 的域名失败: %v", acc.Label, err))
+				continue
+			}
+			zones = list
+		} else {
+			z, err := client.GetZoneDetails(ctx, acc, zone)
+			if err != nil {
+				if !errors.Is(err, cfclient.ErrZoneNotFound) {
+					errs = append(errs, fmt.Errorf("查询 %s(%s) 失败: %v", zone, acc.Label, err))
+				}
+				continue
+			}
+			zones = []cfclient.ZoneDetail{z}
+		}
+		for _, z := range zones {
+			list, err := client.ListZoneOriginCACertificates(ctx, acc, z.ID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("获取 %s(%s) 的源站证书失败: %v", z.Name, acc.Label, err))
+				continue
+			}
+			for _, info := range list {
+				if seen[info.ID] {
+					continue
+				}
+				seen[info.ID] = true
+				certs = append(certs, Cert{Account: acc.Label, Zone: z.Name, OriginCACertInfo: info})
+			}
+		}
+	}
+	sort.SliceStable(certs, func(i, j int) bool {
+		if certs[i].Zone != certs[j].Zone {
+			return certs[i].Zone < certs[j].Zone
+		}
+		return certs[i].ExpiresOn.Before(certs[j].ExpiresOn)
+	})
+	return certs, errs
+}
+
+// Duplicates 返回覆盖相同 hostname 集合的有效证书分组（每组至少两张）
+func Duplicates(certs []Cert, now time.Time) [][]Cert {
+	groups := map[string][]Cert{}
+	var keys []string
+	for _, c := range certs {
+		if !c.Active(now) {
+			continue
+		}
+		k := c.Account + "|" + c.HostKey()
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], c)
+	}
+	var out [][]Cert
+	for _, k := range keys {
+		if len(groups[k]) > 1 {
+			out = append(out, groups[k])
+		}
+	}
+	return out
+}
+
+// Expiry 是一张命中提醒阈值的证书
+type Expiry struct {
+	Cert
+	DaysLeft  int
+	Threshold int // 命中的最小阈值
+}
+
+// Expiring 返回未吊销且剩余天数不超过任一阈值的证书（包括过期不超过 expiredDays 天的），
+// Threshold 为命中的最小阈值，便于按阈值去重提醒。expiredDays <= 0 时使用 DefaultExpiredDays。
+// 已有覆盖相同 hostname、且不在提醒范围内的新证书时视为已续签，不再提醒。
+func Expiring(certs []Cert, now time.Time, thresholds []int, expiredDays int) []Expiry {
+	if len(thresholds) == 0 {
+		thresholds = DefaultAlertDays
+	}
+	if expiredDays <= 0 {
+		expiredDays = DefaultExpiredDays
+	}
+	sorted := append([]int(nil), thresholds...)
+	sort.Ints(sorted)
+	maxDays := sorted[len(sorted)-1]
+
+	renewed := map[string]bool{}
+	for _, c := range certs {
+		if !c.Revoked() && c.DaysLeft(now) > maxDays {
+			renewed[c.Account+"|"+c.HostKey()] = true
+		}
+	}
+
+	var out []Expiry
+	for _, c := range certs {
+		if c.Revoked() || renewed[c.Account+"|"+c.HostKey()] {
+			continue
+		}
+		left := c.DaysLeft(now)
+		if left < -expiredDays {
+			continue
+		}
+		for _, t := range sorted {
+			if left <= t {
+				out = append(out, Expiry{Cert: c, DaysLeft: left, Threshold: t})
+				break
+			}
+		}
+	}
+	return out
+}
+
+// ShortID 返回便于展示的证书 ID 前缀
+func ShortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,96 @@
+package origincert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+type fakeCF struct {
+	cfclient.Client
+	zones map[string][]cfclient.ZoneDetail
+	certs map[string][]cfclient.OriginCACertInfo
+}
+
+func (f *fakeCF) ListZones(ctx context.Context, acc config.CF) ([]cfclient.ZoneDetail, error) {
+	return f.zones[acc.Label], nil
+}
+
+func (f *fakeCF) GetZoneDetails(ctx context.Context, acc config.CF, domain string) (cfclient.ZoneDetail, error) {
+	for _, z := range f.zones[acc.Label] {
+		if z.Name == domain {
+			return z, nil
+		}
+	}
+	return cfclient.ZoneDetail{}, cfclient.ErrZoneNotFound
+}
+
+func (f *fakeCF) ListZoneOriginCACertificates(ctx context.Context, acc config.CF, zoneID string) ([]cfclient.OriginCACertInfo, error) {
+	return f.certs[zoneID], nil
+}
+
+var now = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func cert(id string, days int, hosts ...string) cfclient.OriginCACertInfo {
+	return cfclient.OriginCACertInfo{ID: id, Hostnames: hosts, ExpiresOn: now.Add(time.Duration(days)*24*time.Hour + time.Hour)}
+}
+
+func TestCollectAndDuplicates(t *testing.T) {
+	revokedAt := now.Add(-time.Hour)
+	revoked := cert("r", 100, "a.com", "*.a.com")
+	revoked.RevokedAt = &revokedAt
+	cf := &fakeCF{
+		zones: map[string][]cfclient.ZoneDetail{
+			"acc": {{ID: "za", Name: "a.com"}, {ID: "zb", Name: "b.com"}},
+		},
+		certs: map[string][]cfclient.OriginCACertInfo{
+			"za": {cert("1", 5000, "a.com", "*.a.com"), cert("2", 4000, "*.A.com", "a.com"), revoked},
+			"zb": {cert("3", 10, "b.com")},
+		},
+	}
+	accounts := []config.CF{{Label: "acc"}}
+
+	certs, errs := Collect(context.Background(), cf, accounts, "")
+	if len(errs) != 0 || len(certs) != 4 {
+		t.Fatalf("collect: %d certs, errs=%v", len(certs), errs)
+	}
+	dups := Duplicates(certs, now)
+	if len(dups) != 1 || len(dups[0]) != 2 {
+		t.Fatalf("expected one duplicate group of 2, got %+v", dups)
+	}
+
+	only, _ := Collect(context.Background(), cf, accounts, "b.com")
+	if len(only) != 1 || only[0].ID != "3" {
+		t.Fatalf("zone filter: %+v", only)
+	}
+}
+
+func TestExpiring(t *testing.T) {
+	certs := []Cert{
+		{Zone: "a.com", OriginCACertInfo: cert("soon", 6, "a.com")},
+		{Zone: "b.com", OriginCACertInfo: cert("month", 20, "b.com")},
+		{Zone: "c.com", OriginCACertInfo: cert("far", 400, "c.com")},
+		{Zone: "d.com", OriginCACertInfo: cert("expired", -3, "d.com")},
+		// 过期太久，不再提醒
+		{Zone: "f.com", OriginCACertInfo: cert("stale", -60, "f.com")},
+		// 已续签：旧证书即将到期，但存在覆盖相同主机名的新证书
+		{Zone: "e.com", OriginCACertInfo: cert("old", 2, "e.com")},
+		{Zone: "e.com", OriginCACertInfo: cert("new", 5000, "e.com")},
+	}
+	got := map[string]int{}
+	for _, e := range Expiring(certs, now, []int{30, 7, 14}, 30) {
+		got[e.ID] = e.Threshold
+	}
+	want := map[string]int{"soon": 7, "month": 30, "expired": 7}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for id, th := range want {
+		if got[id] != th {
+			t.Fatalf("%s: threshold %d, want %d", id, got[id], th)
+		}
+	}
+}
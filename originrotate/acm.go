@@ -0,0 +1,45 @@
+package originrotate
+
+import (
+	"context"
+	"fmt"
+
+	"DomainC/acmclient"
+	"DomainC/config"
+)
+
+// AWSACM 基于配置中的 AWS 目标查找/重新导入 ACM 证书
+type AWSACM struct {
+	Targets map[string]config.AWSTarget
+}
+
+func (a *AWSACM) FindBySerial(ctx context.Context, serial string) ([]ACMCert, error) {
+	var out []ACMCert
+	for _, alias := range acmclient.Aliases(a.Targets) {
+		client, err := acmclient.New(ctx, alias, a.Targets[alias])
+		if err != nil {
+			return nil, fmt.Errorf("[%s] %v", alias, err)
+		}
+		certs, err := client.FindBySerial(ctx, serial)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range certs {
+			out = append(out, ACMCert{Target: alias, Region: c.Region, ARN: c.ARN})
+		}
+	}
+	return out, nil
+}
+
+func (a *AWSACM) Reimport(ctx context.Context, target ACMCert, certPEM, keyPEM string, tags map[string]string) error {
+	t, ok := a.Targets[target.Target]
+	if !ok {
+		return fmt.Errorf("未知 AWS 目标别名：%s", target.Target)
+	}
+	client, err := acmclient.New(ctx, target.Target, t)
+	if err != nil {
+		return err
+	}
+	_, err = client.Import(ctx, target.ARN, certPEM, keyPEM, tags)
+	return err
+}
@@ -0,0 +1,210 @@
+// Package originrotate 轮换 Cloudflare Origin CA 源站证书：签发新证书、
+// 重新导入到原有 ACM ARN，全部成功后才吊销旧证书。
+package originrotate
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"DomainC/acmclient"
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+// ACMCert 是一张导入在 ACM 中的证书
+type ACMCert struct {
+	Target string // config.AWSTargets 中的别名
+	Region string
+	ARN    string
+}
+
+func (c ACMCert) String() string {
+	return fmt.Sprintf("%s (%s) %s", c.Target, c.Region, c.ARN)
+}
+
+// ACM 查找并覆盖导入在 ACM 中的证书
+type ACM interface {
+	// FindBySerial 返回各 AWS 目标中序列号为 serial 的导入证书
+	FindBySerial(ctx context.Context, serial string) ([]ACMCert, error)
+	// Reimport 把证书重新导入到已有 ARN（ARN 不变，引用该证书的 ALB/CloudFront 无需修改）并更新标签。
+	// 只有标签更新失败时返回包装了 acmclient.ErrTagging 的错误，此时证书已生效。
+	Reimport(ctx context.Context, target ACMCert, certPEM, keyPEM string, tags map[string]string) error
+}
+
+// Plan 是一次轮换的计划
+type Plan struct {
+	Account config.CF
+	Zone    string
+	Old     cfclient.OriginCACertInfo
+	Targets []ACMCert
+}
+
+// RevokeOld 表示轮换成功后是否自动吊销旧证书。
+// 旧证书未导入任何 ACM 时可能部署在其他源站，不自动吊销。
+func (p Plan) RevokeOld() bool {
+	return len(p.Targets) > 0
+}
+
+// Result 是轮换结果
+type Result struct {
+	New        cfclient.OriginCert
+	Imported   []ACMCert
+	Failed     *ACMCert // 导入失败的 ARN
+	ImportErr  error
+	TagErrs    []error // 已导入但更新标签失败的 ARN（证书已生效，仅为警告）
+	Revoked    bool    // 旧证书已吊销
+	RevokeErr  error   // 吊销旧证书失败（新旧证书均有效）
+	RolledBack bool    // 第一个 ARN 就导入失败，已吊销新证书
+}
+
+// Complete 表示所有 ARN 均已换成新证书
+func (r Result) Complete() bool {
+	return r.ImportErr == nil && r.New.ID != ""
+}
+
+// Rotator 执行轮换
+type Rotator struct {
+	CF  cfclient.Client
+	ACM ACM // 为空时不查找/导入 ACM
+}
+
+// ErrNoCert 表示 Zone 下没有可轮换的有效证书
+var ErrNoCert = errors.New("no active origin certificate")
+
+// Prepare 选出要轮换的旧证书并找出导入了它的 ACM ARN。
+// certID 为空时 Zone 下必须恰好有一张有效证书。
+func (r *Rotator) Prepare(ctx context.Context, account config.CF, zone cfclient.ZoneDetail, certID string) (Plan, error) {
+	certs, err := r.CF.ListZoneOriginCACertificates(ctx, account, zone.ID)
+	if err != nil {
+		return Plan{}, err
+	}
+	var candidates []cfclient.OriginCACertInfo
+	for _, c := range certs {
+		if c.RevokedAt != nil && !c.RevokedAt.IsZero() {
+			continue
+		}
+		if certID != "" && !strings.HasPrefix(c.ID, certID) {
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+	switch {
+	case len(candidates) == 0:
+		return Plan{}, fmt.Errorf("%w: %s", ErrNoCert, zone.Name)
+	case len(candidates) > 1:
+		var ids []string
+		for _, c := range candidates {
+			ids = append(ids, c.ID)
+		}
+		return Plan{}, fmt.Errorf("%s 下有 %d 张有效证书，请指定证书 ID: %s", zone.Name, len(candidates), strings.Join(ids, ", "))
+	}
+
+	plan := Plan{Account: account, Zone: zone.Name, Old: candidates[0]}
+	if r.ACM != nil {
+		serial, err := CertSerial(plan.Old.CertificatePEM)
+		if err != nil {
+			return Plan{}, err
+		}
+		if plan.Targets, err = r.ACM.FindBySerial(ctx, serial); err != nil {
+			return Plan{}, err
+		}
+	}
+	return plan, nil
+}
+
+// Rotate 按计划轮换。每一步失败时都保证已部署的证书仍然有效：
+//   - 签发失败：未做任何改动
+//   - 第一个 ARN 导入失败：吊销刚签发的新证书，恢复原状
+//   - 导入成功但更新标签失败：视为已导入，只记录警告（新证书已在使用，绝不吊销）
+//   - 后续 ARN 导入失败：已导入的 ARN 使用新证书，其余仍使用旧证书，旧证书不吊销
+//   - 吊销旧证书失败：新旧证书均有效，可稍后手动吊销
+func (r *Rotator) Rotate(ctx context.Context, plan Plan, notify func(string)) (Result, error) {
+	if notify == nil {
+		notify = func(string) {}
+	}
+	var res Result
+
+	hostnames := plan.Old.Hostnames
+	if len(hostnames) == 0 {
+		hostnames = []string{plan.Zone, "*." + plan.Zone}
+	}
+	// 沿用旧证书的私钥类型与有效期
+	opts := cfclient.OriginCertOptionsFor(plan.Old.RequestType, plan.Old.RequestedDays)
+	cert, err := r.CF.CreateOriginCertificate(ctx, plan.Account, hostnames, opts)
+	if err != nil {
+		return res, fmt.Errorf("签发新证书失败，未做任何改动: %w", err)
+	}
+	res.New = cert
+	notify(fmt.Sprintf("1/3 已签发新证书 %s", cert.ID))
+
+	for i, target := range plan.Targets {
+		err := r.ACM.Reimport(ctx, target, cert.CertificatePEM, cert.PrivateKeyPEM, acmclient.Tags(hostnames, cert.ID))
+		if errors.Is(err, acmclient.ErrTagging) {
+			res.TagErrs = append(res.TagErrs, fmt.Errorf("%s: %w", target, err))
+			notify(fmt.Sprintf("⚠️ %s 已重新导入，但更新标签失败: %v", target, err))
+			err = nil
+		}
+		if err != nil {
+			t := target
+			res.Failed = &t
+			res.ImportErr = err
+			if i == 0 {
+				if rerr := r.CF.RevokeOriginCACertificate(ctx, plan.Account, cert.ID); rerr != nil {
+					return res, fmt.Errorf("导入 %s 失败: %v；回滚时吊销新证书也失败: %v", target, err, rerr)
+				}
+				res.RolledBack = true
+				res.New = cfclient.OriginCert{}
+				return res, fmt.Errorf("导入 %s 失败，已吊销新证书，旧证书保持不变: %w", target, err)
+			}
+			return res, fmt.Errorf("导入 %s 失败，已导入的 %d 个 ARN 使用新证书，旧证书未吊销: %w", target, len(res.Imported), err)
+		}
+		res.Imported = append(res.Imported, target)
+		notify(fmt.Sprintf("2/3 已重新导入 %s", target))
+	}
+
+	if !plan.RevokeOld() {
+		notify("3/3 旧证书未导入 ACM，跳过吊销")
+		return res, nil
+	}
+	if err := r.CF.RevokeOriginCACertificate(ctx, plan.Account, plan.Old.ID); err != nil {
+		res.RevokeErr = err
+		return res, nil
+	}
+	res.Revoked = true
+	notify(fmt.Sprintf("3/3 已吊销旧证书 %s", plan.Old.ID))
+	return res, nil
+}
+
+// CertSerial 返回 PEM 证书的序列号（小写十六进制，不含冒号与前导 0）
+func CertSerial(certPEM string) (string, error) {
+	data := strings.TrimSpace(certPEM)
+	if data == "" {
+		return "", fmt.Errorf("证书内容为空，无法计算序列号")
+	}
+	if !strings.Contains(data, "-----BEGIN") {
+		data = "-----BEGIN CERTIFICATE-----\n" + data + "\n-----END CERTIFICATE-----"
+	}
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return "", fmt.Errorf("证书 PEM 解析失败")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("证书解析失败: %v", err)
+	}
+	return serialHex(cert.SerialNumber), nil
+}
+
+// NormalizeSerial 把 ACM 返回的 "0e:5d:..." 形式序列号转换为 CertSerial 的格式
+func NormalizeSerial(s string) string {
+	return acmclient.NormalizeSerial(s)
+}
+
+func serialHex(n *big.Int) string {
+	return NormalizeSerial(n.Text(16))
+}
@@ -0,0 +1,175 @@
+package originrotate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"DomainC/acmclient"
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+type fakeCF struct {
+	cfclient.Client
+	certs   []cfclient.OriginCACertInfo
+	revoked []string
+	created int
+}
+
+func (f *fakeCF) ListZoneOriginCACertificates(ctx context.Context, account config.CF, zoneID string) ([]cfclient.OriginCACertInfo, error) {
+	return f.certs, nil
+}
+
+func (f *fakeCF) CreateOriginCertificate(ctx context.Context, account config.CF, hostnames []string, opts cfclient.OriginCertOptions) (cfclient.OriginCert, error) {
+	f.created++
+	return cfclient.OriginCert{ID: "new", Hostnames: hostnames, CertificatePEM: "CERT", PrivateKeyPEM: "KEY"}, nil
+}
+
+func (f *fakeCF) RevokeOriginCACertificate(ctx context.Context, account config.CF, certID string) error {
+	f.revoked = append(f.revoked, certID)
+	return nil
+}
+
+type fakeACM struct {
+	found    []ACMCert
+	failARN  string
+	tagFail  string // 重新导入成功但更新标签失败的 ARN
+	imported []string
+	serial   string
+	tags     map[string]string
+}
+
+func (f *fakeACM) FindBySerial(ctx context.Context, serial string) ([]ACMCert, error) {
+	f.serial = serial
+	return f.found, nil
+}
+
+func (f *fakeACM) Reimport(ctx context.Context, target ACMCert, certPEM, keyPEM string, tags map[string]string) error {
+	if target.ARN == f.failARN {
+		return errors.New("access denied")
+	}
+	f.imported = append(f.imported, target.ARN)
+	f.tags = tags
+	if target.ARN == f.tagFail {
+		return fmt.Errorf("%w: throttled", acmclient.ErrTagging)
+	}
+	return nil
+}
+
+func testCertPEM(t *testing.T, serial int64) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+var targets = []ACMCert{{Target: "us", ARN: "arn:1"}, {Target: "sg", ARN: "arn:2"}}
+
+func plan() Plan {
+	return Plan{Zone: "example.com", Old: cfclient.OriginCACertInfo{ID: "old", Hostnames: []string{"example.com", "*.example.com"}}, Targets: targets}
+}
+
+func TestPrepareMatchesACMBySerial(t *testing.T) {
+	cf := &fakeCF{certs: []cfclient.OriginCACertInfo{{ID: "old", CertificatePEM: testCertPEM(t, 0x0e5d)}}}
+	acm := &fakeACM{found: targets}
+	r := &Rotator{CF: cf, ACM: acm}
+	p, err := r.Prepare(context.Background(), config.CF{}, cfclient.ZoneDetail{ID: "z", Name: "example.com"}, "")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if acm.serial != "e5d" || len(p.Targets) != 2 || !p.RevokeOld() {
+		t.Fatalf("unexpected plan: serial=%s %+v", acm.serial, p)
+	}
+	if NormalizeSerial("00:0E:5D") != "e5d" {
+		t.Fatal("NormalizeSerial should strip colons and leading zeros")
+	}
+
+	cf.certs = append(cf.certs, cfclient.OriginCACertInfo{ID: "other"})
+	if _, err := r.Prepare(context.Background(), config.CF{}, cfclient.ZoneDetail{Name: "example.com"}, ""); err == nil {
+		t.Fatal("expected error when several active certs exist")
+	}
+}
+
+func TestRotateSuccessRevokesOld(t *testing.T) {
+	cf, acm := &fakeCF{}, &fakeACM{}
+	res, err := (&Rotator{CF: cf, ACM: acm}).Rotate(context.Background(), plan(), nil)
+	if err != nil || !res.Complete() || !res.Revoked {
+		t.Fatalf("rotate: %v %+v", err, res)
+	}
+	if len(acm.imported) != 2 || len(cf.revoked) != 1 || cf.revoked[0] != "old" {
+		t.Fatalf("imported=%v revoked=%v", acm.imported, cf.revoked)
+	}
+	if acm.tags[acmclient.TagCertID] != res.New.ID {
+		t.Fatalf("tags=%v new=%s", acm.tags, res.New.ID)
+	}
+}
+
+func TestRotateFirstImportFailureRollsBack(t *testing.T) {
+	cf, acm := &fakeCF{}, &fakeACM{failARN: "arn:1"}
+	res, err := (&Rotator{CF: cf, ACM: acm}).Rotate(context.Background(), plan(), nil)
+	if err == nil || !res.RolledBack {
+		t.Fatalf("expected rollback, got %v %+v", err, res)
+	}
+	if len(cf.revoked) != 1 || cf.revoked[0] != "new" {
+		t.Fatalf("only the new cert should be revoked, got %v", cf.revoked)
+	}
+}
+
+func TestRotateFirstTagFailureDoesNotRollBack(t *testing.T) {
+	cf, acm := &fakeCF{}, &fakeACM{tagFail: "arn:1"}
+	var notes []string
+	res, err := (&Rotator{CF: cf, ACM: acm}).Rotate(context.Background(), plan(), func(s string) { notes = append(notes, s) })
+	if err != nil || res.RolledBack || !res.Complete() {
+		t.Fatalf("tag failure must not roll back: %v %+v", err, res)
+	}
+	// 新证书已在 arn:1 上生效，绝不能被吊销；两个 ARN 都算已导入，旧证书照常吊销
+	if len(res.Imported) != 2 || len(cf.revoked) != 1 || cf.revoked[0] != "old" {
+		t.Fatalf("imported=%v revoked=%v", res.Imported, cf.revoked)
+	}
+	if len(res.TagErrs) != 1 || !errors.Is(res.TagErrs[0], acmclient.ErrTagging) {
+		t.Fatalf("expected tag warning, got %v", res.TagErrs)
+	}
+}
+
+func TestRotateLaterImportFailureKeepsOld(t *testing.T) {
+	cf, acm := &fakeCF{}, &fakeACM{failARN: "arn:2"}
+	res, err := (&Rotator{CF: cf, ACM: acm}).Rotate(context.Background(), plan(), nil)
+	if err == nil || res.RolledBack || res.Revoked {
+		t.Fatalf("unexpected result: %v %+v", err, res)
+	}
+	if len(cf.revoked) != 0 || len(res.Imported) != 1 {
+		t.Fatalf("nothing should be revoked: revoked=%v imported=%v", cf.revoked, res.Imported)
+	}
+}
+
+func TestRotateWithoutACMDoesNotRevoke(t *testing.T) {
+	cf := &fakeCF{}
+	p := plan()
+	p.Targets = nil
+	res, err := (&Rotator{CF: cf}).Rotate(context.Background(), p, nil)
+	if err != nil || res.Revoked || len(cf.revoked) != 0 || cf.created != 1 {
+		t.Fatalf("unexpected: %v %+v revoked=%v", err, res, cf.revoked)
+	}
+}
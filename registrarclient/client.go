@@ -20,6 +20,8 @@ type Client interface {
 	GetNameServers(ctx context.Context, registrar config.Registrar, domain string) ([]string, error)
 	SetNameServers(ctx context.Context, registrar config.Registrar, domain string, nameServers []string) error
 	ListDomains(ctx context.Context, registrar config.Registrar) ([]string, error)
+	GetDSRecords(ctx context.Context, registrar config.Registrar, domain string) ([]DSRecord, error)
+	SetDSRecords(ctx context.Context, registrar config.Registrar, domain string, records []DSRecord) error
 }
 
 type apiClient struct {
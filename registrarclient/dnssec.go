@@ -0,0 +1,293 @@
+package registrarclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"DomainC/config"
+)
+
+// ErrDNSSECUnsupported 表示注册商 API 不支持提交 DS，需要到控制台手动操作
+var ErrDNSSECUnsupported = errors.New("registrar api does not support dnssec")
+
+// DSRecord 是提交到注册局的 DS 记录
+type DSRecord struct {
+	KeyTag     int
+	Algorithm  int
+	DigestType int
+	Digest     string
+}
+
+func (r DSRecord) String() string {
+	return fmt.Sprintf("%d %d %d %s", r.KeyTag, r.Algorithm, r.DigestType, strings.ToUpper(r.Digest))
+}
+
+// ParseDSRecord 解析 "keytag algorithm digesttype digest" 形式的 DS 数据，
+// 兼容前面带 "name TTL IN DS" 的完整记录文本。
+func ParseDSRecord(s string) (DSRecord, error) {
+	fields := strings.Fields(s)
+	for i, f := range fields {
+		if strings.EqualFold(f, "DS") {
+			fields = fields[i+1:]
+			break
+		}
+	}
+	if len(fields) < 4 {
+		return DSRecord{}, fmt.Errorf("DS 记录格式不正确: %s", s)
+	}
+	var ds DSRecord
+	var err error
+	if ds.KeyTag, err = strconv.Atoi(fields[0]); err != nil {
+		return DSRecord{}, fmt.Errorf("DS key tag 不正确: %s", fields[0])
+	}
+	if ds.Algorithm, err = strconv.Atoi(fields[1]); err != nil {
+		return DSRecord{}, fmt.Errorf("DS algorithm 不正确: %s", fields[1])
+	}
+	if ds.DigestType, err = strconv.Atoi(fields[2]); err != nil {
+		return DSRecord{}, fmt.Errorf("DS digest type 不正确: %s", fields[2])
+	}
+	ds.Digest = strings.ToUpper(strings.Join(fields[3:], ""))
+	return ds, nil
+}
+
+// SameDSRecords 判断两组 DS 是否一致（忽略顺序与 digest 大小写）
+func SameDSRecords(a, b []DSRecord) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	key := func(in []DSRecord) []string {
+		out := make([]string, 0, len(in))
+		for _, r := range in {
+			out = append(out, r.String())
+		}
+		sort.Strings(out)
+		return out
+	}
+	ka, kb := key(a), key(b)
+	for i := range ka {
+		if ka[i] != kb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsDS 判断 want 是否出现在 list 中
+func ContainsDS(list []DSRecord, want DSRecord) bool {
+	for _, r := range list {
+		if r.String() == want.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *apiClient) GetDSRecords(ctx context.Context, registrar config.Registrar, domain string) ([]DSRecord, error) {
+	switch strings.ToLower(strings.TrimSpace(registrar.Type)) {
+	case "namecheap":
+		return nil, fmt.Errorf("%w: namecheap", ErrDNSSECUnsupported)
+	case "godaddy":
+		if registrar.GoDaddy == nil {
+			return nil, fmt.Errorf("godaddy 配置缺失")
+		}
+		return c.goDaddyGetDSRecords(ctx, *registrar.GoDaddy, domain)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedRegistrar, registrar.Type)
+	}
+}
+
+// SetDSRecords 让注册商上的 DS 与 records 保持一致，records 为空表示全部移除。
+func (c *apiClient) SetDSRecords(ctx context.Context, registrar config.Registrar, domain string, records []DSRecord) error {
+	switch strings.ToLower(strings.TrimSpace(registrar.Type)) {
+	case "namecheap":
+		// namecheap 公开 API 没有 DNSSEC 相关命令，只能在控制台 Advanced DNS 中手动添加
+		return fmt.Errorf("%w: namecheap", ErrDNSSECUnsupported)
+	case "godaddy":
+		if registrar.GoDaddy == nil {
+			return fmt.Errorf("godaddy 配置缺失")
+		}
+		return c.goDaddySetDSRecords(ctx, *registrar.GoDaddy, domain, records)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedRegistrar, registrar.Type)
+	}
+}
+
+// GoDaddy v2 接口使用算法/摘要类型的名称而不是编号
+var goDaddyDSAlgorithms = map[int]string{
+	1:  "RSAMD5",
+	3:  "DSA",
+	5:  "RSASHA1",
+	6:  "DSA_NSEC3_SHA1",
+	7:  "RSASHA1_NSEC3_SHA1",
+	8:  "RSASHA256",
+	10: "RSASHA512",
+	12: "ECC_GOST",
+	13: "ECDSAP256SHA256",
+	14: "ECDSAP384SHA384",
+	15: "ED25519",
+	16: "ED448",
+}
+
+var goDaddyDSDigestTypes = map[int]string{
+	1: "SHA1",
+	2: "SHA256",
+	3: "GOST",
+	4: "SHA384",
+}
+
+type goDaddyDNSSECRecord struct {
+	Algorithm  string `json:"algorithm"`
+	Digest     string `json:"digest"`
+	DigestType string `json:"digestType"`
+	KeyTag     int    `json:"keyTag"`
+}
+
+type goDaddyDomainDetailV2 struct {
+	DNSSECRecords []goDaddyDNSSECRecord `json:"dnssecRecords"`
+}
+
+func toGoDaddyDS(r DSRecord) (goDaddyDNSSECRecord, error) {
+	alg, ok := goDaddyDSAlgorithms[r.Algorithm]
+	if !ok {
+		return goDaddyDNSSECRecord{}, fmt.Errorf("godaddy 不支持 DS 算法 %d", r.Algorithm)
+	}
+	dt, ok := goDaddyDSDigestTypes[r.DigestType]
+	if !ok {
+		return goDaddyDNSSECRecord{}, fmt.Errorf("godaddy 不支持 DS 摘要类型 %d", r.DigestType)
+	}
+	return goDaddyDNSSECRecord{Algorithm: alg, Digest: strings.ToUpper(r.Digest), DigestType: dt, KeyTag: r.KeyTag}, nil
+}
+
+func fromGoDaddyDS(r goDaddyDNSSECRecord) DSRecord {
+	ds := DSRecord{KeyTag: r.KeyTag, Digest: strings.ToUpper(r.Digest)}
+	for k, v := range goDaddyDSAlgorithms {
+		if strings.EqualFold(v, r.Algorithm) {
+			ds.Algorithm = k
+		}
+	}
+	for k, v := range goDaddyDSDigestTypes {
+		if strings.EqualFold(v, r.DigestType) {
+			ds.DigestType = k
+		}
+	}
+	return ds
+}
+
+func goDaddyV2DomainURL(cfg config.GoDaddyConfig, domain string) (string, error) {
+	if strings.TrimSpace(cfg.CustomerID) == "" {
+		return "", fmt.Errorf("godaddy DNSSEC 需要配置 customerId")
+	}
+	return fmt.Sprintf("https://api.godaddy.com/v2/customers/%s/domains/%s", cfg.CustomerID, domain), nil
+}
+
+func (c *apiClient) goDaddyGetDSRecords(ctx context.Context, cfg config.GoDaddyConfig, domain string) ([]DSRecord, error) {
+	endpoint, err := goDaddyV2DomainURL(cfg, domain)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.goDaddyDo(ctx, cfg, http.MethodGet, endpoint+"?includes=dnssecRecords", nil)
+	if err != nil {
+		return nil, err
+	}
+	var detail goDaddyDomainDetailV2
+	if err := json.Unmarshal(data, &detail); err != nil {
+		return nil, fmt.Errorf("godaddy 解析失败: %w", err)
+	}
+	out := make([]DSRecord, 0, len(detail.DNSSECRecords))
+	for _, r := range detail.DNSSECRecords {
+		out = append(out, fromGoDaddyDS(r))
+	}
+	return out, nil
+}
+
+func (c *apiClient) goDaddySetDSRecords(ctx context.Context, cfg config.GoDaddyConfig, domain string, records []DSRecord) error {
+	current, err := c.goDaddyGetDSRecords(ctx, cfg, domain)
+	if err != nil {
+		return err
+	}
+	if SameDSRecords(current, records) {
+		return nil
+	}
+	endpoint, err := goDaddyV2DomainURL(cfg, domain)
+	if err != nil {
+		return err
+	}
+
+	var add, remove []goDaddyDNSSECRecord
+	for _, r := range records {
+		if ContainsDS(current, r) {
+			continue
+		}
+		item, err := toGoDaddyDS(r)
+		if err != nil {
+			return err
+		}
+		add = append(add, item)
+	}
+	for _, r := range current {
+		if ContainsDS(records, r) {
+			continue
+		}
+		item, err := toGoDaddyDS(r)
+		if err != nil {
+			return err
+		}
+		remove = append(remove, item)
+	}
+
+	// 先添加再删除，避免轮换过程中注册局出现没有 DS 的空窗
+	if len(add) > 0 {
+		if _, err := c.goDaddyDo(ctx, cfg, http.MethodPatch, endpoint+"/dnssecRecords", add); err != nil {
+			return err
+		}
+	}
+	if len(remove) > 0 {
+		if _, err := c.goDaddyDo(ctx, cfg, http.MethodDelete, endpoint+"/dnssecRecords", remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *apiClient) goDaddyDo(ctx context.Context, cfg config.GoDaddyConfig, method, endpoint string, payload any) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("godaddy 序列化失败: %w", err)
+		}
+		body = strings.NewReader(string(raw))
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("godaddy 请求创建失败: %w", err)
+	}
+	applyGoDaddyAuth(req, cfg)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("godaddy 请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("godaddy 读取响应失败: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrDomainNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("godaddy 响应异常: %s", strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
@@ -0,0 +1,118 @@
+package registrarclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"DomainC/config"
+)
+
+func TestParseDSRecord(t *testing.T) {
+	for _, in := range []string{
+		"2371 13 2 abcd",
+		"example.com. 3600 IN DS 2371 13 2 AB CD",
+	} {
+		ds, err := ParseDSRecord(in)
+		if err != nil {
+			t.Fatalf("%q: %v", in, err)
+		}
+		if ds != (DSRecord{KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: "ABCD"}) {
+			t.Fatalf("%q: unexpected %+v", in, ds)
+		}
+	}
+	if _, err := ParseDSRecord("2371 13"); err == nil {
+		t.Fatal("expected error for short record")
+	}
+}
+
+func TestSameDSRecords(t *testing.T) {
+	a := []DSRecord{{1, 13, 2, "AA"}, {2, 13, 2, "bb"}}
+	b := []DSRecord{{2, 13, 2, "BB"}, {1, 13, 2, "aa"}}
+	if !SameDSRecords(a, b) {
+		t.Fatal("expected equal ignoring order and case")
+	}
+	if SameDSRecords(a, b[:1]) {
+		t.Fatal("expected different length to differ")
+	}
+}
+
+// rewriteTransport 把请求转发到测试服务器
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGoDaddySetDSRecords(t *testing.T) {
+	var mu sync.Mutex
+	current := []goDaddyDNSSECRecord{{Algorithm: "RSASHA256", Digest: "OLD", DigestType: "SHA256", KeyTag: 1}}
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, r.Method)
+		if r.URL.Path != "/v2/customers/cust/domains/example.com" && r.URL.Path != "/v2/customers/cust/domains/example.com/dnssecRecords" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(goDaddyDomainDetailV2{DNSSECRecords: current})
+		case http.MethodPatch:
+			var add []goDaddyDNSSECRecord
+			_ = json.NewDecoder(r.Body).Decode(&add)
+			current = append(current, add...)
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			var del []goDaddyDNSSECRecord
+			_ = json.NewDecoder(r.Body).Decode(&del)
+			var keep []goDaddyDNSSECRecord
+			for _, c := range current {
+				if c.KeyTag != del[0].KeyTag {
+					keep = append(keep, c)
+				}
+			}
+			current = keep
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	target, _ := url.Parse(srv.URL)
+	c := &apiClient{httpClient: &http.Client{Transport: rewriteTransport{target: target}}}
+	reg := config.Registrar{Label: "gd", Type: "godaddy", GoDaddy: &config.GoDaddyConfig{CustomerID: "cust"}}
+	want := []DSRecord{{KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: "ABCD"}}
+
+	if err := c.SetDSRecords(context.Background(), reg, "example.com", want); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	got, err := c.GetDSRecords(context.Background(), reg, "example.com")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !SameDSRecords(got, want) {
+		t.Fatalf("unexpected DS at registrar: %+v", got)
+	}
+	// 新 DS 必须先于旧 DS 删除提交
+	if len(calls) != 4 || calls[1] != http.MethodPatch || calls[2] != http.MethodDelete {
+		t.Fatalf("unexpected call order: %v", calls)
+	}
+}
+
+func TestNamecheapDSUnsupported(t *testing.T) {
+	c := &apiClient{httpClient: http.DefaultClient}
+	err := c.SetDSRecords(context.Background(), config.Registrar{Type: "namecheap"}, "example.com", nil)
+	if err == nil || !errors.Is(err, ErrDNSSECUnsupported) {
+		t.Fatalf("expected ErrDNSSECUnsupported, got %v", err)
+	}
+}
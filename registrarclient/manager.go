@@ -152,3 +152,51 @@ func (m *Manager) GetNameServersForDomain(ctx context.Context, domain string) (c
 func (m *Manager) ListDomainsForRegistrar(ctx context.Context, registrar config.Registrar) ([]string, error) {
 	return m.client.ListDomains(ctx, registrar)
 }
+
+// GetDSRecordsForDomain 从持有该域名的注册商读取已提交的 DS。
+// 注册商 API 不支持 DNSSEC 时返回该注册商以及 ErrDNSSECUnsupported。
+func (m *Manager) GetDSRecordsForDomain(ctx context.Context, domain string) (config.Registrar, []DSRecord, error) {
+	var records []DSRecord
+	r, err := m.withDomainRegistrar(ctx, domain, func(r config.Registrar) error {
+		var err error
+		records, err = m.client.GetDSRecords(ctx, r, domain)
+		return err
+	})
+	return r, records, err
+}
+
+// SetDSRecordsForDomain 把 DS 写入持有该域名的注册商，records 为空表示移除全部 DS。
+func (m *Manager) SetDSRecordsForDomain(ctx context.Context, domain string, records []DSRecord) (config.Registrar, error) {
+	return m.withDomainRegistrar(ctx, domain, func(r config.Registrar) error {
+		return m.client.SetDSRecords(ctx, r, domain, records)
+	})
+}
+
+// withDomainRegistrar 依次在注册商账号上执行 fn，跳过不持有该域名的账号。
+// 对不支持 DNSSEC 的注册商，通过读取 NS 确认域名归属后再返回 ErrDNSSECUnsupported。
+func (m *Manager) withDomainRegistrar(ctx context.Context, domain string, fn func(config.Registrar) error) (config.Registrar, error) {
+	if len(m.registrars) == 0 {
+		return config.Registrar{}, fmt.Errorf("未配置注册商")
+	}
+	var lastErr error
+	for _, r := range m.registrarsForDomain(domain) {
+		err := fn(r)
+		if err == nil {
+			return r, nil
+		}
+		if errors.Is(err, ErrDomainNotFound) {
+			continue
+		}
+		if errors.Is(err, ErrDNSSECUnsupported) {
+			if _, nsErr := m.client.GetNameServers(ctx, r, domain); nsErr == nil {
+				return r, err
+			}
+			continue
+		}
+		lastErr = fmt.Errorf("[%s] %w", r.Label, err)
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("未在任何注册商账号下找到该域名")
+	}
+	return config.Registrar{}, lastErr
+}
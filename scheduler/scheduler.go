@@ -2,6 +2,8 @@ package scheduler
 
 import (
 	"context"
+	"log"
+	"time"
 )
 
 type DailyScheduler struct{}
@@ -35,3 +37,24 @@ func NewDailyScheduler() *DailyScheduler {
 func (s *DailyScheduler) ScheduleDaily(ctx context.Context, hour, min int, job func()) {
 
 }
+
+// ScheduleEvery 启动后立即执行一次 job，之后每隔 interval 执行一次，直到 ctx 结束。
+// 上一次执行未结束时不会重复启动。
+func (s *DailyScheduler) ScheduleEvery(ctx context.Context, interval time.Duration, job func()) {
+	if interval <= 0 {
+		log.Printf("忽略间隔无效的计划任务: %v", interval)
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			job()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,57 @@
+// Package severity 定义检查类功能（解析检查、邮件认证检查）共用的结果级别。
+package severity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level 是检查结果的级别，数值越大越严重
+type Level int
+
+const (
+	OK Level = iota
+	Info
+	Warning
+	Error
+)
+
+func (l Level) String() string {
+	switch l {
+	case OK:
+		return "ok"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return "info"
+}
+
+// Icon 返回用于 Telegram 消息的标记
+func (l Level) Icon() string {
+	switch l {
+	case OK:
+		return "✅"
+	case Warning:
+		return "⚠️"
+	case Error:
+		return "❌"
+	}
+	return "ℹ️"
+}
+
+// Parse 解析 ok/info/warning/error，大小写不敏感，空字符串视为 info
+func Parse(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "ok":
+		return OK, nil
+	case "info", "":
+		return Info, nil
+	case "warning", "warn":
+		return Warning, nil
+	case "error":
+		return Error, nil
+	}
+	return Info, fmt.Errorf("未知的严重程度: %s", s)
+}
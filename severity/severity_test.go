@@ -0,0 +1,21 @@
+package severity
+
+import "testing"
+
+func TestParseAndFormat(t *testing.T) {
+	for in, want := range map[string]Level{"": Info, "OK": OK, " warn ": Warning, "error": Error} {
+		got, err := Parse(in)
+		if err != nil || got != want {
+			t.Fatalf("Parse(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := Parse("fatal"); err == nil {
+		t.Fatal("expected error for unknown level")
+	}
+	if !(OK < Info && Info < Warning && Warning < Error) {
+		t.Fatal("levels must be ordered by severity")
+	}
+	if Error.String() != "error" || Error.Icon() != "❌" || OK.Icon() != "✅" {
+		t.Fatalf("unexpected formatting: %s %s %s", Error, Error.Icon(), OK.Icon())
+	}
+}
@@ -0,0 +1,142 @@
+// Package takeover 扫描各 Zone 的解析记录，找出指向已被删除的第三方服务（S3、Heroku、
+// GitHub Pages、ELB 等）的悬空记录，这类记录可能被他人注册同名资源后接管子域名。
+package takeover
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"strings"
+
+	"DomainC/config"
+)
+
+// Severity 是发现项的严重程度
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = []string{"info", "low", "medium", "high", "critical"}
+var severityIcons = []string{"ℹ️", "🔵", "🟡", "🟠", "🔴"}
+
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return "unknown"
+	}
+	return severityNames[s]
+}
+
+// Icon 按 info→critical 返回由浅到深的颜色标记
+func (s Severity) Icon() string {
+	if s < 0 || int(s) >= len(severityIcons) {
+		return "❔"
+	}
+	return severityIcons[s]
+}
+
+// ParseSeverity 解析 info/low/medium/high/critical，大小写不敏感
+func ParseSeverity(s string) (Severity, error) {
+	for i, n := range severityNames {
+		if strings.EqualFold(strings.TrimSpace(s), n) {
+			return Severity(i), nil
+		}
+	}
+	return SeverityInfo, fmt.Errorf("未知的严重程度: %s", s)
+}
+
+// Fingerprint 描述一个易被接管的服务商
+type Fingerprint struct {
+	Provider string
+	// CNAME 为 CNAME 目标的后缀，包含 * 时按通配符匹配整个目标
+	CNAME []string
+	// CIDR 为 A/AAAA 记录所在的地址段（例如云厂商弹性 IP 段）
+	CIDR []*net.IPNet
+	// Body 为资源不存在时服务商返回页面中的特征文本
+	Body []string
+	// NXDomain 为 true 时，CNAME 目标不存在即可认定为悬空
+	NXDomain bool
+	// Severity 为确认可接管时的严重程度
+	Severity Severity
+}
+
+// MatchTarget 判断 CNAME 目标是否属于该服务商
+func (f Fingerprint) MatchTarget(target string) bool {
+	target = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(target)), ".")
+	for _, p := range f.CNAME {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if strings.Contains(p, "*") {
+			if ok, _ := path.Match(p, target); ok {
+				return true
+			}
+			continue
+		}
+		p = strings.TrimPrefix(p, ".")
+		if target == p || strings.HasSuffix(target, "."+p) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchIP 判断地址是否落在该服务商的地址段中
+func (f Fingerprint) MatchIP(ip net.IP) bool {
+	for _, n := range f.CIDR {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultFingerprints 是内置的常见易接管服务商，参考 can-i-take-over-xyz
+func DefaultFingerprints() []Fingerprint {
+	return []Fingerprint{
+		{Provider: "AWS S3", CNAME: []string{"s3.amazonaws.com", "s3-website*.amazonaws.com", "*.s3-website*.amazonaws.com", "*.s3.*.amazonaws.com"}, Body: []string{"NoSuchBucket", "The specified bucket does not exist"}, Severity: SeverityHigh},
+		{Provider: "AWS Elastic Beanstalk", CNAME: []string{"elasticbeanstalk.com"}, NXDomain: true, Severity: SeverityCritical},
+		{Provider: "AWS ELB", CNAME: []string{"elb.amazonaws.com"}, NXDomain: true, Severity: SeverityMedium},
+		{Provider: "Heroku", CNAME: []string{"herokuapp.com", "herokudns.com", "herokussl.com"}, Body: []string{"No such app", "herokucdn.com/error-pages/no-such-app.html"}, NXDomain: true, Severity: SeverityHigh},
+		{Provider: "GitHub Pages", CNAME: []string{"github.io"}, Body: []string{"There isn't a GitHub Pages site here."}, Severity: SeverityHigh},
+		{Provider: "Azure", CNAME: []string{"azurewebsites.net", "cloudapp.net", "cloudapp.azure.com", "trafficmanager.net", "blob.core.windows.net", "azureedge.net"}, NXDomain: true, Severity: SeverityCritical},
+		{Provider: "Netlify", CNAME: []string{"netlify.app", "netlify.com"}, Body: []string{"Not Found - Request ID"}, Severity: SeverityMedium},
+		{Provider: "Shopify", CNAME: []string{"myshopify.com"}, Body: []string{"Sorry, this shop is currently unavailable."}, Severity: SeverityMedium},
+		{Provider: "Fastly", CNAME: []string{"fastly.net"}, Body: []string{"Fastly error: unknown domain"}, Severity: SeverityMedium},
+		{Provider: "Zendesk", CNAME: []string{"zendesk.com"}, Body: []string{"Help Center Closed"}, Severity: SeverityMedium},
+		{Provider: "Vercel", CNAME: []string{"vercel.app", "now.sh"}, Body: []string{"DEPLOYMENT_NOT_FOUND"}, Severity: SeverityMedium},
+	}
+}
+
+// FromConfig 把配置中的服务商转换为 Fingerprint；includeDefaults 为 true 时追加内置列表
+func FromConfig(items []config.TakeoverFingerprint, includeDefaults bool) ([]Fingerprint, error) {
+	var out []Fingerprint
+	for _, item := range items {
+		fp := Fingerprint{Provider: item.Provider, CNAME: item.CNAME, Body: item.Body, NXDomain: item.NXDomain, Severity: SeverityHigh}
+		if item.Severity != "" {
+			sev, err := ParseSeverity(item.Severity)
+			if err != nil {
+				return nil, fmt.Errorf("服务商 %s: %v", item.Provider, err)
+			}
+			fp.Severity = sev
+		}
+		for _, c := range item.CIDR {
+			_, n, err := net.ParseCIDR(strings.TrimSpace(c))
+			if err != nil {
+				return nil, fmt.Errorf("服务商 %s 的地址段 %q 无效: %v", item.Provider, c, err)
+			}
+			fp.CIDR = append(fp.CIDR, n)
+		}
+		if len(fp.CNAME) == 0 && len(fp.CIDR) == 0 {
+			return nil, fmt.Errorf("服务商 %s 未配置 cname 或 cidr", item.Provider)
+		}
+		out = append(out, fp)
+	}
+	if includeDefaults {
+		out = append(out, DefaultFingerprints()...)
+	}
+	return out, nil
+}
@@ -0,0 +1,274 @@
+package takeover
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Finding 是一条疑似悬空的记录
+type Finding struct {
+	Account  string
+	Zone     string
+	Name     string
+	Type     string
+	Target   string
+	Provider string
+	Severity Severity
+	Reason   string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s [%s] %s %s → %s（%s）: %s", f.Severity.Icon(), f.Severity, f.Name, f.Type, f.Target, f.Provider, f.Reason)
+}
+
+// Prober 执行网络探测，测试中可替换
+type Prober interface {
+	// Resolve 解析主机名；目标不存在时返回 ErrNXDomain
+	Resolve(ctx context.Context, host string) ([]string, error)
+	// Fetch 以 host 作为 Host 访问 HTTP(S) 并返回状态码与页面前若干字节
+	Fetch(ctx context.Context, host string) (int, string, error)
+	// Reachable 判断 IP 的 80/443 端口是否可连接
+	Reachable(ctx context.Context, ip string) bool
+}
+
+// ErrNXDomain 表示域名不存在
+var ErrNXDomain = errors.New("NXDOMAIN")
+
+// Scanner 遍历所有 Zone 的记录并检测悬空记录
+type Scanner struct {
+	CF           cfclient.Client
+	Fingerprints []Fingerprint
+	Prober       Prober
+	// Concurrency 同时探测的记录数，默认 8
+	Concurrency int
+}
+
+// Scan 扫描给定账号，返回按严重程度从高到低排序的发现
+func (s *Scanner) Scan(ctx context.Context, accounts []config.CF) ([]Finding, []error) {
+	type job struct {
+		account string
+		zone    string
+		record  cloudflare.DNSRecord
+		fp      Fingerprint
+	}
+	var jobs []job
+	var errs []error
+	for _, acc := range accounts {
+		zones, err := s.CF.ListZones(ctx, acc)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("列出账号 %s 的域名失败: %v", acc.Label, err))
+			continue
+		}
+		for _, z := range zones {
+			records, err := s.CF.ListDNSRecords(ctx, acc, z.Name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("获取 %s 的解析失败: %v", z.Name, err))
+				continue
+			}
+			for _, r := range records {
+				if fp, ok := s.match(r); ok {
+					jobs = append(jobs, job{account: acc.Label, zone: z.Name, record: r, fp: fp})
+				}
+			}
+		}
+	}
+
+	workers := s.Concurrency
+	if workers <= 0 {
+		workers = 8
+	}
+	var (
+		mu       sync.Mutex
+		findings []Finding
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, workers)
+	)
+	for _, j := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(j job) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if f, ok := s.Check(ctx, j.record, j.fp); ok {
+				f.Account, f.Zone = j.account, j.zone
+				mu.Lock()
+				findings = append(findings, f)
+				mu.Unlock()
+			}
+		}(j)
+	}
+	wg.Wait()
+
+	SortFindings(findings)
+	return findings, errs
+}
+
+// SortFindings 按严重程度降序、名称升序排序
+func SortFindings(findings []Finding) {
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Severity != findings[j].Severity {
+			return findings[i].Severity > findings[j].Severity
+		}
+		return findings[i].Name < findings[j].Name
+	})
+}
+
+// match 找到记录对应的服务商特征
+func (s *Scanner) match(r cloudflare.DNSRecord) (Fingerprint, bool) {
+	switch strings.ToUpper(r.Type) {
+	case "CNAME":
+		for _, fp := range s.Fingerprints {
+			if fp.MatchTarget(r.Content) {
+				return fp, true
+			}
+		}
+	case "A", "AAAA":
+		ip := net.ParseIP(r.Content)
+		if ip == nil {
+			return Fingerprint{}, false
+		}
+		for _, fp := range s.Fingerprints {
+			if fp.MatchIP(ip) {
+				return fp, true
+			}
+		}
+	}
+	return Fingerprint{}, false
+}
+
+// Check 探测单条记录，返回是否疑似悬空
+func (s *Scanner) Check(ctx context.Context, r cloudflare.DNSRecord, fp Fingerprint) (Finding, bool) {
+	f := Finding{Name: r.Name, Type: r.Type, Target: r.Content, Provider: fp.Provider}
+	prober := s.Prober
+	if prober == nil {
+		prober = NetProber{}
+	}
+
+	if strings.EqualFold(r.Type, "A") || strings.EqualFold(r.Type, "AAAA") {
+		if prober.Reachable(ctx, r.Content) {
+			return f, false
+		}
+		f.Severity = SeverityMedium
+		f.Reason = "地址 80/443 端口无响应，IP 可能已释放并可被他人申请"
+		return f, true
+	}
+
+	if _, err := prober.Resolve(ctx, r.Content); err != nil {
+		if errors.Is(err, ErrNXDomain) {
+			f.Severity = SeverityLow
+			f.Reason = "CNAME 目标不存在 (NXDOMAIN)"
+			if fp.NXDomain {
+				f.Severity = fp.Severity
+				f.Reason += "，该服务商允许重新注册同名资源"
+			}
+			return f, true
+		}
+		f.Severity = SeverityInfo
+		f.Reason = fmt.Sprintf("解析 CNAME 目标失败: %v", err)
+		return f, true
+	}
+
+	if len(fp.Body) == 0 {
+		return f, false
+	}
+	status, body, err := prober.Fetch(ctx, strings.TrimSuffix(r.Name, "."))
+	if err != nil {
+		f.Severity = SeverityLow
+		f.Reason = fmt.Sprintf("HTTP 无响应: %v", err)
+		return f, true
+	}
+	for _, sig := range fp.Body {
+		if strings.Contains(body, sig) {
+			f.Severity = fp.Severity
+			f.Reason = fmt.Sprintf("HTTP %d 返回服务商资源不存在页面（%q）", status, sig)
+			return f, true
+		}
+	}
+	return f, false
+}
+
+// NetProber 是基于真实网络的 Prober
+type NetProber struct {
+	Resolver *net.Resolver
+	Timeout  time.Duration
+}
+
+func (p NetProber) timeout() time.Duration {
+	if p.Timeout <= 0 {
+		return 10 * time.Second
+	}
+	return p.Timeout
+}
+
+func (p NetProber) Resolve(ctx context.Context, host string) ([]string, error) {
+	r := p.Resolver
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.timeout())
+	defer cancel()
+	addrs, err := r.LookupHost(ctx, strings.TrimSuffix(host, "."))
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return nil, ErrNXDomain
+	}
+	return addrs, err
+}
+
+func (p NetProber) Fetch(ctx context.Context, host string) (int, string, error) {
+	client := &http.Client{
+		Timeout: p.timeout(),
+		Transport: &http.Transport{
+			// 只读取页面特征，不校验证书（悬空资源通常证书不匹配）
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 3 {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+	var lastErr error
+	for _, scheme := range []string{"https", "http"} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+host+"/", nil)
+		if err != nil {
+			return 0, "", err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		resp.Body.Close()
+		return resp.StatusCode, string(body), nil
+	}
+	return 0, "", lastErr
+}
+
+func (p NetProber) Reachable(ctx context.Context, ip string) bool {
+	d := net.Dialer{Timeout: p.timeout()}
+	for _, port := range []string{"443", "80"} {
+		conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(ip, port))
+		if err == nil {
+			conn.Close()
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package takeover
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+type fakeCF struct {
+	cfclient.Client
+	records []cloudflare.DNSRecord
+}
+
+func (f fakeCF) ListZones(ctx context.Context, account config.CF) ([]cfclient.ZoneDetail, error) {
+	return []cfclient.ZoneDetail{{Name: "example.com"}}, nil
+}
+
+func (f fakeCF) ListDNSRecords(ctx context.Context, account config.CF, domain string) ([]cloudflare.DNSRecord, error) {
+	return f.records, nil
+}
+
+type fakeProber struct {
+	resolve   map[string]error
+	bodies    map[string]string
+	reachable map[string]bool
+}
+
+func (p fakeProber) Resolve(ctx context.Context, host string) ([]string, error) {
+	if err := p.resolve[host]; err != nil {
+		return nil, err
+	}
+	return []string{"192.0.2.1"}, nil
+}
+
+func (p fakeProber) Fetch(ctx context.Context, host string) (int, string, error) {
+	body, ok := p.bodies[host]
+	if !ok {
+		return 0, "", errors.New("connection refused")
+	}
+	return 404, body, nil
+}
+
+func (p fakeProber) Reachable(ctx context.Context, ip string) bool {
+	return p.reachable[ip]
+}
+
+func TestScan(t *testing.T) {
+	fps, err := FromConfig([]config.TakeoverFingerprint{
+		{Provider: "Old DC", CIDR: []string{"203.0.113.0/24"}, Severity: "medium"},
+	}, true)
+	if err != nil {
+		t.Fatalf("FromConfig: %v", err)
+	}
+
+	scanner := &Scanner{
+		CF: fakeCF{records: []cloudflare.DNSRecord{
+			{Type: "CNAME", Name: "assets.example.com", Content: "gone-bucket.s3.amazonaws.com"},
+			{Type: "CNAME", Name: "docs.example.com", Content: "acme.github.io"},
+			{Type: "CNAME", Name: "app.example.com", Content: "acme.azurewebsites.net"},
+			{Type: "CNAME", Name: "ok.example.com", Content: "live.herokuapp.com"},
+			{Type: "CNAME", Name: "www.example.com", Content: "example.com"},
+			{Type: "A", Name: "legacy.example.com", Content: "203.0.113.9"},
+			{Type: "A", Name: "web.example.com", Content: "203.0.113.10"},
+			{Type: "A", Name: "other.example.com", Content: "198.51.100.1"},
+		}},
+		Fingerprints: fps,
+		Prober: fakeProber{
+			resolve: map[string]error{"acme.azurewebsites.net": ErrNXDomain},
+			bodies: map[string]string{
+				"assets.example.com": "<Code>NoSuchBucket</Code>",
+				"docs.example.com":   "<h1>Welcome</h1>",
+				"ok.example.com":     "hello",
+			},
+			reachable: map[string]bool{"203.0.113.10": true},
+		},
+	}
+
+	findings, errs := scanner.Scan(context.Background(), []config.CF{{Label: "acc"}})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	want := []struct {
+		name     string
+		provider string
+		sev      Severity
+	}{
+		{"app.example.com", "Azure", SeverityCritical},
+		{"assets.example.com", "AWS S3", SeverityHigh},
+		{"legacy.example.com", "Old DC", SeverityMedium},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("expected %d findings, got %d: %v", len(want), len(findings), findings)
+	}
+	for i, w := range want {
+		f := findings[i]
+		if f.Name != w.name || f.Provider != w.provider || f.Severity != w.sev || f.Account != "acc" || f.Zone != "example.com" {
+			t.Fatalf("finding %d = %+v, want %+v", i, f, w)
+		}
+	}
+}
+
+func TestFingerprintMatch(t *testing.T) {
+	fp := Fingerprint{CNAME: []string{"github.io", "*.s3-website-*.amazonaws.com"}}
+	cases := map[string]bool{
+		"acme.github.io.":                      true,
+		"github.io":                            true,
+		"notgithub.io":                         false,
+		"b.s3-website-us-east-1.amazonaws.com": true,
+		"b.s3-website.us-east-1.amazonaws.com": false,
+		"acme.github.io.evil.example":          false,
+	}
+	for target, want := range cases {
+		if got := fp.MatchTarget(target); got != want {
+			t.Fatalf("MatchTarget(%q) = %v, want %v", target, got, want)
+		}
+	}
+
+	_, n, _ := net.ParseCIDR("10.0.0.0/8")
+	fp = Fingerprint{CIDR: []*net.IPNet{n}}
+	if !fp.MatchIP(net.ParseIP("10.1.2.3")) || fp.MatchIP(net.ParseIP("11.0.0.1")) {
+		t.Fatalf("unexpected CIDR match")
+	}
+}
+
+func TestFromConfigRejectsInvalid(t *testing.T) {
+	if _, err := FromConfig([]config.TakeoverFingerprint{{Provider: "x"}}, false); err == nil {
+		t.Fatalf("expected error for fingerprint without cname/cidr")
+	}
+	if _, err := FromConfig([]config.TakeoverFingerprint{{Provider: "x", CIDR: []string{"nope"}}}, false); err == nil {
+		t.Fatalf("expected error for invalid CIDR")
+	}
+	if _, err := FromConfig([]config.TakeoverFingerprint{{Provider: "x", CNAME: []string{"a.com"}, Severity: "urgent"}}, false); err == nil {
+		t.Fatalf("expected error for invalid severity")
+	}
+}
@@ -0,0 +1,99 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"DomainC/acmclient"
+	"DomainC/config"
+)
+
+const acmUsage = "用法: /acm <aws-alias|all>\n列出 AWS ACM 中的证书：到期时间、是否被 ALB/CloudFront 等资源使用，以及本工具写入的 Cloudflare 证书 ID 标签。"
+
+// acmExpiringDays 列表中标记为即将到期的天数
+const acmExpiringDays = 30
+
+func (h *CommandHandler) handleACMCommand(args []string) {
+	if len(config.Cfg.AWSTargets) == 0 {
+		h.sendText("未配置 AWS 目标（awsTargets）。")
+		return
+	}
+	if len(args) < 1 {
+		var sb strings.Builder
+		sb.WriteString(acmUsage + "\n\n可用 AWS 目标：\n")
+		for _, alias := range acmclient.Aliases(config.Cfg.AWSTargets) {
+			sb.WriteString(fmt.Sprintf("- %s (%s)\n", alias, config.Cfg.AWSTargets[alias].Region))
+		}
+		h.sendText(sb.String())
+		return
+	}
+
+	aliases := []string{strings.TrimSpace(args[0])}
+	if strings.EqualFold(aliases[0], "all") {
+		aliases = acmclient.Aliases(config.Cfg.AWSTargets)
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+	for _, alias := range aliases {
+		client, err := acmclient.NewForAlias(ctx, alias)
+		if err != nil {
+			h.sendText(fmt.Sprintf("%v\n\n%s", err, acmUsage))
+			continue
+		}
+		certs, err := client.List(ctx)
+		if err != nil {
+			h.sendText(fmt.Sprintf("❌ %v", err))
+			continue
+		}
+
+		var lines []string
+		inUse, unused, expiring := 0, 0, 0
+		for _, c := range certs {
+			if c.InUse() {
+				inUse++
+			} else {
+				unused++
+			}
+			if c.DaysLeft(now) <= acmExpiringDays {
+				expiring++
+			}
+			lines = append(lines, formatACMCert(c, now))
+		}
+		header := fmt.Sprintf("🔐【ACM 证书】%s (%s)\n共 %d 张：使用中 %d，未使用 %d，%d 天内到期 %d",
+			alias, client.Region, len(certs), inUse, unused, acmExpiringDays, expiring)
+		if len(lines) == 0 {
+			h.sendText(header + "\n未找到证书。")
+			continue
+		}
+		_ = h.Sender.Send(ctx, header+"\n"+strings.Join(lines, "\n"))
+	}
+}
+
+func formatACMCert(c acmclient.Cert, now time.Time) string {
+	icon := "✅"
+	note := fmt.Sprintf("剩 %d 天", c.DaysLeft(now))
+	switch {
+	case !c.NotAfter.After(now):
+		icon = "⛔"
+		note = "已过期"
+	case c.DaysLeft(now) <= acmExpiringDays:
+		icon = "⚠️"
+	case !c.InUse():
+		icon = "💤"
+	}
+
+	usage := "未使用"
+	if c.InUse() {
+		usage = fmt.Sprintf("使用中（%d 个资源）", len(c.InUseBy))
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s [%s] 到期 %s（%s）\n   %s\n   %s",
+		icon, c.DomainName, strings.ToLower(c.Type), c.NotAfter.Format("2006-01-02"), note, c.ARN, usage))
+	if c.Managed() {
+		sb.WriteString("\n   CF 证书: " + c.Tags[acmclient.TagCertID])
+	}
+	return sb.String()
+}
@@ -0,0 +1,140 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/origincert"
+)
+
+const certsUsage = "用法: /certs [账号标签|zone|all]\n列出 Origin CA 源站证书，标出重复签发、已吊销与即将到期的证书。"
+
+// maxRegenButtons 单条消息最多附带的重新签发按钮数
+const maxRegenButtons = 10
+
+func (h *CommandHandler) handleCertsCommand(args []string) {
+	selector := "all"
+	if len(args) > 0 {
+		selector = strings.TrimSpace(args[0])
+	}
+
+	targets := h.Accounts
+	zone := ""
+	switch {
+	case strings.EqualFold(selector, "all"):
+	case h.getAccountByLabel(selector) != nil:
+		targets = []config.CF{*h.getAccountByLabel(selector)}
+	default:
+		name, err := extractDomainOrHost(selector)
+		if err != nil {
+			h.sendText(fmt.Sprintf("参数不合法：%v\n%s", err, certsUsage))
+			return
+		}
+		zone = name
+	}
+	if len(targets) == 0 {
+		h.sendText("未配置可用的 Cloudflare 账号。")
+		return
+	}
+	if zone == "" {
+		h.sendText("正在读取各 Zone 的源站证书，请稍候...")
+	}
+
+	ctx := context.Background()
+	certs, errs := origincert.Collect(ctx, h.CFClient, targets, zone)
+	now := time.Now()
+	thresholds := config.Cfg.OriginCerts.AlertDays
+	if len(thresholds) == 0 {
+		thresholds = origincert.DefaultAlertDays
+	}
+
+	expiring := map[string]origincert.Expiry{}
+	var regenZones []string
+	for _, e := range origincert.Expiring(certs, now, thresholds, config.Cfg.OriginCerts.ExpiredDays) {
+		expiring[e.ID] = e
+		regenZones = append(regenZones, e.Zone)
+	}
+	var lines []string
+	active, revoked := 0, 0
+	for _, c := range certs {
+		switch {
+		case c.Revoked():
+			revoked++
+		case c.Active(now):
+			active++
+		}
+		lines = append(lines, formatOriginCert(c, now, expiring))
+	}
+	for _, group := range origincert.Duplicates(certs, now) {
+		var ids []string
+		for _, c := range group {
+			ids = append(ids, origincert.ShortID(c.ID))
+		}
+		lines = append(lines, fmt.Sprintf("♻️ 重复签发 %s (%s): %d 张有效证书覆盖相同主机名 %s\n   %s",
+			group[0].Zone, group[0].Account, len(group), strings.Join(group[0].Hostnames, ", "), strings.Join(ids, ", ")))
+	}
+	for _, err := range errs {
+		lines = append(lines, "❌ "+err.Error())
+	}
+
+	maxDays := 0
+	for _, t := range thresholds {
+		maxDays = max(maxDays, t)
+	}
+	header := fmt.Sprintf("📜【源站证书】%s\n共 %d 张：有效 %d，已吊销 %d，%d 天内到期 %d",
+		selector, len(certs), active, revoked, maxDays, len(expiring))
+	if len(lines) == 0 {
+		h.sendText(header + "\n未找到源站证书。")
+		return
+	}
+	_ = h.Sender.Send(ctx, header+"\n"+strings.Join(lines, "\n"))
+
+	if buttons := CertRegenButtons(regenZones, formatOperator(h.operator)); len(buttons) > 0 {
+		msg := "以下 Zone 的源站证书即将到期，可按 /ssl 流程重新签发（裸域 + 通配符）："
+		if err := h.Sender.SendWithButtons(ctx, msg, buttons); err != nil {
+			h.sendText(fmt.Sprintf("发送按钮失败: %v", err))
+		}
+	}
+}
+
+func formatOriginCert(c origincert.Cert, now time.Time, expiring map[string]origincert.Expiry) string {
+	icon := "✅"
+	note := fmt.Sprintf("剩 %d 天", c.DaysLeft(now))
+	switch {
+	case c.Revoked():
+		icon = "🚫"
+		note = "已吊销 " + c.RevokedAt.Format("2006-01-02")
+	case !c.ExpiresOn.After(now):
+		icon = "⛔"
+		note = "已过期"
+	case expiring[c.ID].ID != "":
+		icon = "⚠️"
+	}
+	return fmt.Sprintf("%s %s (%s) [%s]\n   %s\n   到期 %s（%s）",
+		icon, c.Zone, c.Account, origincert.ShortID(c.ID), strings.Join(c.Hostnames, ", "), c.ExpiresOn.Format("2006-01-02"), note)
+}
+
+// CertRegenButtons 为需要续签的 Zone 生成「重新签发」按钮，每个 Zone 一个
+func CertRegenButtons(zones []string, operator string) [][]Button {
+	seen := map[string]bool{}
+	var rows [][]Button
+	for _, z := range zones {
+		if seen[z] || len(rows) >= maxRegenButtons {
+			continue
+		}
+		seen[z] = true
+		token := SetCertRegenPayload(CertRegenPayload{Operator: operator, Zone: z})
+		rows = append(rows, []Button{{Text: "🔄 重新签发 " + z, CallbackData: fmt.Sprintf("certs_regen|%s", token)}})
+	}
+	return rows
+}
+
+// RegenerateOriginCert 复用 /ssl 流程为 Zone 重新签发源站证书。由按钮回调调用。
+func RegenerateOriginCert(ctx context.Context, cf cfclient.Client, sender Sender, payload CertRegenPayload) {
+	h := &CommandHandler{CFClient: cf, Accounts: config.Cfg.CloudflareAccounts, Sender: sender}
+	h.handleOriginSSLCommand([]string{payload.Zone})
+}
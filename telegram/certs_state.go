@@ -0,0 +1,35 @@
+package telegram
+
+import "sync"
+
+// CertRegenPayload 保存一次「重新签发源站证书」按钮对应的 Zone
+type CertRegenPayload struct {
+	Operator string
+	Zone     string
+}
+
+var certRegenState = struct {
+	mu       sync.Mutex
+	payloads map[string]CertRegenPayload
+}{
+	payloads: make(map[string]CertRegenPayload),
+}
+
+func SetCertRegenPayload(payload CertRegenPayload) string {
+	token := newIPListToken()
+	certRegenState.mu.Lock()
+	defer certRegenState.mu.Unlock()
+	certRegenState.payloads[token] = payload
+	return token
+}
+
+// TakeCertRegenPayload 取出并删除待执行的签发请求，保证只执行一次
+func TakeCertRegenPayload(token string) (CertRegenPayload, bool) {
+	certRegenState.mu.Lock()
+	defer certRegenState.mu.Unlock()
+	payload, ok := certRegenState.payloads[token]
+	if ok {
+		delete(certRegenState.payloads, token)
+	}
+	return payload, ok
+}
@@ -6,19 +6,50 @@ import (
 	"fmt"
 	"strings"
 
+	"DomainC/cachepurge"
 	"DomainC/cfclient"
 )
 
+const clsUsage = `用法:
+/cls <URL ...>  按 URL 清理，自动归入各自的 Zone（可多行粘贴）
+/cls host <主机名 ...>  清理主机名下的全部缓存
+/cls prefix <主机名/路径 ...>  按路径前缀清理，如 www.example.com/static
+/cls tag <zone> <Cache-Tag ...>  按 Cache-Tag 清理（Enterprise）
+/cls <domain.com>  或  /cls all <domain.com>  清理整个 Zone`
+
 func (h *CommandHandler) handleCLSCommand(args []string) {
 	if len(args) < 1 {
-		h.sendText("用法: /cls <domain.com | sub.domain.com | URL>")
+		h.sendText(clsUsage)
 		return
 	}
 
-	raw := strings.TrimSpace(args[0])
-	q, err := extractDomainOrHost(raw)
+	switch strings.ToLower(args[0]) {
+	case "all", "everything":
+		if len(args) < 2 {
+			h.sendText(clsUsage)
+			return
+		}
+		h.purgeZoneEverything(args[1])
+	case "host", "hosts":
+		h.purgeCacheItems(cfclient.PurgeHosts, args[1:])
+	case "prefix", "prefixes":
+		h.purgeCacheItems(cfclient.PurgePrefixes, args[1:])
+	case "tag", "tags":
+		h.purgeCacheTags(args[1:])
+	default:
+		// 兼容旧用法：单个不带路径的域名清理整个 Zone
+		if len(args) == 1 && !strings.Contains(args[0], "/") {
+			h.purgeZoneEverything(args[0])
+			return
+		}
+		h.purgeCacheItems(cfclient.PurgeFiles, args)
+	}
+}
+
+func (h *CommandHandler) purgeZoneEverything(raw string) {
+	q, err := extractDomainOrHost(strings.TrimSpace(raw))
 	if err != nil {
-		h.sendText(fmt.Sprintf("参数不合法：%v\n用法: /cls <domain.com | sub.domain.com | URL>", err))
+		h.sendText(fmt.Sprintf("参数不合法：%v\n\n%s", err, clsUsage))
 		return
 	}
 
@@ -40,3 +71,86 @@ func (h *CommandHandler) handleCLSCommand(args []string) {
 	operator := formatOperator(h.operator)
 	h.sendText(fmt.Sprintf("✅ 已清理缓存：%s (账号: %s，操作人: %s)", zone.Name, account.Label, operator))
 }
+
+func (h *CommandHandler) purgeCacheItems(kind string, items []string) {
+	if len(items) == 0 {
+		h.sendText(clsUsage)
+		return
+	}
+	groups, errs := cachepurge.Plan(kind, items, h.purgeZoneResolver())
+	h.reportCachePurge(kind, groups, errs)
+}
+
+func (h *CommandHandler) purgeCacheTags(args []string) {
+	if len(args) < 2 {
+		h.sendText(clsUsage)
+		return
+	}
+	account, zone, err := h.findZone(args[0])
+	if err != nil {
+		if errors.Is(err, cfclient.ErrZoneNotFound) {
+			h.sendText(fmt.Sprintf("域名 %s 不属于任何 Cloudflare 账号。", args[0]))
+			return
+		}
+		h.sendText(fmt.Sprintf("查询域名失败: %v", err))
+		return
+	}
+	var tags []string
+	seen := map[string]bool{}
+	for _, t := range args[1:] {
+		for _, tag := range strings.Split(t, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" && !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	group := cachepurge.Group{
+		Zone:  cachepurge.Zone{Account: *account, Name: zone.Name, ID: zone.ID},
+		Kind:  cfclient.PurgeTags,
+		Items: tags,
+	}
+	h.reportCachePurge(cfclient.PurgeTags, []cachepurge.Group{group}, nil)
+}
+
+// purgeZoneResolver 按主机名查找 Zone，同一 Zone 的不同主机名只需各查询一次
+func (h *CommandHandler) purgeZoneResolver() cachepurge.Resolver {
+	return func(host string) (cachepurge.Zone, error) {
+		account, zone, err := h.findZone(host)
+		if err != nil {
+			if errors.Is(err, cfclient.ErrZoneNotFound) {
+				return cachepurge.Zone{}, fmt.Errorf("不属于任何 Cloudflare 账号")
+			}
+			return cachepurge.Zone{}, err
+		}
+		return cachepurge.Zone{Account: *account, Name: zone.Name, ID: zone.ID}, nil
+	}
+}
+
+func (h *CommandHandler) reportCachePurge(kind string, groups []cachepurge.Group, errs []error) {
+	ctx := context.Background()
+	results := cachepurge.Execute(ctx, h.CFClient, groups)
+
+	title := cachepurge.KindTitle[kind]
+	purged := 0
+	var lines []string
+	for _, r := range results {
+		purged += r.Purged
+		name := fmt.Sprintf("%s (%s)", r.Group.Zone.Name, r.Group.Zone.Account.Label)
+		items := "   " + strings.Join(r.Group.Items, "\n   ")
+		if r.Err != nil {
+			lines = append(lines, fmt.Sprintf("❌ %s：已提交 %d/%d 个 %s，%v\n%s", name, r.Purged, len(r.Group.Items), title, r.Err, items))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("✅ %s：%d 个 %s\n%s", name, r.Purged, title, items))
+	}
+	for _, err := range errs {
+		lines = append(lines, "⚠️ 未清理 "+err.Error())
+	}
+	if len(lines) == 0 {
+		h.sendText("没有可清理的条目。\n\n" + clsUsage)
+		return
+	}
+	header := fmt.Sprintf("🧹【清理缓存】按%s清理 %d 个，涉及 %d 个 Zone（操作人: %s）", title, purged, len(results), formatOperator(h.operator))
+	_ = h.Sender.Send(ctx, header+"\n"+strings.Join(lines, "\n"))
+}
@@ -5,7 +5,9 @@ import (
 
 	"DomainC/cfclient"
 	"DomainC/config"
+	"DomainC/dnssnapshot"
 	"DomainC/registrarclient"
+	"DomainC/zonewatch"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -16,7 +18,11 @@ type CommandHandler struct {
 	Accounts         []config.CF
 	Sender           Sender
 	ChatID           int64
-	operator         *tgbotapi.User
+	// Snapshots 为空时 /history 不可用
+	Snapshots *dnssnapshot.Store
+	// ZoneWatcher 为空时 /getns 新建的 Zone 不会被跟踪激活状态
+	ZoneWatcher *zonewatch.Watcher
+	operator    *tgbotapi.User
 }
 
 func NewCommandHandler(cf cfclient.Client, registrarManager *registrarclient.Manager, sender Sender, accounts []config.CF, chatID int64) *CommandHandler {
@@ -37,6 +43,11 @@ func (h *CommandHandler) HandleMessage(msg *tgbotapi.Message) {
 		return
 	}
 	if !msg.IsCommand() {
+		if msg.Document != nil {
+			h.operator = msg.From
+			go h.handleDNSImportDocument(msg.Document, msg.Caption)
+			return
+		}
 		if msg.From != nil && msg.Text != "" {
 			if h.handlePendingIPListAdd(msg.Text, msg.From.ID) {
 				return
@@ -59,6 +70,10 @@ func (h *CommandHandler) HandleMessage(msg *tgbotapi.Message) {
 		go h.handleSetDNSCommand(args)
 	case "csv":
 		go h.handleCSVCommand(args)
+	case "export":
+		go h.handleExportCommand(args)
+	case "tfexport":
+		go h.handleTFExportCommand(args)
 	case "ssl":
 		go h.handleOriginSSLCommand(args)
 	case "domainsource":
@@ -73,6 +88,48 @@ func (h *CommandHandler) HandleMessage(msg *tgbotapi.Message) {
 		go h.handleDelDNSCommand(args)
 	case "iplist":
 		go h.handleIPListCommand(args)
+	case "history":
+		go h.handleHistoryCommand(args)
+	case "plan":
+		go h.handlePlanCommand(args)
+	case "movezone":
+		go h.handleMoveZoneCommand(args)
+	case "dig":
+		go h.handleDigCommand(args)
+	case "mailcheck":
+		go h.handleMailCheckCommand(args)
+	case "mailsetup":
+		go h.handleMailSetupCommand(args)
+	case "lint":
+		go h.handleLintCommand(args)
+	case "takeover":
+		go h.handleTakeoverCommand(args)
+	case "nscheck":
+		go h.handleNSCheckCommand(args)
+	case "dnssec":
+		go h.handleDNSSECCommand(args)
+	case "certs":
+		go h.handleCertsCommand(args)
+	case "sslrevoke":
+		go h.handleSSLRevokeCommand(args)
+	case "sslrotate":
+		go h.handleSSLRotateCommand(args)
+	case "acm":
+		go h.handleACMCommand(args)
+	case "sslget":
+		go h.handleSSLGetCommand(args)
+	case "zoneset":
+		go h.handleZoneSetCommand(args)
+	case "zoneaudit":
+		go h.handleZoneAuditCommand(args)
+	case "redirect":
+		go h.handleRedirectCommand(args)
+	case "uam":
+		go h.handleUAMCommand(args)
+	case "devmode":
+		go h.handleDevModeCommand(args)
+	case "zonewatch":
+		go h.handleZoneWatchCommand(args)
 	}
 
 }
@@ -3,7 +3,6 @@ package telegram
 import (
 	"bytes"
 	"context"
-	"encoding/csv"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +10,8 @@ import (
 	"time"
 
 	"DomainC/config"
+	"DomainC/dnsexport"
+	"DomainC/dnsfilter"
 )
 
 func (h *CommandHandler) handleCSVCommand(args []string) {
@@ -42,10 +43,16 @@ func (h *CommandHandler) handleCSVCommand(args []string) {
 		}
 		targets = []config.CF{*acc}
 	}
+
+	filter, err := dnsfilter.ParseArgs(args[1:])
+	if err != nil {
+		h.sendText(fmt.Sprintf("%v\n\n%s", err, h.csvPromptText()))
+		return
+	}
 	h.sendText("要遍历所有账号的所有解析记录，且要控制查询速度，避免被 Cloudflare 限制，因此过程较慢，请耐心等待...")
 	// 3) 拉取数据并生成 CSV
 	ctx := context.Background()
-	csvBytes, filename, err := h.buildDNSExportCSV(ctx, targets)
+	csvBytes, filename, err := h.buildDNSExportCSV(ctx, targets, filter)
 	if err != nil {
 		h.sendText(fmt.Sprintf("导出失败: %v", err))
 		return
@@ -80,8 +87,11 @@ func (h *CommandHandler) handleCSVCommand(args []string) {
 		return
 	}
 
+	if !filter.Empty() {
+		h.sendText(fmt.Sprintf("✅ 导出完成：%s（过滤条件: %s）", filename, filter))
+		return
+	}
 	h.sendText(fmt.Sprintf("✅ 导出完成：%s", filename))
-
 }
 
 // 提示文本：可导出的账号 + 示例
@@ -98,7 +108,9 @@ func (h *CommandHandler) csvPromptText() string {
 		}
 		sb.WriteString("- " + a.Label + "\n")
 	}
-	sb.WriteString("- all\n\n请输入：\n/csv all\n或者：\n/csv 账号标签")
+	sb.WriteString("- all\n\n请输入：\n/csv all\n或者：\n/csv 账号标签\n\n可追加过滤条件（空格分隔，同时满足）：\n")
+	sb.WriteString(dnsfilter.Help)
+	sb.WriteString("\n例如：/csv 账号A type:A proxied:no")
 	return sb.String()
 }
 
@@ -112,91 +124,47 @@ func (h *CommandHandler) getAccountByLabel(label string) *config.CF {
 	return nil
 }
 
-func (h *CommandHandler) buildDNSExportCSV(ctx context.Context, accounts []config.CF) ([]byte, string, error) {
+func (h *CommandHandler) buildDNSExportCSV(ctx context.Context, accounts []config.CF, filter *dnsfilter.Filter) ([]byte, string, error) {
 	// 文件名：dns-export-YYYYMMDD-HHMMSS.csv
 	filename := fmt.Sprintf("dns-export-%s.csv", time.Now().Format("20060102-150405"))
 
+	zones, err := h.collectExportZones(ctx, accounts, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
 	buf := &bytes.Buffer{}
-	w := csv.NewWriter(buf)
-	w.UseCRLF = false
-
-	// Header
-	if err := w.Write([]string{
-		"所属账户",
-		"主域名",
-		"子域名",
-		"解析类型",
-		"解析地址",
-		"是否代理",
-		"Zone状态",
-		"是否暂停",
-	}); err != nil {
+	if err := (dnsexport.CSVExporter{}).Export(buf, zones); err != nil {
 		return nil, "", err
 	}
+	return buf.Bytes(), filename, nil
+}
 
+// collectExportZones 拉取账号下全部 Zone 及其解析记录；
+// filter 非空时只保留匹配的记录，没有匹配记录的 Zone 不输出
+func (h *CommandHandler) collectExportZones(ctx context.Context, accounts []config.CF, filter *dnsfilter.Filter) ([]dnsexport.Zone, error) {
+	var out []dnsexport.Zone
 	for _, acc := range accounts {
 		zones, err := h.CFClient.ListZones(ctx, acc)
 		if err != nil {
-			return nil, "", fmt.Errorf("列出账号 %s 的域名失败: %w", acc.Label, err)
+			return nil, fmt.Errorf("列出账号 %s 的域名失败: %w", acc.Label, err)
 		}
 
 		for _, z := range zones {
-			zonePaused := "否"
-			if z.Paused {
-				zonePaused = "是"
+			if !filter.MatchZone(z) {
+				continue
 			}
-
 			records, err := h.CFClient.ListDNSRecords(ctx, acc, z.Name)
 			if err != nil {
-				return nil, "", fmt.Errorf("获取 %s(%s) DNS 失败: %w", z.Name, acc.Label, err)
-			}
-
-			// 没有记录也写一行（保留 zone 维度信息）
-			if len(records) == 0 {
-				_ = w.Write([]string{
-					acc.Label,
-					z.Name,
-					"",
-					"",
-					"",
-					"",
-					z.Status,
-					zonePaused,
-				})
-				continue
+				return nil, fmt.Errorf("获取 %s(%s) DNS 失败: %w", z.Name, acc.Label, err)
 			}
-
-			for _, r := range records {
-				proxied := "否"
-				if r.Proxied != nil && *r.Proxied {
-					proxied = "是"
-				}
-
-				subDomain := r.Name
-				if subDomain == "@" || subDomain == z.Name {
-					subDomain = z.Name
-				}
-
-				if err := w.Write([]string{
-					acc.Label,  // 所属账户
-					z.Name,     // 主域名
-					subDomain,  // 子域名（完整 FQDN）
-					r.Type,     // 解析类型
-					r.Content,  // 解析地址
-					proxied,    // 是否代理
-					z.Status,   // Zone状态
-					zonePaused, // 是否暂停
-				}); err != nil {
-					return nil, "", err
+			if !filter.Empty() {
+				if records = filter.Apply(records, z); len(records) == 0 {
+					continue
 				}
 			}
+			out = append(out, dnsexport.Zone{AccountLabel: acc.Label, Detail: z, Records: records})
 		}
 	}
-
-	w.Flush()
-	if err := w.Error(); err != nil {
-		return nil, "", err
-	}
-
-	return buf.Bytes(), filename, nil
+	return out, nil
 }
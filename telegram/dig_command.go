@@ -0,0 +1,116 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"DomainC/config"
+	"DomainC/dnsresolver"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/miekg/dns"
+)
+
+const digUsage = "用法: /dig <name> [type] [@resolver]\n默认查询 A 记录并使用 dig.resolvers 中配置的全部解析器；@ 后可填 dig.resolvers 中的别名或 IP[:端口]（DoH 地址只能写在配置中）。\n示例: /dig www.example.com CNAME @1.1.1.1"
+
+func (h *CommandHandler) handleDigCommand(args []string) {
+	if len(args) < 1 {
+		h.sendText(digUsage)
+		return
+	}
+	name, err := extractDomainOrHost(args[0])
+	if err != nil {
+		h.sendText(fmt.Sprintf("参数不合法：%v\n%s", err, digUsage))
+		return
+	}
+
+	qtype := dns.TypeA
+	var selector string
+	for _, arg := range args[1:] {
+		if strings.HasPrefix(arg, "@") {
+			selector = strings.TrimPrefix(arg, "@")
+			continue
+		}
+		t, err := dnsresolver.ParseType(arg)
+		if err != nil {
+			h.sendText(fmt.Sprintf("%v\n%s", err, digUsage))
+			return
+		}
+		qtype = t
+	}
+
+	resolvers, err := digResolvers(config.Cfg.Dig.Resolvers, selector)
+	if err != nil {
+		h.sendText(err.Error())
+		return
+	}
+
+	ctx := context.Background()
+	stored, zoneInfo := h.digStoredRecords(ctx, name)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔎 dig %s %s\n%s\n", name, dns.TypeToString[qtype], zoneInfo))
+	for _, r := range resolvers {
+		sb.WriteString("\n")
+		ans, err := r.Query(ctx, name, qtype)
+		if err != nil {
+			sb.WriteString(fmt.Sprintf("[%s] ❌ %v\n", r.Name(), err))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("[%s] %s，%d 条，%v\n", r.Name(), ans.Rcode, len(ans.Records), ans.RTT.Round(time.Millisecond)))
+		for _, rr := range ans.Records {
+			sb.WriteString(fmt.Sprintf("  %s %d %s %s\n", rr.Name, rr.TTL, rr.Type, rr.Value))
+		}
+		if stored != nil {
+			sb.WriteString("  " + dnsresolver.Compare(ans, stored).Summary() + "\n")
+		}
+	}
+	h.sendText(sb.String())
+}
+
+// digStoredRecords 返回 Cloudflare 中保存的记录；不在任何账号时返回 nil
+func (h *CommandHandler) digStoredRecords(ctx context.Context, name string) ([]cloudflare.DNSRecord, string) {
+	acc, zone, err := h.findZone(name)
+	if err != nil {
+		return nil, "（不在任何 Cloudflare 账号中，仅显示解析结果）"
+	}
+	records, err := h.CFClient.ListDNSRecords(ctx, *acc, zone.Name)
+	if err != nil {
+		return nil, fmt.Sprintf("（获取 Cloudflare 记录失败 [%s]: %v）", zone.Name, err)
+	}
+	if records == nil {
+		records = []cloudflare.DNSRecord{}
+	}
+	return records, fmt.Sprintf("Zone: %s（账号 %s）", zone.Name, acc.Label)
+}
+
+// digResolvers 按 @selector 选择解析器：匹配配置中的别名，否则只接受 IP[:端口]（DoH 只能写在配置中）；
+// 未指定时使用全部配置
+func digResolvers(specs []string, selector string) ([]dnsresolver.Resolver, error) {
+	if len(specs) == 0 {
+		specs = []string{"system"}
+	}
+	var configured []dnsresolver.Resolver
+	for _, spec := range specs {
+		r, err := dnsresolver.Parse(spec)
+		if err != nil {
+			return nil, fmt.Errorf("dig.resolvers 配置错误: %v", err)
+		}
+		configured = append(configured, r)
+	}
+	if selector == "" {
+		return configured, nil
+	}
+	for _, r := range configured {
+		if strings.EqualFold(r.Name(), selector) {
+			return []dnsresolver.Resolver{r}, nil
+		}
+	}
+	r, err := dnsresolver.ParseServer(selector)
+	if err != nil {
+		return nil, fmt.Errorf("%v（@ 后只能是 dig.resolvers 中的别名或 IP[:端口]）", err)
+	}
+	return []dnsresolver.Resolver{r}, nil
+}
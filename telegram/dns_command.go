@@ -10,13 +10,16 @@ import (
 	"strings"
 
 	"DomainC/cfclient"
+	"DomainC/dnsfilter"
 
 	"github.com/cloudflare/cloudflare-go"
 )
 
+const dnsUsage = "用法: /dns <domain.com | sub.domain.com | URL> [过滤条件...]\n例如: /dns example.com type:A proxied:no"
+
 func (h *CommandHandler) handleDNSCommand(_ string, args []string) {
 	if len(args) < 1 {
-		h.sendText("用法: /dns <domain.com | sub.domain.com | URL>")
+		h.sendText(dnsUsage)
 		return
 	}
 
@@ -24,7 +27,12 @@ func (h *CommandHandler) handleDNSCommand(_ string, args []string) {
 	q, err := extractDomainOrHost(raw)
 	if err != nil {
 		log.Printf("[/dns] invalid input: raw=%q err=%v", raw, err)
-		h.sendText(fmt.Sprintf("参数不合法：%v\n用法: /dns <domain.com | sub.domain.com | URL>", err))
+		h.sendText(fmt.Sprintf("参数不合法：%v\n%s", err, dnsUsage))
+		return
+	}
+	filter, err := dnsfilter.ParseArgs(args[1:])
+	if err != nil {
+		h.sendText(fmt.Sprintf("%v\n%s", err, dnsUsage))
 		return
 	}
 
@@ -62,16 +70,24 @@ func (h *CommandHandler) handleDNSCommand(_ string, args []string) {
 	if !strings.EqualFold(q, zone.Name) {
 		filtered = filterRecordsByNameOrSubtree(records, q)
 	}
+	filtered = filter.Apply(filtered, zone)
 
 	log.Printf("[/dns] records: total=%d filtered=%d q=%q zone=%q", len(records), len(filtered), q, zone.Name)
 
 	if len(filtered) == 0 {
+		if !filter.Empty() {
+			h.sendText(fmt.Sprintf("在 Zone %s 中没有找到与 %s 相关且满足 %s 的 DNS 记录。", zone.Name, q, filter))
+			return
+		}
 		h.sendText(fmt.Sprintf("在 Zone %s 中没有找到与 %s 相关的 DNS 记录。", zone.Name, q))
 		return
 	}
 
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("域名 %s 的 DNS 记录（账号: %s，Zone: %s）：\n", q, account.Label, zone.Name))
+	if !filter.Empty() {
+		sb.WriteString(fmt.Sprintf("过滤条件: %s\n", filter))
+	}
 	for _, r := range filtered {
 		proxied := "否"
 		if r.Proxied != nil && *r.Proxied {
@@ -0,0 +1,150 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/dnsimport"
+	"DomainC/dnsplan"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// dnsImportExts 只有这些后缀的上传文件会被当作解析导入
+var dnsImportExts = map[string]bool{".csv": true, ".zone": true, ".bind": true, ".db": true, ".txt": true}
+
+// handleDNSImportDocument 处理上传的 CSV / BIND zone 文件，生成变更计划并请求确认。
+// BIND 文件可在上传说明(caption)中填写域名，否则使用文件内的 $ORIGIN/SOA 或文件名。
+func (h *CommandHandler) handleDNSImportDocument(doc *tgbotapi.Document, caption string) {
+	if doc == nil || !dnsImportExts[strings.ToLower(filepath.Ext(doc.FileName))] {
+		return
+	}
+	ctx := context.Background()
+
+	data, err := h.Sender.DownloadFile(ctx, doc.FileID)
+	if err != nil {
+		h.sendText(fmt.Sprintf("下载文件失败: %v", err))
+		return
+	}
+	format, err := dnsimport.DetectFormat(doc.FileName, data)
+	if err != nil {
+		if strings.EqualFold(filepath.Ext(doc.FileName), ".txt") {
+			// .txt 可能只是普通附件，无法识别时静默忽略
+			return
+		}
+		h.sendText(err.Error())
+		return
+	}
+
+	var zones []dnsimport.ZoneRecords
+	switch format {
+	case dnsimport.FormatCSV:
+		zones, err = dnsimport.ParseCSV(bytes.NewReader(data))
+	case dnsimport.FormatBIND:
+		origin := strings.TrimSpace(caption)
+		if origin == "" && !bytes.Contains(data, []byte("$ORIGIN")) && !bytes.Contains(data, []byte("SOA")) {
+			origin = dnsimport.ZoneFromFilename(doc.FileName)
+		}
+		var zr dnsimport.ZoneRecords
+		zr, err = dnsimport.ParseBIND(bytes.NewReader(data), origin)
+		zones = []dnsimport.ZoneRecords{zr}
+	}
+	if err != nil {
+		h.sendText(fmt.Sprintf("解析文件 %s 失败: %v", doc.FileName, err))
+		return
+	}
+	if len(zones) == 0 {
+		h.sendText(fmt.Sprintf("文件 %s 中没有可导入的记录", doc.FileName))
+		return
+	}
+
+	h.sendText(fmt.Sprintf("已收到 %s（%s），正在对比 %d 个 Zone 的线上解析...", doc.FileName, format, len(zones)))
+
+	var plans []dnsplan.ZonePlan
+	var failures []string
+	for _, zr := range zones {
+		plan, err := h.buildDNSImportPlan(ctx, zr)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", zr.Zone, err))
+			continue
+		}
+		plans = append(plans, plan)
+	}
+
+	h.sendDNSImportConfirm(doc.FileName, plans, failures)
+}
+
+// buildDNSImportPlan 定位 Zone 所在账号并与线上记录对比
+func (h *CommandHandler) buildDNSImportPlan(ctx context.Context, zr dnsimport.ZoneRecords) (dnsplan.ZonePlan, error) {
+	plan := dnsplan.ZonePlan{Zone: zr.Zone, Warnings: zr.Skipped}
+
+	var account *config.CF
+	if zr.AccountLabel != "" {
+		account = h.getAccountByLabel(zr.AccountLabel)
+		if account == nil {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("未找到账号 %s，改为自动查找", zr.AccountLabel))
+		}
+	}
+	if account == nil {
+		acc, zone, err := h.findZone(zr.Zone)
+		if err != nil {
+			return plan, fmt.Errorf("未在任何账号中找到该 Zone: %v", err)
+		}
+		if zone.Name != zr.Zone {
+			return plan, fmt.Errorf("该域名属于 Zone %s，请按 Zone 维度导入", zone.Name)
+		}
+		account = acc
+	}
+	plan.AccountLabel = account.Label
+
+	current, err := h.CFClient.ListDNSRecords(ctx, *account, zr.Zone)
+	if err != nil {
+		return plan, fmt.Errorf("获取线上解析失败: %v", err)
+	}
+	plan.Changes = dnsplan.Compute(zr.Zone, zr.Records, current)
+	return plan, nil
+}
+
+func (h *CommandHandler) sendDNSImportConfirm(filename string, plans []dnsplan.ZonePlan, failures []string) {
+	var sb strings.Builder
+	sb.WriteString("📥【批量导入解析预览】\n")
+	sb.WriteString(fmt.Sprintf("操作人: %s\n文件: %s\n", formatOperator(h.operator), filename))
+
+	total := writeZonePlans(&sb, plans)
+	if len(failures) > 0 {
+		sb.WriteString("\n以下 Zone 无法导入：\n")
+		for _, f := range failures {
+			sb.WriteString("- " + f + "\n")
+		}
+	}
+
+	if total == 0 {
+		sb.WriteString("\n线上解析已与文件一致，无需变更。")
+		h.sendText(sb.String())
+		return
+	}
+	sb.WriteString("\n确认执行以上变更吗？")
+
+	token := SetDNSImportPayload(DNSImportPayload{
+		Filename: filename,
+		Operator: formatOperator(h.operator),
+		Plans:    plans,
+	})
+	buttons := [][]Button{{
+		{Text: "✅ 执行导入", CallbackData: fmt.Sprintf("dnsimport_apply|%s", token)},
+		{Text: "❌ 取消", CallbackData: fmt.Sprintf("dnsimport_cancel|%s", token)},
+	}}
+	if err := h.Sender.SendWithButtons(context.Background(), sb.String(), buttons); err != nil {
+		h.sendText(fmt.Sprintf("发送确认消息失败: %v", err))
+	}
+}
+
+// ApplyDNSImport 执行导入计划并逐条回执结果。命令与按钮回调共用。
+func ApplyDNSImport(ctx context.Context, client cfclient.Client, sender Sender, payload DNSImportPayload) {
+	applyZonePlans(ctx, client, sender, "导入结果", payload.Operator, payload.Plans)
+}
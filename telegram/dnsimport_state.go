@@ -0,0 +1,40 @@
+package telegram
+
+import (
+	"sync"
+
+	"DomainC/dnsplan"
+)
+
+// DNSImportPayload 保存等待确认的批量导入计划
+type DNSImportPayload struct {
+	Filename string
+	Operator string
+	Plans    []dnsplan.ZonePlan
+}
+
+var dnsImportState = struct {
+	mu       sync.Mutex
+	payloads map[string]DNSImportPayload
+}{
+	payloads: make(map[string]DNSImportPayload),
+}
+
+func SetDNSImportPayload(payload DNSImportPayload) string {
+	token := newIPListToken()
+	dnsImportState.mu.Lock()
+	defer dnsImportState.mu.Unlock()
+	dnsImportState.payloads[token] = payload
+	return token
+}
+
+// TakeDNSImportPayload 取出并删除计划，保证同一计划只会被执行一次
+func TakeDNSImportPayload(token string) (DNSImportPayload, bool) {
+	dnsImportState.mu.Lock()
+	defer dnsImportState.mu.Unlock()
+	payload, ok := dnsImportState.payloads[token]
+	if ok {
+		delete(dnsImportState.payloads, token)
+	}
+	return payload, ok
+}
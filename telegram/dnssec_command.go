@@ -0,0 +1,211 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/dnssec"
+	"DomainC/registrarclient"
+)
+
+const dnssecUsage = "用法: /dnssec <zone> [status|on|off]\nstatus 对比 Cloudflare、注册商与注册局发布的 DS；on 开启签名并把 DS 提交到注册商；off 关闭签名（注册局仍有 DS 时会先提示）。"
+
+func (h *CommandHandler) handleDNSSECCommand(args []string) {
+	if len(args) < 1 {
+		h.sendText(dnssecUsage)
+		return
+	}
+	action := "status"
+	if len(args) > 1 {
+		action = strings.ToLower(strings.TrimSpace(args[1]))
+	}
+	if action != "status" && action != "on" && action != "off" {
+		h.sendText(dnssecUsage)
+		return
+	}
+
+	account, zone, err := h.findZone(args[0])
+	if err != nil {
+		if errors.Is(err, cfclient.ErrZoneNotFound) {
+			h.sendText(fmt.Sprintf("未在任何账号下找到 %s。", args[0]))
+			return
+		}
+		h.sendText(fmt.Sprintf("查询 Zone 失败: %v", err))
+		return
+	}
+
+	m, err := newDNSSECManager(h.CFClient, h.RegistrarManager)
+	if err != nil {
+		h.sendText(err.Error())
+		return
+	}
+	ctx := context.Background()
+
+	switch action {
+	case "status":
+		st, err := m.Status(ctx, *account, zone.Name)
+		if err != nil {
+			h.sendText(err.Error())
+			return
+		}
+		h.sendText(formatDNSSECStatus(st))
+
+	case "on":
+		res, err := m.Enable(ctx, *account, zone.Name)
+		if err != nil {
+			h.sendText(err.Error())
+			return
+		}
+		h.sendText(formatDNSSECEnable(zone.Name, account.Label, res, formatOperator(h.operator)))
+
+	case "off":
+		st, err := m.Status(ctx, *account, zone.Name)
+		if err != nil {
+			h.sendText(err.Error())
+			return
+		}
+		if st.RegistryErr == nil && !st.Published() && len(st.RegistrarDS) == 0 {
+			info, err := m.Disable(ctx, *account, zone.Name, false)
+			if err != nil {
+				h.sendText(err.Error())
+				return
+			}
+			h.sendText(fmt.Sprintf("✅ 已关闭 %s (%s) 的 DNSSEC，当前状态: %s（操作人: %s）", zone.Name, account.Label, info.Status, formatOperator(h.operator)))
+			return
+		}
+		h.sendDNSSECDisableConfirm(ctx, *account, st)
+	}
+}
+
+func (h *CommandHandler) sendDNSSECDisableConfirm(ctx context.Context, account config.CF, st dnssec.Status) {
+	token := SetDNSSECPayload(DNSSECPayload{Operator: formatOperator(h.operator), AccountLabel: account.Label, Zone: st.Zone})
+
+	var sb strings.Builder
+	sb.WriteString(formatDNSSECStatus(st))
+	sb.WriteString("\n\n⚠️ 注册局/注册商上仍有 DS 记录，此时关闭 Cloudflare 签名会让开启 DNSSEC 验证的解析器对该域名返回 SERVFAIL。")
+	sb.WriteString("\n建议先移除注册商 DS，等待注册局 DS 过期（通常为 DS 的 TTL，最长约 2 天）后再执行 /dnssec " + st.Zone + " off。")
+	buttons := [][]Button{
+		{{Text: "🗑 移除注册商 DS", CallbackData: fmt.Sprintf("dnssec_removeds|%s", token)}},
+		{
+			{Text: "⚠️ 仍然关闭签名", CallbackData: fmt.Sprintf("dnssec_forceoff|%s", token)},
+			{Text: "❌ 取消", CallbackData: fmt.Sprintf("dnssec_cancel|%s", token)},
+		},
+	}
+	if err := h.Sender.SendWithButtons(ctx, sb.String(), buttons); err != nil {
+		h.sendText(fmt.Sprintf("发送确认消息失败: %v", err))
+	}
+}
+
+// RemoveRegistrarDS 移除注册商上登记的 DS 并回执结果。由按钮回调调用。
+func RemoveRegistrarDS(ctx context.Context, cf cfclient.Client, sender Sender, payload DNSSECPayload) {
+	m, err := newDNSSECManager(cf, registrarclient.NewManager(nil, config.Cfg.Registrars))
+	if err != nil {
+		_ = sender.Send(ctx, err.Error())
+		return
+	}
+	registrar, err := m.RemoveRegistrarDS(ctx, payload.Zone)
+	switch {
+	case errors.Is(err, registrarclient.ErrDNSSECUnsupported):
+		_ = sender.Send(ctx, fmt.Sprintf("注册商 %s (%s) 的 API 不支持 DNSSEC，请到控制台手动删除 %s 的 DS 记录。", registrar.Label, registrar.Type, payload.Zone))
+	case err != nil:
+		_ = sender.Send(ctx, fmt.Sprintf("❌ 移除 %s 的注册商 DS 失败: %v", payload.Zone, err))
+	default:
+		_ = sender.Send(ctx, fmt.Sprintf("✅ 已移除 %s 在 %s (%s) 的 DS（操作人: %s）。\n注册局 DS 过期后再执行 /dnssec %s off 关闭签名。", payload.Zone, registrar.Label, registrar.Type, payload.Operator, payload.Zone))
+	}
+}
+
+// ForceDisableDNSSEC 忽略注册局 DS 直接关闭签名。由按钮回调调用。
+func ForceDisableDNSSEC(ctx context.Context, cf cfclient.Client, sender Sender, account config.CF, payload DNSSECPayload) {
+	m := &dnssec.Manager{CF: cf}
+	info, err := m.Disable(ctx, account, payload.Zone, true)
+	if err != nil {
+		_ = sender.Send(ctx, fmt.Sprintf("❌ 关闭 %s 的 DNSSEC 失败: %v", payload.Zone, err))
+		return
+	}
+	_ = sender.Send(ctx, fmt.Sprintf("已关闭 %s (%s) 的 DNSSEC，当前状态: %s（操作人: %s）。\n请尽快移除注册商上的 DS 记录。", payload.Zone, account.Label, info.Status, payload.Operator))
+}
+
+func newDNSSECManager(cf cfclient.Client, registrars *registrarclient.Manager) (*dnssec.Manager, error) {
+	lookup, err := dnssec.NewLookup(config.Cfg.DNSSEC.Resolver)
+	if err != nil {
+		return nil, fmt.Errorf("dnssec.resolver 配置无效: %v", err)
+	}
+	m := &dnssec.Manager{CF: cf, Lookup: lookup}
+	if registrars != nil && len(registrars.Registrars()) > 0 {
+		m.Registrar = registrars
+	}
+	return m, nil
+}
+
+func formatDNSSECStatus(st dnssec.Status) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔐【DNSSEC】%s (%s)\nCloudflare 状态: %s\n", st.Zone, st.Account, st.Cloudflare.Status))
+	if want, ok := st.Expected(); ok {
+		sb.WriteString("Cloudflare DS: " + want.String() + "\n")
+	}
+
+	switch {
+	case st.RegistryErr != nil:
+		sb.WriteString(fmt.Sprintf("注册局 DS: 查询失败 (%v)\n", st.RegistryErr))
+	case len(st.Registry) == 0:
+		sb.WriteString("注册局 DS: 无\n")
+	default:
+		sb.WriteString("注册局 DS:\n")
+		for _, ds := range st.Registry {
+			sb.WriteString("  " + ds.String() + "\n")
+		}
+	}
+
+	switch {
+	case st.Registrar.Label == "" && st.RegistrarErr == nil:
+		sb.WriteString("注册商 DS: 未配置注册商\n")
+	case errors.Is(st.RegistrarErr, registrarclient.ErrDNSSECUnsupported):
+		sb.WriteString(fmt.Sprintf("注册商 DS [%s]: API 不支持，需在控制台查看\n", st.Registrar.Label))
+	case st.RegistrarErr != nil:
+		sb.WriteString(fmt.Sprintf("注册商 DS: 查询失败 (%v)\n", st.RegistrarErr))
+	case len(st.RegistrarDS) == 0:
+		sb.WriteString(fmt.Sprintf("注册商 DS [%s]: 无\n", st.Registrar.Label))
+	default:
+		sb.WriteString(fmt.Sprintf("注册商 DS [%s]:\n", st.Registrar.Label))
+		for _, ds := range st.RegistrarDS {
+			sb.WriteString("  " + ds.String() + "\n")
+		}
+	}
+
+	if st.OK() {
+		if st.Cloudflare.Enabled() {
+			sb.WriteString("✅ 注册局 DS 与 Cloudflare 一致，DNSSEC 已生效")
+		} else {
+			sb.WriteString("✅ 未开启 DNSSEC，注册局无 DS")
+		}
+		return sb.String()
+	}
+	for _, p := range st.Problems() {
+		sb.WriteString("❗ " + p + "\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func formatDNSSECEnable(zone, account string, res dnssec.EnableResult, operator string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔐 已开启 %s (%s) 的 DNSSEC，Cloudflare 状态: %s（操作人: %s）\n", zone, account, res.Cloudflare.Status, operator))
+	info := res.Cloudflare
+	if info.HasDS() {
+		sb.WriteString(fmt.Sprintf("DS: %d %d %d %s\n", info.KeyTag, info.Algorithm, info.DigestType, info.Digest))
+	}
+	switch {
+	case res.Submitted:
+		sb.WriteString(fmt.Sprintf("✅ DS 已提交到注册商 %s (%s)\n", res.Registrar.Label, res.Registrar.Type))
+	case errors.Is(res.RegistrarErr, registrarclient.ErrDNSSECUnsupported):
+		sb.WriteString(fmt.Sprintf("注册商 %s (%s) 的 API 不支持 DNSSEC，请在控制台手动添加 DS：\n", res.Registrar.Label, res.Registrar.Type))
+		sb.WriteString(fmt.Sprintf("  Key Tag: %d\n  Algorithm: %d\n  Digest Type: %d\n  Digest: %s\n", info.KeyTag, info.Algorithm, info.DigestType, info.Digest))
+	case res.RegistrarErr != nil:
+		sb.WriteString(fmt.Sprintf("❌ 提交 DS 到注册商失败: %v\n", res.RegistrarErr))
+	}
+	sb.WriteString(fmt.Sprintf("注册局发布 DS 通常需要数分钟到数小时，之后可执行 /dnssec %s status 核对。", zone))
+	return sb.String()
+}
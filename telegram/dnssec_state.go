@@ -0,0 +1,36 @@
+package telegram
+
+import "sync"
+
+// DNSSECPayload 保存 /dnssec off 在注册局仍有 DS 时等待确认的操作
+type DNSSECPayload struct {
+	Operator     string
+	AccountLabel string
+	Zone         string
+}
+
+var dnssecState = struct {
+	mu       sync.Mutex
+	payloads map[string]DNSSECPayload
+}{
+	payloads: make(map[string]DNSSECPayload),
+}
+
+func SetDNSSECPayload(payload DNSSECPayload) string {
+	token := newIPListToken()
+	dnssecState.mu.Lock()
+	defer dnssecState.mu.Unlock()
+	dnssecState.payloads[token] = payload
+	return token
+}
+
+// TakeDNSSECPayload 取出并删除待确认操作，保证只执行一次
+func TakeDNSSECPayload(token string) (DNSSECPayload, bool) {
+	dnssecState.mu.Lock()
+	defer dnssecState.mu.Unlock()
+	payload, ok := dnssecState.payloads[token]
+	if ok {
+		delete(dnssecState.payloads, token)
+	}
+	return payload, ok
+}
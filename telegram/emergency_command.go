@@ -0,0 +1,257 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/emergency"
+)
+
+const emergencyUsage = "用法: /%s on|off <zone|账号标签|标签|all>[,...] [revert=30m]\n/%s status 查看当前开启记录\non 会记录原设置，off 恢复原设置；revert= 到期自动关闭。多个 Zone 时需要按钮确认。"
+
+var emergencyManager *emergency.Manager
+
+// SetEmergencyManager 设置执行 /uam、/devmode 的应急模式管理器
+func SetEmergencyManager(m *emergency.Manager) {
+	emergencyManager = m
+}
+
+// EmergencyManager 返回当前的应急模式管理器，未设置时为 nil
+func EmergencyManager() *emergency.Manager {
+	return emergencyManager
+}
+
+func (h *CommandHandler) handleUAMCommand(args []string) {
+	h.handleEmergencyCommand(emergency.UnderAttack, args)
+}
+
+func (h *CommandHandler) handleDevModeCommand(args []string) {
+	h.handleEmergencyCommand(emergency.DevMode, args)
+}
+
+func (h *CommandHandler) handleEmergencyCommand(mode emergency.Mode, args []string) {
+	usage := fmt.Sprintf(emergencyUsage, mode.Name, mode.Name)
+	m := EmergencyManager()
+	if m == nil {
+		h.sendText("应急模式切换未启用。")
+		return
+	}
+	if len(args) < 1 {
+		h.sendText(usage)
+		return
+	}
+	action := strings.ToLower(args[0])
+	if action == "status" || action == "list" {
+		h.sendEmergencyStatus(m, mode)
+		return
+	}
+	if (action != "on" && action != "off") || len(args) < 2 {
+		h.sendText(usage)
+		return
+	}
+
+	var revertAfter time.Duration
+	for _, a := range args[2:] {
+		v, ok := strings.CutPrefix(strings.ToLower(a), "revert=")
+		if !ok {
+			h.sendText(fmt.Sprintf("无法识别的参数: %s\n\n%s", a, usage))
+			return
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			h.sendText(fmt.Sprintf("revert 时长不合法: %s（例如 30m、2h）", v))
+			return
+		}
+		revertAfter = d
+	}
+	if action == "off" && revertAfter > 0 {
+		h.sendText("revert= 只能与 on 一起使用。")
+		return
+	}
+
+	ctx := context.Background()
+	selector := args[1]
+	targets, errs := h.resolveEmergencyTargets(ctx, selector)
+	for _, err := range errs {
+		h.sendText("❌ " + err.Error())
+	}
+	if len(targets) == 0 {
+		if len(errs) == 0 {
+			h.sendText(fmt.Sprintf("%s 下没有 Zone。", selector))
+		}
+		return
+	}
+
+	payload := EmergencyPayload{
+		Mode:        mode.Name,
+		On:          action == "on",
+		Selector:    selector,
+		Targets:     targets,
+		RevertAfter: revertAfter,
+		Operator:    formatOperator(h.operator),
+	}
+	if len(targets) == 1 {
+		RunEmergency(ctx, m, h.Sender, payload)
+		return
+	}
+
+	var names []string
+	for _, t := range targets {
+		names = append(names, fmt.Sprintf("%s (%s)", t.Zone, t.Account.Label))
+	}
+	header := fmt.Sprintf("🚨 将%s %d 个 Zone 的%s（%s=%s）：", emergencyVerb(payload.On), len(targets), mode.Title, mode.Setting, emergencyValue(mode, payload.On))
+	_ = h.Sender.Send(ctx, header+"\n"+strings.Join(names, "\n"))
+
+	token := SetEmergencyPayload(payload)
+	buttons := [][]Button{{
+		{Text: fmt.Sprintf("✅ 确认%s（%d 个 Zone）", emergencyVerb(payload.On), len(targets)), CallbackData: fmt.Sprintf("emergency_confirm|%s", token)},
+		{Text: "❌ 取消", CallbackData: fmt.Sprintf("emergency_cancel|%s", token)},
+	}}
+	if err := h.Sender.SendWithButtons(ctx, fmt.Sprintf("是否%s以上 Zone 的%s？", emergencyVerb(payload.On), mode.Title), buttons); err != nil {
+		h.sendText(fmt.Sprintf("发送确认消息失败: %v", err))
+	}
+}
+
+// resolveEmergencyTargets 解析逗号分隔的选择器：all、账号标签、emergency.tags 中的标签或单个 zone
+func (h *CommandHandler) resolveEmergencyTargets(ctx context.Context, selector string) ([]emergency.Target, []error) {
+	var targets []emergency.Target
+	var errs []error
+	seen := map[string]bool{}
+	add := func(acc config.CF, zone cfclient.ZoneDetail) {
+		if !seen[zone.ID] {
+			seen[zone.ID] = true
+			targets = append(targets, emergency.Target{Account: acc, Zone: zone.Name, ZoneID: zone.ID})
+		}
+	}
+	addAccount := func(acc config.CF) {
+		zones, err := h.CFClient.ListZones(ctx, acc)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("列出账号 %s 的域名失败: %v", acc.Label, err))
+			return
+		}
+		for _, z := range zones {
+			add(acc, z)
+		}
+	}
+	addZone := func(name string) {
+		acc, zone, err := h.findZone(name)
+		if err != nil {
+			if errors.Is(err, cfclient.ErrZoneNotFound) {
+				err = fmt.Errorf("未在任何账号下找到 %s", name)
+			}
+			errs = append(errs, err)
+			return
+		}
+		add(*acc, zone)
+	}
+
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+		case strings.EqualFold(part, "all"):
+			for _, acc := range h.Accounts {
+				addAccount(acc)
+			}
+		case h.getAccountByLabel(part) != nil:
+			addAccount(*h.getAccountByLabel(part))
+		case emergencyTag(part) != nil:
+			for _, z := range emergencyTag(part) {
+				addZone(z)
+			}
+		default:
+			addZone(part)
+		}
+	}
+	return targets, errs
+}
+
+func emergencyTag(name string) []string {
+	for tag, zones := range config.Cfg.Emergency.Tags {
+		if strings.EqualFold(tag, name) {
+			return zones
+		}
+	}
+	return nil
+}
+
+// RunEmergency 执行应急模式切换并回执。单个 Zone 时由命令直接调用，多个 Zone 时由按钮回调调用。
+func RunEmergency(ctx context.Context, m *emergency.Manager, sender Sender, payload EmergencyPayload) {
+	mode, ok := emergency.LookupMode(payload.Mode)
+	if !ok {
+		_ = sender.Send(ctx, fmt.Sprintf("未知的应急模式: %s", payload.Mode))
+		return
+	}
+	var outcomes []emergency.Outcome
+	if payload.On {
+		outcomes = m.On(ctx, mode, payload.Targets, payload.RevertAfter, payload.Operator)
+	} else {
+		outcomes = m.Off(ctx, mode, payload.Targets)
+	}
+
+	changed, unchanged := 0, 0
+	var lines, failed []string
+	for _, o := range outcomes {
+		name := fmt.Sprintf("%s (%s)", o.Target.Zone, o.Target.Account.Label)
+		switch {
+		case o.Err != nil:
+			failed = append(failed, fmt.Sprintf("❌ %s: %v", name, o.Err))
+		case o.Unchanged:
+			unchanged++
+		default:
+			changed++
+			lines = append(lines, fmt.Sprintf("✅ %s: %s → %s", name, o.From, o.To))
+		}
+	}
+	header := fmt.Sprintf("🚨【%s】%s %s（操作人: %s）\n修改 %d 个，无需修改 %d 个，失败 %d 个",
+		mode.Title, emergencyVerb(payload.On), payload.Selector, payload.Operator, changed, unchanged, len(failed))
+	if payload.On && payload.RevertAfter > 0 {
+		header += fmt.Sprintf("\n⏱ 将于 %s 自动关闭", time.Now().Add(payload.RevertAfter).Format("01-02 15:04"))
+	}
+	lines = append(lines, failed...)
+	if len(lines) == 0 {
+		_ = sender.Send(ctx, header)
+		return
+	}
+	_ = sender.Send(ctx, header+"\n"+strings.Join(lines, "\n"))
+}
+
+func (h *CommandHandler) sendEmergencyStatus(m *emergency.Manager, mode emergency.Mode) {
+	records, err := m.Active(mode)
+	if err != nil {
+		h.sendText(fmt.Sprintf("❌ %v", err))
+		return
+	}
+	if len(records) == 0 {
+		h.sendText(fmt.Sprintf("当前没有通过 /%s 开启%s的 Zone。", mode.Name, mode.Title))
+		return
+	}
+	var lines []string
+	for _, r := range records {
+		line := fmt.Sprintf("🚨 %s (%s)：原值 %s，%s 由 %s 开启", r.Zone, r.Account, r.Previous, r.At.Format("01-02 15:04"), r.Operator)
+		if r.RevertAt != nil {
+			line += fmt.Sprintf("，%s 自动关闭", r.RevertAt.Format("01-02 15:04"))
+		}
+		lines = append(lines, line)
+	}
+	h.sendText(fmt.Sprintf("🚨【%s】已开启 %d 个 Zone：\n", mode.Title, len(records)) + strings.Join(lines, "\n"))
+}
+
+func emergencyVerb(on bool) string {
+	if on {
+		return "开启"
+	}
+	return "关闭"
+}
+
+func emergencyValue(mode emergency.Mode, on bool) string {
+	if on {
+		return mode.OnValue
+	}
+	return "原值"
+}
@@ -0,0 +1,44 @@
+package telegram
+
+import (
+	"sync"
+	"time"
+
+	"DomainC/emergency"
+)
+
+// EmergencyPayload 保存等待确认的应急模式切换
+type EmergencyPayload struct {
+	Mode        string
+	On          bool
+	Selector    string
+	Targets     []emergency.Target
+	RevertAfter time.Duration
+	Operator    string
+}
+
+var emergencyState = struct {
+	mu       sync.Mutex
+	payloads map[string]EmergencyPayload
+}{
+	payloads: make(map[string]EmergencyPayload),
+}
+
+func SetEmergencyPayload(payload EmergencyPayload) string {
+	token := newIPListToken()
+	emergencyState.mu.Lock()
+	defer emergencyState.mu.Unlock()
+	emergencyState.payloads[token] = payload
+	return token
+}
+
+// TakeEmergencyPayload 取出并删除待确认的切换，保证只执行一次
+func TakeEmergencyPayload(token string) (EmergencyPayload, bool) {
+	emergencyState.mu.Lock()
+	defer emergencyState.mu.Unlock()
+	payload, ok := emergencyState.payloads[token]
+	if ok {
+		delete(emergencyState.payloads, token)
+	}
+	return payload, ok
+}
@@ -0,0 +1,101 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"DomainC/config"
+	"DomainC/dnsexport"
+)
+
+const exportUsage = "用法: /export <zone|账号标签|all> <格式> [zip]\n" +
+	"格式: %s\n" +
+	"示例:\n/export example.com bind\n/export all json\n/export 账号A yaml zip\n" +
+	"多个 Zone 导出为 BIND 时会自动打包为 zip（每个 Zone 一个文件）。"
+
+func (h *CommandHandler) handleExportCommand(args []string) {
+	formats := strings.Join(dnsexport.Formats(), " | ")
+	if len(args) < 2 {
+		h.sendText(fmt.Sprintf(exportUsage, formats))
+		return
+	}
+	selector := strings.TrimSpace(args[0])
+	exporter, ok := dnsexport.Lookup(args[1])
+	if !ok {
+		h.sendText(fmt.Sprintf("不支持的导出格式 %s。\n\n%s", args[1], fmt.Sprintf(exportUsage, formats)))
+		return
+	}
+	asZip := len(args) >= 3 && strings.EqualFold(args[2], "zip")
+
+	ctx := context.Background()
+	zones, err := h.resolveExportZones(ctx, selector)
+	if err != nil {
+		h.sendText(fmt.Sprintf("导出失败: %v", err))
+		return
+	}
+	if len(zones) == 0 {
+		h.sendText(fmt.Sprintf("%s 下没有可导出的 Zone", selector))
+		return
+	}
+	if len(zones) > 1 && !exporter.MultiZone() {
+		asZip = true
+	}
+
+	buf := &bytes.Buffer{}
+	base := fmt.Sprintf("dns-export-%s-%s", sanitizeFilename(selector), time.Now().Format("20060102-150405"))
+	var filename string
+	if asZip {
+		filename = base + "-" + exporter.Format() + ".zip"
+		err = dnsexport.WriteZip(buf, exporter, zones)
+	} else {
+		filename = base + "." + exporter.Ext()
+		err = exporter.Export(buf, zones)
+	}
+	if err != nil {
+		h.sendText(fmt.Sprintf("导出失败: %v", err))
+		return
+	}
+
+	path := filepath.Join(os.TempDir(), filename)
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		h.sendText(fmt.Sprintf("写入临时文件失败: %v", err))
+		return
+	}
+	defer os.Remove(path)
+
+	caption := fmt.Sprintf("📦 Cloudflare DNS 导出（%s，%d 个 Zone）", exporter.Format(), len(zones))
+	if err := h.Sender.SendDocumentPath(ctx, path, caption); err != nil {
+		h.sendText(fmt.Sprintf("发送导出文件失败: %v", err))
+		return
+	}
+	h.sendText(fmt.Sprintf("✅ 导出完成：%s", filename))
+}
+
+// resolveExportZones 按 all / 账号标签 / Zone 名解析导出范围
+func (h *CommandHandler) resolveExportZones(ctx context.Context, selector string) ([]dnsexport.Zone, error) {
+	if strings.EqualFold(selector, "all") {
+		if len(h.Accounts) == 0 {
+			return nil, fmt.Errorf("未配置可用的 Cloudflare 账号")
+		}
+		h.sendText("要遍历所有账号的所有解析记录，且要控制查询速度，避免被 Cloudflare 限制，因此过程较慢，请耐心等待...")
+		return h.collectExportZones(ctx, h.Accounts, nil)
+	}
+	if acc := h.getAccountByLabel(selector); acc != nil {
+		return h.collectExportZones(ctx, []config.CF{*acc}, nil)
+	}
+
+	acc, zone, err := h.findZone(selector)
+	if err != nil {
+		return nil, fmt.Errorf("%s 既不是账号标签，也未在任何账号中找到对应 Zone: %v", selector, err)
+	}
+	records, err := h.CFClient.ListDNSRecords(ctx, *acc, zone.Name)
+	if err != nil {
+		return nil, fmt.Errorf("获取 %s(%s) DNS 失败: %w", zone.Name, acc.Label, err)
+	}
+	return []dnsexport.Zone{{AccountLabel: acc.Label, Detail: zone, Records: records}}, nil
+}
@@ -38,6 +38,9 @@ func (h *CommandHandler) handleGetNSCommand(args []string) {
 				strings.Join(zone.NameServers, "\n"),
 			))
 			h.setRegistrarNameServers(domain, zone.NameServers)
+			if zone.Status != "active" {
+				h.trackZoneActivation(zone.Name, acc.Label, zone.NameServers)
+			}
 			continue
 		}
 
@@ -60,9 +63,22 @@ func (h *CommandHandler) handleGetNSCommand(args []string) {
 		))
 
 		h.setRegistrarNameServers(domain, zone.NameServers)
+		h.trackZoneActivation(zone.Name, selected.Label, zone.NameServers)
 	}
 }
 
+// trackZoneActivation 把未激活的 Zone 交给激活跟踪器
+func (h *CommandHandler) trackZoneActivation(domain, account string, nameServers []string) {
+	if h.ZoneWatcher == nil {
+		return
+	}
+	if err := h.ZoneWatcher.Track(domain, account, nameServers); err != nil {
+		h.sendText(fmt.Sprintf("加入激活跟踪失败 [%s]: %v", domain, err))
+		return
+	}
+	h.sendText(fmt.Sprintf("已开始跟踪 %s 的激活状态，激活后会在群里通知。", domain))
+}
+
 func (h *CommandHandler) parseGetNSDomainsAndAccount(args []string) ([]string, *config.CF, error) {
 	if len(args) == 0 {
 		return nil, nil, fmt.Errorf("用法: /getns <domain1.com> [domain2.com] ... <accountLabel>")
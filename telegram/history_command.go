@@ -0,0 +1,56 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	historyUsage      = "用法: /history <zone> [n]\n显示该 Zone 最近 n 次解析变更（默认 5，最多 20）"
+	historyDefaultN   = 5
+	historyMaxN       = 20
+	historyEntryLimit = 15
+)
+
+func (h *CommandHandler) handleHistoryCommand(args []string) {
+	if len(args) < 1 {
+		h.sendText(historyUsage)
+		return
+	}
+	if h.Snapshots == nil {
+		h.sendText("未启用 DNS 快照，无法查询变更历史。")
+		return
+	}
+
+	zone, err := extractDomainOrHost(args[0])
+	if err != nil {
+		h.sendText(fmt.Sprintf("参数不合法：%v\n%s", err, historyUsage))
+		return
+	}
+	n := historyDefaultN
+	if len(args) >= 2 {
+		v, err := strconv.Atoi(args[1])
+		if err != nil || v <= 0 {
+			h.sendText(historyUsage)
+			return
+		}
+		n = min(v, historyMaxN)
+	}
+
+	history, err := h.Snapshots.History(zone, n)
+	if err != nil {
+		h.sendText(fmt.Sprintf("读取 %s 的快照失败: %v", zone, err))
+		return
+	}
+	if len(history) == 0 {
+		h.sendText(fmt.Sprintf("%s 暂无解析变更记录（需要至少两次快照）。", zone))
+		return
+	}
+
+	entries := make([]string, 0, len(history))
+	for i, cs := range history {
+		entries = append(entries, fmt.Sprintf("\n#%d\n%s", i+1, cs.Format(historyEntryLimit)))
+	}
+	h.sendText(fmt.Sprintf("%s 最近 %d 次解析变更：\n", zone, len(history)) + strings.Join(entries, "\n"))
+}
@@ -0,0 +1,129 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/dnslint"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+const lintUsage = "用法: /lint <zone|账号标签|all>\n按内置规则检查解析配置（代理指向内网、根域 CNAME、代理记录 TTL、跨账号重复、MX 指向 CNAME、缺少 www 等）。\n/lint rules 查看规则及开关状态。"
+
+func (h *CommandHandler) handleLintCommand(args []string) {
+	if len(args) < 1 {
+		h.sendText(lintUsage)
+		return
+	}
+	linter, err := dnslint.New(config.Cfg.Lint.Rules)
+	if err != nil {
+		h.sendText(fmt.Sprintf("lint.rules 配置错误: %v", err))
+		return
+	}
+	selector := strings.TrimSpace(args[0])
+	if strings.EqualFold(selector, "rules") {
+		h.sendText(describeLintRules(linter))
+		return
+	}
+
+	accounts := h.Accounts
+	zone := ""
+	switch {
+	case strings.EqualFold(selector, "all"):
+	case h.getAccountByLabel(selector) != nil:
+		accounts = []config.CF{*h.getAccountByLabel(selector)}
+	default:
+		zone, err = extractDomainOrHost(selector)
+		if err != nil {
+			h.sendText(fmt.Sprintf("参数不合法：%v\n%s", err, lintUsage))
+			return
+		}
+	}
+	if zone == "" {
+		h.sendText("正在读取解析记录并检查，请耐心等待...")
+	}
+
+	ctx := context.Background()
+	zones, errs := dnslint.Collect(ctx, h.CFClient, accounts, zone)
+	if zone != "" && len(zones) == 0 && len(errs) == 0 {
+		h.sendText(fmt.Sprintf("未在任何账号中找到 %s。", zone))
+		return
+	}
+	issues := linter.Lint(zones)
+
+	if len(issues) == 0 {
+		h.sendText(fmt.Sprintf("✅ %s：检查了 %d 个 Zone，未发现问题。", selector, len(zones)))
+	} else {
+		lines := make([]string, 0, len(issues))
+		for _, is := range issues {
+			lines = append(lines, fmt.Sprintf("%s\n   账号 %s / Zone %s", is.String(), is.Account, is.Zone))
+		}
+		_ = h.Sender.Send(ctx, fmt.Sprintf("🧹【解析检查】%s：%d 个 Zone，%d 个问题\n", selector, len(zones), len(issues))+strings.Join(lines, "\n"))
+	}
+	if len(errs) > 0 {
+		msgs := make([]string, 0, len(errs))
+		for _, err := range errs {
+			msgs = append(msgs, err.Error())
+		}
+		_ = h.Sender.Send(ctx, "以下 Zone 无法检查：\n"+strings.Join(msgs, "\n"))
+	}
+}
+
+func describeLintRules(linter *dnslint.Linter) string {
+	enabled := make(map[string]bool)
+	for _, r := range linter.Rules {
+		enabled[r.ID] = true
+	}
+	var sb strings.Builder
+	sb.WriteString("解析检查规则（在 lint.rules 中按 ID 开关）：\n")
+	for _, r := range dnslint.BuiltinRules() {
+		state := "关"
+		if enabled[r.ID] {
+			state = "开"
+		}
+		sb.WriteString(fmt.Sprintf("[%s] %s %s — %s\n", state, r.Severity.Icon(), r.ID, r.Description))
+	}
+	return sb.String()
+}
+
+// lintSetDNS 模拟写入后的 Zone，返回涉及本次写入记录、级别不低于 warning 的问题
+func lintSetDNS(account, domain string, records []cloudflare.DNSRecord, params cfclient.DNSRecordParams, plan cfclient.DNSWritePlan) []dnslint.Issue {
+	linter, err := dnslint.New(config.Cfg.Lint.Rules)
+	if err != nil {
+		return nil
+	}
+	drop := make(map[string]bool)
+	for _, r := range plan.Delete {
+		drop[r.ID] = true
+	}
+	if plan.Update != nil {
+		drop[plan.Update.ID] = true
+	}
+	proxied := params.Proxied
+	simulated := make([]cloudflare.DNSRecord, 0, len(records)+1)
+	for _, r := range records {
+		if !drop[r.ID] {
+			simulated = append(simulated, r)
+		}
+	}
+	name := cfclient.RecordFQDN(params.Name, domain)
+	ttl := params.TTL
+	if proxied {
+		// Cloudflare 对代理记录固定使用自动 TTL
+		ttl = 1
+	}
+	simulated = append(simulated, cloudflare.DNSRecord{
+		Type:     params.Type,
+		Name:     name,
+		Content:  params.Content,
+		Proxied:  &proxied,
+		TTL:      ttl,
+		Priority: params.Priority,
+	})
+	issues := linter.Lint([]dnslint.Zone{{Account: account, Name: domain, Records: simulated}})
+	return dnslint.AtLeast(dnslint.Filter(issues, name, params.Type), dnslint.SeverityWarning)
+}
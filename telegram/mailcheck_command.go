@@ -0,0 +1,172 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/dnslint"
+	"DomainC/mailauth"
+	"DomainC/nsaudit"
+)
+
+const mailCheckUsage = "用法: /mailcheck <zone|账号标签|all>\n检查 SPF 语法与查询次数、DMARC 策略、DKIM 选择器（mailAuth.dkimSelectors）与 MTA-STS。"
+
+const mailSetupUsage = "用法: /mailsetup <zone> <模板>\n套用邮件记录模板，执行前会列出将写入与替换的记录。"
+
+func (h *CommandHandler) handleMailCheckCommand(args []string) {
+	if len(args) < 1 {
+		h.sendText(mailCheckUsage)
+		return
+	}
+	selector := strings.TrimSpace(args[0])
+	accounts := h.Accounts
+	zone := ""
+	switch {
+	case strings.EqualFold(selector, "all"):
+	case h.getAccountByLabel(selector) != nil:
+		accounts = []config.CF{*h.getAccountByLabel(selector)}
+	default:
+		z, err := extractDomainOrHost(selector)
+		if err != nil {
+			h.sendText(fmt.Sprintf("参数不合法：%v\n%s", err, mailCheckUsage))
+			return
+		}
+		zone = z
+	}
+	if zone == "" {
+		h.sendText("正在检查邮件认证记录，请耐心等待...")
+	}
+
+	ctx := context.Background()
+	zones, errs := dnslint.Collect(ctx, h.CFClient, accounts, zone)
+	if zone != "" && len(zones) == 0 && len(errs) == 0 {
+		h.sendText(fmt.Sprintf("未在任何账号中找到 %s。", zone))
+		return
+	}
+
+	cfg := config.Cfg.MailAuth
+	auditor := &mailauth.Auditor{DKIMSelectors: cfg.DKIMSelectors, Lookup: nsaudit.NewResolver(cfg.Resolver)}
+
+	var lines []string
+	bad := 0
+	for _, z := range zones {
+		rep := auditor.Audit(ctx, z.Account, z.Name, z.Records)
+		if rep.Worst() >= mailauth.LevelWarning {
+			bad++
+		}
+		// 批量检查时只列出有问题的 Zone 与问题项
+		if zone == "" && rep.Worst() < mailauth.LevelWarning {
+			continue
+		}
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("%s %s (%s)", rep.Worst().Icon(), rep.Zone, rep.Account))
+		for _, f := range rep.Findings {
+			if zone == "" && f.Level < mailauth.LevelWarning {
+				continue
+			}
+			sb.WriteString("\n   " + f.String())
+		}
+		lines = append(lines, sb.String())
+	}
+
+	header := fmt.Sprintf("📧【邮件认证检查】%s：%d 个 Zone，%d 个存在问题", selector, len(zones), bad)
+	if len(lines) == 0 {
+		h.sendText(header + "\n✅ 全部通过。")
+	} else {
+		_ = h.Sender.Send(ctx, header+"\n"+strings.Join(lines, "\n"))
+	}
+	if len(errs) > 0 {
+		msgs := make([]string, 0, len(errs))
+		for _, err := range errs {
+			msgs = append(msgs, err.Error())
+		}
+		_ = h.Sender.Send(ctx, "以下 Zone 无法检查：\n"+strings.Join(msgs, "\n"))
+	}
+}
+
+func (h *CommandHandler) handleMailSetupCommand(args []string) {
+	profiles, err := mailauth.LoadProfiles(config.Cfg.MailAuth.Profiles)
+	if err != nil {
+		h.sendText(fmt.Sprintf("mailAuth.profiles 配置错误: %v", err))
+		return
+	}
+	if len(args) < 2 {
+		h.sendText(mailSetupUsage + "\n\n" + describeMailProfiles(profiles))
+		return
+	}
+	zone, err := extractDomainOrHost(args[0])
+	if err != nil {
+		h.sendText(fmt.Sprintf("参数不合法：%v\n%s", err, mailSetupUsage))
+		return
+	}
+	profile, ok := profiles[args[1]]
+	if !ok {
+		h.sendText(fmt.Sprintf("未找到模板 %s。\n\n%s", args[1], describeMailProfiles(profiles)))
+		return
+	}
+	account, detail, err := h.findZone(zone)
+	if err != nil {
+		h.sendText(fmt.Sprintf("域名 %s 不属于任何 Cloudflare 账号。", zone))
+		return
+	}
+	if detail.Name != zone {
+		h.sendText(fmt.Sprintf("%s 属于 Zone %s，请按 Zone 套用模板。", zone, detail.Name))
+		return
+	}
+
+	existing, err := h.CFClient.ListDNSRecords(context.Background(), *account, zone)
+	if err != nil {
+		h.sendText(fmt.Sprintf("查询现有解析记录失败: %v", err))
+		return
+	}
+	steps := mailauth.Plan(zone, profile, existing)
+
+	var sb strings.Builder
+	sb.WriteString("📧【套用邮件模板确认】\n")
+	sb.WriteString(fmt.Sprintf("操作人: %s\n账号: %s\nZone: %s\n模板: %s\n", formatOperator(h.operator), account.Label, zone, profile.Name))
+	if profile.Description != "" {
+		sb.WriteString(profile.Description + "\n")
+	}
+	sb.WriteString("\n将写入：\n")
+	for _, s := range steps {
+		sb.WriteString(fmt.Sprintf("+ %s %s → %s (%s)\n", s.Params.Type, cfclient.RecordFQDN(s.Params.Name, zone), s.Params.Content, s.Params.Mode))
+		for _, d := range s.Delete {
+			sb.WriteString(fmt.Sprintf("  - 删除 %s %s → %s\n", d.Type, d.Name, d.Content))
+		}
+	}
+	sb.WriteString("\nMX 使用 replace 模式，会删除同名的其它 MX。确认套用吗？")
+
+	token := SetMailSetupPayload(MailSetupPayload{
+		AccountLabel: account.Label,
+		Zone:         zone,
+		Profile:      profile.Name,
+		Operator:     formatOperator(h.operator),
+		Steps:        steps,
+	})
+	buttons := [][]Button{{
+		{Text: "✅ 套用模板", CallbackData: fmt.Sprintf("mailsetup_apply|%s", token)},
+		{Text: "❌ 取消", CallbackData: fmt.Sprintf("mailsetup_cancel|%s", token)},
+	}}
+	if err := h.Sender.SendWithButtons(context.Background(), sb.String(), buttons); err != nil {
+		h.sendText(fmt.Sprintf("发送确认消息失败: %v", err))
+	}
+}
+
+// ApplyMailSetup 写入模板记录并回执结果。由按钮回调调用。
+func ApplyMailSetup(ctx context.Context, client cfclient.Client, sender Sender, account config.CF, payload MailSetupPayload) {
+	lines, failed := mailauth.Apply(ctx, client, account, payload.Zone, payload.Steps)
+	header := fmt.Sprintf("套用模板 %s 到 %s（操作人: %s）：成功 %d / %d", payload.Profile, payload.Zone, payload.Operator, len(payload.Steps)-failed, len(payload.Steps))
+	_ = sender.Send(ctx, header+"\n"+strings.Join(lines, "\n"))
+}
+
+func describeMailProfiles(profiles map[string]mailauth.Profile) string {
+	var sb strings.Builder
+	sb.WriteString("可用模板：\n")
+	for _, name := range mailauth.ProfileNames(profiles) {
+		sb.WriteString(fmt.Sprintf("- %s：%s\n", name, profiles[name].Description))
+	}
+	return sb.String()
+}
@@ -0,0 +1,42 @@
+package telegram
+
+import (
+	"sync"
+
+	"DomainC/mailauth"
+)
+
+// MailSetupPayload 保存等待确认的 /mailsetup 写入步骤
+type MailSetupPayload struct {
+	AccountLabel string
+	Zone         string
+	Profile      string
+	Operator     string
+	Steps        []mailauth.Step
+}
+
+var mailSetupState = struct {
+	mu       sync.Mutex
+	payloads map[string]MailSetupPayload
+}{
+	payloads: make(map[string]MailSetupPayload),
+}
+
+func SetMailSetupPayload(payload MailSetupPayload) string {
+	token := newIPListToken()
+	mailSetupState.mu.Lock()
+	defer mailSetupState.mu.Unlock()
+	mailSetupState.payloads[token] = payload
+	return token
+}
+
+// TakeMailSetupPayload 取出并删除待执行的步骤，保证只执行一次
+func TakeMailSetupPayload(token string) (MailSetupPayload, bool) {
+	mailSetupState.mu.Lock()
+	defer mailSetupState.mu.Unlock()
+	payload, ok := mailSetupState.payloads[token]
+	if ok {
+		delete(mailSetupState.payloads, token)
+	}
+	return payload, ok
+}
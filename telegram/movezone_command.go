@@ -0,0 +1,178 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/registrarclient"
+	"DomainC/zonemove"
+)
+
+const moveZoneUsage = "用法:\n/movezone <domain> <目标账号>  迁移 Zone 到另一个 Cloudflare 账号\n/movezone status <domain>  查看迁移进度\n/movezone resume <domain>  从检查点继续失败或中断的迁移"
+
+func (h *CommandHandler) handleMoveZoneCommand(args []string) {
+	if len(args) < 2 {
+		h.sendText(moveZoneUsage)
+		return
+	}
+	switch strings.ToLower(args[0]) {
+	case "status":
+		h.handleMoveZoneStatus(args[1])
+		return
+	case "resume":
+		h.handleMoveZoneResume(args[1])
+		return
+	}
+
+	domain, err := extractDomainOrHost(args[0])
+	if err != nil {
+		h.sendText(fmt.Sprintf("参数不合法：%v\n%s", err, moveZoneUsage))
+		return
+	}
+	target := h.getAccountByLabel(args[1])
+	if target == nil {
+		h.sendText(fmt.Sprintf("未找到目标账号 %s", args[1]))
+		return
+	}
+	source, zone, err := h.findZone(domain)
+	if err != nil {
+		h.sendText(fmt.Sprintf("未在任何账号中找到 %s: %v", domain, err))
+		return
+	}
+	if zone.Name != domain {
+		h.sendText(fmt.Sprintf("%s 属于 Zone %s，请按 Zone 迁移。", domain, zone.Name))
+		return
+	}
+	if source.Label == target.Label {
+		h.sendText(fmt.Sprintf("%s 已经在账号 %s 中。", domain, target.Label))
+		return
+	}
+	if st, err := newZoneMover(h.CFClient, h.RegistrarManager, h.Sender).Load(domain); err == nil && (st.Status == zonemove.StatusRunning || st.Status == zonemove.StatusManual) {
+		h.sendText(fmt.Sprintf("%s 已有进行中的迁移：\n%s\n如进程曾中断，可使用 /movezone resume %s 继续。", domain, st.Summary(), domain))
+		return
+	}
+
+	records, err := h.CFClient.ListDNSRecords(context.Background(), *source, domain)
+	if err != nil {
+		h.sendText(fmt.Sprintf("获取解析记录失败 [%s]: %v", domain, err))
+		return
+	}
+
+	registrarHint := "未配置注册商，NS 需手动修改"
+	if h.RegistrarManager != nil {
+		registrarHint = "将通过注册商自动切换 NS"
+	}
+	msg := fmt.Sprintf("🚚【Zone 迁移确认】\n操作人: %s\n域名: %s\n源账号: %s\n目标账号: %s\n解析记录: %d 条\n%s\n\n步骤: 快照记录 → 目标账号创建 Zone → 回放记录 → 切换 NS → 等待激活 → 删除源 Zone。\n删除源 Zone 前任一步失败都会自动回滚。确认迁移吗？",
+		formatOperator(h.operator), domain, source.Label, target.Label, len(records), registrarHint)
+
+	token := SetMoveZonePayload(MoveZonePayload{
+		Domain:        domain,
+		SourceAccount: source.Label,
+		TargetAccount: target.Label,
+		Operator:      formatOperator(h.operator),
+	})
+	buttons := [][]Button{{
+		{Text: "✅ 开始迁移", CallbackData: fmt.Sprintf("movezone_confirm|%s", token)},
+		{Text: "❌ 取消", CallbackData: fmt.Sprintf("movezone_cancel|%s", token)},
+	}}
+	if err := h.Sender.SendWithButtons(context.Background(), msg, buttons); err != nil {
+		h.sendText(fmt.Sprintf("发送确认消息失败: %v", err))
+	}
+}
+
+func (h *CommandHandler) handleMoveZoneStatus(arg string) {
+	domain, err := extractDomainOrHost(arg)
+	if err != nil {
+		h.sendText(fmt.Sprintf("参数不合法：%v", err))
+		return
+	}
+	st, err := newZoneMover(h.CFClient, h.RegistrarManager, h.Sender).Load(domain)
+	if err != nil {
+		if os.IsNotExist(err) {
+			h.sendText(fmt.Sprintf("%s 没有迁移记录。", domain))
+			return
+		}
+		h.sendText(fmt.Sprintf("读取迁移记录失败 [%s]: %v", domain, err))
+		return
+	}
+	h.sendText(st.Summary())
+}
+
+func (h *CommandHandler) handleMoveZoneResume(arg string) {
+	domain, err := extractDomainOrHost(arg)
+	if err != nil {
+		h.sendText(fmt.Sprintf("参数不合法：%v", err))
+		return
+	}
+	st, err := newZoneMover(h.CFClient, h.RegistrarManager, h.Sender).Load(domain)
+	if err != nil {
+		h.sendText(fmt.Sprintf("读取迁移记录失败 [%s]: %v", domain, err))
+		return
+	}
+	if st.Status == zonemove.StatusDone {
+		h.sendText(fmt.Sprintf("%s 的迁移已完成。", domain))
+		return
+	}
+
+	token := SetMoveZonePayload(MoveZonePayload{
+		Domain:        st.Domain,
+		SourceAccount: st.SourceAccount,
+		TargetAccount: st.TargetAccount,
+		Operator:      formatOperator(h.operator),
+		Resume:        true,
+	})
+	msg := fmt.Sprintf("🚚【继续 Zone 迁移】\n操作人: %s\n%s\n\n确认从检查点继续吗？", formatOperator(h.operator), st.Summary())
+	buttons := [][]Button{{
+		{Text: "✅ 继续迁移", CallbackData: fmt.Sprintf("movezone_confirm|%s", token)},
+		{Text: "❌ 取消", CallbackData: fmt.Sprintf("movezone_cancel|%s", token)},
+	}}
+	if err := h.Sender.SendWithButtons(context.Background(), msg, buttons); err != nil {
+		h.sendText(fmt.Sprintf("发送确认消息失败: %v", err))
+	}
+}
+
+// RunMoveZone 执行（或继续）一次 Zone 迁移，进度逐步推送到 Telegram。命令与按钮回调共用。
+func RunMoveZone(ctx context.Context, client cfclient.Client, registrars *registrarclient.Manager, sender Sender, payload MoveZonePayload) {
+	mover := newZoneMover(client, registrars, sender)
+
+	var st *zonemove.State
+	var err error
+	if payload.Resume {
+		// 已回滚的迁移会从头开始，中断或回滚不完整的迁移从最后一个检查点继续
+		st, err = mover.Load(payload.Domain)
+	} else {
+		st, err = mover.NewState(payload.Domain, payload.SourceAccount, payload.TargetAccount, payload.Operator)
+	}
+	if err != nil {
+		_ = sender.Send(ctx, fmt.Sprintf("启动迁移失败 [%s]: %v", payload.Domain, err))
+		return
+	}
+	if err := mover.Run(ctx, st); errors.Is(err, zonemove.ErrMoveInProgress) {
+		_ = sender.Send(ctx, fmt.Sprintf("%s 的迁移正在执行中。", payload.Domain))
+	}
+}
+
+func newZoneMover(client cfclient.Client, registrars *registrarclient.Manager, sender Sender) *zonemove.Mover {
+	cfg := config.Cfg.ZoneMove
+	mover := &zonemove.Mover{
+		CF:       client,
+		Accounts: config.Cfg.CloudflareAccounts,
+		Dir:      cfg.Dir,
+		Notify: func(msg string) {
+			_ = sender.Send(context.Background(), msg)
+		},
+		ActivationTimeout: time.Duration(cfg.ActivationTimeoutMinutes) * time.Minute,
+		PollInterval:      time.Duration(cfg.PollIntervalSeconds) * time.Second,
+	}
+	// 避免把 nil 指针包装成非 nil 接口
+	if registrars != nil {
+		mover.Registrar = registrars
+	}
+	return mover
+}
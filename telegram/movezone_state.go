@@ -0,0 +1,38 @@
+package telegram
+
+import "sync"
+
+// MoveZonePayload 保存等待确认的 Zone 迁移
+type MoveZonePayload struct {
+	Domain        string
+	SourceAccount string
+	TargetAccount string
+	Operator      string
+	Resume        bool // 从检查点继续，而不是重新开始
+}
+
+var moveZoneState = struct {
+	mu       sync.Mutex
+	payloads map[string]MoveZonePayload
+}{
+	payloads: make(map[string]MoveZonePayload),
+}
+
+func SetMoveZonePayload(payload MoveZonePayload) string {
+	token := newIPListToken()
+	moveZoneState.mu.Lock()
+	defer moveZoneState.mu.Unlock()
+	moveZoneState.payloads[token] = payload
+	return token
+}
+
+// TakeMoveZonePayload 取出并删除待确认的迁移，保证只执行一次
+func TakeMoveZonePayload(token string) (MoveZonePayload, bool) {
+	moveZoneState.mu.Lock()
+	defer moveZoneState.mu.Unlock()
+	payload, ok := moveZoneState.payloads[token]
+	if ok {
+		delete(moveZoneState.payloads, token)
+	}
+	return payload, ok
+}
@@ -0,0 +1,113 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"DomainC/config"
+	"DomainC/nsaudit"
+	"DomainC/registrarclient"
+)
+
+const nsCheckUsage = "用法: /nscheck [账号标签|all]\n对比 Cloudflare 分配的 NS、注册商登记的 NS 与公网解析到的 NS，列出不一致的 Zone。"
+
+func (h *CommandHandler) handleNSCheckCommand(args []string) {
+	selector := "all"
+	if len(args) > 0 {
+		selector = strings.TrimSpace(args[0])
+	}
+
+	var targets []config.CF
+	if strings.EqualFold(selector, "all") {
+		targets = append(targets, h.Accounts...)
+	} else if acc := h.getAccountByLabel(selector); acc != nil {
+		targets = []config.CF{*acc}
+	} else {
+		h.sendText(fmt.Sprintf("未找到账号 %s。\n\n%s", selector, nsCheckUsage))
+		return
+	}
+	if len(targets) == 0 {
+		h.sendText("未配置可用的 Cloudflare 账号。")
+		return
+	}
+	h.sendText("正在对比各 Zone 的 NS，需要逐个查询注册商与 DNS，请耐心等待...")
+
+	auditor := &nsaudit.Auditor{
+		CF:     h.CFClient,
+		Lookup: nsaudit.NewResolver(config.Cfg.NSCheck.Resolver),
+	}
+	if h.RegistrarManager != nil && len(h.RegistrarManager.Registrars()) > 0 {
+		auditor.Registrar = h.RegistrarManager
+	}
+	results, errs := auditor.Audit(context.Background(), targets)
+
+	var mismatched, unknown []string
+	var syncTargets []NSSyncTarget
+	for _, r := range results {
+		if !r.OK() {
+			line := fmt.Sprintf("❗ %s (%s, %s)\n   Cloudflare NS: %s", r.Zone, r.Account, r.Status, strings.Join(r.CloudflareNS, ", "))
+			for _, p := range r.Problems() {
+				line += "\n   " + p
+			}
+			mismatched = append(mismatched, line)
+		}
+		if r.RegistrarMismatch() {
+			syncTargets = append(syncTargets, NSSyncTarget{Domain: r.Zone, NameServers: r.CloudflareNS})
+		}
+		if r.LookupErr != nil {
+			unknown = append(unknown, fmt.Sprintf("%s: 解析 NS 失败: %v", r.Zone, r.LookupErr))
+		}
+	}
+	for _, err := range errs {
+		unknown = append(unknown, err.Error())
+	}
+
+	ctx := context.Background()
+	header := fmt.Sprintf("🧭【NS 一致性检查】%s\n共 %d 个 Zone，%d 个不一致。", selector, len(results), len(mismatched))
+	if auditor.Registrar == nil {
+		header += "\n（未配置注册商，仅对比解析到的 NS）"
+	}
+	if len(mismatched) == 0 {
+		h.sendText(header + "\n✅ 全部一致。")
+	} else {
+		_ = h.Sender.Send(ctx, header+"\n"+strings.Join(mismatched, "\n"))
+	}
+	if len(unknown) > 0 {
+		_ = h.Sender.Send(ctx, fmt.Sprintf("以下 %d 项无法检查：\n", len(unknown))+strings.Join(unknown, "\n"))
+	}
+
+	if len(syncTargets) == 0 {
+		return
+	}
+	token := SetNSCheckPayload(NSCheckPayload{Operator: formatOperator(h.operator), Targets: syncTargets})
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("以下 %d 个 Zone 的注册商 NS 与 Cloudflare 不一致，是否同步到注册商？\n", len(syncTargets)))
+	for _, t := range syncTargets {
+		sb.WriteString("- " + t.Domain + "\n")
+	}
+	buttons := [][]Button{{
+		{Text: "🔄 同步 NS 到注册商", CallbackData: fmt.Sprintf("nscheck_sync|%s", token)},
+		{Text: "❌ 忽略", CallbackData: fmt.Sprintf("nscheck_cancel|%s", token)},
+	}}
+	if err := h.Sender.SendWithButtons(ctx, sb.String(), buttons); err != nil {
+		h.sendText(fmt.Sprintf("发送确认消息失败: %v", err))
+	}
+}
+
+// SyncRegistrarNS 把 Cloudflare 分配的 NS 写入注册商并回执结果。由按钮回调调用。
+func SyncRegistrarNS(ctx context.Context, registrars *registrarclient.Manager, sender Sender, payload NSCheckPayload) {
+	var lines []string
+	ok := 0
+	for _, t := range payload.Targets {
+		registrar, err := registrars.SetNameServersForDomain(ctx, t.Domain, t.NameServers)
+		if err != nil {
+			lines = append(lines, fmt.Sprintf("❌ %s: %v", t.Domain, err))
+			continue
+		}
+		ok++
+		lines = append(lines, fmt.Sprintf("✅ %s → %s (%s)", t.Domain, registrar.Label, registrar.Type))
+	}
+	header := fmt.Sprintf("同步注册商 NS 结果（操作人: %s）：成功 %d / %d", payload.Operator, ok, len(payload.Targets))
+	_ = sender.Send(ctx, header+"\n"+strings.Join(lines, "\n"))
+}
@@ -0,0 +1,41 @@
+package telegram
+
+import "sync"
+
+// NSSyncTarget 是一个待同步到注册商的 Zone
+type NSSyncTarget struct {
+	Domain      string
+	NameServers []string
+}
+
+// NSCheckPayload 保存 /nscheck 发现的、可一键同步 NS 的 Zone
+type NSCheckPayload struct {
+	Operator string
+	Targets  []NSSyncTarget
+}
+
+var nsCheckState = struct {
+	mu       sync.Mutex
+	payloads map[string]NSCheckPayload
+}{
+	payloads: make(map[string]NSCheckPayload),
+}
+
+func SetNSCheckPayload(payload NSCheckPayload) string {
+	token := newIPListToken()
+	nsCheckState.mu.Lock()
+	defer nsCheckState.mu.Unlock()
+	nsCheckState.payloads[token] = payload
+	return token
+}
+
+// TakeNSCheckPayload 取出并删除待同步列表，保证只执行一次
+func TakeNSCheckPayload(token string) (NSCheckPayload, bool) {
+	nsCheckState.mu.Lock()
+	defer nsCheckState.mu.Unlock()
+	payload, ok := nsCheckState.payloads[token]
+	if ok {
+		delete(nsCheckState.payloads, token)
+	}
+	return payload, ok
+}
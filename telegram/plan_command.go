@@ -0,0 +1,144 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/dnsplan"
+)
+
+// planPreviewLimit 预览中最多列出的变更条数，避免超过 Telegram 单条消息长度
+const planPreviewLimit = 40
+
+const planUsage = "用法: /plan <zone>\n对比期望状态（YAML）与线上解析，确认后执行收敛。"
+
+func (h *CommandHandler) handlePlanCommand(args []string) {
+	if len(args) < 1 {
+		h.sendText(planUsage)
+		return
+	}
+	stateCfg := config.Cfg.DNSState
+	if strings.TrimSpace(stateCfg.Dir) == "" {
+		h.sendText("未配置 dnsState.dir，无法读取期望状态。")
+		return
+	}
+	zone, err := extractDomainOrHost(args[0])
+	if err != nil {
+		h.sendText(fmt.Sprintf("参数不合法：%v\n%s", err, planUsage))
+		return
+	}
+
+	if stateCfg.GitPull {
+		if out, err := exec.Command("git", "-C", stateCfg.Dir, "pull", "--ff-only").CombinedOutput(); err != nil {
+			h.sendText(fmt.Sprintf("拉取期望状态仓库失败: %v\n%s", err, strings.TrimSpace(string(out))))
+			return
+		}
+	}
+
+	states, err := dnsplan.LoadStates(stateCfg.Dir)
+	if err != nil {
+		h.sendText(fmt.Sprintf("读取期望状态失败: %v", err))
+		return
+	}
+	state, err := dnsplan.FindState(states, zone)
+	if err != nil {
+		if errors.Is(err, dnsplan.ErrStateNotFound) {
+			h.sendText(fmt.Sprintf("%s 没有期望状态文件（目录: %s）。", zone, stateCfg.Dir))
+			return
+		}
+		h.sendText(err.Error())
+		return
+	}
+
+	plan, err := dnsplan.PlanState(context.Background(), h.CFClient, h.Accounts, state)
+	if err != nil {
+		h.sendText(fmt.Sprintf("生成计划失败: %v", err))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📝【DNS 期望状态计划】\n")
+	sb.WriteString(fmt.Sprintf("操作人: %s\n文件: %s\n", formatOperator(h.operator), state.Source))
+	if state.Prune {
+		sb.WriteString("模式: prune（删除文件中未声明的记录）\n")
+	}
+	total := writeZonePlans(&sb, []dnsplan.ZonePlan{plan})
+	if total == 0 {
+		sb.WriteString("\n线上解析已与期望状态一致，无需变更。")
+		h.sendText(sb.String())
+		return
+	}
+	sb.WriteString("\n确认执行以上变更吗？")
+
+	token := SetPlanPayload(PlanPayload{Operator: formatOperator(h.operator), Plan: plan})
+	buttons := [][]Button{{
+		{Text: "✅ 执行", CallbackData: fmt.Sprintf("plan_apply|%s", token)},
+		{Text: "❌ 取消", CallbackData: fmt.Sprintf("plan_cancel|%s", token)},
+	}}
+	if err := h.Sender.SendWithButtons(context.Background(), sb.String(), buttons); err != nil {
+		h.sendText(fmt.Sprintf("发送确认消息失败: %v", err))
+	}
+}
+
+// ApplyPlan 执行 /plan 生成的计划并逐条回执结果
+func ApplyPlan(ctx context.Context, client cfclient.Client, sender Sender, payload PlanPayload) {
+	applyZonePlans(ctx, client, sender, "执行结果", payload.Operator, []dnsplan.ZonePlan{payload.Plan})
+}
+
+// writeZonePlans 以 diff 形式写出计划预览，返回变更总数
+func writeZonePlans(sb *strings.Builder, plans []dnsplan.ZonePlan) int {
+	total, shown := 0, 0
+	for _, p := range plans {
+		create, update, del := p.Counts()
+		total += create + update + del
+		sb.WriteString(fmt.Sprintf("\n[%s] %s：新建 %d / 更新 %d / 删除 %d\n", p.AccountLabel, p.Zone, create, update, del))
+		for _, ch := range p.Changes {
+			if shown >= planPreviewLimit {
+				break
+			}
+			sb.WriteString(ch.Describe() + "\n")
+			shown++
+		}
+		for _, w := range p.Warnings {
+			sb.WriteString("⚠️ " + w + "\n")
+		}
+	}
+	if shown < total {
+		sb.WriteString(fmt.Sprintf("\n… 另有 %d 项变更未列出\n", total-shown))
+	}
+	return total
+}
+
+// applyZonePlans 依次执行计划，并按 Zone 回执每条变更的结果
+func applyZonePlans(ctx context.Context, client cfclient.Client, sender Sender, title, operator string, plans []dnsplan.ZonePlan) {
+	for _, plan := range plans {
+		if len(plan.Changes) == 0 {
+			continue
+		}
+		account := cfclient.GetAccountByLabel(plan.AccountLabel)
+		if account == nil {
+			_ = sender.Send(ctx, fmt.Sprintf("%s: %s 失败，未找到账号 %s", title, plan.Zone, plan.AccountLabel))
+			continue
+		}
+
+		results := dnsplan.Apply(ctx, client, *account, plan)
+		ok := 0
+		lines := make([]string, 0, len(results))
+		for _, r := range results {
+			if r.Err != nil {
+				lines = append(lines, fmt.Sprintf("❌ %s\n   %v", r.Change.Describe(), r.Err))
+				continue
+			}
+			ok++
+			lines = append(lines, fmt.Sprintf("✅ %s", r.Change.Describe()))
+		}
+		header := fmt.Sprintf("【%s】[%s] %s：成功 %d / 失败 %d（操作人: %s）",
+			title, plan.AccountLabel, plan.Zone, ok, len(results)-ok, operator)
+		_ = sender.Send(ctx, header+"\n"+strings.Join(lines, "\n"))
+	}
+}
@@ -0,0 +1,39 @@
+package telegram
+
+import (
+	"sync"
+
+	"DomainC/dnsplan"
+)
+
+// PlanPayload 保存等待确认的 /plan 计划
+type PlanPayload struct {
+	Operator string
+	Plan     dnsplan.ZonePlan
+}
+
+var planState = struct {
+	mu       sync.Mutex
+	payloads map[string]PlanPayload
+}{
+	payloads: make(map[string]PlanPayload),
+}
+
+func SetPlanPayload(payload PlanPayload) string {
+	token := newIPListToken()
+	planState.mu.Lock()
+	defer planState.mu.Unlock()
+	planState.payloads[token] = payload
+	return token
+}
+
+// TakePlanPayload 取出并删除计划，保证同一计划只会被执行一次
+func TakePlanPayload(token string) (PlanPayload, bool) {
+	planState.mu.Lock()
+	defer planState.mu.Unlock()
+	payload, ok := planState.payloads[token]
+	if ok {
+		delete(planState.payloads, token)
+	}
+	return payload, ok
+}
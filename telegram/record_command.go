@@ -6,19 +6,35 @@ import (
 	"sort"
 	"strings"
 	"sync"
+
+	"DomainC/dnsfilter"
 )
 
 const recordLookupConcurrency = 20
 
+const recordUsage = "用法: /record <解析记录内容-必须精确匹配> [过滤条件...]\n" +
+	"或者只用过滤条件搜索: /record type:CNAME content:*.elb.amazonaws.com\n\n过滤条件：\n" + dnsfilter.Help
+
 func (h *CommandHandler) handleRecordCommand(args []string) {
 	if len(args) < 1 {
-		h.sendText("用法: /record <解析记录内容-必须精确匹配>")
+		h.sendText(recordUsage)
 		return
 	}
 
-	query := normalizeRecordContent(args[0])
-	if query == "" {
-		h.sendText("用法: /record <解析记录内容-必须精确匹配>")
+	// 第一个参数就是过滤条件时，按表达式搜索；否则第一个参数为精确匹配的内容
+	query := ""
+	filterArgs := args
+	if !dnsfilter.IsTerm(args[0]) {
+		query = normalizeRecordContent(args[0])
+		filterArgs = args[1:]
+		if query == "" {
+			h.sendText(recordUsage)
+			return
+		}
+	}
+	filter, err := dnsfilter.ParseArgs(filterArgs)
+	if err != nil {
+		h.sendText(fmt.Sprintf("%v\n\n%s", err, recordUsage))
 		return
 	}
 
@@ -57,6 +73,9 @@ func (h *CommandHandler) handleRecordCommand(args []string) {
 
 			for _, zone := range zones {
 				zone := zone
+				if !filter.MatchZone(zone) {
+					continue
+				}
 				wg.Add(1)
 				sem <- struct{}{}
 				go func() {
@@ -71,7 +90,10 @@ func (h *CommandHandler) handleRecordCommand(args []string) {
 
 					localMatches := make([]string, 0)
 					for _, r := range records {
-						if !recordContentEqual(r.Content, query) {
+						if query != "" && !recordContentEqual(r.Content, query) {
+							continue
+						}
+						if !filter.Match(r, zone) {
 							continue
 						}
 
@@ -79,8 +101,13 @@ func (h *CommandHandler) handleRecordCommand(args []string) {
 						if r.Proxied != nil && *r.Proxied {
 							proxied = "是"
 						}
-						localMatches = append(localMatches, fmt.Sprintf("- [%s] %s (代理: %s)",
-							acc.Label, r.Name, proxied))
+						if query != "" {
+							localMatches = append(localMatches, fmt.Sprintf("- [%s] %s (代理: %s)",
+								acc.Label, r.Name, proxied))
+							continue
+						}
+						localMatches = append(localMatches, fmt.Sprintf("- [%s] %s %s → %s (代理: %s, TTL: %d)",
+							acc.Label, r.Type, r.Name, r.Content, proxied, r.TTL))
 					}
 
 					if len(localMatches) == 0 {
@@ -105,19 +132,29 @@ func (h *CommandHandler) handleRecordCommand(args []string) {
 	}
 
 	if len(matches) == 0 {
-		h.sendText(fmt.Sprintf("未找到内容为 %s 的解析记录。", query))
+		switch {
+		case query == "":
+			h.sendText(fmt.Sprintf("未找到满足 %s 的解析记录。", filter))
+		case !filter.Empty():
+			h.sendText(fmt.Sprintf("未找到内容为 %s 且满足 %s 的解析记录。", query, filter))
+		default:
+			h.sendText(fmt.Sprintf("未找到内容为 %s 的解析记录。", query))
+		}
 		return
 	}
 
 	sort.Strings(matches)
 
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("以下记录精确匹配：\n%s\n", query))
-	for _, line := range matches {
-		sb.WriteString(line)
-		sb.WriteString("\n")
+	var header string
+	switch {
+	case query == "":
+		header = fmt.Sprintf("满足 %s 的解析记录（共 %d 条）：", filter, len(matches))
+	case !filter.Empty():
+		header = fmt.Sprintf("以下记录精确匹配：\n%s\n过滤条件: %s", query, filter)
+	default:
+		header = fmt.Sprintf("以下记录精确匹配：\n%s", query)
 	}
-	h.sendText(sb.String())
+	_ = h.Sender.Send(ctx, header+"\n"+strings.Join(matches, "\n"))
 }
 
 func normalizeRecordContent(content string) string {
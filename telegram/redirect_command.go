@@ -0,0 +1,287 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+const redirectUsage = `用法:
+/redirect <zone>  查看 zone 的重定向规则
+/redirect <zone> <目标地址> [301|302|307|308] [preserve-path] [preserve-query]  把 zone 与 www 跳转到目标地址（目标是其中之一时只跳转另一个）
+/redirect <zone> off  删除重定向规则
+/redirect bulk <账号标签> list
+/redirect bulk <账号标签> add <来源域名> <目标地址> [301|302|307|308] [preserve-path] [preserve-query] [subdomains]
+/redirect bulk <账号标签> rm <来源域名> [...]
+没有解析的主机名会自动创建已代理的占位 A 记录。`
+
+// redirectOptions 是重定向命令的可选参数
+type redirectOptions struct {
+	StatusCode    int
+	PreservePath  bool
+	PreserveQuery bool
+	Subdomains    bool
+}
+
+func parseRedirectOptions(args []string) (redirectOptions, error) {
+	opts := redirectOptions{StatusCode: 301}
+	for _, a := range args {
+		switch strings.ToLower(a) {
+		case "preserve-path", "path":
+			opts.PreservePath = true
+		case "preserve-query", "query":
+			opts.PreserveQuery = true
+		case "subdomains":
+			opts.Subdomains = true
+		default:
+			code, err := strconv.Atoi(a)
+			if err != nil || !cfclient.ValidRedirectStatus(code) {
+				return opts, fmt.Errorf("无法识别的参数: %s", a)
+			}
+			opts.StatusCode = code
+		}
+	}
+	return opts, nil
+}
+
+func (h *CommandHandler) handleRedirectCommand(args []string) {
+	if len(args) < 1 {
+		h.sendText(redirectUsage)
+		return
+	}
+	if strings.EqualFold(args[0], "bulk") {
+		h.handleBulkRedirect(args[1:])
+		return
+	}
+
+	acc, zone, err := h.findZone(args[0])
+	if err != nil {
+		if errors.Is(err, cfclient.ErrZoneNotFound) {
+			h.sendText(fmt.Sprintf("未在任何账号下找到 %s。", args[0]))
+			return
+		}
+		h.sendText(fmt.Sprintf("查询 Zone 失败: %v", err))
+		return
+	}
+	ctx := context.Background()
+
+	if len(args) == 1 {
+		rule, err := h.CFClient.GetRedirectRule(ctx, *acc, zone.ID)
+		if err != nil {
+			h.sendText(fmt.Sprintf("❌ %v", err))
+			return
+		}
+		if rule == nil {
+			h.sendText(fmt.Sprintf("%s (%s) 没有由本工具管理的重定向规则。\n\n%s", zone.Name, acc.Label, redirectUsage))
+			return
+		}
+		h.sendText(fmt.Sprintf("↪️ %s (%s)\n%s", zone.Name, acc.Label, formatRedirectRule(*rule)))
+		return
+	}
+
+	if strings.EqualFold(args[1], "off") || strings.EqualFold(args[1], "rm") {
+		removed, err := h.CFClient.DeleteRedirectRule(ctx, *acc, zone.ID)
+		if err != nil {
+			h.sendText(fmt.Sprintf("❌ %v", err))
+			return
+		}
+		if !removed {
+			h.sendText(fmt.Sprintf("%s 没有由本工具管理的重定向规则。", zone.Name))
+			return
+		}
+		h.sendText(fmt.Sprintf("✅ 已删除 %s (%s) 的重定向规则（操作人: %s）。占位解析记录未删除，如不再需要请用 /deldns 清理。", zone.Name, acc.Label, formatOperator(h.operator)))
+		return
+	}
+
+	opts, err := parseRedirectOptions(args[2:])
+	if err != nil {
+		h.sendText(fmt.Sprintf("%v\n\n%s", err, redirectUsage))
+		return
+	}
+	target, err := cfclient.NormalizeRedirectTarget(args[1], opts.PreservePath)
+	if err != nil {
+		h.sendText(fmt.Sprintf("参数不合法：%v", err))
+		return
+	}
+	// 跳转到 www 或裸域时只匹配另一个主机名，否则会循环跳转
+	hosts := cfclient.RedirectSourceHosts([]string{zone.Name, "www." + zone.Name}, target)
+	rule := cfclient.RedirectRule{
+		Hosts:         hosts,
+		Target:        target,
+		StatusCode:    opts.StatusCode,
+		PreservePath:  opts.PreservePath,
+		PreserveQuery: opts.PreserveQuery,
+		Enabled:       true,
+	}
+	replaced, err := h.CFClient.UpsertRedirectRule(ctx, *acc, zone.ID, rule)
+	if err != nil {
+		h.sendText(fmt.Sprintf("❌ %v", err))
+		return
+	}
+	verb := "创建"
+	if replaced {
+		verb = "更新"
+	}
+	lines := h.ensureRedirectPlaceholders(ctx, *acc, zone.Name, rule.Hosts)
+	h.sendText(fmt.Sprintf("✅ 已%s %s (%s) 的重定向规则（操作人: %s）\n%s\n%s",
+		verb, zone.Name, acc.Label, formatOperator(h.operator), formatRedirectRule(rule), strings.Join(lines, "\n")))
+}
+
+func (h *CommandHandler) handleBulkRedirect(args []string) {
+	if len(args) < 2 {
+		h.sendText(redirectUsage)
+		return
+	}
+	acc := h.getAccountByLabel(args[0])
+	if acc == nil {
+		h.sendText(fmt.Sprintf("未找到账号 %s。\n\n%s", args[0], redirectUsage))
+		return
+	}
+	listName := bulkRedirectList()
+	ctx := context.Background()
+
+	switch strings.ToLower(args[1]) {
+	case "list", "ls":
+		items, err := h.CFClient.ListBulkRedirects(ctx, *acc, listName)
+		if err != nil {
+			h.sendText(fmt.Sprintf("❌ %v", err))
+			return
+		}
+		if len(items) == 0 {
+			h.sendText(fmt.Sprintf("账号 %s 的重定向列表 %s 为空。", acc.Label, listName))
+			return
+		}
+		var lines []string
+		for _, b := range items {
+			lines = append(lines, fmt.Sprintf("↪️ %s → %s（%d%s）", b.Source, b.Target, b.StatusCode, bulkRedirectFlags(b)))
+		}
+		_ = h.Sender.Send(ctx, fmt.Sprintf("📋【Bulk Redirect】%s / %s 共 %d 条：\n", acc.Label, listName, len(items))+strings.Join(lines, "\n"))
+
+	case "add":
+		if len(args) < 4 {
+			h.sendText(redirectUsage)
+			return
+		}
+		opts, err := parseRedirectOptions(args[4:])
+		if err != nil {
+			h.sendText(fmt.Sprintf("%v\n\n%s", err, redirectUsage))
+			return
+		}
+		source, err := cfclient.NormalizeRedirectSource(args[2])
+		if err != nil {
+			h.sendText(fmt.Sprintf("参数不合法：%v", err))
+			return
+		}
+		b := cfclient.BulkRedirect{
+			Source:            source,
+			Target:            args[3],
+			StatusCode:        opts.StatusCode,
+			PreservePath:      opts.PreservePath,
+			PreserveQuery:     opts.PreserveQuery,
+			IncludeSubdomains: opts.Subdomains,
+		}
+		if err := h.CFClient.AddBulkRedirects(ctx, *acc, listName, []cfclient.BulkRedirect{b}); err != nil {
+			h.sendText(fmt.Sprintf("❌ %v", err))
+			return
+		}
+		msg := fmt.Sprintf("✅ 已添加到 %s / %s：%s → %s（%d%s，操作人: %s）", acc.Label, listName, b.Source, b.Target, b.StatusCode, bulkRedirectFlags(b), formatOperator(h.operator))
+		host, _, _ := strings.Cut(source, "/")
+		if zacc, zone, err := h.findZone(host); err == nil {
+			msg += "\n" + strings.Join(h.ensureRedirectPlaceholders(ctx, *zacc, zone.Name, []string{host}), "\n")
+		} else {
+			msg += fmt.Sprintf("\n⚠️ %s 不在已配置的账号中，请确认其解析已通过 Cloudflare 代理，否则重定向不会生效。", host)
+		}
+		h.sendText(msg)
+
+	case "rm", "del":
+		if len(args) < 3 {
+			h.sendText(redirectUsage)
+			return
+		}
+		n, err := h.CFClient.DeleteBulkRedirects(ctx, *acc, listName, args[2:])
+		if err != nil {
+			h.sendText(fmt.Sprintf("❌ %v", err))
+			return
+		}
+		h.sendText(fmt.Sprintf("✅ 已从 %s / %s 删除 %d 条重定向（操作人: %s）", acc.Label, listName, n, formatOperator(h.operator)))
+
+	default:
+		h.sendText(redirectUsage)
+	}
+}
+
+// ensureRedirectPlaceholders 为没有解析的主机名创建已代理的占位 A 记录，返回每个主机名的处理结果
+func (h *CommandHandler) ensureRedirectPlaceholders(ctx context.Context, acc config.CF, zone string, hosts []string) []string {
+	records, err := h.CFClient.ListDNSRecords(ctx, acc, zone)
+	if err != nil {
+		return []string{fmt.Sprintf("⚠️ 读取 %s 的解析失败，未检查占位记录: %v", zone, err)}
+	}
+	ip := strings.TrimSpace(config.Cfg.Redirect.PlaceholderIP)
+	if ip == "" {
+		ip = cfclient.DefaultRedirectPlaceholderIP
+	}
+	var lines []string
+	for _, host := range hosts {
+		switch cfclient.CheckRedirectPlaceholder(records, host) {
+		case cfclient.PlaceholderProxied:
+			lines = append(lines, fmt.Sprintf("✔️ %s 已有代理解析", host))
+		case cfclient.PlaceholderUnproxied:
+			lines = append(lines, fmt.Sprintf("⚠️ %s 的解析未开启代理，重定向不会生效", host))
+		case cfclient.PlaceholderMissing:
+			_, err := h.CFClient.UpsertDNSRecord(ctx, acc, zone, cfclient.DNSRecordParams{Type: "A", Name: host, Content: ip, Proxied: true, TTL: 1})
+			if err != nil {
+				lines = append(lines, fmt.Sprintf("❌ 创建 %s 的占位记录失败: %v", host, err))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("➕ 已创建占位记录 %s A %s（已代理）", host, ip))
+		}
+	}
+	return lines
+}
+
+func formatRedirectRule(r cfclient.RedirectRule) string {
+	var flags []string
+	if r.PreservePath {
+		flags = append(flags, "保留路径")
+	}
+	if r.PreserveQuery {
+		flags = append(flags, "保留查询参数")
+	}
+	if !r.Enabled {
+		flags = append(flags, "已停用")
+	}
+	s := fmt.Sprintf("%s → %s（%d", strings.Join(r.Hosts, ", "), r.Target, r.StatusCode)
+	if len(flags) > 0 {
+		s += "，" + strings.Join(flags, "，")
+	}
+	return s + "）"
+}
+
+func bulkRedirectFlags(b cfclient.BulkRedirect) string {
+	var flags []string
+	if b.PreservePath {
+		flags = append(flags, "保留路径")
+	}
+	if b.PreserveQuery {
+		flags = append(flags, "保留查询参数")
+	}
+	if b.IncludeSubdomains {
+		flags = append(flags, "含子域名")
+	}
+	if len(flags) == 0 {
+		return ""
+	}
+	return "，" + strings.Join(flags, "，")
+}
+
+func bulkRedirectList() string {
+	if name := strings.TrimSpace(config.Cfg.Redirect.BulkList); name != "" {
+		return name
+	}
+	return cfclient.DefaultBulkRedirectList
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"strings"
 	"time"
 
@@ -19,6 +21,10 @@ type Sender interface {
 	EditButtons(ctx context.Context, chatID int64, messageID int, buttons [][]Button) error
 	ClearButtons(ctx context.Context, chatID int64, messageID int) error
 	AnswerCallback(ctx context.Context, callbackID, text string) error
+	DownloadFile(ctx context.Context, fileID string) ([]byte, error)
+	// SendDocumentTo 向指定会话（如用户私聊）发送文件，返回消息 ID
+	SendDocumentTo(ctx context.Context, chatID int64, filepath string, caption string) (int, error)
+	DeleteMessage(ctx context.Context, chatID int64, messageID int) error
 }
 
 type NoopSender struct{}
@@ -39,6 +45,13 @@ func (NoopSender) EditButtons(ctx context.Context, chatID int64, messageID int,
 }
 func (NoopSender) ClearButtons(ctx context.Context, chatID int64, messageID int) error { return nil }
 func (NoopSender) AnswerCallback(ctx context.Context, callbackID, text string) error   { return nil }
+func (NoopSender) DownloadFile(ctx context.Context, fileID string) ([]byte, error) {
+	return nil, errors.New("telegram sender 未初始化")
+}
+func (NoopSender) SendDocumentTo(ctx context.Context, chatID int64, filepath string, caption string) (int, error) {
+	return 0, errors.New("telegram sender 未初始化")
+}
+func (NoopSender) DeleteMessage(ctx context.Context, chatID int64, messageID int) error { return nil }
 
 // BotSender 实现了带简单重试和节流的 Telegram 发送能力。
 type BotSender struct {
@@ -193,16 +206,30 @@ func (s *BotSender) StartListener(ctx context.Context, handleCallback func(cb *t
 	}
 }
 func (s *BotSender) SendDocumentPath(ctx context.Context, filepath string, caption string) error {
+	_, err := s.sendDocument(ctx, s.chatID, filepath, caption)
+	return err
+}
+
+// SendDocumentTo 向指定会话发送文件（用户 ID 即私聊会话 ID，用户需先私聊过机器人）
+func (s *BotSender) SendDocumentTo(ctx context.Context, chatID int64, filepath string, caption string) (int, error) {
+	return s.sendDocument(ctx, chatID, filepath, caption)
+}
+
+func (s *BotSender) sendDocument(ctx context.Context, chatID int64, filepath string, caption string) (int, error) {
 	if filepath == "" {
-		return errors.New("filepath is empty")
+		return 0, errors.New("filepath is empty")
 	}
 
 	for attempt := 0; attempt <= s.retryTimes; attempt++ {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return 0, ctx.Err()
 		case <-s.rate.C:
-			result := make(chan error, 1)
+			type sent struct {
+				id  int
+				err error
+			}
+			result := make(chan sent, 1)
 
 			sendCtx := ctx
 			cancel := func() {}
@@ -211,34 +238,34 @@ func (s *BotSender) SendDocumentPath(ctx context.Context, filepath string, capti
 			}
 
 			go func() {
-				doc := tgbotapi.NewDocument(s.chatID, tgbotapi.FilePath(filepath))
+				doc := tgbotapi.NewDocument(chatID, tgbotapi.FilePath(filepath))
 				if caption != "" {
 					doc.Caption = caption
 				}
-				_, err := s.bot.Send(doc)
-				result <- err
+				msg, err := s.bot.Send(doc)
+				result <- sent{id: msg.MessageID, err: err}
 			}()
 
 			select {
 			case <-sendCtx.Done():
 				cancel()
 				if attempt == s.retryTimes {
-					return fmt.Errorf("发送文件超时: %w", sendCtx.Err())
+					return 0, fmt.Errorf("发送文件超时: %w", sendCtx.Err())
 				}
 				continue
-			case err := <-result:
+			case r := <-result:
 				cancel()
-				if err == nil {
-					return nil
+				if r.err == nil {
+					return r.id, nil
 				}
 				if attempt == s.retryTimes {
-					return fmt.Errorf("发送文件失败: %w", err)
+					return 0, fmt.Errorf("发送文件失败: %w", r.err)
 				}
 				time.Sleep(time.Duration(attempt+1) * 200 * time.Millisecond)
 			}
 		}
 	}
-	return nil
+	return 0, nil
 }
 func (s *BotSender) requestWithRetry(ctx context.Context, cfg tgbotapi.Chattable) error {
 	for attempt := 0; attempt <= s.retryTimes; attempt++ {
@@ -300,8 +327,54 @@ func (s *BotSender) ClearButtons(ctx context.Context, chatID int64, messageID in
 	return s.requestWithRetry(ctx, edit)
 }
 
+func (s *BotSender) DeleteMessage(ctx context.Context, chatID int64, messageID int) error {
+	return s.requestWithRetry(ctx, tgbotapi.NewDeleteMessage(chatID, messageID))
+}
+
 func (s *BotSender) AnswerCallback(ctx context.Context, callbackID, text string) error {
 	cfg := tgbotapi.NewCallback(callbackID, text)
 	cfg.ShowAlert = false
 	return s.requestWithRetry(ctx, cfg)
 }
+
+// maxDownloadSize Telegram Bot API 允许下载的文件上限为 20MB
+const maxDownloadSize = 20 << 20
+
+// DownloadFile 下载用户上传到群里的文件内容
+func (s *BotSender) DownloadFile(ctx context.Context, fileID string) ([]byte, error) {
+	if fileID == "" {
+		return nil, errors.New("fileID is empty")
+	}
+	fileURL, err := s.bot.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("获取文件地址失败: %w", err)
+	}
+
+	reqCtx := ctx
+	cancel := func() {}
+	if s.timeout > 0 {
+		reqCtx, cancel = context.WithTimeout(ctx, 3*s.timeout)
+	}
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("构建下载请求失败: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("下载文件失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("下载文件失败: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
+	if len(data) > maxDownloadSize {
+		return nil, fmt.Errorf("文件超过 20MB 限制")
+	}
+	return data, nil
+}
@@ -6,11 +6,22 @@ import (
 	"strings"
 
 	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/dnslint"
+
+	"github.com/cloudflare/cloudflare-go"
 )
 
+const setDNSUsage = "用法: /setdns <domain.com> <type> <name> <content> [proxied:yes/no] [mode:update/add/replace]\n" +
+	"示例: /setdns example.com A @ 192.0.2.1 yes\n" +
+	"模式说明：\n" +
+	"- update（默认）：更新内容相同或唯一的一条同名记录\n" +
+	"- add：追加一条记录（轮询 A、多条 MX/TXT）\n" +
+	"- replace：替换所有同名同类型记录，执行前需确认"
+
 func (h *CommandHandler) handleSetDNSCommand(args []string) {
 	if len(args) < 4 {
-		h.sendText("用法: /setdns <domain.com> <type> <name> <content> [proxied:yes/no]\n示例: /setdns example.com A @ 192.0.2.1 yes")
+		h.sendText(setDNSUsage)
 		return
 	}
 
@@ -23,11 +34,18 @@ func (h *CommandHandler) handleSetDNSCommand(args []string) {
 		Content: args[3],
 		Proxied: false,
 		TTL:     3600, // 固定默认 3600
+		Mode:    cfclient.DNSWriteUpdate,
 	}
 
-	// 可选 proxied
-	if len(args) >= 5 {
-		v := strings.ToLower(strings.TrimSpace(args[4]))
+	// 可选 proxied / mode，顺序不限
+	for _, raw := range args[4:] {
+		v := strings.ToLower(strings.TrimSpace(raw))
+		v = strings.TrimPrefix(v, "mode:")
+		v = strings.TrimPrefix(v, "mode=")
+		if mode, ok := cfclient.ParseDNSWriteMode(v); ok && v != "" {
+			params.Mode = mode
+			continue
+		}
 		params.Proxied = v == "yes" || v == "true" || v == "1"
 	}
 
@@ -37,19 +55,85 @@ func (h *CommandHandler) handleSetDNSCommand(args []string) {
 		return
 	}
 
-	// 1) upsert 主记录
-	record, err := h.CFClient.UpsertDNSRecord(context.Background(), *account, domain, params)
+	// 写入前预检：replace 模式列出将被删除的记录，命中检查规则时给出警告，二者都需确认
+	records, err := h.CFClient.ListDNSRecords(context.Background(), *account, domain)
+	if err != nil {
+		h.sendText(fmt.Sprintf("查询现有解析记录失败: %v", err))
+		return
+	}
+	plan, err := cfclient.PlanDNSWrite(cfclient.FilterSameNameType(records, domain, params), params)
 	if err != nil {
 		h.sendText(fmt.Sprintf("设置 DNS 记录失败: %v", err))
 		return
 	}
+	var removed []cloudflare.DNSRecord
+	if params.Mode == cfclient.DNSWriteReplace {
+		removed = plan.Delete
+	}
+	warnings := lintSetDNS(account.Label, domain, records, params, plan)
+	if len(removed) > 0 || len(warnings) > 0 {
+		h.sendSetDNSConfirm(*account, domain, params, removed, warnings)
+		return
+	}
+
+	ApplySetDNS(context.Background(), h.CFClient, h.Sender, *account, domain, params)
+}
+
+func (h *CommandHandler) sendSetDNSConfirm(account config.CF, domain string, params cfclient.DNSRecordParams, removed []cloudflare.DNSRecord, warnings []dnslint.Issue) {
+	var sb strings.Builder
+	if len(removed) > 0 {
+		sb.WriteString("⚠️【替换解析二次确认】\n")
+	} else {
+		sb.WriteString("⚠️【解析检查警告】\n")
+	}
+	sb.WriteString(fmt.Sprintf("操作人: %s\n账号: %s\nZone: %s\n", formatOperator(h.operator), account.Label, domain))
+	sb.WriteString(fmt.Sprintf("\n写入: %s %s → %s\n", params.Type, cfclient.RecordFQDN(params.Name, domain), params.Content))
+	if len(removed) > 0 {
+		sb.WriteString(fmt.Sprintf("\n以下 %d 条同名记录将被删除：\n", len(removed)))
+		for _, r := range removed {
+			sb.WriteString(fmt.Sprintf("- %s %s → %s (TTL: %d)\n", r.Type, r.Name, r.Content, r.TTL))
+		}
+	}
+	if len(warnings) > 0 {
+		sb.WriteString(fmt.Sprintf("\n写入后将触发 %d 条检查规则：\n", len(warnings)))
+		for _, w := range warnings {
+			sb.WriteString(fmt.Sprintf("- %s [%s] %s\n", w.Severity.Icon(), w.Rule, w.Message))
+		}
+	}
+	sb.WriteString("\n确认仍要写入吗？")
+
+	token := SetSetDNSPayload(SetDNSPayload{
+		AccountLabel: account.Label,
+		Domain:       domain,
+		Params:       params,
+	})
+	buttons := [][]Button{{
+		{Text: "✅ 确认写入", CallbackData: fmt.Sprintf("setdns_confirm|%s", token)},
+		{Text: "❌ 取消", CallbackData: fmt.Sprintf("setdns_cancel|%s", token)},
+	}}
+	if err := h.Sender.SendWithButtons(context.Background(), sb.String(), buttons); err != nil {
+		h.sendText(fmt.Sprintf("发送确认消息失败: %v", err))
+	}
+}
+
+// ApplySetDNS 写入解析记录并回执；根域(@)会顺带把 www CNAME 到根域。
+// 命令与按钮回调共用。
+func ApplySetDNS(ctx context.Context, client cfclient.Client, sender Sender, account config.CF, domain string, params cfclient.DNSRecordParams) {
+	send := func(msg string) { _ = sender.Send(ctx, msg) }
+
+	// 1) upsert 主记录
+	record, err := client.UpsertDNSRecord(ctx, account, domain, params)
+	if err != nil {
+		send(fmt.Sprintf("设置 DNS 记录失败: %v", err))
+		return
+	}
 
 	proxyStatus := "否"
 	if record.Proxied != nil && *record.Proxied {
 		proxyStatus = "是"
 	}
-	h.sendText(fmt.Sprintf("已在账号 %s 设置记录: %s %s → %s (代理:%s, TTL:%d)",
-		account.Label, record.Type, record.Name, record.Content, proxyStatus, params.TTL,
+	send(fmt.Sprintf("已在账号 %s 设置记录(%s): %s %s → %s (代理:%s, TTL:%d)",
+		account.Label, params.Mode, record.Type, record.Name, record.Content, proxyStatus, params.TTL,
 	))
 
 	// 2) 如果用户设置的是根域(@)，顺便把 www 也解析掉：www.<domain> CNAME <domain>
@@ -60,11 +144,12 @@ func (h *CommandHandler) handleSetDNSCommand(args []string) {
 			Content: domain,         // 指向根域 domain.com
 			Proxied: params.Proxied, // 跟随用户 proxied（你也可改成固定值）
 			TTL:     3600,
+			Mode:    cfclient.DNSWriteUpdate,
 		}
 
-		wwwRecord, wwwErr := h.CFClient.UpsertDNSRecord(context.Background(), *account, domain, wwwParams)
+		wwwRecord, wwwErr := client.UpsertDNSRecord(ctx, account, domain, wwwParams)
 		if wwwErr != nil {
-			h.sendText(fmt.Sprintf("已设置根域记录，但设置 www CNAME 失败: %v", wwwErr))
+			send(fmt.Sprintf("已设置根域记录，但设置 www CNAME 失败: %v", wwwErr))
 			return
 		}
 
@@ -72,7 +157,7 @@ func (h *CommandHandler) handleSetDNSCommand(args []string) {
 		if wwwRecord.Proxied != nil && *wwwRecord.Proxied {
 			wwwProxyStatus = "是"
 		}
-		h.sendText(fmt.Sprintf("已自动设置 www 记录: %s %s → %s (代理:%s, TTL:%d)",
+		send(fmt.Sprintf("已自动设置 www 记录: %s %s → %s (代理:%s, TTL:%d)",
 			wwwRecord.Type, wwwRecord.Name, wwwRecord.Content, wwwProxyStatus, wwwParams.TTL,
 		))
 	}
@@ -0,0 +1,40 @@
+package telegram
+
+import (
+	"sync"
+
+	"DomainC/cfclient"
+)
+
+// SetDNSPayload 保存等待确认的 /setdns 写入请求
+type SetDNSPayload struct {
+	AccountLabel string
+	Domain       string
+	Params       cfclient.DNSRecordParams
+}
+
+var setDNSState = struct {
+	mu       sync.Mutex
+	payloads map[string]SetDNSPayload
+}{
+	payloads: make(map[string]SetDNSPayload),
+}
+
+func SetSetDNSPayload(payload SetDNSPayload) string {
+	token := newIPListToken()
+	setDNSState.mu.Lock()
+	defer setDNSState.mu.Unlock()
+	setDNSState.payloads[token] = payload
+	return token
+}
+
+// TakeSetDNSPayload 取出并删除 payload，避免重复点击导致重复写入
+func TakeSetDNSPayload(token string) (SetDNSPayload, bool) {
+	setDNSState.mu.Lock()
+	defer setDNSState.mu.Unlock()
+	payload, ok := setDNSState.payloads[token]
+	if ok {
+		delete(setDNSState.payloads, token)
+	}
+	return payload, ok
+}
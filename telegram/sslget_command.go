@@ -0,0 +1,142 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"DomainC/certbundle"
+	"DomainC/certvault"
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+const sslGetUsage = "用法: /sslget <引用ID> [key|fullchain|p12|k8s] [ns=命名空间]\n把 vault 中保存的源站证书私钥（或含私钥的 PKCS#12、Kubernetes Secret）私聊发送给你（需在 certVault.allowedUsers 中，且已私聊过机器人），到时自动删除。不带参数时列出最近保存的证书。"
+
+// sslGetListLimit 不带参数时列出的条目数
+const sslGetListLimit = 10
+
+var certVault *certvault.Vault
+
+// SetCertVault 设置保存源站证书私钥的 vault，为空时 /ssl 仍把私钥文件发到群里
+func SetCertVault(v *certvault.Vault) {
+	certVault = v
+}
+
+// CertVault 返回当前的 vault，未启用时为 nil
+func CertVault() *certvault.Vault {
+	return certVault
+}
+
+func (h *CommandHandler) handleSSLGetCommand(args []string) {
+	v := CertVault()
+	if v == nil {
+		h.sendText("未启用证书 vault（certVault.enabled），私钥不会被保存。")
+		return
+	}
+	if len(args) < 1 {
+		h.listVaultEntries(v)
+		return
+	}
+
+	op := h.operator
+	if op == nil || !slices.Contains(config.Cfg.CertVault.AllowedUsers, op.ID) {
+		id := int64(0)
+		if op != nil {
+			id = op.ID
+		}
+		h.sendText(fmt.Sprintf("无权获取私钥：请管理员把你的 Telegram 用户 ID（%d）加入 certVault.allowedUsers。", id))
+		return
+	}
+
+	entry, err := v.Get(args[0])
+	if err != nil {
+		if errors.Is(err, certvault.ErrNotFound) {
+			h.sendText(fmt.Sprintf("未找到 vault 条目 %s。\n\n%s", args[0], sslGetUsage))
+			return
+		}
+		h.sendText(fmt.Sprintf("读取 vault 失败: %v", err))
+		return
+	}
+
+	format, namespace := "key", ""
+	for _, a := range args[1:] {
+		if ns, ok := strings.CutPrefix(a, "ns="); ok {
+			namespace = ns
+			continue
+		}
+		format = strings.ToLower(strings.TrimSpace(a))
+	}
+
+	minutes := config.Cfg.CertVault.DeleteAfterMinutes
+	if minutes <= 0 {
+		minutes = 10
+	}
+	filename := sanitizeFilename(fmt.Sprintf("origin-ca-%s-%s-key.pem", entry.Zone, entry.ID))
+	data := []byte(pemBlock("PRIVATE KEY", entry.PrivateKeyPEM))
+	title := "源站证书私钥"
+	if format != "key" {
+		formats, err := certbundle.ParseFormats(format)
+		if err != nil || len(formats) != 1 {
+			h.sendText(fmt.Sprintf("格式不合法：%s\n\n%s", format, sslGetUsage))
+			return
+		}
+		cert := cfclient.OriginCert{ID: entry.CertID, CertificatePEM: entry.CertificatePEM, PrivateKeyPEM: entry.PrivateKeyPEM}
+		f, err := certbundle.Build(formats[0], cert, originRoots(), certbundle.Options{Name: entry.Zone, Namespace: namespace})
+		if err != nil {
+			h.sendText(fmt.Sprintf("导出 %s 失败: %v", formats[0], err))
+			return
+		}
+		filename, data, title = sanitizeFilename(f.Name), f.Data, bundleCaption(f)
+	}
+	path, err := writeTempAndMove(filename, data, 0600)
+	if err != nil {
+		h.sendText(fmt.Sprintf("写入私钥文件失败: %v", err))
+		return
+	}
+	defer os.Remove(path)
+
+	ctx := context.Background()
+	caption := fmt.Sprintf("🔐 %s %s（%s）\n%d 分钟后自动删除，请及时保存。", entry.Zone, title, entry.ID, minutes)
+	msgID, err := h.Sender.SendDocumentTo(ctx, op.ID, path, caption)
+	if err != nil {
+		h.sendText(fmt.Sprintf("私聊发送失败，请先私聊机器人发送 /start 后重试: %v", err))
+		return
+	}
+	sender := h.Sender
+	time.AfterFunc(time.Duration(minutes)*time.Minute, func() {
+		if err := sender.DeleteMessage(context.Background(), op.ID, msgID); err != nil {
+			_ = sender.Send(context.Background(), fmt.Sprintf("⚠️ 自动删除 %s 私聊中的私钥消息失败，请手动删除: %v", formatOperator(op), err))
+		}
+	})
+	h.sendText(fmt.Sprintf("🔐 已私聊 %s 发送 %s（%s）的 %s，%d 分钟后自动删除。", formatOperator(op), entry.ID, entry.Zone, format, minutes))
+}
+
+func (h *CommandHandler) listVaultEntries(v *certvault.Vault) {
+	entries, errs := v.List()
+	var lines []string
+	for i, e := range entries {
+		if i == sslGetListLimit {
+			break
+		}
+		by := ""
+		if e.CreatedBy != "" {
+			by = "，签发人 " + e.CreatedBy
+		}
+		lines = append(lines, fmt.Sprintf("🔒 %s  %s (%s)\n   %s\n   保存于 %s%s，到期 %s",
+			e.ID, e.Zone, e.Account, strings.Join(e.Hostnames, ", "), e.CreatedAt.Format("2006-01-02 15:04"), by, e.ExpiresOn.Format("2006-01-02")))
+	}
+	for _, err := range errs {
+		lines = append(lines, "❌ "+err.Error())
+	}
+	header := fmt.Sprintf("%s\n\nvault 中共 %d 张证书，最近 %d 张：", sslGetUsage, len(entries), min(len(entries), sslGetListLimit))
+	if len(lines) == 0 {
+		h.sendText(sslGetUsage + "\n\nvault 中还没有证书。")
+		return
+	}
+	h.sendText(header + "\n" + strings.Join(lines, "\n"))
+}
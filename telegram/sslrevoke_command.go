@@ -0,0 +1,145 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/origincert"
+	"DomainC/originrotate"
+)
+
+const sslRevokeUsage = "用法: /sslrevoke <证书ID|zone>\n吊销 Origin CA 源站证书（可用 /certs 查看证书 ID）。按 zone 吊销时该 Zone 下必须只有一张有效证书。"
+
+func (h *CommandHandler) handleSSLRevokeCommand(args []string) {
+	if len(args) < 1 {
+		h.sendText(sslRevokeUsage)
+		return
+	}
+	arg := strings.TrimSpace(args[0])
+	ctx := context.Background()
+
+	var cert origincert.Cert
+	var err error
+	if strings.Contains(arg, ".") {
+		cert, err = h.findZoneOriginCert(ctx, arg)
+	} else {
+		cert, err = h.findOriginCertByID(ctx, arg)
+	}
+	if err != nil {
+		h.sendText(fmt.Sprintf("%v\n\n%s", err, sslRevokeUsage))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("⚠️ 确认吊销以下源站证书？吊销后无法恢复，仍在使用该证书的源站在 Full (Strict) 模式下会返回 526。\n\n")
+	sb.WriteString(formatOriginCert(cert, time.Now(), nil))
+	sb.WriteString("\nID: " + cert.ID)
+	if len(config.Cfg.AWSTargets) > 0 {
+		used, err := findACMUsage(ctx, cert.CertificatePEM)
+		switch {
+		case err != nil:
+			sb.WriteString(fmt.Sprintf("\n\n查询 ACM 使用情况失败: %v", err))
+		case len(used) > 0:
+			sb.WriteString("\n\n❗ 该证书仍导入在以下 ACM 中，建议改用 /sslrotate 轮换：")
+			for _, u := range used {
+				sb.WriteString("\n- " + u.String())
+			}
+		default:
+			sb.WriteString("\n\nACM 中未发现使用该证书。")
+		}
+	}
+
+	token := SetSSLRevokePayload(SSLRevokePayload{
+		Operator:     formatOperator(h.operator),
+		AccountLabel: cert.Account,
+		Zone:         cert.Zone,
+		CertID:       cert.ID,
+	})
+	buttons := [][]Button{{
+		{Text: "🗑 确认吊销", CallbackData: fmt.Sprintf("sslrevoke_confirm|%s", token)},
+		{Text: "❌ 取消", CallbackData: fmt.Sprintf("sslrevoke_cancel|%s", token)},
+	}}
+	if err := h.Sender.SendWithButtons(ctx, sb.String(), buttons); err != nil {
+		h.sendText(fmt.Sprintf("发送确认消息失败: %v", err))
+	}
+}
+
+// findZoneOriginCert 返回 Zone 下唯一的有效证书
+func (h *CommandHandler) findZoneOriginCert(ctx context.Context, domain string) (origincert.Cert, error) {
+	acc, zone, err := h.findZone(domain)
+	if err != nil {
+		if errors.Is(err, cfclient.ErrZoneNotFound) {
+			return origincert.Cert{}, fmt.Errorf("未在任何账号下找到 %s", domain)
+		}
+		return origincert.Cert{}, err
+	}
+	certs, errs := origincert.Collect(ctx, h.CFClient, []config.CF{*acc}, zone.Name)
+	if len(errs) > 0 {
+		return origincert.Cert{}, errs[0]
+	}
+	now := time.Now()
+	var active []origincert.Cert
+	for _, c := range certs {
+		if c.Active(now) {
+			active = append(active, c)
+		}
+	}
+	switch len(active) {
+	case 0:
+		return origincert.Cert{}, fmt.Errorf("%s 下没有有效的源站证书", zone.Name)
+	case 1:
+		return active[0], nil
+	}
+	var ids []string
+	for _, c := range active {
+		ids = append(ids, fmt.Sprintf("%s（%s，到期 %s）", c.ID, strings.Join(c.Hostnames, ", "), c.ExpiresOn.Format("2006-01-02")))
+	}
+	return origincert.Cert{}, fmt.Errorf("%s 下有 %d 张有效证书，请指定证书 ID：\n%s", zone.Name, len(active), strings.Join(ids, "\n"))
+}
+
+// findOriginCertByID 在全部账号中按 ID（或至少 8 位前缀）查找证书
+func (h *CommandHandler) findOriginCertByID(ctx context.Context, id string) (origincert.Cert, error) {
+	if len(id) < 8 {
+		return origincert.Cert{}, fmt.Errorf("证书 ID 至少需要 8 位")
+	}
+	certs, errs := origincert.Collect(ctx, h.CFClient, h.Accounts, "")
+	var matched []origincert.Cert
+	for _, c := range certs {
+		if strings.HasPrefix(c.ID, id) {
+			matched = append(matched, c)
+		}
+	}
+	switch {
+	case len(matched) == 1:
+		return matched[0], nil
+	case len(matched) > 1:
+		return origincert.Cert{}, fmt.Errorf("证书 ID %s 匹配到 %d 张证书，请输入更长的 ID", id, len(matched))
+	case len(errs) > 0:
+		return origincert.Cert{}, fmt.Errorf("未找到证书 %s（部分 Zone 读取失败: %v）", id, errs[0])
+	}
+	return origincert.Cert{}, fmt.Errorf("未找到证书 %s", id)
+}
+
+// findACMUsage 查找导入了该证书的 ACM ARN
+func findACMUsage(ctx context.Context, certPEM string) ([]originrotate.ACMCert, error) {
+	serial, err := originrotate.CertSerial(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	acmClient := &originrotate.AWSACM{Targets: config.Cfg.AWSTargets}
+	return acmClient.FindBySerial(ctx, serial)
+}
+
+// RevokeOriginCert 吊销源站证书并回执结果。由按钮回调调用。
+func RevokeOriginCert(ctx context.Context, cf cfclient.Client, sender Sender, account config.CF, payload SSLRevokePayload) {
+	if err := cf.RevokeOriginCACertificate(ctx, account, payload.CertID); err != nil {
+		_ = sender.Send(ctx, fmt.Sprintf("❌ %v", err))
+		return
+	}
+	_ = sender.Send(ctx, fmt.Sprintf("✅ 已吊销源站证书 %s（%s / %s，操作人: %s）", payload.CertID, payload.Zone, account.Label, payload.Operator))
+}
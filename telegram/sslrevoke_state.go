@@ -0,0 +1,37 @@
+package telegram
+
+import "sync"
+
+// SSLRevokePayload 保存等待确认吊销的源站证书
+type SSLRevokePayload struct {
+	Operator     string
+	AccountLabel string
+	Zone         string
+	CertID       string
+}
+
+var sslRevokeState = struct {
+	mu       sync.Mutex
+	payloads map[string]SSLRevokePayload
+}{
+	payloads: make(map[string]SSLRevokePayload),
+}
+
+func SetSSLRevokePayload(payload SSLRevokePayload) string {
+	token := newIPListToken()
+	sslRevokeState.mu.Lock()
+	defer sslRevokeState.mu.Unlock()
+	sslRevokeState.payloads[token] = payload
+	return token
+}
+
+// TakeSSLRevokePayload 取出并删除待确认的吊销，保证只执行一次
+func TakeSSLRevokePayload(token string) (SSLRevokePayload, bool) {
+	sslRevokeState.mu.Lock()
+	defer sslRevokeState.mu.Unlock()
+	payload, ok := sslRevokeState.payloads[token]
+	if ok {
+		delete(sslRevokeState.payloads, token)
+	}
+	return payload, ok
+}
@@ -0,0 +1,119 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/originrotate"
+)
+
+const sslRotateUsage = "用法: /sslrotate <zone> [证书ID]\n签发新的源站证书（主机名与旧证书相同），重新导入到旧证书所在的 ACM ARN，全部成功后吊销旧证书。Zone 下有多张有效证书时需指定证书 ID。"
+
+func (h *CommandHandler) handleSSLRotateCommand(args []string) {
+	if len(args) < 1 {
+		h.sendText(sslRotateUsage)
+		return
+	}
+	certID := ""
+	if len(args) > 1 {
+		certID = strings.TrimSpace(args[1])
+	}
+
+	acc, zone, err := h.findZone(args[0])
+	if err != nil {
+		if errors.Is(err, cfclient.ErrZoneNotFound) {
+			h.sendText(fmt.Sprintf("未在任何账号下找到 %s。", args[0]))
+			return
+		}
+		h.sendText(fmt.Sprintf("查询 Zone 失败: %v", err))
+		return
+	}
+
+	ctx := context.Background()
+	if len(config.Cfg.AWSTargets) > 0 {
+		h.sendText("正在查找导入了旧证书的 ACM ARN...")
+	}
+	plan, err := newOriginRotator(h.CFClient).Prepare(ctx, *acc, zone, certID)
+	if err != nil {
+		h.sendText(fmt.Sprintf("无法轮换: %v", err))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔁【源站证书轮换】%s (%s)\n", plan.Zone, acc.Label))
+	sb.WriteString(fmt.Sprintf("旧证书: %s\n主机名: %s\n到期: %s\n\n", plan.Old.ID, strings.Join(plan.Old.Hostnames, ", "), plan.Old.ExpiresOn.Format("2006-01-02")))
+	sb.WriteString("1. 签发主机名相同的新证书\n")
+	if len(plan.Targets) == 0 {
+		sb.WriteString("2. ACM 中未发现使用旧证书的 ARN，跳过导入\n")
+		sb.WriteString("3. 不自动吊销旧证书：请把新证书部署到源站后再执行 /sslrevoke " + plan.Old.ID + "\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("2. 重新导入到 %d 个 ACM ARN（ARN 不变）：\n", len(plan.Targets)))
+		for _, t := range plan.Targets {
+			sb.WriteString("   - " + t.String() + "\n")
+		}
+		sb.WriteString("3. 全部导入成功后吊销旧证书\n")
+	}
+	sb.WriteString("\n任一步失败都不会吊销旧证书；第一个 ARN 导入失败时会吊销新证书恢复原状。")
+
+	token := SetSSLRotatePayload(SSLRotatePayload{
+		Operator:     formatOperator(h.operator),
+		AccountLabel: acc.Label,
+		Zone:         plan.Zone,
+		Old:          plan.Old,
+		Targets:      plan.Targets,
+	})
+	buttons := [][]Button{{
+		{Text: "🔁 确认轮换", CallbackData: fmt.Sprintf("sslrotate_confirm|%s", token)},
+		{Text: "❌ 取消", CallbackData: fmt.Sprintf("sslrotate_cancel|%s", token)},
+	}}
+	if err := h.Sender.SendWithButtons(ctx, sb.String(), buttons); err != nil {
+		h.sendText(fmt.Sprintf("发送确认消息失败: %v", err))
+	}
+}
+
+func newOriginRotator(cf cfclient.Client) *originrotate.Rotator {
+	r := &originrotate.Rotator{CF: cf}
+	if len(config.Cfg.AWSTargets) > 0 {
+		r.ACM = &originrotate.AWSACM{Targets: config.Cfg.AWSTargets}
+	}
+	return r
+}
+
+// RunSSLRotate 执行证书轮换并回执每一步结果。由按钮回调调用。
+func RunSSLRotate(ctx context.Context, cf cfclient.Client, sender Sender, account config.CF, payload SSLRotatePayload) {
+	plan := originrotate.Plan{Account: account, Zone: payload.Zone, Old: payload.Old, Targets: payload.Targets}
+	notify := func(msg string) { _ = sender.Send(ctx, fmt.Sprintf("[%s] %s", payload.Zone, msg)) }
+
+	res, err := newOriginRotator(cf).Rotate(ctx, plan, notify)
+	if res.New.ID != "" {
+		if ferr := SendOriginCertFiles(ctx, sender, account.Label, payload.Zone, payload.Operator, res.New); ferr != nil {
+			_ = sender.Send(ctx, fmt.Sprintf("⚠️ 新证书 %s 已签发，但发送文件失败: %v", res.New.ID, ferr))
+		}
+	}
+	if err != nil {
+		_ = sender.Send(ctx, fmt.Sprintf("❌ 轮换 %s 未完成（操作人: %s）: %v", payload.Zone, payload.Operator, err))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("✅ 已轮换 %s 的源站证书（操作人: %s）\n新证书: %s\n", payload.Zone, payload.Operator, res.New.ID))
+	if len(res.Imported) > 0 {
+		sb.WriteString(fmt.Sprintf("已重新导入 %d 个 ACM ARN\n", len(res.Imported)))
+	}
+	for _, terr := range res.TagErrs {
+		sb.WriteString(fmt.Sprintf("⚠️ %v\n", terr))
+	}
+	switch {
+	case res.Revoked:
+		sb.WriteString("旧证书已吊销: " + payload.Old.ID)
+	case res.RevokeErr != nil:
+		sb.WriteString(fmt.Sprintf("⚠️ 吊销旧证书失败（新旧证书均有效），请稍后执行 /sslrevoke %s: %v", payload.Old.ID, res.RevokeErr))
+	default:
+		sb.WriteString(fmt.Sprintf("旧证书未吊销，新证书部署到源站后请执行 /sslrevoke %s", payload.Old.ID))
+	}
+	_ = sender.Send(ctx, sb.String())
+}
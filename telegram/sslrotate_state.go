@@ -0,0 +1,43 @@
+package telegram
+
+import (
+	"sync"
+
+	"DomainC/cfclient"
+	"DomainC/originrotate"
+)
+
+// SSLRotatePayload 保存等待确认的证书轮换计划
+type SSLRotatePayload struct {
+	Operator     string
+	AccountLabel string
+	Zone         string
+	Old          cfclient.OriginCACertInfo
+	Targets      []originrotate.ACMCert
+}
+
+var sslRotateState = struct {
+	mu       sync.Mutex
+	payloads map[string]SSLRotatePayload
+}{
+	payloads: make(map[string]SSLRotatePayload),
+}
+
+func SetSSLRotatePayload(payload SSLRotatePayload) string {
+	token := newIPListToken()
+	sslRotateState.mu.Lock()
+	defer sslRotateState.mu.Unlock()
+	sslRotateState.payloads[token] = payload
+	return token
+}
+
+// TakeSSLRotatePayload 取出并删除待确认的轮换，保证只执行一次
+func TakeSSLRotatePayload(token string) (SSLRotatePayload, bool) {
+	sslRotateState.mu.Lock()
+	defer sslRotateState.mu.Unlock()
+	payload, ok := sslRotateState.payloads[token]
+	if ok {
+		delete(sslRotateState.payloads, token)
+	}
+	return payload, ok
+}
@@ -0,0 +1,57 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"DomainC/config"
+	"DomainC/takeover"
+)
+
+const takeoverUsage = "用法: /takeover [账号标签|all]\n扫描 CNAME 目标与 A 记录，找出指向已删除第三方资源（S3、Heroku、GitHub Pages 等）的悬空记录。"
+
+func (h *CommandHandler) handleTakeoverCommand(args []string) {
+	selector := "all"
+	if len(args) > 0 {
+		selector = strings.TrimSpace(args[0])
+	}
+	var targets []config.CF
+	if strings.EqualFold(selector, "all") {
+		targets = append(targets, h.Accounts...)
+	} else if acc := h.getAccountByLabel(selector); acc != nil {
+		targets = []config.CF{*acc}
+	} else {
+		h.sendText(fmt.Sprintf("未找到账号 %s。\n\n%s", selector, takeoverUsage))
+		return
+	}
+
+	cfg := config.Cfg.Takeover
+	fps, err := takeover.FromConfig(cfg.Fingerprints, !cfg.NoDefaults)
+	if err != nil {
+		h.sendText(fmt.Sprintf("takeover.fingerprints 配置错误: %v", err))
+		return
+	}
+	h.sendText(fmt.Sprintf("正在扫描 %s 的解析记录（%d 个服务商特征），请耐心等待...", selector, len(fps)))
+
+	ctx := context.Background()
+	scanner := &takeover.Scanner{CF: h.CFClient, Fingerprints: fps}
+	findings, errs := scanner.Scan(ctx, targets)
+
+	lines := make([]string, 0, len(findings))
+	for _, f := range findings {
+		lines = append(lines, fmt.Sprintf("%s\n   账号 %s / Zone %s", f.String(), f.Account, f.Zone))
+	}
+	if len(lines) == 0 {
+		h.sendText("✅ 未发现疑似悬空的记录。")
+	} else {
+		_ = h.Sender.Send(ctx, fmt.Sprintf("🚨【子域名接管扫描】发现 %d 条疑似悬空记录：\n", len(lines))+strings.Join(lines, "\n"))
+	}
+	if len(errs) > 0 {
+		var msgs []string
+		for _, err := range errs {
+			msgs = append(msgs, err.Error())
+		}
+		_ = h.Sender.Send(ctx, "以下账号或 Zone 无法扫描：\n"+strings.Join(msgs, "\n"))
+	}
+}
@@ -0,0 +1,75 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"DomainC/config"
+	"DomainC/iacexport"
+)
+
+const tfExportUsage = "用法: /tfexport <账号标签|all>\n导出 Terraform（cloudflare_zone / cloudflare_record / cloudflare_list + import.sh）与 DNSControl dnsconfig.js，打包为 zip 发送。"
+
+func (h *CommandHandler) handleTFExportCommand(args []string) {
+	if len(args) < 1 {
+		h.sendText(tfExportUsage)
+		return
+	}
+	selector := strings.TrimSpace(args[0])
+
+	var targets []config.CF
+	if strings.EqualFold(selector, "all") {
+		targets = append(targets, h.Accounts...)
+	} else if acc := h.getAccountByLabel(selector); acc != nil {
+		targets = []config.CF{*acc}
+	} else {
+		h.sendText(fmt.Sprintf("未找到账号 %s。\n\n%s", selector, tfExportUsage))
+		return
+	}
+	if len(targets) == 0 {
+		h.sendText("未配置可用的 Cloudflare 账号，无法导出。")
+		return
+	}
+	h.sendText("正在遍历账号的 Zone、解析记录与自定义列表，过程较慢，请耐心等待...")
+
+	ctx := context.Background()
+	inv, err := iacexport.Collect(ctx, h.CFClient, targets)
+	if err != nil {
+		h.sendText(fmt.Sprintf("导出失败: %v", err))
+		return
+	}
+	files, err := iacexport.Generate(inv)
+	if err != nil {
+		h.sendText(fmt.Sprintf("导出失败: %v", err))
+		return
+	}
+	buf := &bytes.Buffer{}
+	if err := files.WriteZip(buf); err != nil {
+		h.sendText(fmt.Sprintf("打包失败: %v", err))
+		return
+	}
+
+	filename := fmt.Sprintf("iac-export-%s-%s.zip", sanitizeFilename(selector), time.Now().Format("20060102-150405"))
+	path := filepath.Join(os.TempDir(), filename)
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		h.sendText(fmt.Sprintf("写入临时文件失败: %v", err))
+		return
+	}
+	defer os.Remove(path)
+
+	zones := 0
+	for _, acc := range inv.Accounts {
+		zones += len(acc.Zones)
+	}
+	caption := fmt.Sprintf("🏗 IaC 导出（%d 个账号，%d 个 Zone）", len(inv.Accounts), zones)
+	if err := h.Sender.SendDocumentPath(ctx, path, caption); err != nil {
+		h.sendText(fmt.Sprintf("发送导出文件失败: %v", err))
+		return
+	}
+	h.sendText(fmt.Sprintf("✅ 导出完成：%s", filename))
+}
@@ -0,0 +1,102 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+	"DomainC/zoneaudit"
+)
+
+const zoneAuditUsage = "用法: /zoneaudit [账号标签|all]\n按 zoneAudit.baseline 检查各 Zone 的设置，列出偏离基线的 Zone，可一键应用基线。"
+
+func (h *CommandHandler) handleZoneAuditCommand(args []string) {
+	scope := "all"
+	if len(args) > 0 {
+		scope = strings.TrimSpace(args[0])
+	}
+	targets := h.Accounts
+	if !strings.EqualFold(scope, "all") {
+		acc := h.getAccountByLabel(scope)
+		if acc == nil {
+			h.sendText(fmt.Sprintf("未找到账号 %s。\n\n%s", scope, zoneAuditUsage))
+			return
+		}
+		targets = []config.CF{*acc}
+	}
+	if len(targets) == 0 {
+		h.sendText("未配置可用的 Cloudflare 账号。")
+		return
+	}
+	baseline, err := zoneaudit.Baseline(config.Cfg.ZoneAudit.Baseline)
+	if err != nil {
+		h.sendText(fmt.Sprintf("zoneAudit.baseline 配置错误: %v", err))
+		return
+	}
+
+	h.sendText("正在读取各 Zone 的设置，请稍候...")
+	ctx := context.Background()
+	results, checked, errs := zoneaudit.Audit(ctx, h.CFClient, targets, baseline, config.Cfg.ZoneAudit.Exclude)
+
+	var want []string
+	for _, id := range cfclient.ZoneSettingIDs(baseline) {
+		want = append(want, id+"="+baseline[id])
+	}
+	header := fmt.Sprintf("🧭【Zone 设置审计】%s\n基线: %s\n共检查 %d 个 Zone，偏离基线 %d 个",
+		scope, strings.Join(want, ", "), checked, len(results))
+
+	var lines []string
+	for _, r := range results {
+		var devs []string
+		for _, d := range r.Deviations {
+			devs = append(devs, "   "+d.String())
+		}
+		lines = append(lines, fmt.Sprintf("⚠️ %s (%s)\n%s", r.Zone, r.Account, strings.Join(devs, "\n")))
+	}
+	for _, err := range errs {
+		lines = append(lines, "❌ "+err.Error())
+	}
+	if len(lines) == 0 {
+		h.sendText(header + "\n✅ 全部 Zone 符合基线。")
+		return
+	}
+	_ = h.Sender.Send(ctx, header+"\n"+strings.Join(lines, "\n"))
+	if len(results) == 0 {
+		return
+	}
+
+	token := SetZoneAuditPayload(ZoneAuditPayload{Operator: formatOperator(h.operator), Scope: scope, Results: results})
+	buttons := [][]Button{{
+		{Text: fmt.Sprintf("✅ 应用基线（%d 个 Zone）", len(results)), CallbackData: fmt.Sprintf("zoneaudit_apply|%s", token)},
+		{Text: "❌ 取消", CallbackData: fmt.Sprintf("zoneaudit_cancel|%s", token)},
+	}}
+	if err := h.Sender.SendWithButtons(ctx, "是否把以上 Zone 的偏离项改为基线值？", buttons); err != nil {
+		h.sendText(fmt.Sprintf("发送确认消息失败: %v", err))
+	}
+}
+
+// ApplyZoneBaseline 把审计结果中的偏离项改为基线值并回执。由按钮回调调用。
+func ApplyZoneBaseline(ctx context.Context, cf cfclient.Client, sender Sender, payload ZoneAuditPayload) {
+	total := 0
+	var failed []string
+	for _, r := range payload.Results {
+		account := cfclient.GetAccountByLabel(r.Account)
+		if account == nil {
+			failed = append(failed, fmt.Sprintf("%s: 未找到账号 %s", r.Zone, r.Account))
+			continue
+		}
+		applied, errs := zoneaudit.Apply(ctx, cf, *account, r)
+		total += applied
+		for _, err := range errs {
+			failed = append(failed, err.Error())
+		}
+	}
+	msg := fmt.Sprintf("✅ 已应用基线（%s，操作人: %s）：修改 %d 项设置", payload.Scope, payload.Operator, total)
+	if len(failed) > 0 {
+		_ = sender.Send(ctx, msg+fmt.Sprintf("，失败 %d 项：\n", len(failed))+strings.Join(failed, "\n"))
+		return
+	}
+	_ = sender.Send(ctx, msg)
+}
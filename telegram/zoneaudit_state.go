@@ -0,0 +1,40 @@
+package telegram
+
+import (
+	"sync"
+
+	"DomainC/zoneaudit"
+)
+
+// ZoneAuditPayload 保存等待确认的基线应用
+type ZoneAuditPayload struct {
+	Operator string
+	Scope    string
+	Results  []zoneaudit.Result
+}
+
+var zoneAuditState = struct {
+	mu       sync.Mutex
+	payloads map[string]ZoneAuditPayload
+}{
+	payloads: make(map[string]ZoneAuditPayload),
+}
+
+func SetZoneAuditPayload(payload ZoneAuditPayload) string {
+	token := newIPListToken()
+	zoneAuditState.mu.Lock()
+	defer zoneAuditState.mu.Unlock()
+	zoneAuditState.payloads[token] = payload
+	return token
+}
+
+// TakeZoneAuditPayload 取出并删除待确认的基线应用，保证只执行一次
+func TakeZoneAuditPayload(token string) (ZoneAuditPayload, bool) {
+	zoneAuditState.mu.Lock()
+	defer zoneAuditState.mu.Unlock()
+	payload, ok := zoneAuditState.payloads[token]
+	if ok {
+		delete(zoneAuditState.payloads, token)
+	}
+	return payload, ok
+}
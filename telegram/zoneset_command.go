@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"DomainC/cfclient"
+)
+
+const zoneSetUsage = "用法: /zoneset <zone> [设置] [值]\n不带设置时列出当前取值；只带设置时显示当前值与可选值。\n例如: /zoneset example.com min_tls_version 1.2"
+
+func (h *CommandHandler) handleZoneSetCommand(args []string) {
+	if len(args) < 1 {
+		h.sendText(zoneSetUsage + "\n\n" + zoneSettingList())
+		return
+	}
+	acc, zone, err := h.findZone(args[0])
+	if err != nil {
+		if errors.Is(err, cfclient.ErrZoneNotFound) {
+			h.sendText(fmt.Sprintf("未在任何账号下找到 %s。", args[0]))
+			return
+		}
+		h.sendText(fmt.Sprintf("查询 Zone 失败: %v", err))
+		return
+	}
+
+	ctx := context.Background()
+	current, err := h.CFClient.GetZoneSettings(ctx, *acc, zone.ID)
+	if err != nil {
+		h.sendText(fmt.Sprintf("❌ %v", err))
+		return
+	}
+
+	if len(args) == 1 {
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("⚙️【Zone 设置】%s (%s)\n", zone.Name, acc.Label))
+		for _, spec := range cfclient.ZoneSettingSpecs {
+			value, ok := current[spec.ID]
+			if !ok {
+				value = "(未返回)"
+			}
+			sb.WriteString(fmt.Sprintf("- %s = %s（%s）\n", spec.ID, value, spec.Desc))
+		}
+		h.sendText(sb.String())
+		return
+	}
+
+	spec, ok := cfclient.LookupZoneSetting(args[1])
+	if !ok {
+		h.sendText(fmt.Sprintf("不支持的设置: %s\n\n%s", args[1], zoneSettingList()))
+		return
+	}
+	old, ok := current[spec.ID]
+	if !ok {
+		old = "(未返回)"
+	}
+	if len(args) == 2 {
+		h.sendText(fmt.Sprintf("%s (%s) 的 %s（%s）当前为 %s\n可选值: %s",
+			zone.Name, acc.Label, spec.ID, spec.Desc, old, strings.Join(spec.Values, " / ")))
+		return
+	}
+
+	id, value, err := cfclient.NormalizeZoneSetting(spec.ID, args[2])
+	if err != nil {
+		h.sendText(fmt.Sprintf("参数不合法：%v", err))
+		return
+	}
+	if old == value {
+		h.sendText(fmt.Sprintf("%s 的 %s 已经是 %s，无需修改。", zone.Name, id, value))
+		return
+	}
+	if err := h.CFClient.UpdateZoneSetting(ctx, *acc, zone.ID, id, value); err != nil {
+		h.sendText(fmt.Sprintf("❌ %v", err))
+		return
+	}
+	h.sendText(fmt.Sprintf("✅ 已修改 %s (%s) 的 %s: %s → %s（操作人: %s）", zone.Name, acc.Label, id, old, value, formatOperator(h.operator)))
+}
+
+func zoneSettingList() string {
+	var sb strings.Builder
+	sb.WriteString("支持的设置：\n")
+	for _, spec := range cfclient.ZoneSettingSpecs {
+		sb.WriteString(fmt.Sprintf("- %s（%s）: %s\n", spec.ID, spec.Desc, strings.Join(spec.Values, "/")))
+	}
+	return sb.String()
+}
@@ -0,0 +1,79 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const zoneWatchUsage = "用法:\n/zonewatch  列出正在跟踪激活状态的 Zone\n/zonewatch add <domain>  手动加入跟踪\n/zonewatch rm <domain>  停止跟踪"
+
+func (h *CommandHandler) handleZoneWatchCommand(args []string) {
+	if h.ZoneWatcher == nil {
+		h.sendText("未启用 Zone 激活跟踪（zoneWatch.disabled）。")
+		return
+	}
+	if len(args) == 0 {
+		h.listZoneWatch()
+		return
+	}
+	if len(args) < 2 {
+		h.sendText(zoneWatchUsage)
+		return
+	}
+	domain, err := extractDomainOrHost(args[1])
+	if err != nil {
+		h.sendText(fmt.Sprintf("参数不合法：%v\n%s", err, zoneWatchUsage))
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "add":
+		acc, zone, err := h.findZone(domain)
+		if err != nil {
+			h.sendText(fmt.Sprintf("未在任何账号中找到 %s: %v", domain, err))
+			return
+		}
+		if zone.Status == "active" {
+			h.sendText(fmt.Sprintf("%s 已处于 active 状态，无需跟踪。", zone.Name))
+			return
+		}
+		h.trackZoneActivation(zone.Name, acc.Label, zone.NameServers)
+	case "rm", "remove", "del":
+		if err := h.ZoneWatcher.Untrack(domain); err != nil {
+			h.sendText(fmt.Sprintf("停止跟踪失败 [%s]: %v", domain, err))
+			return
+		}
+		h.sendText(fmt.Sprintf("已停止跟踪 %s。", domain))
+	default:
+		h.sendText(zoneWatchUsage)
+	}
+}
+
+func (h *CommandHandler) listZoneWatch() {
+	entries, err := h.ZoneWatcher.List()
+	if err != nil {
+		h.sendText(fmt.Sprintf("读取跟踪列表失败: %v", err))
+		return
+	}
+	if len(entries) == 0 {
+		h.sendText("当前没有等待激活的 Zone。")
+		return
+	}
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		status := e.LastStatus
+		if status == "" {
+			status = "pending"
+		}
+		mark := ""
+		if e.Escalated {
+			mark = " ⚠️"
+		}
+		lines = append(lines, fmt.Sprintf("%s (%s) %s，已等待 %s，下次检查 %s%s",
+			e.Domain, e.Account, status,
+			time.Since(e.AddedAt).Truncate(time.Minute),
+			e.NextCheck.Local().Format("01-02 15:04"), mark))
+	}
+	h.sendText(fmt.Sprintf("⏳ 等待激活的 Zone（%d 个）：\n", len(entries)) + strings.Join(lines, "\n"))
+}
@@ -0,0 +1,128 @@
+// Package zoneaudit 按基线检查各 Zone 的设置（SSL 模式、HTTPS、TLS 版本等），
+// 列出偏离基线的项并支持一键应用基线。
+package zoneaudit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+// DefaultBaseline 未配置基线时使用
+var DefaultBaseline = map[string]string{
+	"ssl":                      "strict",
+	"always_use_https":         "on",
+	"min_tls_version":          "1.2",
+	"tls_1_3":                  "on",
+	"automatic_https_rewrites": "on",
+	"brotli":                   "on",
+	"security_level":           "medium",
+	"browser_check":            "on",
+}
+
+// Baseline 校验并规范化配置中的基线，为空时返回 DefaultBaseline
+func Baseline(cfg map[string]string) (map[string]string, error) {
+	if len(cfg) == 0 {
+		cfg = DefaultBaseline
+	}
+	out := make(map[string]string, len(cfg))
+	for id, value := range cfg {
+		nid, nvalue, err := cfclient.NormalizeZoneSetting(id, value)
+		if err != nil {
+			return nil, err
+		}
+		out[nid] = nvalue
+	}
+	return out, nil
+}
+
+// Deviation 是一项偏离基线的设置；Got 为空表示 Cloudflare 未返回该设置（套餐不支持等）
+type Deviation struct {
+	Setting string
+	Want    string
+	Got     string
+}
+
+func (d Deviation) String() string {
+	got := d.Got
+	if got == "" {
+		got = "(未返回)"
+	}
+	return fmt.Sprintf("%s: %s → %s", d.Setting, got, d.Want)
+}
+
+// Compare 返回 settings 中偏离基线的项（按设置 ID 排序）
+func Compare(settings, baseline map[string]string) []Deviation {
+	var out []Deviation
+	for _, id := range cfclient.ZoneSettingIDs(baseline) {
+		if got := settings[id]; !strings.EqualFold(got, baseline[id]) {
+			out = append(out, Deviation{Setting: id, Want: baseline[id], Got: got})
+		}
+	}
+	return out
+}
+
+// Result 是单个 Zone 的审计结果
+type Result struct {
+	Account    string
+	Zone       string
+	ZoneID     string
+	Deviations []Deviation
+}
+
+// Audit 检查账号下全部 Zone，只返回偏离基线的 Zone；读取失败的 Zone 返回在 errs 中
+func Audit(ctx context.Context, client cfclient.Client, accounts []config.CF, baseline map[string]string, exclude []string) ([]Result, int, []error) {
+	skip := map[string]bool{}
+	for _, z := range exclude {
+		skip[strings.ToLower(strings.TrimSpace(z))] = true
+	}
+	var results []Result
+	var errs []error
+	checked := 0
+	for _, acc := range accounts {
+		zones, err := client.ListZones(ctx, acc)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("列出账号 %s 的域名失败: %v", acc.Label, err))
+			continue
+		}
+		for _, z := range zones {
+			if skip[strings.ToLower(z.Name)] {
+				continue
+			}
+			settings, err := client.GetZoneSettings(ctx, acc, z.ID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("读取 %s(%s) 的设置失败: %v", z.Name, acc.Label, err))
+				continue
+			}
+			checked++
+			if devs := Compare(settings, baseline); len(devs) > 0 {
+				results = append(results, Result{Account: acc.Label, Zone: z.Name, ZoneID: z.ID, Deviations: devs})
+			}
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Account != results[j].Account {
+			return results[i].Account < results[j].Account
+		}
+		return results[i].Zone < results[j].Zone
+	})
+	return results, checked, errs
+}
+
+// Apply 把偏离项改为基线值，返回成功修改的数量与失败原因
+func Apply(ctx context.Context, client cfclient.Client, account config.CF, r Result) (int, []error) {
+	applied := 0
+	var errs []error
+	for _, d := range r.Deviations {
+		if err := client.UpdateZoneSetting(ctx, account, r.ZoneID, d.Setting, d.Want); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %v", r.Zone, d.Setting, err))
+			continue
+		}
+		applied++
+	}
+	return applied, errs
+}
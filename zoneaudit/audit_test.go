@@ -0,0 +1,95 @@
+package zoneaudit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+type fakeCF struct {
+	cfclient.Client
+	zones    map[string][]cfclient.ZoneDetail
+	settings map[string]map[string]string
+	failID   string
+	updated  []string
+}
+
+func (f *fakeCF) ListZones(ctx context.Context, acc config.CF) ([]cfclient.ZoneDetail, error) {
+	if z, ok := f.zones[acc.Label]; ok {
+		return z, nil
+	}
+	return nil, errors.New("unauthorized")
+}
+
+func (f *fakeCF) GetZoneSettings(ctx context.Context, acc config.CF, zoneID string) (map[string]string, error) {
+	if zoneID == f.failID {
+		return nil, errors.New("timeout")
+	}
+	return f.settings[zoneID], nil
+}
+
+func (f *fakeCF) UpdateZoneSetting(ctx context.Context, acc config.CF, zoneID, id, value string) error {
+	if id == "brotli" {
+		return errors.New("not entitled")
+	}
+	f.updated = append(f.updated, zoneID+":"+id+"="+value)
+	f.settings[zoneID][id] = value
+	return nil
+}
+
+func TestBaseline(t *testing.T) {
+	b, err := Baseline(nil)
+	if err != nil || b["ssl"] != "strict" || len(b) != len(DefaultBaseline) {
+		t.Fatalf("default baseline: %v %v", b, err)
+	}
+	b, err = Baseline(map[string]string{"SSL": "full_strict", "https": "true"})
+	if err != nil || b["ssl"] != "strict" || b["always_use_https"] != "on" || len(b) != 2 {
+		t.Fatalf("normalized baseline: %v %v", b, err)
+	}
+	if _, err := Baseline(map[string]string{"min_tls_version": "2.0"}); err == nil {
+		t.Fatal("expected error for invalid value")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := map[string]string{"ssl": "strict", "min_tls_version": "1.2", "brotli": "on"}
+	devs := Compare(map[string]string{"ssl": "flexible", "min_tls_version": "1.2"}, baseline)
+	if len(devs) != 2 || devs[0].Setting != "brotli" || devs[0].Got != "" || devs[1].Setting != "ssl" || devs[1].Got != "flexible" {
+		t.Fatalf("unexpected deviations: %+v", devs)
+	}
+	if devs[1].String() != "ssl: flexible → strict" || devs[0].String() != "brotli: (未返回) → on" {
+		t.Fatalf("unexpected strings: %s / %s", devs[1], devs[0])
+	}
+}
+
+func TestAuditAndApply(t *testing.T) {
+	cf := &fakeCF{
+		zones: map[string][]cfclient.ZoneDetail{
+			"a": {{ID: "z1", Name: "b.example"}, {ID: "z2", Name: "a.example"}, {ID: "z3", Name: "skip.example"}, {ID: "z4", Name: "broken.example"}},
+		},
+		settings: map[string]map[string]string{
+			"z1": {"ssl": "strict", "brotli": "on"},
+			"z2": {"ssl": "full", "brotli": "off"},
+			"z3": {"ssl": "off"},
+		},
+		failID: "z4",
+	}
+	baseline := map[string]string{"ssl": "strict", "brotli": "on"}
+	accounts := []config.CF{{Label: "a"}, {Label: "missing"}}
+
+	results, checked, errs := Audit(context.Background(), cf, accounts, baseline, []string{"SKIP.example"})
+	if checked != 2 || len(errs) != 2 {
+		t.Fatalf("checked=%d errs=%v", checked, errs)
+	}
+	if len(results) != 1 || results[0].Zone != "a.example" || len(results[0].Deviations) != 2 {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	applied, errs := Apply(context.Background(), cf, accounts[0], results[0])
+	if applied != 1 || len(errs) != 1 || len(cf.updated) != 1 || cf.updated[0] != "z2:ssl=strict" {
+		t.Fatalf("applied=%d errs=%v updated=%v", applied, errs, cf.updated)
+	}
+}
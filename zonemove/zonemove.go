@@ -0,0 +1,470 @@
+// Package zonemove 把一个 Zone 从一个 Cloudflare 账号迁移到另一个账号。
+//
+// 步骤依次为：快照记录 → 在目标账号创建 Zone → 回放记录 → 通过注册商切换 NS →
+// 等待目标 Zone 激活 → 删除源 Zone。每完成一步都会写入检查点文件，
+// 目标 Zone 激活之前任何一步失败都会回滚（恢复注册商 NS、删除目标 Zone）；
+// 激活之后目标 Zone 已在服务，删除源 Zone 失败时只标记为待处理，不再回滚。
+package zonemove
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+type Step string
+
+const (
+	StepSnapshot    Step = "snapshot"
+	StepCreateZone  Step = "create_zone"
+	StepReplay      Step = "replay_records"
+	StepRegistrarNS Step = "registrar_ns"
+	StepWaitActive  Step = "wait_active"
+	StepDeleteOld   Step = "delete_old"
+)
+
+// Steps 为执行顺序
+var Steps = []Step{StepSnapshot, StepCreateZone, StepReplay, StepRegistrarNS, StepWaitActive, StepDeleteOld}
+
+var stepNames = map[Step]string{
+	StepSnapshot:    "快照源记录",
+	StepCreateZone:  "在目标账号创建 Zone",
+	StepReplay:      "回放解析记录",
+	StepRegistrarNS: "切换注册商 NS",
+	StepWaitActive:  "等待目标 Zone 激活",
+	StepDeleteOld:   "删除源 Zone",
+}
+
+// Name 返回步骤的中文名称
+func (s Step) Name() string {
+	if n, ok := stepNames[s]; ok {
+		return n
+	}
+	return string(s)
+}
+
+type Status string
+
+const (
+	StatusRunning    Status = "running"
+	StatusDone       Status = "done"
+	StatusRolledBack Status = "rolled_back"
+	StatusFailed     Status = "failed" // 回滚本身也失败，需要人工处理
+	StatusManual     Status = "manual" // 目标 Zone 已激活但删除源 Zone 失败，需继续迁移或人工删除
+)
+
+// State 是迁移的检查点，每完成一步落盘一次
+type State struct {
+	Domain        string                 `json:"domain"`
+	SourceAccount string                 `json:"source_account"`
+	TargetAccount string                 `json:"target_account"`
+	Operator      string                 `json:"operator,omitempty"`
+	Status        Status                 `json:"status"`
+	Completed     []Step                 `json:"completed"`
+	Records       []cloudflare.DNSRecord `json:"records,omitempty"`
+	SourceZoneID  string                 `json:"source_zone_id,omitempty"`
+	SourceNS      []string               `json:"source_ns,omitempty"`
+	RegistrarNS   []string               `json:"registrar_ns,omitempty"` // 切换前注册商上的 NS，用于回滚
+	NSChanged     bool                   `json:"ns_changed,omitempty"`
+	TargetZoneID  string                 `json:"target_zone_id,omitempty"`
+	TargetNS      []string               `json:"target_ns,omitempty"`
+	Replayed      int                    `json:"replayed,omitempty"`
+	Error         string                 `json:"error,omitempty"`
+	StartedAt     time.Time              `json:"started_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// Done 判断某一步是否已完成
+func (s *State) Done(step Step) bool {
+	for _, c := range s.Completed {
+		if c == step {
+			return true
+		}
+	}
+	return false
+}
+
+// Registrar 是迁移用到的注册商能力，由 registrarclient.Manager 实现
+type Registrar interface {
+	GetNameServersForDomain(ctx context.Context, domain string) (config.Registrar, []string, error)
+	SetNameServersForDomain(ctx context.Context, domain string, nameServers []string) (config.Registrar, error)
+}
+
+// Mover 执行迁移
+type Mover struct {
+	CF        cfclient.Client
+	Registrar Registrar // 为空时需人工修改 NS
+	Accounts  []config.CF
+	Dir       string // 检查点目录
+	Notify    func(msg string)
+
+	// PollInterval / ActivationTimeout 控制等待激活的轮询
+	PollInterval      time.Duration
+	ActivationTimeout time.Duration
+}
+
+// ErrMoveInProgress 同一域名已有进行中的迁移
+var ErrMoveInProgress = errors.New("该域名已有进行中的迁移")
+
+// NewState 校验参数并生成初始检查点
+func (m *Mover) NewState(domain, source, target, operator string) (*State, error) {
+	if strings.EqualFold(source, target) {
+		return nil, fmt.Errorf("源账号与目标账号相同")
+	}
+	if prev, err := m.Load(domain); err == nil && (prev.Status == StatusRunning || prev.Status == StatusManual) {
+		return nil, fmt.Errorf("%w（当前步骤: %s）", ErrMoveInProgress, prev.current().Name())
+	}
+	now := time.Now()
+	st := &State{
+		Domain:        strings.ToLower(domain),
+		SourceAccount: source,
+		TargetAccount: target,
+		Operator:      operator,
+		Status:        StatusRunning,
+		StartedAt:     now,
+		UpdatedAt:     now,
+	}
+	return st, m.save(st)
+}
+
+// running 记录本进程内正在执行的迁移，防止同一域名被重复执行
+var running sync.Map
+
+// Run 从检查点继续执行剩余步骤；目标 Zone 激活前失败会回滚，之后失败只记录并返回错误
+func (m *Mover) Run(ctx context.Context, st *State) error {
+	if _, busy := running.LoadOrStore(st.Domain, true); busy {
+		return ErrMoveInProgress
+	}
+	defer running.Delete(st.Domain)
+
+	st.Status = StatusRunning
+	st.Error = ""
+	source, err := m.account(st.SourceAccount)
+	if err != nil {
+		return m.fail(ctx, st, StepSnapshot, err)
+	}
+	target, err := m.account(st.TargetAccount)
+	if err != nil {
+		return m.fail(ctx, st, StepSnapshot, err)
+	}
+
+	for _, step := range Steps {
+		if st.Done(step) {
+			continue
+		}
+		if err := m.runStep(ctx, st, step, source, target); err != nil {
+			return m.fail(ctx, st, step, err)
+		}
+		st.Completed = append(st.Completed, step)
+		if step == StepDeleteOld {
+			st.Status = StatusDone
+		}
+		if err := m.save(st); err != nil {
+			m.notify(fmt.Sprintf("⚠️ 保存迁移检查点失败: %v", err))
+		}
+	}
+	m.notify(fmt.Sprintf("✅【Zone 迁移完成】%s：%s → %s，已回放 %d 条记录。", st.Domain, st.SourceAccount, st.TargetAccount, st.Replayed))
+	return nil
+}
+
+func (m *Mover) runStep(ctx context.Context, st *State, step Step, source, target config.CF) error {
+	m.notify(fmt.Sprintf("【Zone 迁移】%s 步骤 %d/%d：%s...", st.Domain, stepIndex(step)+1, len(Steps), step.Name()))
+
+	switch step {
+	case StepSnapshot:
+		zone, err := m.CF.GetZoneDetails(ctx, source, st.Domain)
+		if err != nil {
+			return fmt.Errorf("读取源 Zone 失败: %w", err)
+		}
+		records, err := m.CF.ListDNSRecords(ctx, source, st.Domain)
+		if err != nil {
+			return fmt.Errorf("读取源记录失败: %w", err)
+		}
+		st.SourceZoneID = zone.ID
+		st.SourceNS = zone.NameServers
+		st.Records = records
+
+	case StepCreateZone:
+		zone, err := m.CF.CreateZone(ctx, target, st.Domain)
+		if err != nil {
+			return fmt.Errorf("创建目标 Zone 失败: %w", err)
+		}
+		if len(zone.NameServers) == 0 {
+			// 本步骤未完成时回滚不会删除目标 Zone，这里自行清理，避免残留
+			if err := m.CF.DeleteZoneByID(ctx, target, zone.ID); err != nil && !errors.Is(err, cfclient.ErrZoneNotFound) {
+				return fmt.Errorf("目标 Zone 未返回 NS，且删除已创建的目标 Zone 失败: %v", err)
+			}
+			return fmt.Errorf("目标 Zone 未返回 NS")
+		}
+		st.TargetZoneID = zone.ID
+		st.TargetNS = zone.NameServers
+
+	case StepReplay:
+		// 检查点之后重试时，先清点目标 Zone 已有的记录，避免重复创建
+		existing, err := m.CF.ListDNSRecords(ctx, target, st.Domain)
+		if err != nil {
+			return fmt.Errorf("读取目标记录失败: %w", err)
+		}
+		st.Replayed = 0
+		for _, r := range st.Records {
+			if containsRecord(existing, r) {
+				st.Replayed++
+				continue
+			}
+			if _, err := m.CF.UpsertDNSRecord(ctx, target, st.Domain, replayParams(st.Domain, r)); err != nil {
+				return fmt.Errorf("回放 %s %s 失败: %w", r.Type, r.Name, err)
+			}
+			st.Replayed++
+		}
+
+	case StepRegistrarNS:
+		if m.Registrar == nil {
+			m.notify(fmt.Sprintf("未配置注册商，请手动把 %s 的 NS 修改为:\n%s", st.Domain, strings.Join(st.TargetNS, "\n")))
+			return nil
+		}
+		if _, ns, err := m.Registrar.GetNameServersForDomain(ctx, st.Domain); err == nil {
+			st.RegistrarNS = ns
+		}
+		registrar, err := m.Registrar.SetNameServersForDomain(ctx, st.Domain, st.TargetNS)
+		if err != nil {
+			// 注册商不在配置中时允许人工修改，等待激活超时后仍会回滚
+			m.notify(fmt.Sprintf("同步注册商 NS 失败: %v\n请手动把 %s 的 NS 修改为:\n%s", err, st.Domain, strings.Join(st.TargetNS, "\n")))
+			return nil
+		}
+		st.NSChanged = true
+		m.notify(fmt.Sprintf("已通过注册商 %s (%s) 把 NS 切换为:\n%s", registrar.Label, registrar.Type, strings.Join(st.TargetNS, "\n")))
+
+	case StepWaitActive:
+		return m.waitActive(ctx, st, target)
+
+	case StepDeleteOld:
+		// 此时两个账号下都有同名 Zone，必须按快照时记录的 ID 删除，不能按名称查找
+		if st.SourceZoneID == "" {
+			return fmt.Errorf("检查点缺少源 Zone ID，请手动删除源账号 %s 中的 Zone", st.SourceAccount)
+		}
+		if err := m.CF.DeleteZoneByID(ctx, source, st.SourceZoneID); err != nil {
+			return fmt.Errorf("删除源 Zone 失败: %w", err)
+		}
+	}
+	return nil
+}
+
+func (m *Mover) waitActive(ctx context.Context, st *State, target config.CF) error {
+	interval := m.PollInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	timeout := m.ActivationTimeout
+	if timeout <= 0 {
+		timeout = 24 * time.Hour
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		zone, err := m.CF.GetZoneDetails(ctx, target, st.Domain)
+		if err == nil && zone.Status == "active" {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			status := zone.Status
+			if err != nil {
+				status = err.Error()
+			}
+			return fmt.Errorf("等待激活超时（%v），当前状态: %s", timeout, status)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// fail 记录失败；目标 Zone 激活之前回滚，之后交给 hold 处理
+func (m *Mover) fail(ctx context.Context, st *State, step Step, cause error) error {
+	if st.Done(StepWaitActive) {
+		return m.hold(st, step, cause)
+	}
+	st.Error = fmt.Sprintf("%s: %v", step.Name(), cause)
+	m.notify(fmt.Sprintf("❌【Zone 迁移失败】%s 在步骤「%s」失败: %v\n开始回滚...", st.Domain, step.Name(), cause))
+
+	var problems []string
+	if st.NSChanged && m.Registrar != nil {
+		restore := st.RegistrarNS
+		if len(restore) == 0 {
+			restore = st.SourceNS
+		}
+		if len(restore) > 0 {
+			if _, err := m.Registrar.SetNameServersForDomain(ctx, st.Domain, restore); err != nil {
+				problems = append(problems, fmt.Sprintf("恢复注册商 NS 失败: %v（原 NS: %s）", err, strings.Join(restore, ", ")))
+			} else {
+				st.NSChanged = false
+			}
+		}
+	}
+	if st.Done(StepCreateZone) {
+		// 按 ID 删除目标 Zone，避免误删源账号中的同名 Zone
+		if target, err := m.account(st.TargetAccount); err != nil {
+			problems = append(problems, err.Error())
+		} else if st.TargetZoneID == "" {
+			problems = append(problems, "检查点缺少目标 Zone ID，请手动删除目标账号中的 Zone")
+		} else if err := m.CF.DeleteZoneByID(ctx, target, st.TargetZoneID); err != nil && !errors.Is(err, cfclient.ErrZoneNotFound) {
+			problems = append(problems, fmt.Sprintf("删除目标 Zone 失败: %v", err))
+		}
+	}
+	if len(problems) == 0 {
+		// 已完全回滚，继续迁移时从头开始并重新快照
+		st.Completed = nil
+	}
+
+	if len(problems) > 0 {
+		st.Status = StatusFailed
+		st.Error += "；回滚问题: " + strings.Join(problems, "；")
+		m.notify(fmt.Sprintf("⚠️ %s 回滚未完全成功，请人工处理：\n%s", st.Domain, strings.Join(problems, "\n")))
+	} else {
+		st.Status = StatusRolledBack
+		m.notify(fmt.Sprintf("已回滚 %s，源账号 %s 中的 Zone 未受影响。", st.Domain, st.SourceAccount))
+	}
+	if err := m.save(st); err != nil {
+		m.notify(fmt.Sprintf("⚠️ 保存迁移检查点失败: %v", err))
+	}
+	return cause
+}
+
+// hold 记录目标 Zone 激活之后的失败。此时目标 Zone 已在服务、NS 已指向目标账号，
+// 回滚反而会造成中断，因此保留检查点，等待 /movezone resume 重试剩余步骤
+func (m *Mover) hold(st *State, step Step, cause error) error {
+	st.Status = StatusManual
+	st.Error = fmt.Sprintf("%s: %v", step.Name(), cause)
+	m.notify(fmt.Sprintf("⚠️【Zone 迁移未完成】%s 在步骤「%s」失败: %v\n目标账号 %s 中的 Zone 已激活，不会回滚。请使用 /movezone resume %s 重试，或手动删除源账号 %s 中的 Zone。",
+		st.Domain, step.Name(), cause, st.TargetAccount, st.Domain, st.SourceAccount))
+	if err := m.save(st); err != nil {
+		m.notify(fmt.Sprintf("⚠️ 保存迁移检查点失败: %v", err))
+	}
+	return cause
+}
+
+// Load 读取域名的迁移检查点
+func (m *Mover) Load(domain string) (*State, error) {
+	data, err := os.ReadFile(m.path(domain))
+	if err != nil {
+		return nil, err
+	}
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("解析迁移检查点失败: %w", err)
+	}
+	return &st, nil
+}
+
+// Summary 返回检查点的可读描述
+func (s *State) Summary() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("域名: %s\n%s → %s\n状态: %s\n", s.Domain, s.SourceAccount, s.TargetAccount, s.Status))
+	for i, step := range Steps {
+		mark := "⬜"
+		if s.Done(step) {
+			mark = "✅"
+		}
+		sb.WriteString(fmt.Sprintf("%s %d. %s\n", mark, i+1, step.Name()))
+	}
+	if s.Error != "" {
+		sb.WriteString("错误: " + s.Error + "\n")
+	}
+	sb.WriteString(fmt.Sprintf("更新时间: %s", s.UpdatedAt.Local().Format("2006-01-02 15:04:05")))
+	return sb.String()
+}
+
+func (s *State) current() Step {
+	for _, step := range Steps {
+		if !s.Done(step) {
+			return step
+		}
+	}
+	return StepDeleteOld
+}
+
+func (m *Mover) save(st *State) error {
+	st.UpdatedAt = time.Now()
+	if err := os.MkdirAll(m.dir(), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := m.path(st.Domain) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.path(st.Domain))
+}
+
+func (m *Mover) dir() string {
+	if strings.TrimSpace(m.Dir) == "" {
+		return "zone_moves"
+	}
+	return m.Dir
+}
+
+func (m *Mover) path(domain string) string {
+	return filepath.Join(m.dir(), strings.ToLower(strings.TrimSpace(domain))+".json")
+}
+
+func (m *Mover) account(label string) (config.CF, error) {
+	for _, acc := range m.Accounts {
+		if strings.EqualFold(acc.Label, label) {
+			return acc, nil
+		}
+	}
+	return config.CF{}, fmt.Errorf("未找到账号 %s", label)
+}
+
+func (m *Mover) notify(msg string) {
+	if m.Notify != nil {
+		m.Notify(msg)
+	}
+}
+
+func stepIndex(step Step) int {
+	for i, s := range Steps {
+		if s == step {
+			return i
+		}
+	}
+	return 0
+}
+
+func replayParams(zone string, r cloudflare.DNSRecord) cfclient.DNSRecordParams {
+	return cfclient.DNSRecordParams{
+		Type:     r.Type,
+		Name:     cfclient.RecordFQDN(r.Name, zone),
+		Content:  r.Content,
+		Proxied:  r.Proxied != nil && *r.Proxied,
+		TTL:      r.TTL,
+		Priority: r.Priority,
+		Mode:     cfclient.DNSWriteAdd,
+	}
+}
+
+func containsRecord(records []cloudflare.DNSRecord, r cloudflare.DNSRecord) bool {
+	for _, e := range records {
+		if strings.EqualFold(e.Type, r.Type) &&
+			strings.EqualFold(strings.TrimSuffix(e.Name, "."), strings.TrimSuffix(r.Name, ".")) &&
+			cfclient.DNSContentEqual(r.Type, e.Content, r.Content) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,406 @@
+package zonemove
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// fakeCF 模拟多个账号下的 Zone，只实现迁移用到的方法
+type fakeCF struct {
+	cfclient.Client
+
+	mu         sync.Mutex
+	zones      map[string]*fakeZone // key: 账号标签
+	activeWhen int                  // GetZoneDetails 第几次查询目标 Zone 时返回 active，0 表示永不
+	polls      int
+	failUpsert string // 回放该名称的记录时报错
+	failDelete string // 删除该账号下的 Zone 时报错
+	noNS       bool   // CreateZone 不返回 NS
+	// shared 非空时模拟一个能同时看到多个账号的 token：按名称列出/删除 Zone 时
+	// 依次返回这些账号下的同名 Zone
+	shared []string
+}
+
+type fakeZone struct {
+	status  string
+	ns      []string
+	records []cloudflare.DNSRecord
+}
+
+func (f *fakeCF) zone(account config.CF) (*fakeZone, error) {
+	z, ok := f.zones[account.Label]
+	if !ok {
+		return nil, cfclient.ErrZoneNotFound
+	}
+	return z, nil
+}
+
+func (f *fakeCF) GetZoneDetails(ctx context.Context, account config.CF, domain string) (cfclient.ZoneDetail, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	z, err := f.zone(account)
+	if err != nil {
+		return cfclient.ZoneDetail{}, err
+	}
+	if z.status == "pending" {
+		f.polls++
+		if f.activeWhen > 0 && f.polls >= f.activeWhen {
+			z.status = "active"
+		}
+	}
+	return cfclient.ZoneDetail{ID: account.Label + "-id", Name: domain, Status: z.status, NameServers: z.ns}, nil
+}
+
+func (f *fakeCF) ListDNSRecords(ctx context.Context, account config.CF, domain string) ([]cloudflare.DNSRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	z, err := f.zone(account)
+	if err != nil {
+		return nil, err
+	}
+	return append([]cloudflare.DNSRecord(nil), z.records...), nil
+}
+
+func (f *fakeCF) CreateZone(ctx context.Context, account config.CF, domain string) (cfclient.ZoneDetail, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ns := []string{"ada.ns.cloudflare.com", "bob.ns.cloudflare.com"}
+	f.zones[account.Label] = &fakeZone{status: "pending", ns: ns}
+	if f.noNS {
+		ns = nil
+	}
+	return cfclient.ZoneDetail{ID: account.Label + "-id", Name: domain, Status: "pending", NameServers: ns}, nil
+}
+
+func (f *fakeCF) UpsertDNSRecord(ctx context.Context, account config.CF, domain string, params cfclient.DNSRecordParams) (cloudflare.DNSRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if params.Name == f.failUpsert {
+		return cloudflare.DNSRecord{}, errors.New("boom")
+	}
+	z, err := f.zone(account)
+	if err != nil {
+		return cloudflare.DNSRecord{}, err
+	}
+	proxied := params.Proxied
+	r := cloudflare.DNSRecord{Type: params.Type, Name: params.Name, Content: params.Content, TTL: params.TTL, Proxied: &proxied, Priority: params.Priority}
+	z.records = append(z.records, r)
+	return r, nil
+}
+
+// ListZones 按名称列出可见的 Zone；shared 时包含其它账号下的同名 Zone
+func (f *fakeCF) ListZones(ctx context.Context, account config.CF) ([]cfclient.ZoneDetail, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.visible(account), nil
+}
+
+func (f *fakeCF) visible(account config.CF) []cfclient.ZoneDetail {
+	labels := []string{account.Label}
+	if len(f.shared) > 0 {
+		labels = f.shared
+	}
+	var out []cfclient.ZoneDetail
+	for _, label := range labels {
+		if z, ok := f.zones[label]; ok {
+			out = append(out, cfclient.ZoneDetail{ID: label + "-id", Name: "example.com", Status: z.status, NameServers: z.ns})
+		}
+	}
+	return out
+}
+
+// DeleteDomain 与真实实现一样按名称查找并删除第一个结果
+func (f *fakeCF) DeleteDomain(ctx context.Context, account config.CF, domain string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	zones := f.visible(account)
+	if len(zones) == 0 {
+		return cfclient.ErrZoneNotFound
+	}
+	return f.deleteID(zones[0].ID)
+}
+
+func (f *fakeCF) DeleteZoneByID(ctx context.Context, account config.CF, zoneID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, z := range f.visible(account) {
+		if z.ID == zoneID {
+			return f.deleteID(zoneID)
+		}
+	}
+	return cfclient.ErrZoneNotFound
+}
+
+func (f *fakeCF) deleteID(zoneID string) error {
+	label := strings.TrimSuffix(zoneID, "-id")
+	if label == f.failDelete {
+		return errors.New("permission denied")
+	}
+	delete(f.zones, label)
+	return nil
+}
+
+type fakeRegistrar struct {
+	ns   []string
+	sets [][]string
+	err  error
+}
+
+func (r *fakeRegistrar) GetNameServersForDomain(ctx context.Context, domain string) (config.Registrar, []string, error) {
+	return config.Registrar{Label: "reg"}, r.ns, nil
+}
+
+func (r *fakeRegistrar) SetNameServersForDomain(ctx context.Context, domain string, ns []string) (config.Registrar, error) {
+	if r.err != nil {
+		return config.Registrar{}, r.err
+	}
+	r.sets = append(r.sets, ns)
+	r.ns = ns
+	return config.Registrar{Label: "reg", Type: "godaddy"}, nil
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func newFixture(t *testing.T) (*fakeCF, *fakeRegistrar, *Mover) {
+	t.Helper()
+	oldNS := []string{"old1.ns.cloudflare.com", "old2.ns.cloudflare.com"}
+	cf := &fakeCF{
+		activeWhen: 2,
+		zones: map[string]*fakeZone{
+			"src": {status: "active", ns: oldNS, records: []cloudflare.DNSRecord{
+				{ID: "1", Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 1, Proxied: boolPtr(true)},
+				{ID: "2", Type: "MX", Name: "example.com", Content: "mx.example.com", TTL: 3600, Priority: func() *uint16 { p := uint16(10); return &p }()},
+				{ID: "3", Type: "TXT", Name: "www.example.com", Content: "hello", TTL: 300, Proxied: boolPtr(false)},
+			}},
+		},
+	}
+	reg := &fakeRegistrar{ns: oldNS}
+	m := &Mover{
+		CF:                cf,
+		Registrar:         reg,
+		Accounts:          []config.CF{{Label: "src"}, {Label: "dst"}},
+		Dir:               t.TempDir(),
+		PollInterval:      time.Millisecond,
+		ActivationTimeout: time.Second,
+	}
+	return cf, reg, m
+}
+
+func TestMoveZoneSuccess(t *testing.T) {
+	cf, reg, m := newFixture(t)
+	st, err := m.NewState("example.com", "src", "dst", "tester")
+	if err != nil {
+		t.Fatalf("NewState: %v", err)
+	}
+	if err := m.Run(context.Background(), st); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if _, ok := cf.zones["src"]; ok {
+		t.Fatalf("source zone should be deleted")
+	}
+	dst := cf.zones["dst"]
+	if dst == nil || len(dst.records) != 3 {
+		t.Fatalf("expected 3 replayed records, got %+v", dst)
+	}
+	for _, r := range dst.records {
+		switch r.Type {
+		case "A":
+			if r.Proxied == nil || !*r.Proxied || r.TTL != 1 {
+				t.Fatalf("proxied/TTL not preserved: %+v", r)
+			}
+		case "MX":
+			if r.Priority == nil || *r.Priority != 10 || r.TTL != 3600 {
+				t.Fatalf("priority/TTL not preserved: %+v", r)
+			}
+		}
+	}
+	if len(reg.sets) != 1 || reg.sets[0][0] != "ada.ns.cloudflare.com" {
+		t.Fatalf("registrar NS not switched: %v", reg.sets)
+	}
+
+	saved, err := m.Load("example.com")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if saved.Status != StatusDone || len(saved.Completed) != len(Steps) {
+		t.Fatalf("unexpected final state: %+v", saved)
+	}
+}
+
+func TestMoveZoneRollbackOnActivationTimeout(t *testing.T) {
+	cf, reg, m := newFixture(t)
+	cf.activeWhen = 0
+	m.ActivationTimeout = 10 * time.Millisecond
+
+	st, _ := m.NewState("example.com", "src", "dst", "tester")
+	if err := m.Run(context.Background(), st); err == nil {
+		t.Fatalf("expected activation timeout")
+	}
+
+	if _, ok := cf.zones["dst"]; ok {
+		t.Fatalf("target zone should be removed on rollback")
+	}
+	if src := cf.zones["src"]; src == nil || len(src.records) != 3 {
+		t.Fatalf("source zone must be untouched")
+	}
+	if got := reg.ns[0]; got != "old1.ns.cloudflare.com" {
+		t.Fatalf("registrar NS not restored, got %v", reg.ns)
+	}
+	saved, _ := m.Load("example.com")
+	if saved.Status != StatusRolledBack || !strings.Contains(saved.Error, StepWaitActive.Name()) {
+		t.Fatalf("unexpected state after rollback: %+v", saved)
+	}
+}
+
+func TestMoveZoneResumeAfterReplayFailure(t *testing.T) {
+	cf, _, m := newFixture(t)
+	cf.failUpsert = "www.example.com"
+
+	st, _ := m.NewState("example.com", "src", "dst", "tester")
+	if err := m.Run(context.Background(), st); err == nil {
+		t.Fatalf("expected replay failure")
+	}
+	if _, ok := cf.zones["dst"]; ok {
+		t.Fatalf("target zone should be removed on rollback")
+	}
+
+	cf.failUpsert = ""
+	st, err := m.Load("example.com")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if err := m.Run(context.Background(), st); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if dst := cf.zones["dst"]; dst == nil || len(dst.records) != 3 {
+		t.Fatalf("expected records replayed exactly once after resume, got %+v", dst)
+	}
+}
+
+func TestMoveZoneDeleteOldFailureDoesNotRollBack(t *testing.T) {
+	cf, reg, m := newFixture(t)
+	cf.failDelete = "src"
+
+	st, _ := m.NewState("example.com", "src", "dst", "tester")
+	if err := m.Run(context.Background(), st); err == nil {
+		t.Fatalf("expected delete_old failure")
+	}
+	if dst := cf.zones["dst"]; dst == nil || dst.status != "active" {
+		t.Fatalf("active target zone must be kept, got %+v", dst)
+	}
+	if got := reg.ns[0]; got != "ada.ns.cloudflare.com" {
+		t.Fatalf("registrar NS must stay on target, got %v", reg.ns)
+	}
+	saved, _ := m.Load("example.com")
+	if saved.Status != StatusManual || !saved.Done(StepWaitActive) || saved.Done(StepDeleteOld) {
+		t.Fatalf("unexpected state: %+v", saved)
+	}
+	if _, err := m.NewState("example.com", "src", "dst", ""); !errors.Is(err, ErrMoveInProgress) {
+		t.Fatalf("new move must be rejected while delete_old is pending, got %v", err)
+	}
+
+	// resume 只重试删除源 Zone
+	cf.failDelete = ""
+	if err := m.Run(context.Background(), saved); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if _, ok := cf.zones["src"]; ok {
+		t.Fatalf("source zone should be deleted on resume")
+	}
+	if dst := cf.zones["dst"]; dst == nil || len(dst.records) != 3 {
+		t.Fatalf("target zone changed on resume: %+v", dst)
+	}
+	if saved, _ = m.Load("example.com"); saved.Status != StatusDone {
+		t.Fatalf("expected done after resume, got %s", saved.Status)
+	}
+}
+
+func TestMoveZoneCreatedWithoutNSIsRemoved(t *testing.T) {
+	cf, _, m := newFixture(t)
+	cf.noNS = true
+
+	st, _ := m.NewState("example.com", "src", "dst", "tester")
+	if err := m.Run(context.Background(), st); err == nil {
+		t.Fatalf("expected missing NS error")
+	}
+	if _, ok := cf.zones["dst"]; ok {
+		t.Fatalf("target zone without NS must not be left behind")
+	}
+	if saved, _ := m.Load("example.com"); saved.Status != StatusRolledBack {
+		t.Fatalf("unexpected status %s", saved.Status)
+	}
+}
+
+func TestMoveZoneDeletesByIDWhenTokenSeesBothZones(t *testing.T) {
+	// 无论 API 按名称返回的顺序如何，都只能删除对应账号的 Zone
+	for _, order := range [][]string{{"src", "dst"}, {"dst", "src"}} {
+		cf, _, m := newFixture(t)
+		cf.shared = order
+		st, _ := m.NewState("example.com", "src", "dst", "tester")
+		if err := m.Run(context.Background(), st); err != nil {
+			t.Fatalf("%v: Run: %v", order, err)
+		}
+		if _, ok := cf.zones["src"]; ok {
+			t.Fatalf("%v: source zone should be deleted", order)
+		}
+		if dst := cf.zones["dst"]; dst == nil || len(dst.records) != 3 {
+			t.Fatalf("%v: migrated target zone must be kept, got %+v", order, dst)
+		}
+
+		cf, _, m = newFixture(t)
+		cf.shared = order
+		cf.activeWhen = 0
+		m.ActivationTimeout = 10 * time.Millisecond
+		st, _ = m.NewState("example.com", "src", "dst", "tester")
+		if err := m.Run(context.Background(), st); err == nil {
+			t.Fatalf("%v: expected activation timeout", order)
+		}
+		if _, ok := cf.zones["dst"]; ok {
+			t.Fatalf("%v: target zone should be removed on rollback", order)
+		}
+		if src := cf.zones["src"]; src == nil || len(src.records) != 3 {
+			t.Fatalf("%v: rollback must not touch the source zone", order)
+		}
+	}
+}
+
+func TestMoveZoneWithoutRegistrar(t *testing.T) {
+	cf, _, m := newFixture(t)
+	m.Registrar = nil
+	var notes []string
+	m.Notify = func(msg string) { notes = append(notes, msg) }
+
+	st, _ := m.NewState("example.com", "src", "dst", "tester")
+	if err := m.Run(context.Background(), st); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if _, ok := cf.zones["src"]; ok {
+		t.Fatalf("source zone should be deleted once target is active")
+	}
+	if !strings.Contains(strings.Join(notes, "\n"), "请手动把 example.com 的 NS") {
+		t.Fatalf("expected manual NS instructions, got %v", notes)
+	}
+}
+
+func TestNewStateRejectsSameAccountAndConcurrentMove(t *testing.T) {
+	_, _, m := newFixture(t)
+	if _, err := m.NewState("example.com", "src", "src", ""); err == nil {
+		t.Fatalf("expected error for same account")
+	}
+	if _, err := m.NewState("example.com", "src", "dst", ""); err != nil {
+		t.Fatalf("NewState: %v", err)
+	}
+	if _, err := m.NewState("example.com", "src", "dst", ""); !errors.Is(err, ErrMoveInProgress) {
+		t.Fatalf("expected ErrMoveInProgress, got %v", err)
+	}
+}
@@ -0,0 +1,371 @@
+// Package zonewatch 跟踪新建的 Zone，按退避间隔轮询激活状态，
+// 激活后通知群组；超过期限仍未激活则升级告警，并对比注册商与 Cloudflare 的 NS。
+package zonewatch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+const (
+	// DefaultFile 未配置时保存跟踪列表的文件
+	DefaultFile = "zone_watch.json"
+	// DefaultEscalateAfter 未激活多久后升级告警
+	DefaultEscalateAfter = 3 * 24 * time.Hour
+	// DefaultMinInterval / DefaultMaxInterval 是轮询退避的起点与上限
+	DefaultMinInterval = 5 * time.Minute
+	DefaultMaxInterval = 6 * time.Hour
+)
+
+// Entry 是一个被跟踪的 Zone
+type Entry struct {
+	Domain      string    `json:"domain"`
+	Account     string    `json:"account"`
+	NameServers []string  `json:"name_servers"` // Cloudflare 分配的 NS
+	AddedAt     time.Time `json:"added_at"`
+	NextCheck   time.Time `json:"next_check"`
+	Attempts    int       `json:"attempts"`
+	LastStatus  string    `json:"last_status,omitempty"`
+	Escalated   bool      `json:"escalated,omitempty"`
+}
+
+// Registrar 用于在升级告警时读取注册商当前的 NS，由 registrarclient.Manager 实现
+type Registrar interface {
+	GetNameServersForDomain(ctx context.Context, domain string) (config.Registrar, []string, error)
+}
+
+// Watcher 维护跟踪列表并执行检查
+type Watcher struct {
+	CF        cfclient.Client
+	Registrar Registrar // 为空时升级告警中不显示注册商 NS
+	Accounts  []config.CF
+	File      string
+	Notify    func(msg string)
+
+	EscalateAfter time.Duration
+	MinInterval   time.Duration
+	MaxInterval   time.Duration
+
+	mu    sync.Mutex // 保护跟踪列表文件，不在持有期间做网络请求
+	runMu sync.Mutex // 串行化 Run，避免同一条目被并发检查
+	now   func() time.Time
+}
+
+// Track 开始（或重新）跟踪一个 Zone；已跟踪的 Zone 会更新 NS 并重置退避
+func (w *Watcher) Track(domain, account string, nameServers []string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entries, err := w.load()
+	if err != nil {
+		return err
+	}
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	now := w.clock()
+	e, ok := entries[domain]
+	if !ok || !strings.EqualFold(e.Account, account) {
+		e = Entry{Domain: domain, Account: account, AddedAt: now}
+	}
+	e.NameServers = nameServers
+	e.Attempts = 0
+	e.NextCheck = now.Add(w.backoff(0))
+	entries[domain] = e
+	return w.save(entries)
+}
+
+// Untrack 停止跟踪
+func (w *Watcher) Untrack(domain string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entries, err := w.load()
+	if err != nil {
+		return err
+	}
+	delete(entries, strings.ToLower(strings.TrimSpace(domain)))
+	return w.save(entries)
+}
+
+// List 返回按加入时间排序的跟踪列表
+func (w *Watcher) List() ([]Entry, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entries, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].AddedAt.Before(out[j].AddedAt) })
+	return out, nil
+}
+
+// Run 检查所有到期的条目，适合作为定时任务频繁调用。
+// 只在读取与写回跟踪列表时持锁，查询 Cloudflare 与发送通知期间 Track/Untrack 不会被阻塞。
+func (w *Watcher) Run(ctx context.Context) {
+	w.runMu.Lock()
+	defer w.runMu.Unlock()
+
+	w.mu.Lock()
+	entries, err := w.load()
+	w.mu.Unlock()
+	if err != nil {
+		log.Printf("Zone 激活跟踪: %v", err)
+		return
+	}
+	now := w.clock()
+	type result struct {
+		before Entry // 检查前的条目，用于识别检查期间被 Track 重置或移除的条目
+		after  Entry
+		keep   bool
+	}
+	var results []result
+	for _, e := range entries {
+		if now.Before(e.NextCheck) {
+			continue
+		}
+		before := e
+		keep := w.check(ctx, &e, now)
+		results = append(results, result{before: before, after: e, keep: keep})
+	}
+	if len(results) == 0 {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	entries, err = w.load()
+	if err != nil {
+		log.Printf("Zone 激活跟踪: %v", err)
+		return
+	}
+	for _, r := range results {
+		cur, ok := entries[r.before.Domain]
+		if !ok || !sameEntry(cur, r.before) {
+			continue
+		}
+		if r.keep {
+			entries[r.before.Domain] = r.after
+		} else {
+			delete(entries, r.before.Domain)
+		}
+	}
+	if err := w.save(entries); err != nil {
+		log.Printf("Zone 激活跟踪: %v", err)
+	}
+}
+
+// sameEntry 判断条目在检查期间是否未被 Track 修改
+func sameEntry(a, b Entry) bool {
+	return a.Account == b.Account && a.AddedAt.Equal(b.AddedAt) && a.NextCheck.Equal(b.NextCheck) && a.Attempts == b.Attempts
+}
+
+// check 查询一次状态，返回是否继续跟踪
+func (w *Watcher) check(ctx context.Context, e *Entry, now time.Time) bool {
+	account, ok := w.account(e.Account)
+	if !ok {
+		w.notify(fmt.Sprintf("Zone 激活跟踪: 账号 %s 已不在配置中，停止跟踪 %s。", e.Account, e.Domain))
+		return false
+	}
+
+	zone, err := w.CF.GetZoneDetails(ctx, account, e.Domain)
+	if errors.Is(err, cfclient.ErrZoneNotFound) {
+		w.notify(fmt.Sprintf("Zone 激活跟踪: %s 已不在账号 %s 中，停止跟踪。", e.Domain, e.Account))
+		return false
+	}
+	e.Attempts++
+	if err != nil {
+		log.Printf("Zone 激活跟踪: 查询 %s 失败: %v", e.Domain, err)
+		e.NextCheck = now.Add(w.backoff(e.Attempts))
+		return true
+	}
+	e.LastStatus = zone.Status
+	if len(zone.NameServers) > 0 {
+		e.NameServers = zone.NameServers
+	}
+
+	if zone.Status == "active" {
+		w.notify(fmt.Sprintf("✅【Zone 已激活】%s（账号 %s），从添加到激活用时 %s。", e.Domain, e.Account, formatDuration(now.Sub(e.AddedAt))))
+		return false
+	}
+
+	if !e.Escalated && now.Sub(e.AddedAt) >= w.escalateAfter() {
+		e.Escalated = true
+		w.notify(w.escalation(ctx, *e, now))
+	}
+	e.NextCheck = now.Add(w.backoff(e.Attempts))
+	return true
+}
+
+func (w *Watcher) escalation(ctx context.Context, e Entry, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString("⚠️【Zone 长时间未激活】\n")
+	sb.WriteString(fmt.Sprintf("域名: %s\n账号: %s\n状态: %s\n已等待: %s\n", e.Domain, e.Account, e.LastStatus, formatDuration(now.Sub(e.AddedAt))))
+	sb.WriteString("\nCloudflare 分配的 NS:\n")
+	for _, ns := range e.NameServers {
+		sb.WriteString("  " + ns + "\n")
+	}
+
+	if w.Registrar == nil {
+		sb.WriteString("\n未配置注册商，请人工确认域名的 NS。")
+		return sb.String()
+	}
+	registrar, current, err := w.Registrar.GetNameServersForDomain(ctx, e.Domain)
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("\n查询注册商 NS 失败: %v", err))
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("\n注册商 %s (%s) 当前 NS:\n", registrar.Label, registrar.Type))
+	for _, ns := range current {
+		sb.WriteString("  " + ns + "\n")
+	}
+	if SameNameServers(current, e.NameServers) {
+		sb.WriteString("\nNS 已一致，可能仍在等待注册局生效，或可在 Cloudflare 面板重新触发激活检查。")
+	} else {
+		sb.WriteString("\nNS 不一致，请把注册商 NS 修改为 Cloudflare 分配的 NS。")
+	}
+	return sb.String()
+}
+
+// SameNameServers 忽略大小写、末尾点与顺序比较两组 NS
+func SameNameServers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	norm := func(list []string) []string {
+		out := make([]string, len(list))
+		for i, ns := range list {
+			out[i] = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(ns)), ".")
+		}
+		sort.Strings(out)
+		return out
+	}
+	na, nb := norm(a), norm(b)
+	for i := range na {
+		if na[i] != nb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// backoff 返回第 attempts 次检查后的等待时间：从 MinInterval 开始翻倍，不超过 MaxInterval
+func (w *Watcher) backoff(attempts int) time.Duration {
+	lo, hi := w.MinInterval, w.MaxInterval
+	if lo <= 0 {
+		lo = DefaultMinInterval
+	}
+	if hi <= 0 {
+		hi = DefaultMaxInterval
+	}
+	d := lo
+	for i := 0; i < attempts && d < hi; i++ {
+		d *= 2
+	}
+	if d > hi {
+		d = hi
+	}
+	return d
+}
+
+func (w *Watcher) escalateAfter() time.Duration {
+	if w.EscalateAfter <= 0 {
+		return DefaultEscalateAfter
+	}
+	return w.EscalateAfter
+}
+
+func (w *Watcher) account(label string) (config.CF, bool) {
+	for _, acc := range w.Accounts {
+		if acc.Label == label {
+			return acc, true
+		}
+	}
+	return config.CF{}, false
+}
+
+func (w *Watcher) clock() time.Time {
+	if w.now != nil {
+		return w.now()
+	}
+	return time.Now()
+}
+
+func (w *Watcher) notify(msg string) {
+	if w.Notify != nil {
+		w.Notify(msg)
+	}
+}
+
+func (w *Watcher) path() string {
+	if strings.TrimSpace(w.File) == "" {
+		return DefaultFile
+	}
+	return w.File
+}
+
+func (w *Watcher) load() (map[string]Entry, error) {
+	entries := make(map[string]Entry)
+	data, err := os.ReadFile(w.path())
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取跟踪列表失败 [%s]: %v", w.path(), err)
+	}
+	var list []Entry
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("解析跟踪列表失败 [%s]: %v", w.path(), err)
+	}
+	for _, e := range list {
+		entries[e.Domain] = e
+	}
+	return entries, nil
+}
+
+func (w *Watcher) save(entries map[string]Entry) error {
+	list := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Domain < list[j].Domain })
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(w.path()); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("保存跟踪列表失败 [%s]: %v", w.path(), err)
+		}
+	}
+	tmp := w.path() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("保存跟踪列表失败 [%s]: %v", w.path(), err)
+	}
+	return os.Rename(tmp, w.path())
+}
+
+func formatDuration(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%d 天 %d 小时", int(d.Hours())/24, int(d.Hours())%24)
+	}
+	if d >= time.Hour {
+		return fmt.Sprintf("%d 小时 %d 分钟", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%d 分钟", int(d.Minutes()))
+}
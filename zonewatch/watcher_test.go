@@ -0,0 +1,190 @@
+package zonewatch
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"DomainC/cfclient"
+	"DomainC/config"
+)
+
+type fakeCF struct {
+	cfclient.Client
+	status string
+	calls  int
+	// polling 非空时，GetZoneDetails 先通知 polling，再等 release 关闭后返回
+	polling chan struct{}
+	release chan struct{}
+}
+
+func (f *fakeCF) GetZoneDetails(ctx context.Context, account config.CF, domain string) (cfclient.ZoneDetail, error) {
+	if f.polling != nil {
+		f.polling <- struct{}{}
+		<-f.release
+	}
+	f.calls++
+	if f.status == "" {
+		return cfclient.ZoneDetail{}, cfclient.ErrZoneNotFound
+	}
+	return cfclient.ZoneDetail{Name: domain, Status: f.status, NameServers: []string{"ada.ns.cloudflare.com", "bob.ns.cloudflare.com"}}, nil
+}
+
+type fakeRegistrar struct{ ns []string }
+
+func (r fakeRegistrar) GetNameServersForDomain(ctx context.Context, domain string) (config.Registrar, []string, error) {
+	return config.Registrar{Label: "gd", Type: "godaddy"}, r.ns, nil
+}
+
+func newWatcher(t *testing.T, cf *fakeCF, clock *time.Time) (*Watcher, *[]string) {
+	t.Helper()
+	var notes []string
+	w := &Watcher{
+		CF:            cf,
+		Registrar:     fakeRegistrar{ns: []string{"ns1.parked.example", "ns2.parked.example"}},
+		Accounts:      []config.CF{{Label: "acc"}},
+		File:          filepath.Join(t.TempDir(), "watch.json"),
+		Notify:        func(msg string) { notes = append(notes, msg) },
+		EscalateAfter: 48 * time.Hour,
+		MinInterval:   time.Minute,
+		MaxInterval:   time.Hour,
+		now:           func() time.Time { return *clock },
+	}
+	return w, &notes
+}
+
+func TestBackoffDoublesUpToMax(t *testing.T) {
+	w := &Watcher{MinInterval: time.Minute, MaxInterval: 10 * time.Minute}
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute, 10 * time.Minute}
+	for i, d := range want {
+		if got := w.backoff(i); got != d {
+			t.Fatalf("backoff(%d) = %v, want %v", i, got, d)
+		}
+	}
+}
+
+func TestWatcherNotifiesOnActivation(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cf := &fakeCF{status: "pending"}
+	w, notes := newWatcher(t, cf, &clock)
+
+	if err := w.Track("Example.com", "acc", []string{"ada.ns.cloudflare.com"}); err != nil {
+		t.Fatalf("Track: %v", err)
+	}
+
+	// 未到检查时间时不应查询
+	w.Run(context.Background())
+	if cf.calls != 0 {
+		t.Fatalf("expected no poll before NextCheck, got %d", cf.calls)
+	}
+
+	clock = clock.Add(time.Minute)
+	w.Run(context.Background())
+	if cf.calls != 1 || len(*notes) != 0 {
+		t.Fatalf("expected one silent poll, calls=%d notes=%v", cf.calls, *notes)
+	}
+	list, _ := w.List()
+	if len(list) != 1 || !list[0].NextCheck.Equal(clock.Add(2*time.Minute)) {
+		t.Fatalf("expected backoff to double, got %+v", list)
+	}
+
+	cf.status = "active"
+	clock = clock.Add(2 * time.Minute)
+	w.Run(context.Background())
+	if len(*notes) != 1 || !strings.Contains((*notes)[0], "已激活") {
+		t.Fatalf("expected activation notice, got %v", *notes)
+	}
+	if list, _ := w.List(); len(list) != 0 {
+		t.Fatalf("active zone should no longer be tracked: %+v", list)
+	}
+}
+
+func TestTrackDoesNotWaitForPoll(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cf := &fakeCF{status: "pending", polling: make(chan struct{}, 1), release: make(chan struct{})}
+	w, _ := newWatcher(t, cf, &clock)
+	if err := w.Track("example.com", "acc", nil); err != nil {
+		t.Fatalf("Track: %v", err)
+	}
+	clock = clock.Add(time.Minute)
+
+	done := make(chan struct{})
+	go func() {
+		w.Run(context.Background())
+		close(done)
+	}()
+	<-cf.polling
+
+	// 轮询进行中：/getns 触发的 Track 应立即返回，且重置的条目不会被本轮结果覆盖
+	tracked := make(chan error, 1)
+	go func() {
+		_ = w.Track("other.com", "acc", nil)
+		tracked <- w.Track("example.com", "acc", []string{"new.ns.cloudflare.com"})
+	}()
+	select {
+	case err := <-tracked:
+		if err != nil {
+			t.Fatalf("Track: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Track blocked while Run was polling Cloudflare")
+	}
+	close(cf.release)
+	<-done
+
+	list, _ := w.List()
+	if len(list) != 2 {
+		t.Fatalf("expected both zones tracked, got %+v", list)
+	}
+	for _, e := range list {
+		if e.Domain == "example.com" && (e.Attempts != 0 || e.NameServers[0] != "new.ns.cloudflare.com") {
+			t.Fatalf("entry reset by Track was overwritten by the poll: %+v", e)
+		}
+	}
+}
+
+func TestWatcherEscalatesOnceWithRegistrarNS(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cf := &fakeCF{status: "pending"}
+	w, notes := newWatcher(t, cf, &clock)
+	_ = w.Track("example.com", "acc", nil)
+
+	for i := 0; i < 60; i++ {
+		clock = clock.Add(time.Hour)
+		w.Run(context.Background())
+	}
+
+	if len(*notes) != 1 {
+		t.Fatalf("expected exactly one escalation, got %v", *notes)
+	}
+	msg := (*notes)[0]
+	for _, want := range []string{"长时间未激活", "ada.ns.cloudflare.com", "ns1.parked.example", "NS 不一致"} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("escalation missing %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestWatcherDropsDeletedZone(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cf := &fakeCF{}
+	w, notes := newWatcher(t, cf, &clock)
+	_ = w.Track("example.com", "acc", nil)
+
+	clock = clock.Add(time.Hour)
+	w.Run(context.Background())
+	if list, _ := w.List(); len(list) != 0 || len(*notes) != 1 {
+		t.Fatalf("expected deleted zone to be dropped, list=%v notes=%v", list, *notes)
+	}
+}
+
+func TestSameNameServers(t *testing.T) {
+	if !SameNameServers([]string{"B.ns.cloudflare.com.", "a.ns.cloudflare.com"}, []string{"a.ns.cloudflare.com", "b.ns.cloudflare.com"}) {
+		t.Fatalf("expected equal NS sets")
+	}
+	if SameNameServers([]string{"a"}, []string{"a", "b"}) {
+		t.Fatalf("expected different NS sets")
+	}
+}